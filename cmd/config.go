@@ -15,6 +15,8 @@ import (
 	"github.com/trento-project/agent/v3/internal/discovery"
 	"github.com/trento-project/agent/v3/internal/discovery/collector"
 	"github.com/trento-project/agent/v3/internal/identity"
+	"github.com/trento-project/agent/v3/internal/operations"
+	"github.com/trento-project/agent/v3/internal/operations/operator"
 )
 
 const prometheusModePush = "push"
@@ -139,12 +141,26 @@ func LoadConfig(fileSystem afero.Fs) (*agent.Config, error) {
 	}
 
 	return &agent.Config{
-		AgentID:           agentID,
-		InstanceName:      hostname,
-		DiscoveriesConfig: discoveriesConfig,
-		FactsServiceURL:   viper.GetString("facts-service-url"),
-		PluginsFolder:     viper.GetString("plugins-folder"),
-		PrometheusConfig:  prometheusConfig,
-		HeartbeatInterval: viper.GetDuration("heartbeat-interval"),
+		AgentID:                agentID,
+		InstanceName:           hostname,
+		DiscoveriesConfig:      discoveriesConfig,
+		FactsServiceURL:        viper.GetString("facts-service-url"),
+		HdbsqlUserstoreKey:     viper.GetString("hdbsql-userstore-key"),
+		ConfigFileAllowedPaths: viper.GetStringSlice("config-file-allowed-paths"),
+		PluginsFolder:          viper.GetString("plugins-folder"),
+		OperatorPluginsFolder:  viper.GetString("operator-plugins-folder"),
+		PrometheusConfig:       prometheusConfig,
+		HeartbeatInterval:      viper.GetDuration("heartbeat-interval"),
+		OperatorsConfig: operator.Config{
+			ServiceStateAllowedUnits: viper.GetStringSlice("servicestate-allowed-units"),
+		},
+		SignatureConfig: operations.SignatureConfig{
+			PublicKeysFiles: viper.GetStringSlice("operations-public-keys"),
+			Enforced:        viper.GetBool("operations-signature-enforced"),
+			MaxAge:          viper.GetDuration("operations-signature-max-age"),
+			NoncesFile:      viper.GetString("operations-signature-nonces-file"),
+		},
+		PolicyFile:      viper.GetString("operations-policy-file"),
+		RollbacksFolder: viper.GetString("operations-rollbacks-folder"),
 	}, nil
 }
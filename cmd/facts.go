@@ -102,7 +102,9 @@ func gather(cmd *cobra.Command, _ []string) {
 	}
 
 	gathererRegistry := gatherers.NewRegistry(gatherers.StandardGatherers(gatherers.Config{
-		AgentID: agentID,
+		AgentID:                agentID,
+		ConfigFileAllowedPaths: viper.GetStringSlice("config-file-allowed-paths"),
+		HdbsqlUserstoreKey:     viper.GetString("hdbsql-userstore-key"),
 	}))
 
 	slog.Info("loading plugins")
@@ -30,6 +30,7 @@ func NewOperatorCmd() *cobra.Command {
 
 	operatorCmd.AddCommand(NewOperatorRunCmd())
 	operatorCmd.AddCommand(NewOperatorListCmd())
+	operatorCmd.AddCommand(NewOperatorRollbackCmd())
 
 	return operatorCmd
 }
@@ -79,9 +80,32 @@ func NewOperatorListCmd() *cobra.Command {
 		},
 	}
 
+	listCmd.Flags().String("output", "text", "Output format, text or json. The json output includes the arguments schema")
+
 	return listCmd
 }
 
+func NewOperatorRollbackCmd() *cobra.Command {
+	rollbackCmd := &cobra.Command{
+		Use:   "rollback <operation-id>",
+		Short: "Run the deferred rollback of a failed operation",
+		Args:  cobra.ExactArgs(1),
+		Run:   rollbackOperation,
+		PersistentPreRunE: func(agentCmd *cobra.Command, _ []string) error {
+			agentCmd.Flags().VisitAll(func(f *pflag.Flag) {
+				err := viper.BindPFlag(f.Name, f)
+				if err != nil {
+					panic(fmt.Errorf("error during cli init: %w", err))
+				}
+			})
+
+			return agent.InitConfig("agent")
+		},
+	}
+
+	return rollbackCmd
+}
+
 func runOperator(cmd *cobra.Command, _ []string) {
 	var (
 		operatorName = viper.GetString("operator")
@@ -102,12 +126,22 @@ func runOperator(cmd *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	registry := operator.StandardRegistry()
+	registry := operator.StandardRegistry(operator.Config{
+		ServiceStateAllowedUnits: viper.GetStringSlice("servicestate-allowed-units"),
+	})
+
+	err = loadOperatorPlugins(registry)
+	if err != nil {
+		logger.Error("error loading operator plugins", "err", err)
+		os.Exit(1)
+	}
+
+	defer operator.CleanupPlugins()
 
 	operatorBuilder, err := registry.GetOperatorBuilder(operatorName)
 	if err != nil {
 		logger.Error("error building operator", "err", err)
-		os.Exit(1)
+		cleanupPluginsAndExit(1)
 	}
 
 	ctx, cancel := context.WithCancel(cmd.Context())
@@ -131,13 +165,13 @@ func runOperator(cmd *cobra.Command, _ []string) {
 
 	if report.Error != nil {
 		logger.Error(report.Error.Error())
-		os.Exit(0)
+		cleanupPluginsAndExit(0)
 	}
 
 	diff, err := json.Marshal(report.Success.Diff)
 	if err != nil {
 		logger.Error("error marshalling diff output", "err", err)
-		os.Exit(1)
+		cleanupPluginsAndExit(1)
 	}
 
 	logger.Info("Operation succeeded",
@@ -153,7 +187,31 @@ func listOperators(*cobra.Command, []string) {
 
 	slog.SetDefault(logger)
 
-	registry := operator.StandardRegistry()
+	registry := operator.StandardRegistry(operator.Config{
+		ServiceStateAllowedUnits: viper.GetStringSlice("servicestate-allowed-units"),
+	})
+
+	err := loadOperatorPlugins(registry)
+	if err != nil {
+		logger.Error("error loading operator plugins", "err", err)
+		os.Exit(1)
+	}
+
+	defer operator.CleanupPlugins()
+
+	if viper.GetString("output") == "json" {
+		catalogue, err := json.MarshalIndent(registry.Catalogue(), "", "  ")
+		if err != nil {
+			logger.Error("error marshalling operators catalogue", "err", err)
+			cleanupPluginsAndExit(1)
+		}
+
+		//nolint:forbidigo
+		fmt.Println(string(catalogue))
+
+		return
+	}
+
 	operators := registry.AvailableOperators()
 
 	slog.Info("Available operators:")
@@ -162,3 +220,76 @@ func listOperators(*cobra.Command, []string) {
 		slog.Info(o)
 	}
 }
+
+func rollbackOperation(cmd *cobra.Command, args []string) {
+	var (
+		operationID     = args[0]
+		rollbacksFolder = viper.GetString("operations-rollbacks-folder")
+		logger          = utils.NewDefaultLogger(
+			viper.GetString("log-level"),
+		)
+	)
+
+	slog.SetDefault(logger)
+
+	if rollbacksFolder == "" {
+		logger.Error("deferred rollbacks are not enabled, operations-rollbacks-folder is not configured")
+		os.Exit(1)
+	}
+
+	store := operator.NewDeferredRollbackStore(rollbacksFolder)
+
+	deferredRollback, err := store.Load(operationID)
+	if err != nil {
+		logger.Error("error loading deferred rollback", "err", err)
+		os.Exit(1)
+	}
+
+	slog.Info("Rollback", "operation_id", operationID, "operator", deferredRollback.Operator)
+
+	registry := operator.StandardRegistry(operator.Config{
+		ServiceStateAllowedUnits: viper.GetStringSlice("servicestate-allowed-units"),
+	})
+
+	err = loadOperatorPlugins(registry)
+	if err != nil {
+		logger.Error("error loading operator plugins", "err", err)
+		os.Exit(1)
+	}
+
+	defer operator.CleanupPlugins()
+
+	report := operator.RunDeferredRollback(cmd.Context(), registry, deferredRollback)
+	if report.Error != nil {
+		logger.Error(report.Error.Error())
+		cleanupPluginsAndExit(1)
+	}
+
+	err = store.Delete(operationID)
+	if err != nil {
+		logger.Warn("error removing deferred rollback", "err", err)
+	}
+
+	logger.Info("Rollback succeeded", "operation_id", operationID)
+}
+
+func loadOperatorPlugins(registry *operator.Registry) error {
+	operatorsFromPlugins, err := operator.GetOperatorsFromPlugins(
+		operator.PluginLoaders{
+			"rpc": &operator.RPCPluginLoader{},
+		},
+		viper.GetString("operator-plugins-folder"),
+	)
+	if err != nil {
+		return err
+	}
+
+	registry.AddOperators(operatorsFromPlugins)
+
+	return nil
+}
+
+func cleanupPluginsAndExit(code int) {
+	operator.CleanupPlugins()
+	os.Exit(code)
+}
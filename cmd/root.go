@@ -34,6 +34,8 @@ that can help you deploy, provision and operate infrastructure for SAP Applicati
 		String("log-level", "info", "then minimum severity (error, warn, info, debug) of logs to output")
 	rootCmd.PersistentFlags().
 		String("plugins-folder", "/usr/etc/trento/plugins/", "trento plugins folder")
+	rootCmd.PersistentFlags().
+		String("operator-plugins-folder", "/usr/etc/trento/operator-plugins/", "trento operator plugins folder")
 
 	// Make global flags available in the children commands
 	rootCmd.PersistentFlags().VisitAll(func(f *pflag.Flag) {
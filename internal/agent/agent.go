@@ -19,6 +19,7 @@ import (
 	"github.com/trento-project/agent/v3/internal/factsengine"
 	"github.com/trento-project/agent/v3/internal/factsengine/gatherers"
 	"github.com/trento-project/agent/v3/internal/operations"
+	"github.com/trento-project/agent/v3/internal/operations/localpolicy"
 	"github.com/trento-project/agent/v3/internal/operations/operator"
 )
 
@@ -29,13 +30,20 @@ type Agent struct {
 }
 
 type Config struct {
-	AgentID           string
-	InstanceName      string
-	DiscoveriesConfig *discovery.DiscoveriesConfig
-	FactsServiceURL   string
-	PluginsFolder     string
-	PrometheusConfig  *discovery.PrometheusConfig
-	HeartbeatInterval time.Duration
+	AgentID                string
+	InstanceName           string
+	DiscoveriesConfig      *discovery.DiscoveriesConfig
+	FactsServiceURL        string
+	HdbsqlUserstoreKey     string
+	ConfigFileAllowedPaths []string
+	PluginsFolder          string
+	OperatorPluginsFolder  string
+	PrometheusConfig       *discovery.PrometheusConfig
+	HeartbeatInterval      time.Duration
+	OperatorsConfig        operator.Config
+	SignatureConfig        operations.SignatureConfig
+	PolicyFile             string
+	RollbacksFolder        string
 }
 
 // NewAgent returns a new instance of Agent with the given configuration.
@@ -68,7 +76,11 @@ func NewAgent(config *Config) (*Agent, error) {
 func (a *Agent) Start(ctx context.Context) error {
 	gathererRegistry := gatherers.NewRegistry(
 		gatherers.StandardGatherers(
-			gatherers.Config{AgentID: a.config.AgentID},
+			gatherers.Config{
+				AgentID:                a.config.AgentID,
+				ConfigFileAllowedPaths: a.config.ConfigFileAllowedPaths,
+				HdbsqlUserstoreKey:     a.config.HdbsqlUserstoreKey,
+			},
 		),
 	)
 
@@ -81,9 +93,55 @@ func (a *Agent) Start(ctx context.Context) error {
 		return err
 	}
 
-	operatorsRegistry := operator.StandardRegistry()
+	operatorsConfig := a.config.OperatorsConfig
+	operatorsConfig.ArtifactUploader = a.collectorClient
 
-	op := operations.NewOperationsEngine(a.config.AgentID, a.config.FactsServiceURL, *operatorsRegistry)
+	operatorsRegistry := operator.StandardRegistry(operatorsConfig)
+
+	operatorsFromPlugins, err := operator.GetOperatorsFromPlugins(
+		operator.PluginLoaders{
+			"rpc": &operator.RPCPluginLoader{},
+		},
+		a.config.OperatorPluginsFolder,
+	)
+	if err != nil {
+		return fmt.Errorf("could not load the operator plugins: %w", err)
+	}
+
+	operatorsRegistry.AddOperators(operatorsFromPlugins)
+
+	requestVerifier, err := operations.NewRequestVerifierFromConfig(a.config.SignatureConfig)
+	if err != nil {
+		return fmt.Errorf("could not configure the operation requests verification: %w", err)
+	}
+
+	handleOptions := []operations.HandleEventOption{
+		operations.WithRequestVerifier(requestVerifier),
+	}
+
+	if a.config.PolicyFile != "" {
+		localPolicy := localpolicy.NewPolicy(a.config.PolicyFile)
+
+		err := localPolicy.Check()
+		if err != nil {
+			return fmt.Errorf("could not configure the operations local policy: %w", err)
+		}
+
+		handleOptions = append(handleOptions, operations.WithLocalPolicy(localPolicy))
+	}
+
+	if a.config.RollbacksFolder != "" {
+		handleOptions = append(handleOptions, operations.WithDeferredRollbackStore(
+			operator.NewDeferredRollbackStore(a.config.RollbacksFolder),
+		))
+	}
+
+	op := operations.NewOperationsEngine(
+		a.config.AgentID,
+		a.config.FactsServiceURL,
+		*operatorsRegistry,
+		handleOptions...,
+	)
 
 	slog.Info("Starting operations service...")
 
@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package corosync
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+const (
+	totemSection       = "totem"
+	defaultIndentation = "\t"
+)
+
+// SetTotemOptions returns a copy of the given corosync.conf content with the
+// totem options set to the given values.
+// Comments, indentation and the order of the existing lines are kept untouched.
+// Options not present in the totem section are appended at the end of it.
+func SetTotemOptions(content []byte, options map[string]string) ([]byte, error) {
+	lines := strings.Split(string(content), "\n")
+	updatedLines := make([]string, 0, len(lines)+len(options))
+	stack := []string{}
+	pending := make(map[string]string, len(options))
+	indentation := ""
+	totemFound := false
+
+	for _, line := range lines {
+		trimmedLine := strings.TrimSpace(line)
+		inTotem := len(stack) == 1 && stack[0] == totemSection
+
+		switch {
+		case trimmedLine == "" || strings.HasPrefix(trimmedLine, "#"):
+		case trimmedLine == "}":
+			if inTotem {
+				updatedLines = append(updatedLines, missingOptionLines(pending, indentation)...)
+			}
+
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case strings.HasSuffix(trimmedLine, "{"):
+			section := strings.TrimSpace(trimmedLine[:len(trimmedLine)-1])
+			if len(stack) == 0 && section == totemSection {
+				totemFound = true
+				indentation = ""
+
+				for key, value := range options {
+					pending[key] = value
+				}
+			}
+
+			stack = append(stack, section)
+		case inTotem:
+			before, after, ok := strings.Cut(line, ":")
+			if !ok {
+				break
+			}
+
+			if indentation == "" {
+				indentation = leadingWhitespace(line)
+			}
+
+			key := strings.TrimSpace(before)
+
+			value, found := pending[key]
+			if !found {
+				break
+			}
+
+			delete(pending, key)
+
+			separator := leadingWhitespace(after)
+			line = before + ":" + separator + value
+		}
+
+		updatedLines = append(updatedLines, line)
+	}
+
+	if !totemFound {
+		return nil, errors.New("totem section not found in corosync.conf")
+	}
+
+	return []byte(strings.Join(updatedLines, "\n")), nil
+}
+
+func missingOptionLines(pending map[string]string, indentation string) []string {
+	if indentation == "" {
+		indentation = defaultIndentation
+	}
+
+	keys := make([]string, 0, len(pending))
+	for key := range pending {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		lines = append(lines, indentation+key+": "+pending[key])
+		delete(pending, key)
+	}
+
+	return lines
+}
+
+func leadingWhitespace(s string) string {
+	return s[:len(s)-len(strings.TrimLeft(s, " \t"))]
+}
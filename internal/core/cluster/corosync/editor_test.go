@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package corosync_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/trento-project/agent/v3/internal/core/cluster/corosync"
+	"github.com/trento-project/agent/v3/test/helpers"
+)
+
+type EditorTestSuite struct {
+	suite.Suite
+}
+
+func TestEditorTestSuite(t *testing.T) {
+	suite.Run(t, new(EditorTestSuite))
+}
+
+func (suite *EditorTestSuite) TestSetTotemOptionsUpdatesExistingValues() {
+	content := `# Please read the corosync.conf.5 manual page
+totem {
+	version: 2
+	# token timeout
+	token:   5000
+	interface {
+		token: 1
+	}
+	consensus: 6000
+}
+
+logging {
+	token: 2
+}
+`
+
+	expected := `# Please read the corosync.conf.5 manual page
+totem {
+	version: 2
+	# token timeout
+	token:   30000
+	interface {
+		token: 1
+	}
+	consensus: 36000
+}
+
+logging {
+	token: 2
+}
+`
+
+	result, err := corosync.SetTotemOptions([]byte(content), map[string]string{
+		"token":     "30000",
+		"consensus": "36000",
+	})
+
+	suite.Require().NoError(err)
+	suite.Equal(expected, string(result))
+}
+
+func (suite *EditorTestSuite) TestSetTotemOptionsAppendsMissingValues() {
+	content := `totem {
+        version: 2
+        token: 5000
+}
+`
+
+	expected := `totem {
+        version: 2
+        token: 5000
+        max_messages: 20
+        token_retransmits_before_loss_const: 10
+}
+`
+
+	result, err := corosync.SetTotemOptions([]byte(content), map[string]string{
+		"token_retransmits_before_loss_const": "10",
+		"max_messages":                        "20",
+	})
+
+	suite.Require().NoError(err)
+	suite.Equal(expected, string(result))
+}
+
+func (suite *EditorTestSuite) TestSetTotemOptionsKeepsParsableFile() {
+	content, err := os.ReadFile(helpers.GetFixturePath("discovery/cluster/corosync.conf"))
+	suite.Require().NoError(err)
+
+	result, err := corosync.SetTotemOptions(content, map[string]string{
+		"token":     "40000",
+		"downcheck": "1000",
+	})
+	suite.Require().NoError(err)
+
+	confPath := suite.T().TempDir() + "/corosync.conf"
+	suite.Require().NoError(os.WriteFile(confPath, result, 0o600))
+
+	data, err := corosync.NewCorosyncParser(confPath).Parse()
+	suite.Require().NoError(err)
+	suite.Equal("40000", data.Totem["token"])
+	suite.Equal("1000", data.Totem["downcheck"])
+	suite.Equal("36000", data.Totem["consensus"])
+	suite.Equal("hana_cluster", data.Totem["cluster_name"])
+}
+
+func (suite *EditorTestSuite) TestSetTotemOptionsNoTotemSection() {
+	_, err := corosync.SetTotemOptions([]byte("logging {\n\tdebug: off\n}\n"), map[string]string{
+		"token": "30000",
+	})
+
+	suite.Require().EqualError(err, "totem section not found in corosync.conf")
+}
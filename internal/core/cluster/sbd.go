@@ -8,8 +8,10 @@ import (
 	"log/slog"
 	"os"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"unicode"
 
 	"errors"
 
@@ -23,6 +25,12 @@ const (
 	SBDStatusUnknown   = "unknown"
 	SBDStatusUnhealthy = "unhealthy"
 	SBDStatusHealthy   = "healthy"
+
+	// sbdConfigShellChars are the characters with a special meaning for the shell,
+	// which need quoting when the configuration file is sourced
+	sbdConfigShellChars = " \t;&|<>()[]{}*?~#$`'\"\\"
+	// sbdConfigDoubleQuotedChars are still expanded or escaped inside double quotes
+	sbdConfigDoubleQuotedChars = "$`\"\\"
 )
 
 type SBD struct {
@@ -111,6 +119,86 @@ func LoadSbdConfig(sbdConfigPath string) (map[string]string, error) {
 	return conf, nil
 }
 
+// SetSbdConfigValues returns a copy of the given sbd config file content with the
+// given entries set to the provided values.
+// Comments and the order of the existing lines are kept untouched. Entries that are not
+// found in the file are appended at the end of it.
+// The values must be validated with ValidateSbdConfigValue.
+func SetSbdConfigValues(content []byte, values map[string]string) []byte {
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	pending := make(map[string]bool, len(values))
+
+	for key := range values {
+		pending[key] = true
+	}
+
+	for idx, line := range lines {
+		trimmedLine := strings.TrimSpace(line)
+		if trimmedLine == "" || strings.HasPrefix(trimmedLine, "#") {
+			continue
+		}
+
+		before, after, found := strings.Cut(trimmedLine, "=")
+		if !found {
+			continue
+		}
+
+		key := strings.TrimSpace(before)
+
+		value, requested := values[key]
+		if !requested {
+			continue
+		}
+
+		quoted := strings.HasPrefix(after, `"`) || strings.HasPrefix(after, "'")
+		lines[idx] = key + "=" + formatSbdConfigValue(value, quoted)
+		delete(pending, key)
+	}
+
+	missingKeys := make([]string, 0, len(pending))
+	for key := range pending {
+		missingKeys = append(missingKeys, key)
+	}
+
+	slices.Sort(missingKeys)
+
+	for _, key := range missingKeys {
+		lines = append(lines, key+"="+formatSbdConfigValue(values[key], false))
+	}
+
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
+
+// ValidateSbdConfigValue checks that a value can be written in the sbd configuration file.
+// Control characters would break the file lines, and values mixing single quotes with
+// characters expanded inside double quotes cannot be quoted with shell rules.
+func ValidateSbdConfigValue(value string) error {
+	if strings.ContainsFunc(value, unicode.IsControl) {
+		return fmt.Errorf("value %q contains control characters", value)
+	}
+
+	if strings.Contains(value, "'") && strings.ContainsAny(value, sbdConfigDoubleQuotedChars) {
+		return fmt.Errorf("value %q cannot combine single quotes with any of %s", value, sbdConfigDoubleQuotedChars)
+	}
+
+	return nil
+}
+
+// formatSbdConfigValue quotes the value with shell rules, so it is read literally both by
+// the shell and by systemd. Double quotes are used if nothing is expanded inside them,
+// and single quotes otherwise. Values must be validated with ValidateSbdConfigValue.
+func formatSbdConfigValue(value string, quoted bool) string {
+	if !quoted && !strings.ContainsAny(value, sbdConfigShellChars) {
+		return value
+	}
+
+	if !strings.ContainsAny(value, sbdConfigDoubleQuotedChars) {
+		return `"` + value + `"`
+	}
+
+	return "'" + value + "'"
+}
+
 func NewSBDDevice(executor utils.CommandExecutor, sbdPath, device string) SBDDevice {
 	return SBDDevice{
 		executor: executor,
@@ -5,6 +5,8 @@ package cluster_test
 
 import (
 	"errors"
+	"os"
+	"path"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -242,6 +244,96 @@ func (suite *SbdTestSuite) TestLoadSbdConfigParsingError() {
 	suite.Require().EqualError(err, "could not parse sbd config file: error on line 1: missing =")
 }
 
+func (suite *SbdTestSuite) TestSetSbdConfigValues() {
+	content := `# Whether to enable the pacemaker integration.
+SBD_PACEMAKER=yes
+#SBD_DELAY_START=yes
+SBD_DELAY_START=no
+SBD_DEVICE="/dev/vdc"
+SBD_WATCHDOG_TIMEOUT=5
+`
+
+	expected := `# Whether to enable the pacemaker integration.
+SBD_PACEMAKER=yes
+#SBD_DELAY_START=yes
+SBD_DELAY_START=yes
+SBD_DEVICE="/dev/vdc;/dev/vdb"
+SBD_WATCHDOG_TIMEOUT=15
+SBD_OPTS="-v -v"
+SBD_STARTMODE=clean
+`
+
+	result := cluster.SetSbdConfigValues([]byte(content), map[string]string{
+		"SBD_DELAY_START":      "yes",
+		"SBD_DEVICE":           "/dev/vdc;/dev/vdb",
+		"SBD_WATCHDOG_TIMEOUT": "15",
+		"SBD_STARTMODE":        "clean",
+		"SBD_OPTS":             "-v -v",
+	})
+
+	suite.Equal(expected, string(result))
+}
+
+func (suite *SbdTestSuite) TestSetSbdConfigValuesShellQuoting() {
+	content := "SBD_OPTS=\"-v\"\nSBD_DEVICE='/dev/vdc'\n"
+
+	values := map[string]string{
+		"SBD_OPTS":           `-v "$(reboot)" \`,
+		"SBD_DEVICE":         "/dev/vdc;/dev/vdb",
+		"SBD_TIMEOUT_ACTION": "it's",
+	}
+
+	expected := `SBD_OPTS='-v "$(reboot)" \'
+SBD_DEVICE="/dev/vdc;/dev/vdb"
+SBD_TIMEOUT_ACTION="it's"
+`
+
+	result := cluster.SetSbdConfigValues([]byte(content), values)
+	suite.Equal(expected, string(result))
+
+	configPath := path.Join(suite.T().TempDir(), "sbd")
+	suite.Require().NoError(os.WriteFile(configPath, result, 0o600))
+
+	sbdConfig, err := cluster.LoadSbdConfig(configPath)
+	suite.Require().NoError(err)
+	suite.Equal(values, sbdConfig)
+}
+
+func (suite *SbdTestSuite) TestValidateSbdConfigValue() {
+	suite.NoError(cluster.ValidateSbdConfigValue(`-v "$(reboot)" \`))
+	suite.NoError(cluster.ValidateSbdConfigValue("it's"))
+	suite.EqualError(
+		cluster.ValidateSbdConfigValue("x\nSBD_DEVICE=/dev/evil"),
+		`value "x\nSBD_DEVICE=/dev/evil" contains control characters`,
+	)
+	suite.EqualError(
+		cluster.ValidateSbdConfigValue("x\rSBD_DEVICE=/dev/evil"),
+		`value "x\rSBD_DEVICE=/dev/evil" contains control characters`,
+	)
+	suite.EqualError(
+		cluster.ValidateSbdConfigValue("it's $HOME"),
+		"value \"it's $HOME\" cannot combine single quotes with any of $`\"\\",
+	)
+}
+
+func (suite *SbdTestSuite) TestSetSbdConfigValuesKeepsParsableFile() {
+	content, err := os.ReadFile(helpers.GetFixturePath("discovery/cluster/sbd/sbd_config"))
+	suite.Require().NoError(err)
+
+	configPath := path.Join(suite.T().TempDir(), "sbd")
+	result := cluster.SetSbdConfigValues(content, map[string]string{
+		"SBD_WATCHDOG_TIMEOUT": "10",
+		"SBD_DEVICE":           "/dev/vdd",
+	})
+	suite.Require().NoError(os.WriteFile(configPath, result, 0o600))
+
+	sbdConfig, err := cluster.LoadSbdConfig(configPath)
+	suite.Require().NoError(err)
+	suite.Equal("10", sbdConfig["SBD_WATCHDOG_TIMEOUT"])
+	suite.Equal("/dev/vdd", sbdConfig["SBD_DEVICE"])
+	suite.Equal("always", sbdConfig["SBD_STARTMODE"])
+}
+
 func (suite *SbdTestSuite) TestNewSBD() {
 	mockCommand := new(mocks.MockCommandExecutor)
 	mockCommand.On("Output", "/bin/sbd", "-d", "/dev/vdc", "dump").Return(mockSbdDump(), nil)
@@ -27,6 +27,9 @@ type Connector interface {
 	ListUnitsContext(ctx context.Context) ([]dbus.UnitStatus, error)
 	ListUnitsByNamesContext(ctx context.Context, units []string) ([]dbus.UnitStatus, error)
 	ReloadContext(ctx context.Context) error
+	RestartUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error)
+	StartUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error)
+	StopUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error)
 	// NewWithContext establishes a connection to any available bus and authenticates.
 	// Callers should call Close() when done with the connection.
 	// see https://pkg.go.dev/github.com/coreos/go-systemd/v22@v22.5.0/dbus#NewWithContext
@@ -523,6 +523,183 @@ func (_c *MockConnector_ReloadContext_Call) RunAndReturn(run func(context.Contex
 	return _c
 }
 
+// RestartUnitContext provides a mock function with given fields: ctx, name, mode, ch
+func (_m *MockConnector) RestartUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error) {
+	ret := _m.Called(ctx, name, mode, ch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestartUnitContext")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, chan<- string) (int, error)); ok {
+		return rf(ctx, name, mode, ch)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, chan<- string) int); ok {
+		r0 = rf(ctx, name, mode, ch)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, chan<- string) error); ok {
+		r1 = rf(ctx, name, mode, ch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockConnector_RestartUnitContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RestartUnitContext'
+type MockConnector_RestartUnitContext_Call struct {
+	*mock.Call
+}
+
+// RestartUnitContext is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - mode string
+//   - ch chan<- string
+func (_e *MockConnector_Expecter) RestartUnitContext(ctx interface{}, name interface{}, mode interface{}, ch interface{}) *MockConnector_RestartUnitContext_Call {
+	return &MockConnector_RestartUnitContext_Call{Call: _e.mock.On("RestartUnitContext", ctx, name, mode, ch)}
+}
+
+func (_c *MockConnector_RestartUnitContext_Call) Run(run func(ctx context.Context, name string, mode string, ch chan<- string)) *MockConnector_RestartUnitContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(chan<- string))
+	})
+	return _c
+}
+
+func (_c *MockConnector_RestartUnitContext_Call) Return(_a0 int, _a1 error) *MockConnector_RestartUnitContext_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockConnector_RestartUnitContext_Call) RunAndReturn(run func(context.Context, string, string, chan<- string) (int, error)) *MockConnector_RestartUnitContext_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StartUnitContext provides a mock function with given fields: ctx, name, mode, ch
+func (_m *MockConnector) StartUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error) {
+	ret := _m.Called(ctx, name, mode, ch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartUnitContext")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, chan<- string) (int, error)); ok {
+		return rf(ctx, name, mode, ch)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, chan<- string) int); ok {
+		r0 = rf(ctx, name, mode, ch)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, chan<- string) error); ok {
+		r1 = rf(ctx, name, mode, ch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockConnector_StartUnitContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StartUnitContext'
+type MockConnector_StartUnitContext_Call struct {
+	*mock.Call
+}
+
+// StartUnitContext is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - mode string
+//   - ch chan<- string
+func (_e *MockConnector_Expecter) StartUnitContext(ctx interface{}, name interface{}, mode interface{}, ch interface{}) *MockConnector_StartUnitContext_Call {
+	return &MockConnector_StartUnitContext_Call{Call: _e.mock.On("StartUnitContext", ctx, name, mode, ch)}
+}
+
+func (_c *MockConnector_StartUnitContext_Call) Run(run func(ctx context.Context, name string, mode string, ch chan<- string)) *MockConnector_StartUnitContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(chan<- string))
+	})
+	return _c
+}
+
+func (_c *MockConnector_StartUnitContext_Call) Return(_a0 int, _a1 error) *MockConnector_StartUnitContext_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockConnector_StartUnitContext_Call) RunAndReturn(run func(context.Context, string, string, chan<- string) (int, error)) *MockConnector_StartUnitContext_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StopUnitContext provides a mock function with given fields: ctx, name, mode, ch
+func (_m *MockConnector) StopUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error) {
+	ret := _m.Called(ctx, name, mode, ch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StopUnitContext")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, chan<- string) (int, error)); ok {
+		return rf(ctx, name, mode, ch)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, chan<- string) int); ok {
+		r0 = rf(ctx, name, mode, ch)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, chan<- string) error); ok {
+		r1 = rf(ctx, name, mode, ch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockConnector_StopUnitContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StopUnitContext'
+type MockConnector_StopUnitContext_Call struct {
+	*mock.Call
+}
+
+// StopUnitContext is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - mode string
+//   - ch chan<- string
+func (_e *MockConnector_Expecter) StopUnitContext(ctx interface{}, name interface{}, mode interface{}, ch interface{}) *MockConnector_StopUnitContext_Call {
+	return &MockConnector_StopUnitContext_Call{Call: _e.mock.On("StopUnitContext", ctx, name, mode, ch)}
+}
+
+func (_c *MockConnector_StopUnitContext_Call) Run(run func(ctx context.Context, name string, mode string, ch chan<- string)) *MockConnector_StopUnitContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(chan<- string))
+	})
+	return _c
+}
+
+func (_c *MockConnector_StopUnitContext_Call) Return(_a0 int, _a1 error) *MockConnector_StopUnitContext_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockConnector_StopUnitContext_Call) RunAndReturn(run func(context.Context, string, string, chan<- string) (int, error)) *MockConnector_StopUnitContext_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockConnector creates a new instance of MockConnector. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockConnector(t interface {
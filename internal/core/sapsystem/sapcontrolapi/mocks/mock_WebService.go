@@ -22,6 +22,301 @@ func (_m *MockWebService) EXPECT() *MockWebService_Expecter {
 	return &MockWebService_Expecter{mock: &_m.Mock}
 }
 
+// ABAPGetWPTableContext provides a mock function with given fields: ctx, request
+func (_m *MockWebService) ABAPGetWPTableContext(ctx context.Context, request *sapcontrolapi.ABAPGetWPTable) (*sapcontrolapi.ABAPGetWPTableResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ABAPGetWPTableContext")
+	}
+
+	var r0 *sapcontrolapi.ABAPGetWPTableResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sapcontrolapi.ABAPGetWPTable) (*sapcontrolapi.ABAPGetWPTableResponse, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sapcontrolapi.ABAPGetWPTable) *sapcontrolapi.ABAPGetWPTableResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sapcontrolapi.ABAPGetWPTableResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sapcontrolapi.ABAPGetWPTable) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWebService_ABAPGetWPTableContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ABAPGetWPTableContext'
+type MockWebService_ABAPGetWPTableContext_Call struct {
+	*mock.Call
+}
+
+// ABAPGetWPTableContext is a helper method to define mock.On call
+//   - ctx context.Context
+//   - request *sapcontrolapi.ABAPGetWPTable
+func (_e *MockWebService_Expecter) ABAPGetWPTableContext(ctx interface{}, request interface{}) *MockWebService_ABAPGetWPTableContext_Call {
+	return &MockWebService_ABAPGetWPTableContext_Call{Call: _e.mock.On("ABAPGetWPTableContext", ctx, request)}
+}
+
+func (_c *MockWebService_ABAPGetWPTableContext_Call) Run(run func(ctx context.Context, request *sapcontrolapi.ABAPGetWPTable)) *MockWebService_ABAPGetWPTableContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*sapcontrolapi.ABAPGetWPTable))
+	})
+	return _c
+}
+
+func (_c *MockWebService_ABAPGetWPTableContext_Call) Return(_a0 *sapcontrolapi.ABAPGetWPTableResponse, _a1 error) *MockWebService_ABAPGetWPTableContext_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWebService_ABAPGetWPTableContext_Call) RunAndReturn(run func(context.Context, *sapcontrolapi.ABAPGetWPTable) (*sapcontrolapi.ABAPGetWPTableResponse, error)) *MockWebService_ABAPGetWPTableContext_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// EnqGetStatisticContext provides a mock function with given fields: ctx, request
+func (_m *MockWebService) EnqGetStatisticContext(ctx context.Context, request *sapcontrolapi.EnqGetStatistic) (*sapcontrolapi.EnqGetStatisticResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnqGetStatisticContext")
+	}
+
+	var r0 *sapcontrolapi.EnqGetStatisticResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sapcontrolapi.EnqGetStatistic) (*sapcontrolapi.EnqGetStatisticResponse, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sapcontrolapi.EnqGetStatistic) *sapcontrolapi.EnqGetStatisticResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sapcontrolapi.EnqGetStatisticResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sapcontrolapi.EnqGetStatistic) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWebService_EnqGetStatisticContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnqGetStatisticContext'
+type MockWebService_EnqGetStatisticContext_Call struct {
+	*mock.Call
+}
+
+// EnqGetStatisticContext is a helper method to define mock.On call
+//   - ctx context.Context
+//   - request *sapcontrolapi.EnqGetStatistic
+func (_e *MockWebService_Expecter) EnqGetStatisticContext(ctx interface{}, request interface{}) *MockWebService_EnqGetStatisticContext_Call {
+	return &MockWebService_EnqGetStatisticContext_Call{Call: _e.mock.On("EnqGetStatisticContext", ctx, request)}
+}
+
+func (_c *MockWebService_EnqGetStatisticContext_Call) Run(run func(ctx context.Context, request *sapcontrolapi.EnqGetStatistic)) *MockWebService_EnqGetStatisticContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*sapcontrolapi.EnqGetStatistic))
+	})
+	return _c
+}
+
+func (_c *MockWebService_EnqGetStatisticContext_Call) Return(_a0 *sapcontrolapi.EnqGetStatisticResponse, _a1 error) *MockWebService_EnqGetStatisticContext_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWebService_EnqGetStatisticContext_Call) RunAndReturn(run func(context.Context, *sapcontrolapi.EnqGetStatistic) (*sapcontrolapi.EnqGetStatisticResponse, error)) *MockWebService_EnqGetStatisticContext_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAlertTreeContext provides a mock function with given fields: ctx, request
+func (_m *MockWebService) GetAlertTreeContext(ctx context.Context, request *sapcontrolapi.GetAlertTree) (*sapcontrolapi.GetAlertTreeResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAlertTreeContext")
+	}
+
+	var r0 *sapcontrolapi.GetAlertTreeResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sapcontrolapi.GetAlertTree) (*sapcontrolapi.GetAlertTreeResponse, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sapcontrolapi.GetAlertTree) *sapcontrolapi.GetAlertTreeResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sapcontrolapi.GetAlertTreeResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sapcontrolapi.GetAlertTree) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWebService_GetAlertTreeContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAlertTreeContext'
+type MockWebService_GetAlertTreeContext_Call struct {
+	*mock.Call
+}
+
+// GetAlertTreeContext is a helper method to define mock.On call
+//   - ctx context.Context
+//   - request *sapcontrolapi.GetAlertTree
+func (_e *MockWebService_Expecter) GetAlertTreeContext(ctx interface{}, request interface{}) *MockWebService_GetAlertTreeContext_Call {
+	return &MockWebService_GetAlertTreeContext_Call{Call: _e.mock.On("GetAlertTreeContext", ctx, request)}
+}
+
+func (_c *MockWebService_GetAlertTreeContext_Call) Run(run func(ctx context.Context, request *sapcontrolapi.GetAlertTree)) *MockWebService_GetAlertTreeContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*sapcontrolapi.GetAlertTree))
+	})
+	return _c
+}
+
+func (_c *MockWebService_GetAlertTreeContext_Call) Return(_a0 *sapcontrolapi.GetAlertTreeResponse, _a1 error) *MockWebService_GetAlertTreeContext_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWebService_GetAlertTreeContext_Call) RunAndReturn(run func(context.Context, *sapcontrolapi.GetAlertTree) (*sapcontrolapi.GetAlertTreeResponse, error)) *MockWebService_GetAlertTreeContext_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAlertsContext provides a mock function with given fields: ctx, request
+func (_m *MockWebService) GetAlertsContext(ctx context.Context, request *sapcontrolapi.GetAlerts) (*sapcontrolapi.GetAlertsResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAlertsContext")
+	}
+
+	var r0 *sapcontrolapi.GetAlertsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sapcontrolapi.GetAlerts) (*sapcontrolapi.GetAlertsResponse, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sapcontrolapi.GetAlerts) *sapcontrolapi.GetAlertsResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sapcontrolapi.GetAlertsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sapcontrolapi.GetAlerts) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWebService_GetAlertsContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAlertsContext'
+type MockWebService_GetAlertsContext_Call struct {
+	*mock.Call
+}
+
+// GetAlertsContext is a helper method to define mock.On call
+//   - ctx context.Context
+//   - request *sapcontrolapi.GetAlerts
+func (_e *MockWebService_Expecter) GetAlertsContext(ctx interface{}, request interface{}) *MockWebService_GetAlertsContext_Call {
+	return &MockWebService_GetAlertsContext_Call{Call: _e.mock.On("GetAlertsContext", ctx, request)}
+}
+
+func (_c *MockWebService_GetAlertsContext_Call) Run(run func(ctx context.Context, request *sapcontrolapi.GetAlerts)) *MockWebService_GetAlertsContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*sapcontrolapi.GetAlerts))
+	})
+	return _c
+}
+
+func (_c *MockWebService_GetAlertsContext_Call) Return(_a0 *sapcontrolapi.GetAlertsResponse, _a1 error) *MockWebService_GetAlertsContext_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWebService_GetAlertsContext_Call) RunAndReturn(run func(context.Context, *sapcontrolapi.GetAlerts) (*sapcontrolapi.GetAlertsResponse, error)) *MockWebService_GetAlertsContext_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetEnvironmentContext provides a mock function with given fields: ctx, request
+func (_m *MockWebService) GetEnvironmentContext(ctx context.Context, request *sapcontrolapi.GetEnvironment) (*sapcontrolapi.GetEnvironmentResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEnvironmentContext")
+	}
+
+	var r0 *sapcontrolapi.GetEnvironmentResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sapcontrolapi.GetEnvironment) (*sapcontrolapi.GetEnvironmentResponse, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sapcontrolapi.GetEnvironment) *sapcontrolapi.GetEnvironmentResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sapcontrolapi.GetEnvironmentResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sapcontrolapi.GetEnvironment) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWebService_GetEnvironmentContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetEnvironmentContext'
+type MockWebService_GetEnvironmentContext_Call struct {
+	*mock.Call
+}
+
+// GetEnvironmentContext is a helper method to define mock.On call
+//   - ctx context.Context
+//   - request *sapcontrolapi.GetEnvironment
+func (_e *MockWebService_Expecter) GetEnvironmentContext(ctx interface{}, request interface{}) *MockWebService_GetEnvironmentContext_Call {
+	return &MockWebService_GetEnvironmentContext_Call{Call: _e.mock.On("GetEnvironmentContext", ctx, request)}
+}
+
+func (_c *MockWebService_GetEnvironmentContext_Call) Run(run func(ctx context.Context, request *sapcontrolapi.GetEnvironment)) *MockWebService_GetEnvironmentContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*sapcontrolapi.GetEnvironment))
+	})
+	return _c
+}
+
+func (_c *MockWebService_GetEnvironmentContext_Call) Return(_a0 *sapcontrolapi.GetEnvironmentResponse, _a1 error) *MockWebService_GetEnvironmentContext_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWebService_GetEnvironmentContext_Call) RunAndReturn(run func(context.Context, *sapcontrolapi.GetEnvironment) (*sapcontrolapi.GetEnvironmentResponse, error)) *MockWebService_GetEnvironmentContext_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetInstancePropertiesContext provides a mock function with given fields: ctx, request
 func (_m *MockWebService) GetInstancePropertiesContext(ctx context.Context, request *sapcontrolapi.GetInstanceProperties) (*sapcontrolapi.GetInstancePropertiesResponse, error) {
 	ret := _m.Called(ctx, request)
@@ -140,6 +435,65 @@ func (_c *MockWebService_GetProcessListContext_Call) RunAndReturn(run func(conte
 	return _c
 }
 
+// GetQueueStatisticContext provides a mock function with given fields: ctx, request
+func (_m *MockWebService) GetQueueStatisticContext(ctx context.Context, request *sapcontrolapi.GetQueueStatistic) (*sapcontrolapi.GetQueueStatisticResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetQueueStatisticContext")
+	}
+
+	var r0 *sapcontrolapi.GetQueueStatisticResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sapcontrolapi.GetQueueStatistic) (*sapcontrolapi.GetQueueStatisticResponse, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sapcontrolapi.GetQueueStatistic) *sapcontrolapi.GetQueueStatisticResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sapcontrolapi.GetQueueStatisticResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sapcontrolapi.GetQueueStatistic) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWebService_GetQueueStatisticContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetQueueStatisticContext'
+type MockWebService_GetQueueStatisticContext_Call struct {
+	*mock.Call
+}
+
+// GetQueueStatisticContext is a helper method to define mock.On call
+//   - ctx context.Context
+//   - request *sapcontrolapi.GetQueueStatistic
+func (_e *MockWebService_Expecter) GetQueueStatisticContext(ctx interface{}, request interface{}) *MockWebService_GetQueueStatisticContext_Call {
+	return &MockWebService_GetQueueStatisticContext_Call{Call: _e.mock.On("GetQueueStatisticContext", ctx, request)}
+}
+
+func (_c *MockWebService_GetQueueStatisticContext_Call) Run(run func(ctx context.Context, request *sapcontrolapi.GetQueueStatistic)) *MockWebService_GetQueueStatisticContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*sapcontrolapi.GetQueueStatistic))
+	})
+	return _c
+}
+
+func (_c *MockWebService_GetQueueStatisticContext_Call) Return(_a0 *sapcontrolapi.GetQueueStatisticResponse, _a1 error) *MockWebService_GetQueueStatisticContext_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWebService_GetQueueStatisticContext_Call) RunAndReturn(run func(context.Context, *sapcontrolapi.GetQueueStatistic) (*sapcontrolapi.GetQueueStatisticResponse, error)) *MockWebService_GetQueueStatisticContext_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetSystemInstanceListContext provides a mock function with given fields: ctx, request
 func (_m *MockWebService) GetSystemInstanceListContext(ctx context.Context, request *sapcontrolapi.GetSystemInstanceList) (*sapcontrolapi.GetSystemInstanceListResponse, error) {
 	ret := _m.Called(ctx, request)
@@ -376,6 +730,65 @@ func (_c *MockWebService_HAGetFailoverConfigContext_Call) RunAndReturn(run func(
 	return _c
 }
 
+// ParameterValueContext provides a mock function with given fields: ctx, request
+func (_m *MockWebService) ParameterValueContext(ctx context.Context, request *sapcontrolapi.ParameterValue) (*sapcontrolapi.ParameterValueResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ParameterValueContext")
+	}
+
+	var r0 *sapcontrolapi.ParameterValueResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sapcontrolapi.ParameterValue) (*sapcontrolapi.ParameterValueResponse, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sapcontrolapi.ParameterValue) *sapcontrolapi.ParameterValueResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sapcontrolapi.ParameterValueResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sapcontrolapi.ParameterValue) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWebService_ParameterValueContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ParameterValueContext'
+type MockWebService_ParameterValueContext_Call struct {
+	*mock.Call
+}
+
+// ParameterValueContext is a helper method to define mock.On call
+//   - ctx context.Context
+//   - request *sapcontrolapi.ParameterValue
+func (_e *MockWebService_Expecter) ParameterValueContext(ctx interface{}, request interface{}) *MockWebService_ParameterValueContext_Call {
+	return &MockWebService_ParameterValueContext_Call{Call: _e.mock.On("ParameterValueContext", ctx, request)}
+}
+
+func (_c *MockWebService_ParameterValueContext_Call) Run(run func(ctx context.Context, request *sapcontrolapi.ParameterValue)) *MockWebService_ParameterValueContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*sapcontrolapi.ParameterValue))
+	})
+	return _c
+}
+
+func (_c *MockWebService_ParameterValueContext_Call) Return(_a0 *sapcontrolapi.ParameterValueResponse, _a1 error) *MockWebService_ParameterValueContext_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWebService_ParameterValueContext_Call) RunAndReturn(run func(context.Context, *sapcontrolapi.ParameterValue) (*sapcontrolapi.ParameterValueResponse, error)) *MockWebService_ParameterValueContext_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // StartContext provides a mock function with given fields: ctx, request
 func (_m *MockWebService) StartContext(ctx context.Context, request *sapcontrolapi.Start) (*sapcontrolapi.StartResponse, error) {
 	ret := _m.Called(ctx, request)
@@ -30,6 +30,13 @@ type WebService interface {
 	StopContext(ctx context.Context, request *Stop) (*StopResponse, error)
 	StartSystemContext(ctx context.Context, request *StartSystem) (*StartSystemResponse, error)
 	StopSystemContext(ctx context.Context, request *StopSystem) (*StopSystemResponse, error)
+	ABAPGetWPTableContext(ctx context.Context, request *ABAPGetWPTable) (*ABAPGetWPTableResponse, error)
+	EnqGetStatisticContext(ctx context.Context, request *EnqGetStatistic) (*EnqGetStatisticResponse, error)
+	GetQueueStatisticContext(ctx context.Context, request *GetQueueStatistic) (*GetQueueStatisticResponse, error)
+	GetAlertTreeContext(ctx context.Context, request *GetAlertTree) (*GetAlertTreeResponse, error)
+	GetAlertsContext(ctx context.Context, request *GetAlerts) (*GetAlertsResponse, error)
+	ParameterValueContext(ctx context.Context, request *ParameterValue) (*ParameterValueResponse, error)
+	GetEnvironmentContext(ctx context.Context, request *GetEnvironment) (*GetEnvironmentResponse, error)
 }
 
 type STATECOLOR string   //nolint:revive
@@ -217,6 +224,152 @@ type StopSystem struct {
 type StopSystemResponse struct {
 }
 
+type ABAPGetWPTable struct {
+	XMLName xml.Name `xml:"urn:SAPControl ABAPGetWPTable"`
+}
+
+type ABAPGetWPTableResponse struct {
+	XMLName       xml.Name       `xml:"urn:SAPControl ABAPGetWPTableResponse"`
+	Workprocesses []*WorkProcess `json:"workprocess>item,omitempty"            xml:"workprocess>item,omitempty"`
+}
+
+type WorkProcess struct {
+	No      int32  `json:"No"                xml:"No,omitempty"`
+	Typ     string `json:"Typ,omitempty"     xml:"Typ,omitempty"`
+	Pid     int32  `json:"Pid,omitempty"     xml:"Pid,omitempty"`
+	Status  string `json:"Status,omitempty"  xml:"Status,omitempty"`
+	Reason  string `json:"Reason,omitempty"  xml:"Reason,omitempty"`
+	Start   string `json:"Start,omitempty"   xml:"Start,omitempty"`
+	Err     string `json:"Err,omitempty"     xml:"Err,omitempty"`
+	Sem     string `json:"Sem,omitempty"     xml:"Sem,omitempty"`
+	Cpu     string `json:"Cpu,omitempty"     xml:"Cpu,omitempty"` //nolint:revive
+	Time    string `json:"Time,omitempty"    xml:"Time,omitempty"`
+	Program string `json:"Program,omitempty" xml:"Program,omitempty"`
+	Client  string `json:"Client,omitempty"  xml:"Client,omitempty"`
+	User    string `json:"User,omitempty"    xml:"User,omitempty"`
+	Action  string `json:"Action,omitempty"  xml:"Action,omitempty"`
+	Table   string `json:"Table,omitempty"   xml:"Table,omitempty"`
+}
+
+type EnqGetStatistic struct {
+	XMLName xml.Name `xml:"urn:SAPControl EnqGetStatistic"`
+}
+
+type EnqGetStatisticResponse struct {
+	XMLName            xml.Name   `json:"-"                              xml:"urn:SAPControl EnqGetStatisticResponse"`
+	OwnerNow           int32      `json:"owner-now"                      xml:"owner-now,omitempty"`
+	OwnerHigh          int32      `json:"owner-high"                     xml:"owner-high,omitempty"`
+	OwnerMax           int32      `json:"owner-max"                      xml:"owner-max,omitempty"`
+	OwnerState         STATECOLOR `json:"owner-state,omitempty"          xml:"owner-state,omitempty"`
+	ArgumentsNow       int32      `json:"arguments-now"                  xml:"arguments-now,omitempty"`
+	ArgumentsHigh      int32      `json:"arguments-high"                 xml:"arguments-high,omitempty"`
+	ArgumentsMax       int32      `json:"arguments-max"                  xml:"arguments-max,omitempty"`
+	ArgumentsState     STATECOLOR `json:"arguments-state,omitempty"      xml:"arguments-state,omitempty"`
+	LocksNow           int32      `json:"locks-now"                      xml:"locks-now,omitempty"`
+	LocksHigh          int32      `json:"locks-high"                     xml:"locks-high,omitempty"`
+	LocksMax           int32      `json:"locks-max"                      xml:"locks-max,omitempty"`
+	LocksState         STATECOLOR `json:"locks-state,omitempty"          xml:"locks-state,omitempty"`
+	EnqueueRequests    int64      `json:"enqueue-requests"               xml:"enqueue-requests,omitempty"`
+	EnqueueRejects     int64      `json:"enqueue-rejects"                xml:"enqueue-rejects,omitempty"`
+	EnqueueErrors      int64      `json:"enqueue-errors"                 xml:"enqueue-errors,omitempty"`
+	DequeueRequests    int64      `json:"dequeue-requests"               xml:"dequeue-requests,omitempty"`
+	DequeueErrors      int64      `json:"dequeue-errors"                 xml:"dequeue-errors,omitempty"`
+	DequeueAllRequests int64      `json:"dequeue-all-requests"           xml:"dequeue-all-requests,omitempty"`
+	CleanupRequests    int64      `json:"cleanup-requests"               xml:"cleanup-requests,omitempty"`
+	BackupRequests     int64      `json:"backup-requests"                xml:"backup-requests,omitempty"`
+	ReportingRequests  int64      `json:"reporting-requests"             xml:"reporting-requests,omitempty"`
+	CompressRequests   int64      `json:"compress-requests"              xml:"compress-requests,omitempty"`
+	VerifyRequests     int64      `json:"verify-requests"                xml:"verify-requests,omitempty"`
+	LockTime           float64    `json:"lock-time"                      xml:"lock-time,omitempty"`
+	LockWaitTime       float64    `json:"lock-wait-time"                 xml:"lock-wait-time,omitempty"`
+	ServerTime         float64    `json:"server-time"                    xml:"server-time,omitempty"`
+	ReplicationState   STATECOLOR `json:"replication-state,omitempty"    xml:"replication-state,omitempty"`
+}
+
+// EnqStatistic is the name used in the generated WSDL code for the same type.
+type EnqStatistic = EnqGetStatisticResponse
+
+type GetQueueStatistic struct {
+	XMLName xml.Name `xml:"urn:SAPControl GetQueueStatistic"`
+}
+
+type GetQueueStatisticResponse struct {
+	XMLName xml.Name            `xml:"urn:SAPControl GetQueueStatisticResponse"`
+	Queues  []*TaskHandlerQueue `json:"queue>item,omitempty"                     xml:"queue>item,omitempty"`
+}
+
+type TaskHandlerQueue struct {
+	Typ    string `json:"Typ,omitempty" xml:"Typ,omitempty"`
+	Now    int32  `json:"Now"           xml:"Now,omitempty"`
+	High   int32  `json:"High"          xml:"High,omitempty"`
+	Max    int32  `json:"Max"           xml:"Max,omitempty"`
+	Writes int32  `json:"Writes"        xml:"Writes,omitempty"`
+	Reads  int32  `json:"Reads"         xml:"Reads,omitempty"`
+}
+
+type GetAlertTree struct {
+	XMLName xml.Name `xml:"urn:SAPControl GetAlertTree"`
+}
+
+type GetAlertTreeResponse struct {
+	XMLName xml.Name     `xml:"urn:SAPControl GetAlertTreeResponse"`
+	Tree    []*AlertNode `json:"tree>item,omitempty"                 xml:"tree>item,omitempty"`
+}
+
+type AlertNode struct {
+	Name           string     `json:"name,omitempty"           xml:"name,omitempty"`
+	Parent         int32      `json:"parent"                   xml:"parent,omitempty"`
+	ActualValue    STATECOLOR `json:"ActualValue,omitempty"    xml:"ActualValue,omitempty"`
+	Description    string     `json:"description,omitempty"    xml:"description,omitempty"`
+	Time           string     `json:"Time,omitempty"           xml:"Time,omitempty"`
+	AnalyseTool    string     `json:"AnalyseTool,omitempty"    xml:"AnalyseTool,omitempty"`
+	VisibleLevel   string     `json:"VisibleLevel,omitempty"   xml:"VisibleLevel,omitempty"`
+	HighAlertValue STATECOLOR `json:"HighAlertValue,omitempty" xml:"HighAlertValue,omitempty"`
+	AlDescription  string     `json:"AlDescription,omitempty"  xml:"AlDescription,omitempty"`
+	AlTime         string     `json:"AlTime,omitempty"         xml:"AlTime,omitempty"`
+	Tid            string     `json:"Tid,omitempty"            xml:"Tid,omitempty"`
+}
+
+type GetAlerts struct {
+	XMLName xml.Name `xml:"urn:SAPControl GetAlerts"`
+	RootTid string   `json:"RootTid,omitempty"      xml:"RootTid,omitempty"`
+}
+
+type GetAlertsResponse struct {
+	XMLName     xml.Name `xml:"urn:SAPControl GetAlertsResponse"`
+	RootTidName string   `json:"RootTidName,omitempty"           xml:"RootTidName,omitempty"`
+	Alerts      []*Alert `json:"alert>item,omitempty"            xml:"alert>item,omitempty"`
+}
+
+type Alert struct {
+	Object      string     `json:"Object,omitempty"      xml:"Object,omitempty"`
+	Attribute   string     `json:"Attribute,omitempty"   xml:"Attribute,omitempty"`
+	Value       STATECOLOR `json:"Value,omitempty"       xml:"Value,omitempty"`
+	Description string     `json:"Description,omitempty" xml:"Description,omitempty"`
+	Time        string     `json:"Time,omitempty"        xml:"Time,omitempty"`
+	Tid         string     `json:"Tid,omitempty"         xml:"Tid,omitempty"`
+	Aid         string     `json:"Aid,omitempty"         xml:"Aid,omitempty"`
+}
+
+type ParameterValue struct {
+	XMLName   xml.Name `xml:"urn:SAPControl ParameterValue"`
+	Parameter string   `json:"parameter,omitempty"         xml:"parameter,omitempty"`
+}
+
+type ParameterValueResponse struct {
+	XMLName xml.Name `xml:"urn:SAPControl ParameterValueResponse"`
+	Value   string   `json:"value,omitempty"                     xml:"value,omitempty"`
+}
+
+type GetEnvironment struct {
+	XMLName xml.Name `xml:"urn:SAPControl GetEnvironment"`
+}
+
+type GetEnvironmentResponse struct {
+	XMLName xml.Name `xml:"urn:SAPControl GetEnvironmentResponse"`
+	Env     []string `json:"env>item,omitempty"                  xml:"env>item,omitempty"`
+}
+
 type webService struct {
 	client *soap.Client
 }
@@ -406,6 +559,111 @@ func (service *webService) StopSystemContext(
 	return response, nil
 }
 
+// ABAPGetWPTableContext returns the work process table of an ABAP instance.
+func (service *webService) ABAPGetWPTableContext(
+	ctx context.Context,
+	request *ABAPGetWPTable,
+) (*ABAPGetWPTableResponse, error) {
+	response := new(ABAPGetWPTableResponse)
+
+	err := service.client.CallContext(ctx, "''", request, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// EnqGetStatisticContext returns the enqueue server statistics, including the lock table usage.
+func (service *webService) EnqGetStatisticContext(
+	ctx context.Context,
+	request *EnqGetStatistic,
+) (*EnqGetStatisticResponse, error) {
+	response := new(EnqGetStatisticResponse)
+
+	err := service.client.CallContext(ctx, "''", request, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// GetQueueStatisticContext returns the ABAP dispatcher task handler queues statistics.
+func (service *webService) GetQueueStatisticContext(
+	ctx context.Context,
+	request *GetQueueStatistic,
+) (*GetQueueStatisticResponse, error) {
+	response := new(GetQueueStatisticResponse)
+
+	err := service.client.CallContext(ctx, "''", request, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// GetAlertTreeContext returns the CCMS alert monitoring tree of the instance.
+func (service *webService) GetAlertTreeContext(
+	ctx context.Context,
+	request *GetAlertTree,
+) (*GetAlertTreeResponse, error) {
+	response := new(GetAlertTreeResponse)
+
+	err := service.client.CallContext(ctx, "''", request, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// GetAlertsContext returns the CCMS alerts of the given monitoring tree node, or all the alerts if no node is given.
+func (service *webService) GetAlertsContext(
+	ctx context.Context,
+	request *GetAlerts,
+) (*GetAlertsResponse, error) {
+	response := new(GetAlertsResponse)
+
+	err := service.client.CallContext(ctx, "''", request, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// ParameterValueContext returns the value of a profile parameter of the instance.
+func (service *webService) ParameterValueContext(
+	ctx context.Context,
+	request *ParameterValue,
+) (*ParameterValueResponse, error) {
+	response := new(ParameterValueResponse)
+
+	err := service.client.CallContext(ctx, "''", request, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// GetEnvironmentContext returns the environment variables of the sapstartsrv process.
+func (service *webService) GetEnvironmentContext(
+	ctx context.Context,
+	request *GetEnvironment,
+) (*GetEnvironmentResponse, error) {
+	response := new(GetEnvironmentResponse)
+
+	err := service.client.CallContext(ctx, "''", request, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
 func DispstatusCodeFromStr(state STATECOLOR) STATECOLOR_CODE {
 	return map[STATECOLOR]STATECOLOR_CODE{
 		STATECOLOR_GRAY:   STATECOLOR_CODE_GRAY,
@@ -21,6 +21,53 @@ func (_m *MockSaptune) EXPECT() *MockSaptune_Expecter {
 	return &MockSaptune_Expecter{mock: &_m.Mock}
 }
 
+// ApplyNote provides a mock function with given fields: ctx, note
+func (_m *MockSaptune) ApplyNote(ctx context.Context, note string) error {
+	ret := _m.Called(ctx, note)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApplyNote")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, note)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSaptune_ApplyNote_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ApplyNote'
+type MockSaptune_ApplyNote_Call struct {
+	*mock.Call
+}
+
+// ApplyNote is a helper method to define mock.On call
+//   - ctx context.Context
+//   - note string
+func (_e *MockSaptune_Expecter) ApplyNote(ctx interface{}, note interface{}) *MockSaptune_ApplyNote_Call {
+	return &MockSaptune_ApplyNote_Call{Call: _e.mock.On("ApplyNote", ctx, note)}
+}
+
+func (_c *MockSaptune_ApplyNote_Call) Run(run func(ctx context.Context, note string)) *MockSaptune_ApplyNote_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSaptune_ApplyNote_Call) Return(_a0 error) *MockSaptune_ApplyNote_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSaptune_ApplyNote_Call) RunAndReturn(run func(context.Context, string) error) *MockSaptune_ApplyNote_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ApplySolution provides a mock function with given fields: ctx, solution
 func (_m *MockSaptune) ApplySolution(ctx context.Context, solution string) error {
 	ret := _m.Called(ctx, solution)
@@ -506,6 +553,53 @@ func (_c *MockSaptune_ListSolution_Call) RunAndReturn(run func(context.Context)
 	return _c
 }
 
+// RevertNote provides a mock function with given fields: ctx, note
+func (_m *MockSaptune) RevertNote(ctx context.Context, note string) error {
+	ret := _m.Called(ctx, note)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevertNote")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, note)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSaptune_RevertNote_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevertNote'
+type MockSaptune_RevertNote_Call struct {
+	*mock.Call
+}
+
+// RevertNote is a helper method to define mock.On call
+//   - ctx context.Context
+//   - note string
+func (_e *MockSaptune_Expecter) RevertNote(ctx interface{}, note interface{}) *MockSaptune_RevertNote_Call {
+	return &MockSaptune_RevertNote_Call{Call: _e.mock.On("RevertNote", ctx, note)}
+}
+
+func (_c *MockSaptune_RevertNote_Call) Run(run func(ctx context.Context, note string)) *MockSaptune_RevertNote_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSaptune_RevertNote_Call) Return(_a0 error) *MockSaptune_RevertNote_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSaptune_RevertNote_Call) RunAndReturn(run func(context.Context, string) error) *MockSaptune_RevertNote_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // RevertSolution provides a mock function with given fields: ctx, solution
 func (_m *MockSaptune) RevertSolution(ctx context.Context, solution string) error {
 	ret := _m.Called(ctx, solution)
@@ -5,8 +5,10 @@ package saptune
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"os/exec"
 	"strings"
 
 	"github.com/tidwall/gjson"
@@ -15,7 +17,11 @@ import (
 	"github.com/trento-project/agent/v3/pkg/utils"
 )
 
-const minimalSaptuneVersion = "v3.1.0"
+const (
+	minimalSaptuneVersion = "v3.1.0"
+	// saptune verify commands exit with this code when the system is not compliant
+	nonCompliantExitCode = 1
+)
 
 type Saptune interface {
 	CheckVersionSupport(ctx context.Context) error
@@ -28,6 +34,8 @@ type Saptune interface {
 	RevertSolution(ctx context.Context, solution string) error
 	ListSolution(ctx context.Context) ([]byte, error)
 	VerifySolution(ctx context.Context) ([]byte, error)
+	ApplyNote(ctx context.Context, note string) error
+	RevertNote(ctx context.Context, note string) error
 	ListNote(ctx context.Context) ([]byte, error)
 	VerifyNote(ctx context.Context) ([]byte, error)
 }
@@ -134,12 +142,37 @@ func (s *saptuneClient) VerifySolution(ctx context.Context) ([]byte, error) {
 	return s.runSaptuneJSON(ctx, "solution", "verify")
 }
 
+func (s *saptuneClient) ApplyNote(ctx context.Context, note string) error {
+	_, err := s.runSaptune(ctx, "note", "apply", note)
+
+	return err
+}
+
+func (s *saptuneClient) RevertNote(ctx context.Context, note string) error {
+	_, err := s.runSaptune(ctx, "note", "revert", note)
+
+	return err
+}
+
 func (s *saptuneClient) ListNote(ctx context.Context) ([]byte, error) {
 	return s.runSaptuneJSON(ctx, "note", "list")
 }
 
+// VerifyNote returns the verification of the enabled notes.
+// The output is returned without error when the system is not compliant with the notes.
 func (s *saptuneClient) VerifyNote(ctx context.Context) ([]byte, error) {
-	return s.runSaptuneJSON(ctx, "note", "verify")
+	output, err := s.runSaptuneJSON(ctx, "note", "verify")
+	if err != nil && isNonCompliantExit(err) && gjson.ValidBytes(output) {
+		return output, nil
+	}
+
+	return output, err
+}
+
+func isNonCompliantExit(err error) bool {
+	var exitError *exec.ExitError
+
+	return errors.As(err, &exitError) && exitError.ExitCode() == nonCompliantExitCode
 }
 
 //nolint:unparam
@@ -6,6 +6,7 @@ package saptune_test
 import (
 	"context"
 	"errors"
+	"os/exec"
 	"testing"
 
 	"log/slog"
@@ -535,6 +536,78 @@ func (suite *SaptuneClientTestSuite) TestVerifySolutionError() {
 	suite.Require().ErrorContains(err, "error executing saptune command: verify solution error")
 }
 
+func (suite *SaptuneClientTestSuite) TestApplyNote() {
+	ctx := context.Background()
+
+	suite.mockExecutor.On(
+		"CombinedOutputContext",
+		ctx,
+		"saptune",
+		"note",
+		"apply",
+		"2382421",
+	).Return([]byte(""), nil)
+
+	saptuneClient := saptune.NewSaptuneClient(suite.mockExecutor, suite.logger)
+	err := saptuneClient.ApplyNote(ctx, "2382421")
+
+	suite.Require().NoError(err)
+}
+
+func (suite *SaptuneClientTestSuite) TestApplyNoteError() {
+	ctx := context.Background()
+
+	suite.mockExecutor.On(
+		"CombinedOutputContext",
+		ctx,
+		"saptune",
+		"note",
+		"apply",
+		"2382421",
+	).Return(nil, errors.New("apply note error"))
+
+	saptuneClient := saptune.NewSaptuneClient(suite.mockExecutor, suite.logger)
+	err := saptuneClient.ApplyNote(ctx, "2382421")
+
+	suite.Require().ErrorContains(err, "error executing saptune command: apply note error")
+}
+
+func (suite *SaptuneClientTestSuite) TestRevertNote() {
+	ctx := context.Background()
+
+	suite.mockExecutor.On(
+		"CombinedOutputContext",
+		ctx,
+		"saptune",
+		"note",
+		"revert",
+		"2382421",
+	).Return([]byte(""), nil)
+
+	saptuneClient := saptune.NewSaptuneClient(suite.mockExecutor, suite.logger)
+	err := saptuneClient.RevertNote(ctx, "2382421")
+
+	suite.Require().NoError(err)
+}
+
+func (suite *SaptuneClientTestSuite) TestRevertNoteError() {
+	ctx := context.Background()
+
+	suite.mockExecutor.On(
+		"CombinedOutputContext",
+		ctx,
+		"saptune",
+		"note",
+		"revert",
+		"2382421",
+	).Return(nil, errors.New("revert note error"))
+
+	saptuneClient := saptune.NewSaptuneClient(suite.mockExecutor, suite.logger)
+	err := saptuneClient.RevertNote(ctx, "2382421")
+
+	suite.Require().ErrorContains(err, "error executing saptune command: revert note error")
+}
+
 func (suite *SaptuneClientTestSuite) TestListNote() {
 	ctx := context.Background()
 
@@ -614,3 +687,43 @@ func (suite *SaptuneClientTestSuite) TestVerifyNoteError() {
 	suite.Require().Error(err)
 	suite.Require().ErrorContains(err, "error executing saptune command: verify note error")
 }
+
+func (suite *SaptuneClientTestSuite) TestVerifyNoteNotCompliant() {
+	ctx := context.Background()
+	verifyOutput := helpers.ReadFixture("gatherers/saptune-note-verify.output")
+
+	suite.mockExecutor.On(
+		"OutputContext",
+		ctx,
+		"saptune",
+		"--format",
+		"json",
+		"note",
+		"verify",
+	).Return(verifyOutput, exec.Command("sh", "-c", "exit 1").Run())
+
+	saptuneClient := saptune.NewSaptuneClient(suite.mockExecutor, suite.logger)
+	verifyNoteOutput, err := saptuneClient.VerifyNote(ctx)
+
+	suite.Require().NoError(err)
+	suite.Equal(verifyOutput, verifyNoteOutput)
+}
+
+func (suite *SaptuneClientTestSuite) TestVerifyNoteErrorExitCode() {
+	ctx := context.Background()
+
+	suite.mockExecutor.On(
+		"OutputContext",
+		ctx,
+		"saptune",
+		"--format",
+		"json",
+		"note",
+		"verify",
+	).Return([]byte(""), exec.Command("sh", "-c", "exit 1").Run())
+
+	saptuneClient := saptune.NewSaptuneClient(suite.mockExecutor, suite.logger)
+	_, err := saptuneClient.VerifyNote(ctx)
+
+	suite.Require().ErrorContains(err, "error executing saptune command: exit status 1")
+}
@@ -311,6 +311,147 @@ func (_c *MockSystemd_IsEnabled_Call) RunAndReturn(run func(context.Context, str
 	return _c
 }
 
+// Restart provides a mock function with given fields: ctx, service
+func (_m *MockSystemd) Restart(ctx context.Context, service string) error {
+	ret := _m.Called(ctx, service)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restart")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, service)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSystemd_Restart_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Restart'
+type MockSystemd_Restart_Call struct {
+	*mock.Call
+}
+
+// Restart is a helper method to define mock.On call
+//   - ctx context.Context
+//   - service string
+func (_e *MockSystemd_Expecter) Restart(ctx interface{}, service interface{}) *MockSystemd_Restart_Call {
+	return &MockSystemd_Restart_Call{Call: _e.mock.On("Restart", ctx, service)}
+}
+
+func (_c *MockSystemd_Restart_Call) Run(run func(ctx context.Context, service string)) *MockSystemd_Restart_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSystemd_Restart_Call) Return(_a0 error) *MockSystemd_Restart_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSystemd_Restart_Call) RunAndReturn(run func(context.Context, string) error) *MockSystemd_Restart_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Start provides a mock function with given fields: ctx, service
+func (_m *MockSystemd) Start(ctx context.Context, service string) error {
+	ret := _m.Called(ctx, service)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Start")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, service)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSystemd_Start_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Start'
+type MockSystemd_Start_Call struct {
+	*mock.Call
+}
+
+// Start is a helper method to define mock.On call
+//   - ctx context.Context
+//   - service string
+func (_e *MockSystemd_Expecter) Start(ctx interface{}, service interface{}) *MockSystemd_Start_Call {
+	return &MockSystemd_Start_Call{Call: _e.mock.On("Start", ctx, service)}
+}
+
+func (_c *MockSystemd_Start_Call) Run(run func(ctx context.Context, service string)) *MockSystemd_Start_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSystemd_Start_Call) Return(_a0 error) *MockSystemd_Start_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSystemd_Start_Call) RunAndReturn(run func(context.Context, string) error) *MockSystemd_Start_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Stop provides a mock function with given fields: ctx, service
+func (_m *MockSystemd) Stop(ctx context.Context, service string) error {
+	ret := _m.Called(ctx, service)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stop")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, service)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSystemd_Stop_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stop'
+type MockSystemd_Stop_Call struct {
+	*mock.Call
+}
+
+// Stop is a helper method to define mock.On call
+//   - ctx context.Context
+//   - service string
+func (_e *MockSystemd_Expecter) Stop(ctx interface{}, service interface{}) *MockSystemd_Stop_Call {
+	return &MockSystemd_Stop_Call{Call: _e.mock.On("Stop", ctx, service)}
+}
+
+func (_c *MockSystemd_Stop_Call) Run(run func(ctx context.Context, service string)) *MockSystemd_Stop_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockSystemd_Stop_Call) Return(_a0 error) *MockSystemd_Stop_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSystemd_Stop_Call) RunAndReturn(run func(context.Context, string) error) *MockSystemd_Stop_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockSystemd creates a new instance of MockSystemd. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockSystemd(t interface {
@@ -11,6 +11,13 @@ import (
 	"github.com/trento-project/agent/v3/internal/core/dbus"
 )
 
+const (
+	unitJobMode       = "replace"
+	unitJobResultDone = "done"
+)
+
+type unitJobFunc func(ctx context.Context, name string, mode string, ch chan<- string) (int, error)
+
 type UnitInfo struct {
 	Name          string `json:"name"`
 	UnitFileState string `json:"unit_file_state"`
@@ -19,6 +26,9 @@ type UnitInfo struct {
 type Systemd interface {
 	Enable(ctx context.Context, service string) error
 	Disable(ctx context.Context, service string) error
+	Start(ctx context.Context, service string) error
+	Stop(ctx context.Context, service string) error
+	Restart(ctx context.Context, service string) error
 	IsActive(ctx context.Context, service string) (bool, error)
 	IsEnabled(ctx context.Context, service string) (bool, error)
 	GetUnitsInfo(ctx context.Context, units []string) []UnitInfo
@@ -108,6 +118,21 @@ func (s *Connector) Disable(ctx context.Context, service string) error {
 	return s.reload(ctx, service)
 }
 
+// Start starts the given service and waits until the start job is completed.
+func (s *Connector) Start(ctx context.Context, service string) error {
+	return s.runUnitJob(ctx, "start", service, s.dbusConnection.StartUnitContext)
+}
+
+// Stop stops the given service and waits until the stop job is completed.
+func (s *Connector) Stop(ctx context.Context, service string) error {
+	return s.runUnitJob(ctx, "stop", service, s.dbusConnection.StopUnitContext)
+}
+
+// Restart restarts the given service and waits until the restart job is completed.
+func (s *Connector) Restart(ctx context.Context, service string) error {
+	return s.runUnitJob(ctx, "restart", service, s.dbusConnection.RestartUnitContext)
+}
+
 // IsActive returns if the given service is currently active and running.
 func (s *Connector) IsActive(ctx context.Context, service string) (bool, error) {
 	activeState, err := s.getUnitProperty(ctx, service, "ActiveState")
@@ -189,6 +214,30 @@ func (s *Connector) reload(ctx context.Context, service string) error {
 	return nil
 }
 
+func (s *Connector) runUnitJob(ctx context.Context, action string, service string, job unitJobFunc) error {
+	resultChannel := make(chan string, 1)
+
+	_, err := job(ctx, service, unitJobMode, resultChannel)
+	if err != nil {
+		s.logger.Error("failed to queue service job", "action", action, "service", service, "error", err)
+
+		return fmt.Errorf("failed to %s service %s: %w", action, service, err)
+	}
+
+	select {
+	case result := <-resultChannel:
+		if result != unitJobResultDone {
+			s.logger.Error("service job not completed", "action", action, "service", service, "result", result)
+
+			return fmt.Errorf("failed to %s service %s: job finished with result %s", action, service, result)
+		}
+
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to %s service %s: %w", action, service, ctx.Err())
+	}
+}
+
 func (s *Connector) getUnitProperty(ctx context.Context, unit string, propertyName string) (string, error) {
 	property, err := s.dbusConnection.GetUnitPropertyContext(ctx, unit, propertyName)
 	if err != nil {
@@ -11,6 +11,7 @@ import (
 
 	"github.com/coreos/go-systemd/v22/dbus"
 	innerDbus "github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"github.com/trento-project/agent/v3/internal/core/dbus/mocks"
 	"github.com/trento-project/agent/v3/internal/core/systemd"
@@ -384,6 +385,180 @@ func (suite *SystemdTestSuite) TestSuccessfulDisableService() {
 	suite.Require().NoError(err)
 }
 
+func (suite *SystemdTestSuite) TestStartServiceFailure() {
+	ctx := context.Background()
+
+	suite.dbusMock.On(
+		"StartUnitContext",
+		ctx,
+		"foo.service",
+		"replace",
+		mock.Anything,
+	).Return(
+		0,
+		errors.New("unit foo.service not found"),
+	).Once()
+
+	systemdConnector, _ := systemd.NewSystemd(
+		ctx,
+		systemd.WithCustomDbusConnector(suite.dbusMock),
+		systemd.WithCustomLogger(suite.logger),
+	)
+
+	err := systemdConnector.Start(ctx, "foo.service")
+
+	suite.Require().EqualError(err, "failed to start service foo.service: unit foo.service not found")
+}
+
+func (suite *SystemdTestSuite) TestStartServiceJobFailed() {
+	ctx := context.Background()
+
+	suite.dbusMock.On(
+		"StartUnitContext",
+		ctx,
+		"foo.service",
+		"replace",
+		mock.Anything,
+	).Run(func(args mock.Arguments) {
+		args.Get(3).(chan<- string) <- "failed"
+	}).Return(1, nil).
+		Once()
+
+	systemdConnector, _ := systemd.NewSystemd(
+		ctx,
+		systemd.WithCustomDbusConnector(suite.dbusMock),
+		systemd.WithCustomLogger(suite.logger),
+	)
+
+	err := systemdConnector.Start(ctx, "foo.service")
+
+	suite.Require().EqualError(err, "failed to start service foo.service: job finished with result failed")
+}
+
+func (suite *SystemdTestSuite) TestStartServiceContextCancelled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	suite.dbusMock.On(
+		"StartUnitContext",
+		ctx,
+		"foo.service",
+		"replace",
+		mock.Anything,
+	).Return(1, nil).
+		Once()
+
+	systemdConnector, _ := systemd.NewSystemd(
+		ctx,
+		systemd.WithCustomDbusConnector(suite.dbusMock),
+		systemd.WithCustomLogger(suite.logger),
+	)
+
+	err := systemdConnector.Start(ctx, "foo.service")
+
+	suite.Require().EqualError(err, "failed to start service foo.service: context canceled")
+}
+
+func (suite *SystemdTestSuite) TestSuccessfulStartService() {
+	ctx := context.Background()
+
+	suite.dbusMock.On(
+		"StartUnitContext",
+		ctx,
+		"foo.service",
+		"replace",
+		mock.Anything,
+	).Run(func(args mock.Arguments) {
+		args.Get(3).(chan<- string) <- "done"
+	}).Return(1, nil).
+		Once()
+
+	systemdConnector, _ := systemd.NewSystemd(
+		ctx,
+		systemd.WithCustomDbusConnector(suite.dbusMock),
+		systemd.WithCustomLogger(suite.logger),
+	)
+
+	err := systemdConnector.Start(ctx, "foo.service")
+
+	suite.Require().NoError(err)
+}
+
+func (suite *SystemdTestSuite) TestSuccessfulStopService() {
+	ctx := context.Background()
+
+	suite.dbusMock.On(
+		"StopUnitContext",
+		ctx,
+		"foo.service",
+		"replace",
+		mock.Anything,
+	).Run(func(args mock.Arguments) {
+		args.Get(3).(chan<- string) <- "done"
+	}).Return(1, nil).
+		Once()
+
+	systemdConnector, _ := systemd.NewSystemd(
+		ctx,
+		systemd.WithCustomDbusConnector(suite.dbusMock),
+		systemd.WithCustomLogger(suite.logger),
+	)
+
+	err := systemdConnector.Stop(ctx, "foo.service")
+
+	suite.Require().NoError(err)
+}
+
+func (suite *SystemdTestSuite) TestRestartServiceJobFailed() {
+	ctx := context.Background()
+
+	suite.dbusMock.On(
+		"RestartUnitContext",
+		ctx,
+		"foo.service",
+		"replace",
+		mock.Anything,
+	).Run(func(args mock.Arguments) {
+		args.Get(3).(chan<- string) <- "timeout"
+	}).Return(1, nil).
+		Once()
+
+	systemdConnector, _ := systemd.NewSystemd(
+		ctx,
+		systemd.WithCustomDbusConnector(suite.dbusMock),
+		systemd.WithCustomLogger(suite.logger),
+	)
+
+	err := systemdConnector.Restart(ctx, "foo.service")
+
+	suite.Require().EqualError(err, "failed to restart service foo.service: job finished with result timeout")
+}
+
+func (suite *SystemdTestSuite) TestSuccessfulRestartService() {
+	ctx := context.Background()
+
+	suite.dbusMock.On(
+		"RestartUnitContext",
+		ctx,
+		"foo.service",
+		"replace",
+		mock.Anything,
+	).Run(func(args mock.Arguments) {
+		args.Get(3).(chan<- string) <- "done"
+	}).Return(1, nil).
+		Once()
+
+	systemdConnector, _ := systemd.NewSystemd(
+		ctx,
+		systemd.WithCustomDbusConnector(suite.dbusMock),
+		systemd.WithCustomLogger(suite.logger),
+	)
+
+	err := systemdConnector.Restart(ctx, "foo.service")
+
+	suite.Require().NoError(err)
+}
+
 func (suite *SystemdTestSuite) TestUnableToGetProperties() {
 	ctx := context.Background()
 
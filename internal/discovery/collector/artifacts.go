@@ -0,0 +1,278 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+const (
+	defaultArtifactChunkSize  = 8 * 1024 * 1024
+	artifactChecksumAlgorithm = "sha256"
+)
+
+// Artifact describes a file uploaded to the server, like the support archives.
+// The checksum is the hex encoded sha256 of the whole content, validated by the server
+// once all the chunks are uploaded.
+type Artifact struct {
+	Name     string
+	Size     int64
+	Checksum string
+}
+
+type artifactCreateRequest struct {
+	Name              string `json:"name"`
+	Size              int64  `json:"size"`
+	Checksum          string `json:"checksum"`
+	ChecksumAlgorithm string `json:"checksum_algorithm"`
+	ChunkSize         int    `json:"chunk_size"`
+}
+
+type artifactCreateResponse struct {
+	ID string `json:"id"`
+}
+
+// ArtifactStatusCompleted is the status of an artifact fully uploaded and validated by the server.
+const ArtifactStatusCompleted = "completed"
+
+// ArtifactStatus is the artifact state acknowledged by the server.
+type ArtifactStatus struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	Size     int64  `json:"size"`
+	Checksum string `json:"checksum"`
+}
+
+// UploadArtifact uploads the artifact content in chunks and returns the artifact ID given by the server.
+// The artifact is created first, then each chunk is uploaded in order with its byte range,
+// and finally the upload is completed so the server validates the checksum.
+// If the upload fails once the artifact is created, the artifact is aborted so the server
+// discards the chunks already uploaded.
+func (c *Collector) UploadArtifact(ctx context.Context, artifact Artifact, content io.Reader) (string, error) {
+	artifactID, err := c.createArtifact(ctx, artifact)
+	if err != nil {
+		return "", err
+	}
+
+	err = c.uploadArtifactContent(ctx, artifactID, artifact, content)
+	if err != nil {
+		c.abortArtifact(context.WithoutCancel(ctx), artifactID)
+
+		return "", err
+	}
+
+	return artifactID, nil
+}
+
+// GetArtifactStatus returns the artifact state known by the server.
+func (c *Collector) GetArtifactStatus(ctx context.Context, artifactID string) (ArtifactStatus, error) {
+	url := fmt.Sprintf("%s/api/v1/hosts/%s/artifacts/%s", c.config.ServerURL, c.config.AgentID, artifactID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return ArtifactStatus{}, err
+	}
+
+	c.enrichRequest(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return ArtifactStatus{}, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ArtifactStatus{}, fmt.Errorf("server responded with status code %d while getting artifact %s",
+			resp.StatusCode, artifactID)
+	}
+
+	var status ArtifactStatus
+
+	err = json.NewDecoder(resp.Body).Decode(&status)
+	if err != nil {
+		return ArtifactStatus{}, fmt.Errorf("error decoding artifact %s status: %w", artifactID, err)
+	}
+
+	return status, nil
+}
+
+func (c *Collector) uploadArtifactContent(
+	ctx context.Context,
+	artifactID string,
+	artifact Artifact,
+	content io.Reader,
+) error {
+	chunk := make([]byte, c.artifactChunkSize)
+
+	var offset int64
+
+	for index := 0; ; index++ {
+		read, err := io.ReadFull(content, chunk)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return fmt.Errorf("error reading artifact %s content: %w", artifact.Name, err)
+		}
+
+		err = c.uploadArtifactChunk(ctx, artifactID, index, offset, artifact.Size, chunk[:read])
+		if err != nil {
+			return err
+		}
+
+		offset += int64(read)
+	}
+
+	if offset != artifact.Size {
+		return fmt.Errorf("artifact %s size mismatch, expected %d bytes, read %d", artifact.Name, artifact.Size, offset)
+	}
+
+	return c.completeArtifact(ctx, artifactID)
+}
+
+func (c *Collector) createArtifact(ctx context.Context, artifact Artifact) (string, error) {
+	requestBody, err := json.Marshal(artifactCreateRequest{
+		Name:              artifact.Name,
+		Size:              artifact.Size,
+		Checksum:          artifact.Checksum,
+		ChecksumAlgorithm: artifactChecksumAlgorithm,
+		ChunkSize:         c.artifactChunkSize,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	url := fmt.Sprintf("%s/api/v1/hosts/%s/artifacts", c.config.ServerURL, c.config.AgentID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return "", err
+	}
+
+	c.enrichRequest(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("server responded with status code %d while creating artifact %s",
+			resp.StatusCode, artifact.Name)
+	}
+
+	var response artifactCreateResponse
+
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	if err != nil {
+		return "", fmt.Errorf("error decoding artifact %s creation response: %w", artifact.Name, err)
+	}
+
+	if response.ID == "" {
+		return "", fmt.Errorf("server did not return an id for artifact %s", artifact.Name)
+	}
+
+	return response.ID, nil
+}
+
+func (c *Collector) uploadArtifactChunk(
+	ctx context.Context,
+	artifactID string,
+	index int,
+	offset int64,
+	size int64,
+	chunk []byte,
+) error {
+	url := fmt.Sprintf("%s/api/v1/hosts/%s/artifacts/%s/chunks/%d",
+		c.config.ServerURL, c.config.AgentID, artifactID, index)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(chunk))
+	if err != nil {
+		return err
+	}
+
+	c.enrichRequest(req)
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, offset+int64(len(chunk))-1, size))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("server responded with status code %d while uploading chunk %d of artifact %s",
+			resp.StatusCode, index, artifactID)
+	}
+
+	return nil
+}
+
+func (c *Collector) completeArtifact(ctx context.Context, artifactID string) error {
+	url := fmt.Sprintf("%s/api/v1/hosts/%s/artifacts/%s/complete",
+		c.config.ServerURL, c.config.AgentID, artifactID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return err
+	}
+
+	c.enrichRequest(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("server responded with status code %d while completing artifact %s",
+			resp.StatusCode, artifactID)
+	}
+
+	return nil
+}
+
+// abortArtifact discards a partially uploaded artifact. Failures are only logged,
+// as the upload error is the one reported to the caller.
+func (c *Collector) abortArtifact(ctx context.Context, artifactID string) {
+	url := fmt.Sprintf("%s/api/v1/hosts/%s/artifacts/%s", c.config.ServerURL, c.config.AgentID, artifactID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	if err != nil {
+		slog.Error("Error aborting artifact upload", "artifactID", artifactID, "error", err)
+
+		return
+	}
+
+	c.enrichRequest(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		slog.Error("Error aborting artifact upload", "artifactID", artifactID, "error", err)
+
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		slog.Error("Server responded with an unexpected status code while aborting artifact upload",
+			"artifactID", artifactID, "statusCode", resp.StatusCode)
+	}
+}
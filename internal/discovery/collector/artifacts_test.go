@@ -0,0 +1,205 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package collector_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/trento-project/agent/v3/internal/discovery/collector"
+	"github.com/trento-project/agent/v3/test/helpers"
+)
+
+const artifactsURL = "https://localhost/api/v1/hosts/" + DummyAgentID + "/artifacts"
+
+type ArtifactsTestSuite struct {
+	suite.Suite
+
+	collectorClient *collector.Collector
+	httpClient      *http.Client
+}
+
+func TestArtifactsTestSuite(t *testing.T) {
+	suite.Run(t, new(ArtifactsTestSuite))
+}
+
+func (suite *ArtifactsTestSuite) SetupTest() {
+	suite.httpClient = &http.Client{}
+	suite.collectorClient = collector.NewCollectorClient(
+		&collector.Config{
+			AgentID:   DummyAgentID,
+			ServerURL: "https://localhost",
+			APIKey:    apiKey,
+		},
+		suite.httpClient,
+		collector.WithArtifactChunkSize(4),
+	)
+}
+
+func (suite *ArtifactsTestSuite) TestUploadArtifactSuccess() {
+	chunks := []string{}
+	ranges := []string{}
+
+	suite.httpClient.Transport = helpers.RoundTripFunc(func(req *http.Request) *http.Response {
+		suite.Equal(apiKey, req.Header.Get("X-Trento-Apikey"))
+
+		switch {
+		case req.Method == http.MethodPost && req.URL.String() == artifactsURL:
+			var body map[string]any
+			suite.Require().NoError(json.NewDecoder(req.Body).Decode(&body))
+			suite.Equal(map[string]any{
+				"name":               "crm_report.tar.bz2",
+				"size":               float64(10),
+				"checksum":           "some-checksum",
+				"checksum_algorithm": "sha256",
+				"chunk_size":         float64(4),
+			}, body)
+
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Body:       io.NopCloser(strings.NewReader(`{"id": "artifact-id"}`)),
+			}
+		case req.Method == http.MethodPut:
+			suite.Equal(fmt.Sprintf("%s/artifact-id/chunks/%d", artifactsURL, len(chunks)), req.URL.String())
+			suite.Equal("application/octet-stream", req.Header.Get("Content-Type"))
+
+			chunk, err := io.ReadAll(req.Body)
+			suite.Require().NoError(err)
+
+			chunks = append(chunks, string(chunk))
+			ranges = append(ranges, req.Header.Get("Content-Range"))
+
+			return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody}
+		default:
+			suite.Equal(artifactsURL+"/artifact-id/complete", req.URL.String())
+
+			return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody}
+		}
+	})
+
+	artifactID, err := suite.collectorClient.UploadArtifact(
+		context.Background(),
+		collector.Artifact{Name: "crm_report.tar.bz2", Size: 10, Checksum: "some-checksum"},
+		strings.NewReader("0123456789"),
+	)
+
+	suite.Require().NoError(err)
+	suite.Equal("artifact-id", artifactID)
+	suite.Equal([]string{"0123", "4567", "89"}, chunks)
+	suite.Equal([]string{"bytes 0-3/10", "bytes 4-7/10", "bytes 8-9/10"}, ranges)
+}
+
+func (suite *ArtifactsTestSuite) TestUploadArtifactCreateError() {
+	suite.httpClient.Transport = helpers.RoundTripFunc(func(_ *http.Request) *http.Response {
+		return &http.Response{StatusCode: http.StatusForbidden, Body: http.NoBody}
+	})
+
+	_, err := suite.collectorClient.UploadArtifact(
+		context.Background(),
+		collector.Artifact{Name: "crm_report.tar.bz2", Size: 10, Checksum: "some-checksum"},
+		strings.NewReader("0123456789"),
+	)
+
+	suite.EqualError(err, "server responded with status code 403 while creating artifact crm_report.tar.bz2")
+}
+
+func (suite *ArtifactsTestSuite) TestUploadArtifactChunkError() {
+	aborted := false
+
+	suite.httpClient.Transport = helpers.RoundTripFunc(func(req *http.Request) *http.Response {
+		switch req.Method {
+		case http.MethodPost:
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Body:       io.NopCloser(strings.NewReader(`{"id": "artifact-id"}`)),
+			}
+		case http.MethodDelete:
+			suite.Equal(artifactsURL+"/artifact-id", req.URL.String())
+			aborted = true
+
+			return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody}
+		default:
+			return &http.Response{StatusCode: http.StatusRequestEntityTooLarge, Body: http.NoBody}
+		}
+	})
+
+	_, err := suite.collectorClient.UploadArtifact(
+		context.Background(),
+		collector.Artifact{Name: "crm_report.tar.bz2", Size: 10, Checksum: "some-checksum"},
+		strings.NewReader("0123456789"),
+	)
+
+	suite.EqualError(err, "server responded with status code 413 while uploading chunk 0 of artifact artifact-id")
+	suite.True(aborted)
+}
+
+func (suite *ArtifactsTestSuite) TestUploadArtifactSizeMismatch() {
+	aborted := false
+
+	suite.httpClient.Transport = helpers.RoundTripFunc(func(req *http.Request) *http.Response {
+		switch req.Method {
+		case http.MethodPost:
+			suite.Equal(artifactsURL, req.URL.String(), "the upload must not be completed")
+
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Body:       io.NopCloser(strings.NewReader(`{"id": "artifact-id"}`)),
+			}
+		case http.MethodDelete:
+			aborted = true
+		}
+
+		return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody}
+	})
+
+	_, err := suite.collectorClient.UploadArtifact(
+		context.Background(),
+		collector.Artifact{Name: "crm_report.tar.bz2", Size: 12, Checksum: "some-checksum"},
+		strings.NewReader("0123456789"),
+	)
+
+	suite.EqualError(err, "artifact crm_report.tar.bz2 size mismatch, expected 12 bytes, read 10")
+	suite.True(aborted)
+}
+
+func (suite *ArtifactsTestSuite) TestGetArtifactStatus() {
+	suite.httpClient.Transport = helpers.RoundTripFunc(func(req *http.Request) *http.Response {
+		suite.Equal(http.MethodGet, req.Method)
+		suite.Equal(artifactsURL+"/artifact-id", req.URL.String())
+		suite.Equal(apiKey, req.Header.Get("X-Trento-Apikey"))
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(
+				`{"id": "artifact-id", "status": "completed", "size": 10, "checksum": "some-checksum"}`,
+			)),
+		}
+	})
+
+	status, err := suite.collectorClient.GetArtifactStatus(context.Background(), "artifact-id")
+
+	suite.Require().NoError(err)
+	suite.Equal(collector.ArtifactStatus{
+		ID:       "artifact-id",
+		Status:   collector.ArtifactStatusCompleted,
+		Size:     10,
+		Checksum: "some-checksum",
+	}, status)
+}
+
+func (suite *ArtifactsTestSuite) TestGetArtifactStatusError() {
+	suite.httpClient.Transport = helpers.RoundTripFunc(func(_ *http.Request) *http.Response {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: http.NoBody}
+	})
+
+	_, err := suite.collectorClient.GetArtifactStatus(context.Background(), "artifact-id")
+
+	suite.EqualError(err, "server responded with status code 404 while getting artifact artifact-id")
+}
@@ -19,11 +19,23 @@ const maxErrorResponseBodyBytes = 4 * 1024
 type Client interface {
 	Publish(ctx context.Context, discoveryType string, payload any) error
 	Heartbeat(ctx context.Context) error
+	UploadArtifact(ctx context.Context, artifact Artifact, content io.Reader) (string, error)
+	GetArtifactStatus(ctx context.Context, artifactID string) (ArtifactStatus, error)
 }
 
 type Collector struct {
-	config     *Config
-	httpClient *http.Client
+	config            *Config
+	httpClient        *http.Client
+	artifactChunkSize int
+}
+
+type Option func(*Collector)
+
+// WithArtifactChunkSize sets the size in bytes of the chunks used to upload the artifacts.
+func WithArtifactChunkSize(size int) Option {
+	return func(c *Collector) {
+		c.artifactChunkSize = size
+	}
 }
 
 type Config struct {
@@ -32,11 +44,18 @@ type Config struct {
 	APIKey    string
 }
 
-func NewCollectorClient(config *Config, httpClient *http.Client) *Collector {
-	return &Collector{
-		config:     config,
-		httpClient: httpClient,
+func NewCollectorClient(config *Config, httpClient *http.Client, options ...Option) *Collector {
+	collector := &Collector{
+		config:            config,
+		httpClient:        httpClient,
+		artifactChunkSize: defaultArtifactChunkSize,
 	}
+
+	for _, opt := range options {
+		opt(collector)
+	}
+
+	return collector
 }
 
 func (c *Collector) Publish(ctx context.Context, discoveryType string, payload any) error {
@@ -0,0 +1,342 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package gatherers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/trento-project/agent/v3/pkg/factsengine/entities"
+	"github.com/trento-project/agent/v3/pkg/utils"
+)
+
+const (
+	BlockDevicesGathererName = "block_devices"
+
+	blockDevicesLsblkArgument     = "lsblk"
+	blockDevicesMultipathArgument = "multipath"
+	blockDevicesLvsArgument       = "lvs"
+	blockDevicesVgsArgument       = "vgs"
+)
+
+//nolint:gochecknoglobals
+var (
+	BlockDevicesMissingArgument = entities.FactGatheringError{
+		Type:    "block_devices-missing-argument",
+		Message: missingRequiredArgument,
+	}
+
+	BlockDevicesUnsupportedArgument = entities.FactGatheringError{
+		Type:    "block_devices-unsupported-argument",
+		Message: "requested argument not supported",
+	}
+
+	BlockDevicesCommandError = entities.FactGatheringError{
+		Type:    "block_devices-command-error",
+		Message: "error running block devices command",
+	}
+
+	BlockDevicesDecodingError = entities.FactGatheringError{
+		Type:    "block_devices-decoding-error",
+		Message: "error decoding block devices output",
+	}
+
+	// mpatha (3600140501234) dm-0 LIO-ORG,sbd or 3600140501234 dm-0 LIO-ORG,sbd
+	multipathMapPattern = regexp.MustCompile(`^(\S+)(?: \((\S+)\))? (dm-\d+) ([^,]*),(.*)$`)
+	// |-+- policy='service-time 0' prio=50 status=active
+	multipathGroupPattern = regexp.MustCompile(`^[|` + "`" + ` ]*[|` + "`" + `]-\+- (.*)$`)
+	// | `- 2:0:0:0 sda 8:0 active ready running
+	multipathPathPattern = regexp.MustCompile(
+		`^[|` + "`" + ` ]*[|` + "`" + `]- (\S+) (\S+) (\d+:\d+)\s+(\S+) (\S+) (\S+)`)
+	multipathAttributePattern = regexp.MustCompile(`(\S+)=('[^']*'|\S+)`)
+)
+
+type blockDevicesCommand struct {
+	command string
+	args    []string
+	parser  func([]byte) (entities.FactValue, error)
+}
+
+//nolint:gochecknoglobals
+var blockDevicesCommands = map[string]blockDevicesCommand{
+	blockDevicesLsblkArgument: {
+		command: "/usr/bin/lsblk",
+		args: []string{"--json", "--bytes", "--output",
+			"NAME,KNAME,PATH,TYPE,SIZE,FSTYPE,MOUNTPOINT,SCHED,RQ-SIZE,ROTA,MODEL,SERIAL,WWN"},
+		parser: parseLsblk,
+	},
+	blockDevicesMultipathArgument: {
+		command: "/usr/sbin/multipath",
+		args:    []string{"-ll"},
+		parser:  parseMultipath,
+	},
+	blockDevicesLvsArgument: {
+		command: "/usr/sbin/lvs",
+		args: []string{"--reportformat", "json", "--units", "b", "--nosuffix", "--options",
+			"lv_name,vg_name,lv_attr,lv_size,lv_path,stripes,stripe_size,devices"},
+		parser: makeLvmReportParser("lv"),
+	},
+	blockDevicesVgsArgument: {
+		command: "/usr/sbin/vgs",
+		args: []string{"--reportformat", "json", "--units", "b", "--nosuffix", "--options",
+			"vg_name,vg_attr,vg_size,vg_free,pv_count,lv_count"},
+		parser: makeLvmReportParser("vg"),
+	},
+}
+
+// BlockDevicesGatherer reports the block devices topology underneath the filesystems.
+// Supported arguments:
+//   - lsblk: block devices tree with the filesystems, scheduler and request queue size
+//   - multipath: multipath maps with their path groups and paths state
+//   - lvs: LVM logical volumes with the stripes and backing devices
+//   - vgs: LVM volume groups
+type BlockDevicesGatherer struct {
+	executor utils.CommandExecutor
+}
+
+func NewDefaultBlockDevicesGatherer() *BlockDevicesGatherer {
+	return NewBlockDevicesGatherer(utils.Executor{})
+}
+
+func NewBlockDevicesGatherer(executor utils.CommandExecutor) *BlockDevicesGatherer {
+	return &BlockDevicesGatherer{
+		executor: executor,
+	}
+}
+
+func (g *BlockDevicesGatherer) Gather(
+	ctx context.Context,
+	factsRequests []entities.FactRequest,
+) ([]entities.Fact, error) {
+	slog.Info("Starting facts gathering process", "gatherer", BlockDevicesGathererName)
+
+	facts := make([]entities.Fact, 0, len(factsRequests))
+
+	for _, factReq := range factsRequests {
+		var fact entities.Fact
+
+		factValue, err := g.gatherBlockDevices(ctx, factReq.Argument)
+
+		switch {
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case err != nil:
+			slog.Error(err.Error())
+			fact = entities.NewFactGatheredWithError(factReq, err)
+		default:
+			fact = entities.NewFactGatheredWithRequest(factReq, factValue)
+		}
+
+		facts = append(facts, fact)
+	}
+
+	slog.Info("Requested facts gathered", "gatherer", BlockDevicesGathererName)
+
+	return facts, nil
+}
+
+func (g *BlockDevicesGatherer) gatherBlockDevices(
+	ctx context.Context,
+	argument string,
+) (entities.FactValue, *entities.FactGatheringError) {
+	if argument == "" {
+		return nil, &BlockDevicesMissingArgument
+	}
+
+	command, found := blockDevicesCommands[argument]
+	if !found {
+		return nil, BlockDevicesUnsupportedArgument.Wrap(argument)
+	}
+
+	output, err := g.executor.OutputContext(ctx, command.command, command.args...)
+	if err != nil {
+		return nil, BlockDevicesCommandError.Wrap(fmt.Sprintf("%s: %s", command.command, err))
+	}
+
+	factValue, err := command.parser(output)
+	if err != nil {
+		return nil, BlockDevicesDecodingError.Wrap(fmt.Sprintf("%s: %s", command.command, err))
+	}
+
+	return factValue, nil
+}
+
+// parseLsblk parses the lsblk json output, where the partitions, LVM volumes and
+// multipath maps are nested as children of the devices they are built on
+func parseLsblk(output []byte) (entities.FactValue, error) {
+	var lsblk struct {
+		BlockDevices []any `json:"blockdevices"`
+	}
+
+	// sizes are decoded as json numbers, so they are not converted to floats
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	decoder.UseNumber()
+
+	err := decoder.Decode(&lsblk)
+	if err != nil {
+		return nil, err
+	}
+
+	return entities.NewFactValue(
+		convertJSONNumbers(lsblk.BlockDevices),
+		entities.WithStringConversion(),
+		entities.WithSnakeCaseKeys(),
+	)
+}
+
+// convertJSONNumbers replaces the json numbers decoded with UseNumber by integers,
+// or floats if they have decimals
+func convertJSONNumbers(value any) any {
+	switch typedValue := value.(type) {
+	case json.Number:
+		if integer, err := typedValue.Int64(); err == nil {
+			return integer
+		}
+
+		float, _ := typedValue.Float64()
+
+		return float
+	case []any:
+		for index, item := range typedValue {
+			typedValue[index] = convertJSONNumbers(item)
+		}
+	case map[string]any:
+		for key, item := range typedValue {
+			typedValue[key] = convertJSONNumbers(item)
+		}
+	}
+
+	return value
+}
+
+// makeLvmReportParser returns a parser for the lvs/vgs json report, which has the format:
+// {"report": [{"lv": [{"lv_name": "...", ...}]}]}
+func makeLvmReportParser(reportType string) func([]byte) (entities.FactValue, error) {
+	return func(output []byte) (entities.FactValue, error) {
+		var report struct {
+			Report []map[string][]any `json:"report"`
+		}
+
+		err := json.Unmarshal(output, &report)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(report.Report) == 0 {
+			return nil, errors.New("empty lvm report")
+		}
+
+		entries, found := report.Report[0][reportType]
+		if !found {
+			return nil, fmt.Errorf("%s entries not found in the lvm report", reportType)
+		}
+
+		return entities.NewFactValue(entries, entities.WithStringConversion())
+	}
+}
+
+// parseMultipath parses the multipath -ll output. Each map starts with a line with its name,
+// wwid, device and vendor/product, followed by the attributes line and the path groups with their paths:
+//
+//	mpatha (3600140501234) dm-0 LIO-ORG,sbd
+//	size=10M features='0' hwhandler='1 alua' wp=rw
+//	|-+- policy='service-time 0' prio=50 status=active
+//	| `- 2:0:0:0 sda 8:0 active ready running
+//	`-+- policy='service-time 0' prio=10 status=enabled
+//	  `- 3:0:0:0 sdb 8:16 active ready running
+func parseMultipath(output []byte) (entities.FactValue, error) {
+	maps := []entities.FactValue{}
+
+	var (
+		currentMap    map[string]entities.FactValue
+		currentGroups *entities.FactValueList
+		currentPaths  *entities.FactValueList
+	)
+
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimRight(line, " ")
+		if line == "" {
+			continue
+		}
+
+		if match := multipathGroupPattern.FindStringSubmatch(line); match != nil {
+			if currentMap == nil {
+				return nil, fmt.Errorf("path group found outside a map: %s", line)
+			}
+
+			group := parseMultipathAttributes(match[1])
+			currentPaths = &entities.FactValueList{Value: []entities.FactValue{}}
+			group["paths"] = currentPaths
+			currentGroups.Value = append(currentGroups.Value, &entities.FactValueMap{Value: group})
+
+			continue
+		}
+
+		if match := multipathPathPattern.FindStringSubmatch(line); match != nil {
+			if currentPaths == nil {
+				return nil, fmt.Errorf("path found outside a path group: %s", line)
+			}
+
+			currentPaths.Value = append(currentPaths.Value, &entities.FactValueMap{
+				Value: map[string]entities.FactValue{
+					"hcil":         &entities.FactValueString{Value: match[1]},
+					"device":       &entities.FactValueString{Value: match[2]},
+					"major_minor":  &entities.FactValueString{Value: match[3]},
+					"dm_state":     &entities.FactValueString{Value: match[4]},
+					"path_state":   &entities.FactValueString{Value: match[5]},
+					"online_state": &entities.FactValueString{Value: match[6]},
+				},
+			})
+
+			continue
+		}
+
+		if match := multipathMapPattern.FindStringSubmatch(line); match != nil {
+			name, wwid := match[1], match[2]
+			if wwid == "" {
+				wwid = name
+			}
+
+			currentGroups = &entities.FactValueList{Value: []entities.FactValue{}}
+			currentPaths = nil
+			currentMap = map[string]entities.FactValue{
+				"name":        &entities.FactValueString{Value: name},
+				"wwid":        &entities.FactValueString{Value: wwid},
+				"dm_device":   &entities.FactValueString{Value: match[3]},
+				"vendor":      &entities.FactValueString{Value: strings.TrimSpace(match[4])},
+				"product":     &entities.FactValueString{Value: strings.TrimSpace(match[5])},
+				"path_groups": currentGroups,
+			}
+			maps = append(maps, &entities.FactValueMap{Value: currentMap})
+
+			continue
+		}
+
+		if currentMap == nil {
+			return nil, fmt.Errorf("unexpected multipath line: %s", line)
+		}
+
+		for key, value := range parseMultipathAttributes(line) {
+			currentMap[key] = value
+		}
+	}
+
+	return &entities.FactValueList{Value: maps}, nil
+}
+
+func parseMultipathAttributes(line string) map[string]entities.FactValue {
+	attributes := map[string]entities.FactValue{}
+
+	for _, match := range multipathAttributePattern.FindAllStringSubmatch(line, -1) {
+		attributes[match[1]] = entities.ParseStringToFactValue(strings.Trim(match[2], "'"))
+	}
+
+	return attributes
+}
@@ -0,0 +1,400 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package gatherers_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/trento-project/agent/v3/internal/factsengine/gatherers"
+	"github.com/trento-project/agent/v3/pkg/factsengine/entities"
+	utilsMocks "github.com/trento-project/agent/v3/pkg/utils/mocks"
+	"github.com/trento-project/agent/v3/test/helpers"
+)
+
+const lsblkColumns = "NAME,KNAME,PATH,TYPE,SIZE,FSTYPE,MOUNTPOINT,SCHED,RQ-SIZE,ROTA,MODEL,SERIAL,WWN"
+
+type BlockDevicesGathererTestSuite struct {
+	suite.Suite
+
+	mockExecutor *utilsMocks.MockCommandExecutor
+}
+
+func TestBlockDevicesGathererSuite(t *testing.T) {
+	suite.Run(t, new(BlockDevicesGathererTestSuite))
+}
+
+func (suite *BlockDevicesGathererTestSuite) SetupTest() {
+	suite.mockExecutor = new(utilsMocks.MockCommandExecutor)
+}
+
+func (suite *BlockDevicesGathererTestSuite) readFixture(name string) []byte {
+	content, err := os.ReadFile(helpers.GetFixturePath("gatherers/blockdevices/" + name))
+	suite.Require().NoError(err)
+
+	return content
+}
+
+func (suite *BlockDevicesGathererTestSuite) TestBlockDevicesGatheringLsblk() {
+	suite.mockExecutor.
+		On("OutputContext", mock.Anything, "/usr/bin/lsblk", "--json", "--bytes", "--output", lsblkColumns).
+		Return(suite.readFixture("lsblk.json"), nil)
+
+	g := gatherers.NewBlockDevicesGatherer(suite.mockExecutor)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "lsblk",
+			Gatherer: "block_devices",
+			Argument: "lsblk",
+			CheckID:  "check1",
+		},
+	}
+
+	expectedResults := []entities.Fact{
+		{
+			Name:    "lsblk",
+			CheckID: "check1",
+			Value: &entities.FactValueList{
+				Value: []entities.FactValue{
+					&entities.FactValueMap{
+						Value: map[string]entities.FactValue{
+							"name":       &entities.FactValueString{Value: "sda"},
+							"kname":      &entities.FactValueString{Value: "sda"},
+							"path":       &entities.FactValueString{Value: "/dev/sda"},
+							"type":       &entities.FactValueString{Value: "disk"},
+							"size":       &entities.FactValueInt{Value: 10485760},
+							"fstype":     &entities.FactValueString{Value: "mpath_member"},
+							"mountpoint": &entities.FactValueNil{},
+							"sched":      &entities.FactValueString{Value: "mq-deadline"},
+							"rq_size":    &entities.FactValueInt{Value: 256},
+							"rota":       &entities.FactValueBool{Value: false},
+							"model":      &entities.FactValueString{Value: "sbd"},
+							"serial":     &entities.FactValueInt{Value: 1234},
+							"wwn":        &entities.FactValueString{Value: "0x6001405012340000"},
+							"children": &entities.FactValueList{
+								Value: []entities.FactValue{
+									&entities.FactValueMap{
+										Value: map[string]entities.FactValue{
+											"name":       &entities.FactValueString{Value: "mpatha"},
+											"kname":      &entities.FactValueString{Value: "dm-0"},
+											"path":       &entities.FactValueString{Value: "/dev/mapper/mpatha"},
+											"type":       &entities.FactValueString{Value: "mpath"},
+											"size":       &entities.FactValueInt{Value: 10485760},
+											"fstype":     &entities.FactValueNil{},
+											"mountpoint": &entities.FactValueNil{},
+											"sched":      &entities.FactValueNil{},
+											"rq_size":    &entities.FactValueInt{Value: 128},
+											"rota":       &entities.FactValueBool{Value: false},
+											"model":      &entities.FactValueNil{},
+											"serial":     &entities.FactValueNil{},
+											"wwn":        &entities.FactValueNil{},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	factResults, err := g.Gather(context.Background(), factRequests)
+	suite.NoError(err)
+	suite.ElementsMatch(expectedResults, factResults)
+}
+
+func (suite *BlockDevicesGathererTestSuite) TestBlockDevicesGatheringLsblkNumbers() {
+	// newer lsblk versions print the numeric columns as json numbers, older ones as strings
+	lsblkOutput := `{"blockdevices": [
+		{"name": "sdb", "size": 18014398509481985, "rq-size": "256", "ra": 0.5}
+	]}`
+
+	suite.mockExecutor.
+		On("OutputContext", mock.Anything, "/usr/bin/lsblk", "--json", "--bytes", "--output", lsblkColumns).
+		Return([]byte(lsblkOutput), nil)
+
+	g := gatherers.NewBlockDevicesGatherer(suite.mockExecutor)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "lsblk",
+			Gatherer: "block_devices",
+			Argument: "lsblk",
+			CheckID:  "check1",
+		},
+	}
+
+	expectedResults := []entities.Fact{
+		{
+			Name:    "lsblk",
+			CheckID: "check1",
+			Value: &entities.FactValueList{
+				Value: []entities.FactValue{
+					&entities.FactValueMap{
+						Value: map[string]entities.FactValue{
+							"name":    &entities.FactValueString{Value: "sdb"},
+							"size":    &entities.FactValueInt{Value: 18014398509481985},
+							"rq_size": &entities.FactValueInt{Value: 256},
+							"ra":      &entities.FactValueFloat{Value: 0.5},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	factResults, err := g.Gather(context.Background(), factRequests)
+	suite.NoError(err)
+	suite.ElementsMatch(expectedResults, factResults)
+}
+
+func (suite *BlockDevicesGathererTestSuite) TestBlockDevicesGatheringMultipath() {
+	suite.mockExecutor.
+		On("OutputContext", mock.Anything, "/usr/sbin/multipath", "-ll").
+		Return(suite.readFixture("multipath.output"), nil)
+
+	g := gatherers.NewBlockDevicesGatherer(suite.mockExecutor)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "multipath",
+			Gatherer: "block_devices",
+			Argument: "multipath",
+			CheckID:  "check1",
+		},
+	}
+
+	path := func(hcil, device, majorMinor, dmState, pathState string) entities.FactValue {
+		return &entities.FactValueMap{
+			Value: map[string]entities.FactValue{
+				"hcil":         &entities.FactValueString{Value: hcil},
+				"device":       &entities.FactValueString{Value: device},
+				"major_minor":  &entities.FactValueString{Value: majorMinor},
+				"dm_state":     &entities.FactValueString{Value: dmState},
+				"path_state":   &entities.FactValueString{Value: pathState},
+				"online_state": &entities.FactValueString{Value: "running"},
+			},
+		}
+	}
+
+	group := func(prio int, status string, paths ...entities.FactValue) entities.FactValue {
+		return &entities.FactValueMap{
+			Value: map[string]entities.FactValue{
+				"policy": &entities.FactValueString{Value: "service-time 0"},
+				"prio":   &entities.FactValueInt{Value: prio},
+				"status": &entities.FactValueString{Value: status},
+				"paths":  &entities.FactValueList{Value: paths},
+			},
+		}
+	}
+
+	expectedResults := []entities.Fact{
+		{
+			Name:    "multipath",
+			CheckID: "check1",
+			Value: &entities.FactValueList{
+				Value: []entities.FactValue{
+					&entities.FactValueMap{
+						Value: map[string]entities.FactValue{
+							"name":      &entities.FactValueString{Value: "mpatha"},
+							"wwid":      &entities.FactValueString{Value: "36001405012340000"},
+							"dm_device": &entities.FactValueString{Value: "dm-0"},
+							"vendor":    &entities.FactValueString{Value: "LIO-ORG"},
+							"product":   &entities.FactValueString{Value: "sbd"},
+							"size":      &entities.FactValueString{Value: "10M"},
+							"features":  &entities.FactValueInt{Value: 0},
+							"hwhandler": &entities.FactValueString{Value: "1 alua"},
+							"wp":        &entities.FactValueString{Value: "rw"},
+							"path_groups": &entities.FactValueList{
+								Value: []entities.FactValue{
+									group(50, "active", path("2:0:0:0", "sda", "8:0", "active", "ready")),
+									group(10, "enabled", path("3:0:0:0", "sdb", "8:16", "failed", "faulty")),
+								},
+							},
+						},
+					},
+					&entities.FactValueMap{
+						Value: map[string]entities.FactValue{
+							"name":      &entities.FactValueString{Value: "36001405056780000"},
+							"wwid":      &entities.FactValueString{Value: "36001405056780000"},
+							"dm_device": &entities.FactValueString{Value: "dm-1"},
+							"vendor":    &entities.FactValueString{Value: "LIO-ORG"},
+							"product":   &entities.FactValueString{Value: "data"},
+							"size":      &entities.FactValueString{Value: "1.0G"},
+							"features":  &entities.FactValueString{Value: "1 queue_if_no_path"},
+							"hwhandler": &entities.FactValueString{Value: "1 alua"},
+							"wp":        &entities.FactValueString{Value: "rw"},
+							"path_groups": &entities.FactValueList{
+								Value: []entities.FactValue{
+									group(50, "active",
+										path("2:0:0:1", "sdc", "8:32", "active", "ready"),
+										path("3:0:0:1", "sdd", "8:48", "active", "ready"),
+									),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	factResults, err := g.Gather(context.Background(), factRequests)
+	suite.NoError(err)
+	suite.ElementsMatch(expectedResults, factResults)
+}
+
+func (suite *BlockDevicesGathererTestSuite) TestBlockDevicesGatheringLvm() {
+	suite.mockExecutor.
+		On("OutputContext", mock.Anything, "/usr/sbin/lvs", "--reportformat", "json", "--units", "b", "--nosuffix",
+			"--options", "lv_name,vg_name,lv_attr,lv_size,lv_path,stripes,stripe_size,devices").
+		Return(suite.readFixture("lvs.json"), nil).
+		On("OutputContext", mock.Anything, "/usr/sbin/vgs", "--reportformat", "json", "--units", "b", "--nosuffix",
+			"--options", "vg_name,vg_attr,vg_size,vg_free,pv_count,lv_count").
+		Return(suite.readFixture("vgs.json"), nil)
+
+	g := gatherers.NewBlockDevicesGatherer(suite.mockExecutor)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "lvs",
+			Gatherer: "block_devices",
+			Argument: "lvs",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "vgs",
+			Gatherer: "block_devices",
+			Argument: "vgs",
+			CheckID:  "check2",
+		},
+	}
+
+	expectedResults := []entities.Fact{
+		{
+			Name:    "lvs",
+			CheckID: "check1",
+			Value: &entities.FactValueList{
+				Value: []entities.FactValue{
+					&entities.FactValueMap{
+						Value: map[string]entities.FactValue{
+							"lv_name":     &entities.FactValueString{Value: "hanadata"},
+							"vg_name":     &entities.FactValueString{Value: "vg_hana_data"},
+							"lv_attr":     &entities.FactValueString{Value: "-wi-ao----"},
+							"lv_size":     &entities.FactValueInt{Value: 107374182400},
+							"lv_path":     &entities.FactValueString{Value: "/dev/vg_hana_data/hanadata"},
+							"stripes":     &entities.FactValueInt{Value: 2},
+							"stripe_size": &entities.FactValueInt{Value: 262144},
+							"devices":     &entities.FactValueString{Value: "/dev/sdc(0),/dev/sdd(0)"},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:    "vgs",
+			CheckID: "check2",
+			Value: &entities.FactValueList{
+				Value: []entities.FactValue{
+					&entities.FactValueMap{
+						Value: map[string]entities.FactValue{
+							"vg_name":  &entities.FactValueString{Value: "vg_hana_data"},
+							"vg_attr":  &entities.FactValueString{Value: "wz--n-"},
+							"vg_size":  &entities.FactValueInt{Value: 214740025344},
+							"vg_free":  &entities.FactValueInt{Value: 107365793792},
+							"pv_count": &entities.FactValueInt{Value: 2},
+							"lv_count": &entities.FactValueInt{Value: 1},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	factResults, err := g.Gather(context.Background(), factRequests)
+	suite.NoError(err)
+	suite.ElementsMatch(expectedResults, factResults)
+}
+
+func (suite *BlockDevicesGathererTestSuite) TestBlockDevicesGatheringErrors() {
+	suite.mockExecutor.
+		On("OutputContext", mock.Anything, "/usr/sbin/multipath", "-ll").
+		Return(nil, errors.New("multipath not found")).
+		On("OutputContext", mock.Anything, "/usr/sbin/vgs", mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return([]byte(`{"report": []}`), nil)
+
+	g := gatherers.NewBlockDevicesGatherer(suite.mockExecutor)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "missing",
+			Gatherer: "block_devices",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "unsupported",
+			Gatherer: "block_devices",
+			Argument: "pvs",
+			CheckID:  "check2",
+		},
+		{
+			Name:     "multipath",
+			Gatherer: "block_devices",
+			Argument: "multipath",
+			CheckID:  "check3",
+		},
+		{
+			Name:     "vgs",
+			Gatherer: "block_devices",
+			Argument: "vgs",
+			CheckID:  "check4",
+		},
+	}
+
+	expectedResults := []entities.Fact{
+		{
+			Name:    "missing",
+			CheckID: "check1",
+			Error: &entities.FactGatheringError{
+				Type:    "block_devices-missing-argument",
+				Message: "missing required argument",
+			},
+		},
+		{
+			Name:    "unsupported",
+			CheckID: "check2",
+			Error: &entities.FactGatheringError{
+				Type:    "block_devices-unsupported-argument",
+				Message: "requested argument not supported: pvs",
+			},
+		},
+		{
+			Name:    "multipath",
+			CheckID: "check3",
+			Error: &entities.FactGatheringError{
+				Type:    "block_devices-command-error",
+				Message: "error running block devices command: /usr/sbin/multipath: multipath not found",
+			},
+		},
+		{
+			Name:    "vgs",
+			CheckID: "check4",
+			Error: &entities.FactGatheringError{
+				Type:    "block_devices-decoding-error",
+				Message: "error decoding block devices output: /usr/sbin/vgs: empty lvm report",
+			},
+		},
+	}
+
+	factResults, err := g.Gather(context.Background(), factRequests)
+	suite.NoError(err)
+	suite.ElementsMatch(expectedResults, factResults)
+}
@@ -0,0 +1,292 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package gatherers
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/trento-project/agent/v3/pkg/factsengine/entities"
+	"github.com/trento-project/agent/v3/pkg/utils"
+)
+
+const (
+	CertificatesGathererName = "certificates"
+
+	sapgenpsePath = "/usr/sap/hostctrl/exe/sapgenpse"
+	pseExtension  = ".pse"
+	hoursPerDay   = 24
+)
+
+//nolint:gochecknoglobals
+var (
+	CertificatesMissingArgument = entities.FactGatheringError{
+		Type:    "certificates-missing-argument",
+		Message: missingRequiredArgument,
+	}
+
+	CertificatesScanningError = entities.FactGatheringError{
+		Type:    "certificates-scanning-error",
+		Message: "error scanning the certificates path",
+	}
+
+	CertificatesCommandError = entities.FactGatheringError{
+		Type:    "certificates-command-error",
+		Message: fmt.Sprintf(errRunningCommandFmt, "sapgenpse"),
+	}
+
+	CertificatesDecodingError = entities.FactGatheringError{
+		Type:    "certificates-decoding-error",
+		Message: "error decoding certificate",
+	}
+
+	// certificateExtensions are the files picked up when scanning directories
+	certificateExtensions = []string{".pem", ".crt", ".cer", ".der", pseExtension}
+)
+
+// CertificatesGatherer reports the X.509 certificates found in the given path.
+// The argument is a glob pattern, as in the dir_scan gatherer. Matching files are decoded as PEM or DER
+// certificates, and matching directories are scanned recursively for .pem, .crt, .cer, .der and .pse files.
+// The own certificate of SAP PSE files is exported using sapgenpse.
+//
+// Each certificate reports its path, subject, issuer, SANs, validity and the days remaining until it expires,
+// negative if already expired. Files that cannot be read or decoded are reported with their path and
+// an error field, without discarding the certificates found in the other files.
+type CertificatesGatherer struct {
+	fs       afero.Fs
+	executor utils.CommandExecutor
+	now      func() time.Time
+}
+
+func NewDefaultCertificatesGatherer() *CertificatesGatherer {
+	return NewCertificatesGatherer(afero.NewOsFs(), utils.Executor{}, time.Now)
+}
+
+func NewCertificatesGatherer(fs afero.Fs, executor utils.CommandExecutor, now func() time.Time) *CertificatesGatherer {
+	return &CertificatesGatherer{
+		fs:       fs,
+		executor: executor,
+		now:      now,
+	}
+}
+
+func (g *CertificatesGatherer) Gather(
+	ctx context.Context,
+	factsRequests []entities.FactRequest,
+) ([]entities.Fact, error) {
+	slog.Info("Starting facts gathering process", "gatherer", CertificatesGathererName)
+
+	facts := make([]entities.Fact, 0, len(factsRequests))
+
+	for _, factReq := range factsRequests {
+		var fact entities.Fact
+
+		factValue, err := g.gatherCertificates(ctx, factReq.Argument)
+
+		switch {
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case err != nil:
+			slog.Error(err.Error())
+			fact = entities.NewFactGatheredWithError(factReq, err)
+		default:
+			fact = entities.NewFactGatheredWithRequest(factReq, factValue)
+		}
+
+		facts = append(facts, fact)
+	}
+
+	slog.Info("Requested facts gathered", "gatherer", CertificatesGathererName)
+
+	return facts, nil
+}
+
+func (g *CertificatesGatherer) gatherCertificates(
+	ctx context.Context,
+	pattern string,
+) (entities.FactValue, *entities.FactGatheringError) {
+	if pattern == "" {
+		return nil, &CertificatesMissingArgument
+	}
+
+	files, err := g.findCertificateFiles(pattern)
+	if err != nil {
+		return nil, CertificatesScanningError.Wrap(err.Error())
+	}
+
+	certificates := []entities.FactValue{}
+
+	for _, file := range files {
+		content, gatheringErr := g.readCertificateFile(ctx, file)
+		if gatheringErr != nil {
+			certificates = append(certificates, certificateErrorToFactValue(file, gatheringErr))
+
+			continue
+		}
+
+		parsed, err := parseCertificates(content)
+		if err != nil {
+			certificates = append(certificates,
+				certificateErrorToFactValue(file, CertificatesDecodingError.Wrap(err.Error())))
+
+			continue
+		}
+
+		for _, certificate := range parsed {
+			certificates = append(certificates, g.certificateToFactValue(file, certificate))
+		}
+	}
+
+	return &entities.FactValueList{Value: certificates}, nil
+}
+
+// findCertificateFiles returns the files matching the glob pattern,
+// and the certificate files found recursively in the matching directories
+func (g *CertificatesGatherer) findCertificateFiles(pattern string) ([]string, error) {
+	matches, err := afero.Glob(g.fs, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+
+	for _, match := range matches {
+		info, err := g.fs.Stat(match)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			files = append(files, match)
+
+			continue
+		}
+
+		err = afero.Walk(g.fs, match, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if !info.IsDir() && slices.Contains(certificateExtensions, strings.ToLower(filepath.Ext(path))) {
+				files = append(files, path)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+func (g *CertificatesGatherer) readCertificateFile(
+	ctx context.Context,
+	path string,
+) ([]byte, *entities.FactGatheringError) {
+	if strings.ToLower(filepath.Ext(path)) != pseExtension {
+		content, err := afero.ReadFile(g.fs, path)
+		if err != nil {
+			return nil, CertificatesScanningError.Wrap(err.Error())
+		}
+
+		return content, nil
+	}
+
+	// the PSE own certificate is exported in PEM format to the standard output
+	output, err := g.executor.OutputContext(ctx, sapgenpsePath, "export_own_cert", "-p", path)
+	if err != nil {
+		return nil, CertificatesCommandError.Wrap(err.Error())
+	}
+
+	return output, nil
+}
+
+// parseCertificates returns the certificates in PEM content, ignoring other blocks like
+// private keys, or the DER encoded certificate if the content is not PEM
+func parseCertificates(content []byte) ([]*x509.Certificate, error) {
+	certificates := []*x509.Certificate{}
+	foundPEM := false
+
+	for rest := content; ; {
+		var block *pem.Block
+
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		foundPEM = true
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		certificates = append(certificates, certificate)
+	}
+
+	if foundPEM {
+		return certificates, nil
+	}
+
+	return x509.ParseCertificates(content)
+}
+
+func certificateErrorToFactValue(path string, err *entities.FactGatheringError) entities.FactValue {
+	slog.Warn("Error gathering certificate", "path", path, "error", err.Message)
+
+	return &entities.FactValueMap{Value: map[string]entities.FactValue{
+		"path":  &entities.FactValueString{Value: path},
+		"error": &entities.FactValueString{Value: err.Message},
+	}}
+}
+
+func (g *CertificatesGatherer) certificateToFactValue(path string, certificate *x509.Certificate) entities.FactValue {
+	sans := []entities.FactValue{}
+
+	for _, name := range certificate.DNSNames {
+		sans = append(sans, &entities.FactValueString{Value: name})
+	}
+
+	for _, ip := range certificate.IPAddresses {
+		sans = append(sans, &entities.FactValueString{Value: ip.String()})
+	}
+
+	for _, email := range certificate.EmailAddresses {
+		sans = append(sans, &entities.FactValueString{Value: email})
+	}
+
+	for _, uri := range certificate.URIs {
+		sans = append(sans, &entities.FactValueString{Value: uri.String()})
+	}
+
+	daysRemaining := math.Floor(certificate.NotAfter.Sub(g.now()).Hours() / hoursPerDay)
+
+	return &entities.FactValueMap{Value: map[string]entities.FactValue{
+		"path":           &entities.FactValueString{Value: path},
+		"subject":        &entities.FactValueString{Value: certificate.Subject.String()},
+		"issuer":         &entities.FactValueString{Value: certificate.Issuer.String()},
+		"serial_number":  &entities.FactValueString{Value: certificate.SerialNumber.String()},
+		"sans":           &entities.FactValueList{Value: sans},
+		"not_before":     &entities.FactValueString{Value: certificate.NotBefore.UTC().Format(time.RFC3339)},
+		"not_after":      &entities.FactValueString{Value: certificate.NotAfter.UTC().Format(time.RFC3339)},
+		"days_remaining": &entities.FactValueInt{Value: int(daysRemaining)},
+	}}
+}
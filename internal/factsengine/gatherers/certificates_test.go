@@ -0,0 +1,254 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package gatherers_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/trento-project/agent/v3/internal/factsengine/gatherers"
+	"github.com/trento-project/agent/v3/pkg/factsengine/entities"
+	utilsMocks "github.com/trento-project/agent/v3/pkg/utils/mocks"
+	"github.com/trento-project/agent/v3/test/helpers"
+)
+
+const testCAName = "CN=Trento Test CA,O=SUSE"
+
+type CertificatesGathererTestSuite struct {
+	suite.Suite
+
+	fs           afero.Fs
+	mockExecutor *utilsMocks.MockCommandExecutor
+	now          func() time.Time
+}
+
+func TestCertificatesGathererSuite(t *testing.T) {
+	suite.Run(t, new(CertificatesGathererTestSuite))
+}
+
+func (suite *CertificatesGathererTestSuite) SetupTest() {
+	suite.fs = afero.NewBasePathFs(
+		afero.NewReadOnlyFs(afero.NewOsFs()),
+		helpers.GetFixturePath("gatherers/certificates"),
+	)
+	suite.mockExecutor = new(utilsMocks.MockCommandExecutor)
+	suite.now = func() time.Time {
+		return time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+func certificateFactValue(
+	path, subject, issuer, serial string,
+	sans []string,
+	notBefore, notAfter string,
+	daysRemaining int,
+) entities.FactValue {
+	sansValues := []entities.FactValue{}
+	for _, san := range sans {
+		sansValues = append(sansValues, &entities.FactValueString{Value: san})
+	}
+
+	return &entities.FactValueMap{
+		Value: map[string]entities.FactValue{
+			"path":           &entities.FactValueString{Value: path},
+			"subject":        &entities.FactValueString{Value: subject},
+			"issuer":         &entities.FactValueString{Value: issuer},
+			"serial_number":  &entities.FactValueString{Value: serial},
+			"sans":           &entities.FactValueList{Value: sansValues},
+			"not_before":     &entities.FactValueString{Value: notBefore},
+			"not_after":      &entities.FactValueString{Value: notAfter},
+			"days_remaining": &entities.FactValueInt{Value: daysRemaining},
+		},
+	}
+}
+
+func (suite *CertificatesGathererTestSuite) TestCertificatesGatheringSuccess() {
+	pseOutput, err := os.ReadFile(helpers.GetFixturePath("gatherers/sapgenpse-export.output"))
+	suite.Require().NoError(err)
+
+	suite.mockExecutor.
+		On("OutputContext", mock.Anything, "/usr/sap/hostctrl/exe/sapgenpse", "export_own_cert", "-p", "/sec/SAPSSLS.pse").
+		Return(pseOutput, nil)
+
+	g := gatherers.NewCertificatesGatherer(suite.fs, suite.mockExecutor, suite.now)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "ssl_directory",
+			Gatherer: "certificates",
+			Argument: "/ssl",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "hawk",
+			Gatherer: "certificates",
+			Argument: "/hawk.crt",
+			CheckID:  "check2",
+		},
+		{
+			Name:     "pse",
+			Gatherer: "certificates",
+			Argument: "/sec/*.pse",
+			CheckID:  "check3",
+		},
+		{
+			Name:     "no_matches",
+			Gatherer: "certificates",
+			Argument: "/etc/*.pem",
+			CheckID:  "check4",
+		},
+	}
+
+	expectedResults := []entities.Fact{
+		{
+			Name:    "ssl_directory",
+			CheckID: "check1",
+			Value: &entities.FactValueList{
+				Value: []entities.FactValue{
+					certificateFactValue("/ssl/ca.der", testCAName, testCAName, "1", []string{},
+						"2024-01-01T00:00:00Z", "2034-01-01T00:00:00Z", 2631),
+					certificateFactValue("/ssl/server.pem", "CN=vmhana01", testCAName, "2",
+						[]string{"vmhana01", "vmhana01.example.com", "10.80.1.11"},
+						"2025-01-01T00:00:00Z", "2026-11-18T12:00:00Z", 30),
+				},
+			},
+		},
+		{
+			Name:    "hawk",
+			CheckID: "check2",
+			Value: &entities.FactValueList{
+				Value: []entities.FactValue{
+					certificateFactValue("/hawk.crt", "CN=hawk", testCAName, "3", []string{"hawk.example.com"},
+						"2024-01-01T00:00:00Z", "2026-10-01T00:00:00Z", -18),
+				},
+			},
+		},
+		{
+			Name:    "pse",
+			CheckID: "check3",
+			Value: &entities.FactValueList{
+				Value: []entities.FactValue{
+					certificateFactValue("/sec/SAPSSLS.pse", "CN=vmhana01,OU=PRD", testCAName, "4", []string{"vmhana01"},
+						"2025-01-01T00:00:00Z", "2038-01-01T00:00:00Z", 4092),
+				},
+			},
+		},
+		{
+			Name:    "no_matches",
+			CheckID: "check4",
+			Value:   &entities.FactValueList{Value: []entities.FactValue{}},
+		},
+	}
+
+	factResults, err := g.Gather(context.Background(), factRequests)
+	suite.NoError(err)
+	suite.ElementsMatch(expectedResults, factResults)
+}
+
+func (suite *CertificatesGathererTestSuite) TestCertificatesGatheringErrors() {
+	hawkCertificate, err := os.ReadFile(helpers.GetFixturePath("gatherers/certificates/hawk.crt"))
+	suite.Require().NoError(err)
+
+	fs := afero.NewMemMapFs()
+	suite.Require().NoError(afero.WriteFile(fs, "/etc/ssl/broken.der", []byte("not a certificate"), 0644))
+	suite.Require().NoError(afero.WriteFile(fs, "/etc/ssl/hawk.crt", hawkCertificate, 0644))
+	suite.Require().NoError(afero.WriteFile(fs, "/sec/SAPSSLC.pse", []byte("pse"), 0644))
+
+	suite.mockExecutor.
+		On("OutputContext", mock.Anything, "/usr/sap/hostctrl/exe/sapgenpse", "export_own_cert", "-p", "/sec/SAPSSLC.pse").
+		Return(nil, errors.New("PSE not accessible"))
+
+	g := gatherers.NewCertificatesGatherer(fs, suite.mockExecutor, suite.now)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "missing",
+			Gatherer: "certificates",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "bad_pattern",
+			Gatherer: "certificates",
+			Argument: "/etc/[",
+			CheckID:  "check2",
+		},
+		{
+			Name:     "broken",
+			Gatherer: "certificates",
+			Argument: "/etc/ssl",
+			CheckID:  "check3",
+		},
+		{
+			Name:     "pse",
+			Gatherer: "certificates",
+			Argument: "/sec",
+			CheckID:  "check4",
+		},
+	}
+
+	factResults, err := g.Gather(context.Background(), factRequests)
+	suite.NoError(err)
+	suite.Len(factResults, 4)
+
+	suite.Equal(&entities.FactGatheringError{
+		Type:    "certificates-missing-argument",
+		Message: "missing required argument",
+	}, factResults[0].Error)
+	suite.Equal("certificates-scanning-error", factResults[1].Error.Type)
+
+	// the broken file is reported without discarding the other certificates
+	suite.Nil(factResults[2].Error)
+	brokenCertificates, ok := factResults[2].Value.(*entities.FactValueList)
+	suite.Require().True(ok)
+	suite.Require().Len(brokenCertificates.Value, 2)
+
+	brokenCertificate, ok := brokenCertificates.Value[0].(*entities.FactValueMap)
+	suite.Require().True(ok)
+	suite.Equal(&entities.FactValueString{Value: "/etc/ssl/broken.der"}, brokenCertificate.Value["path"])
+	brokenError, ok := brokenCertificate.Value["error"].(*entities.FactValueString)
+	suite.Require().True(ok)
+	suite.Contains(brokenError.Value, "error decoding certificate")
+	suite.Equal(
+		certificateFactValue("/etc/ssl/hawk.crt", "CN=hawk", testCAName, "3", []string{"hawk.example.com"},
+			"2024-01-01T00:00:00Z", "2026-10-01T00:00:00Z", -18),
+		brokenCertificates.Value[1],
+	)
+
+	suite.Nil(factResults[3].Error)
+	suite.Equal(&entities.FactValueList{
+		Value: []entities.FactValue{
+			&entities.FactValueMap{
+				Value: map[string]entities.FactValue{
+					"path":  &entities.FactValueString{Value: "/sec/SAPSSLC.pse"},
+					"error": &entities.FactValueString{Value: "error running sapgenpse command: PSE not accessible"},
+				},
+			},
+		},
+	}, factResults[3].Value)
+}
+
+func (suite *CertificatesGathererTestSuite) TestCertificatesGatheringContextCancelled() {
+	g := gatherers.NewCertificatesGatherer(suite.fs, suite.mockExecutor, suite.now)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	factResults, err := g.Gather(ctx, []entities.FactRequest{
+		{
+			Name:     "hawk",
+			Gatherer: "certificates",
+			Argument: "/hawk.crt",
+			CheckID:  "check1",
+		},
+	})
+
+	suite.Error(err)
+	suite.Empty(factResults)
+}
@@ -0,0 +1,359 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package gatherers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/clbanning/mxj/v2"
+	"github.com/hashicorp/go-envparse"
+	"github.com/spf13/afero"
+	"github.com/trento-project/agent/v3/pkg/factsengine/entities"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ConfigFileGathererName = "config_file"
+
+	configFileFormatJSON     = "json"
+	configFileFormatYAML     = "yaml"
+	configFileFormatXML      = "xml"
+	configFileFormatINI      = "ini"
+	configFileFormatKeyValue = "keyvalue"
+)
+
+//nolint:gochecknoglobals
+var (
+	ConfigFileMissingArgument = entities.FactGatheringError{
+		Type:    "config_file-missing-argument",
+		Message: missingRequiredArgument,
+	}
+
+	ConfigFileInvalidArgument = entities.FactGatheringError{
+		Type:    "config_file-invalid-argument",
+		Message: "invalid config file argument",
+	}
+
+	ConfigFileNotAllowedError = entities.FactGatheringError{
+		Type:    "config_file-path-not-allowed",
+		Message: "config file path not included in the allowed paths",
+	}
+
+	ConfigFileReadError = entities.FactGatheringError{
+		Type:    "config_file-read-error",
+		Message: "error reading config file",
+	}
+
+	ConfigFileParsingError = entities.FactGatheringError{
+		Type:    "config_file-parsing-error",
+		Message: "error parsing config file",
+	}
+
+	ConfigFileQueryError = entities.FactGatheringError{
+		Type:    "config_file-query-error",
+		Message: "error querying config file",
+	}
+
+	configFileFormats = []string{
+		configFileFormatJSON,
+		configFileFormatYAML,
+		configFileFormatXML,
+		configFileFormatINI,
+		configFileFormatKeyValue,
+	}
+
+	// a.b[0].c, with an optional leading $
+	configFileQueryPattern = regexp.MustCompile(`^\$?((\.?[^.\[\]]+)|(\[\d+\]))*$`)
+	configFileQueryTokens  = regexp.MustCompile(`[^.\[\]]+|\[\d+\]`)
+)
+
+// DefaultConfigFileAllowedPaths lists the files that can be read by the config_file
+// gatherer when no custom allowlist is configured. Only well known files are included,
+// as the whole file content is returned when no query is given.
+// The HANA global directory is usually a link to /sapmnt, so both locations are allowed.
+func DefaultConfigFileAllowedPaths() []string {
+	return []string{
+		"/etc/os-release",
+		"/etc/sysconfig/sbd",
+		"/etc/sysconfig/pacemaker",
+		"/etc/sysconfig/saptune",
+		"/usr/sap/*/SYS/global/hdb/custom/config/*.ini",
+		"/sapmnt/*/global/hdb/custom/config/*.ini",
+	}
+}
+
+// ConfigFileGatherer reads a value from a structured configuration file.
+// The argument is a comma separated list of key=value options:
+//   - path: absolute path of the file. It must match one of the allowed paths, which support
+//     shell file name patterns. Symbolic links are followed, and the file they point to must be allowed too
+//   - format: json, yaml, xml, ini or keyvalue (shell style KEY=value lines, as in sysconfig files)
+//   - query: optional path of the requested value, with dot separated keys and [n] list indexes,
+//     like $.totem.interface[0].bindnetaddr. XML attributes are keys of their element, and INI values
+//     are queried as section.key. The whole file is returned if omitted
+//
+// Example: path=/etc/sysconfig/sbd,format=keyvalue,query=SBD_WATCHDOG_TIMEOUT
+type ConfigFileGatherer struct {
+	fs           afero.Fs
+	evalSymlinks func(path string) (string, error)
+	allowedPaths []string
+}
+
+type configFileRequest struct {
+	path   string
+	format string
+	query  string
+}
+
+func NewDefaultConfigFileGatherer(allowedPaths []string) *ConfigFileGatherer {
+	return NewConfigFileGatherer(afero.NewOsFs(), filepath.EvalSymlinks, allowedPaths)
+}
+
+func NewConfigFileGatherer(
+	fs afero.Fs,
+	evalSymlinks func(path string) (string, error),
+	allowedPaths []string,
+) *ConfigFileGatherer {
+	if len(allowedPaths) == 0 {
+		allowedPaths = DefaultConfigFileAllowedPaths()
+	}
+
+	return &ConfigFileGatherer{
+		fs:           fs,
+		evalSymlinks: evalSymlinks,
+		allowedPaths: allowedPaths,
+	}
+}
+
+func (g *ConfigFileGatherer) Gather(
+	ctx context.Context,
+	factsRequests []entities.FactRequest,
+) ([]entities.Fact, error) {
+	slog.Info("Starting facts gathering process", "gatherer", ConfigFileGathererName)
+
+	facts := make([]entities.Fact, 0, len(factsRequests))
+
+	for _, factReq := range factsRequests {
+		var fact entities.Fact
+
+		factValue, err := g.gatherConfigFile(factReq.Argument)
+
+		switch {
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case err != nil:
+			slog.Error(err.Error())
+			fact = entities.NewFactGatheredWithError(factReq, err)
+		default:
+			fact = entities.NewFactGatheredWithRequest(factReq, factValue)
+		}
+
+		facts = append(facts, fact)
+	}
+
+	slog.Info("Requested facts gathered", "gatherer", ConfigFileGathererName)
+
+	return facts, nil
+}
+
+func (g *ConfigFileGatherer) gatherConfigFile(argument string) (entities.FactValue, *entities.FactGatheringError) {
+	if argument == "" {
+		return nil, &ConfigFileMissingArgument
+	}
+
+	request, err := parseConfigFileRequest(argument)
+	if err != nil {
+		return nil, ConfigFileInvalidArgument.Wrap(err.Error())
+	}
+
+	if !g.isAllowed(request.path) {
+		return nil, ConfigFileNotAllowedError.Wrap(request.path)
+	}
+
+	resolvedPath, err := g.evalSymlinks(request.path)
+	if err != nil {
+		return nil, ConfigFileReadError.Wrap(err.Error())
+	}
+
+	// an allowed path could link to any other file
+	if !g.isAllowed(resolvedPath) {
+		return nil, ConfigFileNotAllowedError.Wrap(fmt.Sprintf("%s links to %s", request.path, resolvedPath))
+	}
+
+	content, err := afero.ReadFile(g.fs, resolvedPath)
+	if err != nil {
+		return nil, ConfigFileReadError.Wrap(err.Error())
+	}
+
+	parsed, err := parseConfigFile(content, request.format)
+	if err != nil {
+		return nil, ConfigFileParsingError.Wrap(fmt.Sprintf("%s: %s", request.path, err))
+	}
+
+	value, err := queryConfigFile(parsed, request.query)
+	if err != nil {
+		return nil, ConfigFileQueryError.Wrap(fmt.Sprintf("%s: %s", request.path, err))
+	}
+
+	var factValueOpts []entities.FactValueOption
+
+	// values of the formats without types are always strings
+	if slices.Contains([]string{configFileFormatXML, configFileFormatINI, configFileFormatKeyValue}, request.format) {
+		factValueOpts = append(factValueOpts, entities.WithStringConversion())
+	}
+
+	factValue, err := entities.NewFactValue(value, factValueOpts...)
+	if err != nil {
+		return nil, ConfigFileParsingError.Wrap(fmt.Sprintf("%s: %s", request.path, err))
+	}
+
+	return factValue, nil
+}
+
+func (g *ConfigFileGatherer) isAllowed(path string) bool {
+	for _, pattern := range g.allowedPaths {
+		matched, err := filepath.Match(pattern, path)
+		if err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseConfigFileRequest(argument string) (*configFileRequest, error) {
+	request := &configFileRequest{}
+
+	for _, option := range strings.Split(argument, ",") {
+		key, value, found := strings.Cut(option, "=")
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		if !found || value == "" {
+			return nil, fmt.Errorf("option %s must be a key=value pair", option)
+		}
+
+		switch key {
+		case "path":
+			if !filepath.IsAbs(value) || filepath.Clean(value) != value {
+				return nil, fmt.Errorf("path %s must be an absolute clean path", value)
+			}
+
+			request.path = value
+		case "format":
+			if !slices.Contains(configFileFormats, value) {
+				return nil, fmt.Errorf("invalid format %s, supported values: %s",
+					value, strings.Join(configFileFormats, ", "))
+			}
+
+			request.format = value
+		case "query":
+			if !configFileQueryPattern.MatchString(value) {
+				return nil, fmt.Errorf("invalid query %s", value)
+			}
+
+			request.query = value
+		default:
+			return nil, fmt.Errorf("unknown option %s", key)
+		}
+	}
+
+	if request.path == "" || request.format == "" {
+		return nil, errors.New("path and format options are required")
+	}
+
+	return request, nil
+}
+
+func parseConfigFile(content []byte, format string) (any, error) {
+	switch format {
+	case configFileFormatJSON:
+		var parsed any
+
+		// numbers are decoded as json numbers, so they are not converted to floats
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+
+		err := decoder.Decode(&parsed)
+		if err != nil {
+			return nil, err
+		}
+
+		return convertJSONNumbers(parsed), nil
+	case configFileFormatYAML:
+		var parsed any
+
+		err := yaml.Unmarshal(content, &parsed)
+		if err != nil {
+			return nil, err
+		}
+
+		return parsed, nil
+	case configFileFormatXML:
+		mv, err := mxj.NewMapXml(content)
+		if err != nil {
+			return nil, err
+		}
+
+		return convertListElements(map[string]any(mv), map[string]bool{}), nil
+	case configFileFormatINI:
+		return parseIni(content)
+	default:
+		values, err := envparse.Parse(bytes.NewReader(content))
+		if err != nil {
+			return nil, err
+		}
+
+		parsed := make(map[string]any, len(values))
+		for key, value := range values {
+			parsed[key] = value
+		}
+
+		return parsed, nil
+	}
+}
+
+// queryConfigFile returns the value in the parsed content found in the query path
+func queryConfigFile(parsed any, query string) (any, error) {
+	current := parsed
+
+	for _, token := range configFileQueryTokens.FindAllString(strings.TrimPrefix(query, "$"), -1) {
+		if strings.HasPrefix(token, "[") {
+			list, ok := current.([]any)
+			if !ok {
+				return nil, fmt.Errorf("cannot index %s, value is not a list", token)
+			}
+
+			index, _ := strconv.Atoi(strings.Trim(token, "[]"))
+			if index >= len(list) {
+				return nil, fmt.Errorf("index %s out of range", token)
+			}
+
+			current = list[index]
+
+			continue
+		}
+
+		values, ok := current.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("cannot get key %s, value is not a map", token)
+		}
+
+		current, ok = values[token]
+		if !ok {
+			return nil, fmt.Errorf("key %s not found", token)
+		}
+	}
+
+	return current, nil
+}
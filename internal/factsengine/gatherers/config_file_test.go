@@ -0,0 +1,394 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package gatherers_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+	"github.com/trento-project/agent/v3/internal/factsengine/gatherers"
+	"github.com/trento-project/agent/v3/pkg/factsengine/entities"
+	"github.com/trento-project/agent/v3/test/helpers"
+)
+
+type ConfigFileGathererTestSuite struct {
+	suite.Suite
+
+	fs    afero.Fs
+	links map[string]string
+}
+
+func TestConfigFileGathererSuite(t *testing.T) {
+	suite.Run(t, new(ConfigFileGathererTestSuite))
+}
+
+func (suite *ConfigFileGathererTestSuite) SetupTest() {
+	suite.fs = afero.NewMemMapFs()
+	suite.links = map[string]string{}
+
+	files := map[string]string{
+		"exporter.json":  "/etc/exporter/config.json",
+		"cluster.yaml":   "/etc/trento/cluster.yaml",
+		"crm_config.xml": "/etc/trento/crm_config.xml",
+		"global.ini":     "/usr/sap/PRD/SYS/global/hdb/custom/config/global.ini",
+		"sbd":            "/etc/sysconfig/sbd",
+	}
+
+	for fixture, path := range files {
+		content, err := os.ReadFile(helpers.GetFixturePath("gatherers/config_file/" + fixture))
+		suite.Require().NoError(err)
+		suite.Require().NoError(afero.WriteFile(suite.fs, path, content, 0644))
+	}
+}
+
+// evalSymlinks resolves the links configured in the test, as the memory filesystem doesn't support them
+func (suite *ConfigFileGathererTestSuite) evalSymlinks(path string) (string, error) {
+	if target, found := suite.links[path]; found {
+		return target, nil
+	}
+
+	return path, nil
+}
+
+func (suite *ConfigFileGathererTestSuite) TestConfigFileGatheringSuccess() {
+	g := gatherers.NewConfigFileGatherer(suite.fs, suite.evalSymlinks, []string{
+		"/etc/exporter/*.json",
+		"/etc/trento/*",
+		"/etc/sysconfig/*",
+		"/usr/sap/*/SYS/global/hdb/custom/config/*.ini",
+	})
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "json_port",
+			Gatherer: "config_file",
+			Argument: "path=/etc/exporter/config.json,format=json,query=$.port",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "json_collector",
+			Gatherer: "config_file",
+			Argument: "path=/etc/exporter/config.json,format=json,query=collectors[1]",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "json_max_requests",
+			Gatherer: "config_file",
+			Argument: "path=/etc/exporter/config.json,format=json,query=max_requests",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "yaml_node",
+			Gatherer: "config_file",
+			Argument: "path=/etc/trento/cluster.yaml,format=yaml,query=cluster.nodes[1].site",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "yaml_timeout",
+			Gatherer: "config_file",
+			Argument: "path=/etc/trento/cluster.yaml,format=yaml,query=cluster.stonith_timeout",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "xml_nvpair",
+			Gatherer: "config_file",
+			Argument: "path=/etc/trento/crm_config.xml,format=xml,query=crm_config.cluster_property_set.nvpair[1]",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "ini_section",
+			Gatherer: "config_file",
+			Argument: "path=/usr/sap/PRD/SYS/global/hdb/custom/config/global.ini,format=ini,query=system_replication",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "ini_key",
+			Gatherer: "config_file",
+			Argument: "path=/usr/sap/PRD/SYS/global/hdb/custom/config/global.ini,format=ini,query=persistence.basepath_datavolumes",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "keyvalue_whole_file",
+			Gatherer: "config_file",
+			Argument: "path=/etc/sysconfig/sbd,format=keyvalue",
+			CheckID:  "check1",
+		},
+	}
+
+	expectedResults := []entities.Fact{
+		{
+			Name:    "json_port",
+			CheckID: "check1",
+			Value:   &entities.FactValueInt{Value: 9100},
+		},
+		{
+			Name:    "json_collector",
+			CheckID: "check1",
+			Value: &entities.FactValueMap{
+				Value: map[string]entities.FactValue{
+					"name":    &entities.FactValueString{Value: "filesystem"},
+					"enabled": &entities.FactValueBool{Value: false},
+					"ignored_mounts": &entities.FactValueList{
+						Value: []entities.FactValue{
+							&entities.FactValueString{Value: "/proc"},
+							&entities.FactValueString{Value: "/sys"},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:    "json_max_requests",
+			CheckID: "check1",
+			Value:   &entities.FactValueInt{Value: 10485760},
+		},
+		{
+			Name:    "yaml_node",
+			CheckID: "check1",
+			Value:   &entities.FactValueString{Value: "Site2"},
+		},
+		{
+			Name:    "yaml_timeout",
+			CheckID: "check1",
+			Value:   &entities.FactValueInt{Value: 144},
+		},
+		{
+			Name:    "xml_nvpair",
+			CheckID: "check1",
+			Value: &entities.FactValueMap{
+				Value: map[string]entities.FactValue{
+					"id":    &entities.FactValueString{Value: "cib-bootstrap-options-stonith-timeout"},
+					"name":  &entities.FactValueString{Value: "stonith-timeout"},
+					"value": &entities.FactValueInt{Value: 144},
+				},
+			},
+		},
+		{
+			Name:    "ini_section",
+			CheckID: "check1",
+			Value: &entities.FactValueMap{
+				Value: map[string]entities.FactValue{
+					"mode":                   &entities.FactValueString{Value: "primary"},
+					"operation_mode":         &entities.FactValueString{Value: "logreplay"},
+					"enable_log_compression": &entities.FactValueBool{Value: true},
+				},
+			},
+		},
+		{
+			Name:    "ini_key",
+			CheckID: "check1",
+			Value:   &entities.FactValueString{Value: "/hana/data/PRD"},
+		},
+		{
+			Name:    "keyvalue_whole_file",
+			CheckID: "check1",
+			Value: &entities.FactValueMap{
+				Value: map[string]entities.FactValue{
+					"SBD_DEVICE":           &entities.FactValueString{Value: "/dev/disk/by-id/scsi-sbd1;/dev/disk/by-id/scsi-sbd2"},
+					"SBD_PACEMAKER":        &entities.FactValueString{Value: "yes"},
+					"SBD_WATCHDOG_TIMEOUT": &entities.FactValueInt{Value: 15},
+				},
+			},
+		},
+	}
+
+	factResults, err := g.Gather(context.Background(), factRequests)
+	suite.NoError(err)
+	suite.ElementsMatch(expectedResults, factResults)
+}
+
+func (suite *ConfigFileGathererTestSuite) TestConfigFileGatheringDefaultAllowedPaths() {
+	content, err := os.ReadFile(helpers.GetFixturePath("gatherers/config_file/global.ini"))
+	suite.Require().NoError(err)
+	suite.Require().NoError(afero.WriteFile(suite.fs, "/sapmnt/QAS/global/hdb/custom/config/global.ini", content, 0644))
+	suite.links["/usr/sap/QAS/SYS/global/hdb/custom/config/global.ini"] =
+		"/sapmnt/QAS/global/hdb/custom/config/global.ini"
+
+	g := gatherers.NewConfigFileGatherer(suite.fs, suite.evalSymlinks, nil)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "sbd_timeout",
+			Gatherer: "config_file",
+			Argument: "path=/etc/sysconfig/sbd,format=keyvalue,query=SBD_WATCHDOG_TIMEOUT",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "linked_global_ini",
+			Gatherer: "config_file",
+			Argument: "path=/usr/sap/QAS/SYS/global/hdb/custom/config/global.ini,format=ini,query=persistence.basepath_datavolumes",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "not_allowed",
+			Gatherer: "config_file",
+			Argument: "path=/etc/trento/cluster.yaml,format=yaml",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "not_allowed_sysconfig",
+			Gatherer: "config_file",
+			Argument: "path=/etc/sysconfig/network/ifcfg-eth0,format=keyvalue",
+			CheckID:  "check1",
+		},
+	}
+
+	expectedResults := []entities.Fact{
+		{
+			Name:    "sbd_timeout",
+			CheckID: "check1",
+			Value:   &entities.FactValueInt{Value: 15},
+		},
+		{
+			Name:    "linked_global_ini",
+			CheckID: "check1",
+			Value:   &entities.FactValueString{Value: "/hana/data/PRD"},
+		},
+		{
+			Name:    "not_allowed",
+			CheckID: "check1",
+			Error: &entities.FactGatheringError{
+				Type:    "config_file-path-not-allowed",
+				Message: "config file path not included in the allowed paths: /etc/trento/cluster.yaml",
+			},
+		},
+		{
+			Name:    "not_allowed_sysconfig",
+			CheckID: "check1",
+			Error: &entities.FactGatheringError{
+				Type:    "config_file-path-not-allowed",
+				Message: "config file path not included in the allowed paths: /etc/sysconfig/network/ifcfg-eth0",
+			},
+		},
+	}
+
+	factResults, err := g.Gather(context.Background(), factRequests)
+	suite.NoError(err)
+	suite.ElementsMatch(expectedResults, factResults)
+}
+
+func (suite *ConfigFileGathererTestSuite) TestConfigFileGatheringErrors() {
+	suite.Require().NoError(afero.WriteFile(suite.fs, "/etc/trento/broken.json", []byte("{"), 0644))
+
+	suite.links["/etc/trento/shadow.yaml"] = "/etc/shadow"
+
+	g := gatherers.NewConfigFileGatherer(suite.fs, suite.evalSymlinks, []string{"/etc/trento/*", "/etc/sysconfig/*"})
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "missing",
+			Gatherer: "config_file",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "unknown_format",
+			Gatherer: "config_file",
+			Argument: "path=/etc/trento/cluster.yaml,format=toml",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "relative_path",
+			Gatherer: "config_file",
+			Argument: "path=/etc/trento/../shadow,format=keyvalue",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "missing_format",
+			Gatherer: "config_file",
+			Argument: "path=/etc/trento/cluster.yaml",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "file_not_found",
+			Gatherer: "config_file",
+			Argument: "path=/etc/sysconfig/pacemaker,format=keyvalue",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "link_not_allowed",
+			Gatherer: "config_file",
+			Argument: "path=/etc/trento/shadow.yaml,format=yaml",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "parsing",
+			Gatherer: "config_file",
+			Argument: "path=/etc/trento/broken.json,format=json",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "key_not_found",
+			Gatherer: "config_file",
+			Argument: "path=/etc/trento/cluster.yaml,format=yaml,query=cluster.token",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "index_out_of_range",
+			Gatherer: "config_file",
+			Argument: "path=/etc/trento/cluster.yaml,format=yaml,query=cluster.nodes[2]",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "not_a_list",
+			Gatherer: "config_file",
+			Argument: "path=/etc/trento/cluster.yaml,format=yaml,query=cluster.name[0]",
+			CheckID:  "check1",
+		},
+	}
+
+	expectedErrors := map[string]entities.FactGatheringError{
+		"missing": {
+			Type:    "config_file-missing-argument",
+			Message: "missing required argument",
+		},
+		"unknown_format": {
+			Type: "config_file-invalid-argument",
+			Message: "invalid config file argument: invalid format toml, " +
+				"supported values: json, yaml, xml, ini, keyvalue",
+		},
+		"relative_path": {
+			Type:    "config_file-invalid-argument",
+			Message: "invalid config file argument: path /etc/trento/../shadow must be an absolute clean path",
+		},
+		"missing_format": {
+			Type:    "config_file-invalid-argument",
+			Message: "invalid config file argument: path and format options are required",
+		},
+		"file_not_found": {
+			Type:    "config_file-read-error",
+			Message: "error reading config file: open /etc/sysconfig/pacemaker: file does not exist",
+		},
+		"link_not_allowed": {
+			Type:    "config_file-path-not-allowed",
+			Message: "config file path not included in the allowed paths: /etc/trento/shadow.yaml links to /etc/shadow",
+		},
+		"parsing": {
+			Type:    "config_file-parsing-error",
+			Message: "error parsing config file: /etc/trento/broken.json: unexpected EOF",
+		},
+		"key_not_found": {
+			Type:    "config_file-query-error",
+			Message: "error querying config file: /etc/trento/cluster.yaml: key token not found",
+		},
+		"index_out_of_range": {
+			Type:    "config_file-query-error",
+			Message: "error querying config file: /etc/trento/cluster.yaml: index [2] out of range",
+		},
+		"not_a_list": {
+			Type:    "config_file-query-error",
+			Message: "error querying config file: /etc/trento/cluster.yaml: cannot index [0], value is not a list",
+		},
+	}
+
+	factResults, err := g.Gather(context.Background(), factRequests)
+	suite.NoError(err)
+	suite.Len(factResults, len(expectedErrors))
+
+	for _, fact := range factResults {
+		suite.Equal(expectedErrors[fact.Name], *fact.Error, fact.Name)
+	}
+}
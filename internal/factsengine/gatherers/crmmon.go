@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package gatherers
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"log/slog"
+
+	"github.com/trento-project/agent/v3/internal/core/cluster/crmmon"
+	"github.com/trento-project/agent/v3/internal/factsengine/factscache"
+	"github.com/trento-project/agent/v3/pkg/factsengine/entities"
+	"github.com/trento-project/agent/v3/pkg/utils"
+)
+
+const (
+	CrmMonGathererName  = "crm_mon"
+	CrmMonGathererCache = "crm_mon"
+)
+
+//nolint:gochecknoglobals
+var (
+	CrmMonCommandError = entities.FactGatheringError{
+		Type:    "crm_mon-command-error",
+		Message: fmt.Sprintf(errRunningCommandFmt, "crm_mon"),
+	}
+
+	CrmMonDecodingError = entities.FactGatheringError{
+		Type:    "crm_mon-decoding-error",
+		Message: fmt.Sprintf(errDecodingOutputFmt, "crm_mon"),
+	}
+)
+
+// CrmMonGatherer exposes the crm_mon XML output, including the inactive resources,
+// the node attributes and the node history with the failcounts and operations.
+// Arguments are dot separated paths into the XML, e.g. crm_mon.nodes.node.0.online
+type CrmMonGatherer struct {
+	executor utils.CommandExecutor
+	cache    *factscache.FactsCache
+}
+
+func NewDefaultCrmMonGatherer() *CrmMonGatherer {
+	return NewCrmMonGatherer(utils.Executor{}, nil)
+}
+
+func NewCrmMonGatherer(executor utils.CommandExecutor, cache *factscache.FactsCache) *CrmMonGatherer {
+	return &CrmMonGatherer{
+		executor: executor,
+		cache:    cache,
+	}
+}
+
+func (g *CrmMonGatherer) SetCache(cache *factscache.FactsCache) {
+	g.cache = cache
+}
+
+func makeMemoizeCrmMon(ctx context.Context) func(...any) (any, error) {
+	return func(args ...any) (any, error) {
+		executor, ok := args[0].(utils.CommandExecutor)
+		if !ok {
+			return nil, ImplementationError.Wrap("error using memoizeCrmMon. executor must be 1st argument")
+		}
+
+		return executor.OutputContext(ctx, "/usr/sbin/crm_mon", "-X", "--inactive")
+	}
+}
+
+func (g *CrmMonGatherer) Gather(ctx context.Context, factsRequests []entities.FactRequest) ([]entities.Fact, error) {
+	slog.Info("Starting facts gathering process", "gatherer", CrmMonGathererName)
+
+	content, err := factscache.GetOrUpdate(g.cache, CrmMonGathererCache, makeMemoizeCrmMon(ctx), g.executor)
+	if err != nil {
+		return nil, CrmMonCommandError.Wrap(err.Error())
+	}
+
+	crmMonOutput, ok := content.([]byte)
+	if !ok {
+		return nil, CrmMonDecodingError.Wrap("error casting the command output")
+	}
+
+	var root crmmon.Root
+
+	err = xml.Unmarshal(crmMonOutput, &root)
+	if err != nil {
+		return nil, CrmMonDecodingError.Wrap(err.Error())
+	}
+
+	if root.Version == "" {
+		return nil, CrmMonDecodingError.Wrap("crm_mon version not found in the output")
+	}
+
+	elementsToList := map[string]bool{"node": true, "resource": true, "clone": true, "group": true,
+		"attribute": true, "resource_history": true, "operation_history": true, "failure": true,
+		"ban": true, "ticket": true}
+
+	factValueMap, err := parseXMLToFactValueMap(crmMonOutput, elementsToList, entities.WithStringConversion())
+	if err != nil {
+		return nil, CrmMonDecodingError.Wrap(err.Error())
+	}
+
+	facts := []entities.Fact{}
+
+	for _, factReq := range factsRequests {
+		var fact entities.Fact
+
+		value, err := factValueMap.GetValue(factReq.Argument)
+		if err == nil {
+			fact = entities.NewFactGatheredWithRequest(factReq, value)
+		} else {
+			slog.Error(err.Error())
+			fact = entities.NewFactGatheredWithError(factReq, err)
+		}
+
+		facts = append(facts, fact)
+	}
+
+	slog.Info("Requested facts gathered", "gatherer", CrmMonGathererName)
+
+	return facts, nil
+}
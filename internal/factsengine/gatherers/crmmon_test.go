@@ -0,0 +1,264 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package gatherers_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/trento-project/agent/v3/internal/factsengine/factscache"
+	"github.com/trento-project/agent/v3/internal/factsengine/gatherers"
+	"github.com/trento-project/agent/v3/pkg/factsengine/entities"
+	utilsMocks "github.com/trento-project/agent/v3/pkg/utils/mocks"
+	"github.com/trento-project/agent/v3/test/helpers"
+)
+
+type CrmMonTestSuite struct {
+	suite.Suite
+
+	mockExecutor *utilsMocks.MockCommandExecutor
+	crmMonOutput []byte
+}
+
+func TestCrmMonTestSuite(t *testing.T) {
+	suite.Run(t, new(CrmMonTestSuite))
+}
+
+func (suite *CrmMonTestSuite) SetupSuite() {
+	content, err := os.ReadFile(helpers.GetFixturePath("gatherers/crmmon.xml"))
+	suite.Require().NoError(err)
+
+	suite.crmMonOutput = content
+}
+
+func (suite *CrmMonTestSuite) SetupTest() {
+	suite.mockExecutor = new(utilsMocks.MockCommandExecutor)
+}
+
+func (suite *CrmMonTestSuite) TestCrmMonGatherCmdNotFound() {
+	suite.mockExecutor.On("OutputContext", mock.Anything, "/usr/sbin/crm_mon", "-X", "--inactive").Return(
+		nil, errors.New("crm_mon not found"))
+
+	p := gatherers.NewCrmMonGatherer(suite.mockExecutor, nil)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "crm_mon",
+			Gatherer: "crm_mon",
+			Argument: "crm_mon",
+			CheckID:  "check1",
+		},
+	}
+
+	_, err := p.Gather(context.Background(), factRequests)
+
+	suite.Require().EqualError(err, "fact gathering error: crm_mon-command-error - "+
+		"error running crm_mon command: crm_mon not found")
+}
+
+func (suite *CrmMonTestSuite) TestCrmMonInvalidXML() {
+	suite.mockExecutor.On("OutputContext", mock.Anything, "/usr/sbin/crm_mon", "-X", "--inactive").Return(
+		[]byte("invalid"), nil)
+
+	p := gatherers.NewCrmMonGatherer(suite.mockExecutor, nil)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "crm_mon",
+			Gatherer: "crm_mon",
+			Argument: "crm_mon",
+			CheckID:  "check1",
+		},
+	}
+
+	_, err := p.Gather(context.Background(), factRequests)
+
+	suite.Require().EqualError(err, "fact gathering error: crm_mon-decoding-error - "+
+		"error decoding crm_mon output: EOF")
+}
+
+func (suite *CrmMonTestSuite) TestCrmMonNotCrmMonOutput() {
+	suite.mockExecutor.On("OutputContext", mock.Anything, "/usr/sbin/crm_mon", "-X", "--inactive").Return(
+		[]byte("<cib><configuration/></cib>"), nil)
+
+	p := gatherers.NewCrmMonGatherer(suite.mockExecutor, nil)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "crm_mon",
+			Gatherer: "crm_mon",
+			Argument: "crm_mon",
+			CheckID:  "check1",
+		},
+	}
+
+	_, err := p.Gather(context.Background(), factRequests)
+
+	suite.Require().EqualError(err, "fact gathering error: crm_mon-decoding-error - "+
+		"error decoding crm_mon output: crm_mon version not found in the output")
+}
+
+func (suite *CrmMonTestSuite) TestCrmMonGather() {
+	suite.mockExecutor.On("OutputContext", mock.Anything, "/usr/sbin/crm_mon", "-X", "--inactive").Return(
+		suite.crmMonOutput, nil)
+
+	p := gatherers.NewCrmMonGatherer(suite.mockExecutor, nil)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "node_online",
+			Gatherer: "crm_mon",
+			Argument: "crm_mon.nodes.node.0.online",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "stonith_role",
+			Gatherer: "crm_mon",
+			Argument: "crm_mon.resources.resource.2.role",
+			CheckID:  "check2",
+		},
+		{
+			Name:     "failcount",
+			Gatherer: "crm_mon",
+			Argument: "crm_mon.node_history.node.0.resource_history.1.fail-count",
+			CheckID:  "check3",
+		},
+		{
+			Name:     "operation",
+			Gatherer: "crm_mon",
+			Argument: "crm_mon.node_history.node.0.resource_history.2.operation_history.0",
+			CheckID:  "check4",
+		},
+		{
+			Name:     "attribute",
+			Gatherer: "crm_mon",
+			Argument: "crm_mon.node_attributes.node.1.attribute.6.value",
+			CheckID:  "check5",
+		},
+		{
+			Name:     "not_found",
+			Gatherer: "crm_mon",
+			Argument: "crm_mon.not_found",
+			CheckID:  "check6",
+		},
+	}
+
+	factResults, err := p.Gather(context.Background(), factRequests)
+
+	expectedResults := []entities.Fact{
+		{
+			Name:    "node_online",
+			Value:   &entities.FactValueBool{Value: true},
+			CheckID: "check1",
+		},
+		{
+			Name:    "stonith_role",
+			Value:   &entities.FactValueString{Value: "Started"},
+			CheckID: "check2",
+		},
+		{
+			Name:    "failcount",
+			Value:   &entities.FactValueInt{Value: 2},
+			CheckID: "check3",
+		},
+		{
+			Name: "operation",
+			Value: &entities.FactValueMap{
+				Value: map[string]entities.FactValue{
+					"call":           &entities.FactValueInt{Value: 6},
+					"task":           &entities.FactValueString{Value: "start"},
+					"last-rc-change": &entities.FactValueString{Value: "Thu Oct 10 12:57:31 2019"},
+					"last-run":       &entities.FactValueString{Value: "Thu Oct 10 12:57:31 2019"},
+					"exec-time":      &entities.FactValueFloat{Value: 2.201},
+					"queue-time":     &entities.FactValueInt{Value: 0},
+					"rc":             &entities.FactValueInt{Value: 0},
+					"rc_text":        &entities.FactValueString{Value: "ok"},
+				},
+			},
+			CheckID: "check4",
+		},
+		{
+			Name:    "attribute",
+			Value:   &entities.FactValueString{Value: "SOK"},
+			CheckID: "check5",
+		},
+		{
+			Name:    "not_found",
+			Value:   nil,
+			CheckID: "check6",
+			Error: &entities.FactGatheringError{
+				Type:    "value-not-found",
+				Message: "error getting value: requested field value not found: crm_mon.not_found",
+			},
+		},
+	}
+
+	suite.Require().NoError(err)
+	suite.ElementsMatch(expectedResults, factResults)
+}
+
+func (suite *CrmMonTestSuite) TestCrmMonGatherWithCache() {
+	suite.mockExecutor.On("OutputContext", mock.Anything, "/usr/sbin/crm_mon", "-X", "--inactive").
+		Return(suite.crmMonOutput, nil).
+		Once()
+
+	cache := factscache.NewFactsCache()
+
+	p := gatherers.NewCrmMonGatherer(suite.mockExecutor, cache)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "version",
+			Gatherer: "crm_mon",
+			Argument: "crm_mon.version",
+			CheckID:  "check1",
+		},
+	}
+
+	expectedResults := []entities.Fact{
+		{
+			Name:    "version",
+			Value:   &entities.FactValueString{Value: "2.0.0"},
+			CheckID: "check1",
+		},
+	}
+
+	factResults, err := p.Gather(context.Background(), factRequests)
+	suite.Require().NoError(err)
+	suite.ElementsMatch(expectedResults, factResults)
+
+	_, err = p.Gather(context.Background(), factRequests)
+	suite.Require().NoError(err)
+
+	entries := cache.Entries()
+	suite.ElementsMatch([]string{"crm_mon"}, entries)
+}
+
+func (suite *CrmMonTestSuite) TestCrmMonGatherCacheCastingError() {
+	cache := factscache.NewFactsCache()
+	_, err := cache.GetOrUpdate("crm_mon", func(_ ...any) (any, error) {
+		return 1, nil
+	})
+	suite.Require().NoError(err)
+
+	p := gatherers.NewCrmMonGatherer(suite.mockExecutor, cache)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "crm_mon",
+			Gatherer: "crm_mon",
+			Argument: "",
+			CheckID:  "check1",
+		},
+	}
+
+	_, err = p.Gather(context.Background(), factRequests)
+
+	suite.Require().EqualError(err, "fact gathering error: crm_mon-decoding-error - "+
+		"error decoding crm_mon output: error casting the command output")
+}
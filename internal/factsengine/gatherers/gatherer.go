@@ -37,7 +37,9 @@ type FactGathererWithCache interface {
 }
 
 type Config struct {
-	AgentID string
+	AgentID                string
+	ConfigFileAllowedPaths []string
+	HdbsqlUserstoreKey     string
 }
 
 func StandardGatherers(config Config) FactGatherersTree {
@@ -45,15 +47,27 @@ func StandardGatherers(config Config) FactGatherersTree {
 		AscsErsClusterGathererName: map[string]FactGatherer{
 			"v1": NewDefaultAscsErsClusterGatherer(),
 		},
+		BlockDevicesGathererName: map[string]FactGatherer{
+			"v1": NewDefaultBlockDevicesGatherer(),
+		},
+		CertificatesGathererName: map[string]FactGatherer{
+			"v1": NewDefaultCertificatesGatherer(),
+		},
 		CibAdminGathererName: map[string]FactGatherer{
 			"v1": NewDefaultCibAdminGatherer(),
 		},
+		ConfigFileGathererName: map[string]FactGatherer{
+			"v1": NewDefaultConfigFileGatherer(config.ConfigFileAllowedPaths),
+		},
 		CorosyncCmapCtlGathererName: map[string]FactGatherer{
 			"v1": NewDefaultCorosyncCmapctlGatherer(),
 		},
 		CorosyncConfGathererName: map[string]FactGatherer{
 			"v1": NewDefaultCorosyncConfGatherer(),
 		},
+		CrmMonGathererName: map[string]FactGatherer{
+			"v1": NewDefaultCrmMonGatherer(),
+		},
 		DirScanGathererName: map[string]FactGatherer{
 			"v1": NewDefaultDirScanGatherer(),
 		},
@@ -69,15 +83,24 @@ func StandardGatherers(config Config) FactGatherersTree {
 		GroupsGathererName: map[string]FactGatherer{
 			"v1": NewDefaultGroupsGatherer(),
 		},
+		HdbsqlGathererName: map[string]FactGatherer{
+			"v1": NewDefaultHdbsqlGatherer(config.HdbsqlUserstoreKey),
+		},
 		HostsFileGathererName: map[string]FactGatherer{
 			"v1": NewDefaultHostsFileGatherer(),
 		},
 		IniFilesGathererName: map[string]FactGatherer{
 			"v1": NewDefaultIniFilesGatherer(),
 		},
+		JournalGathererName: map[string]FactGatherer{
+			"v1": NewDefaultJournalGatherer(),
+		},
 		MountInfoGathererName: map[string]FactGatherer{
 			"v1": NewDefaultMountInfoGatherer(),
 		},
+		NetworkGathererName: map[string]FactGatherer{
+			"v1": NewDefaultNetworkGatherer(),
+		},
 		OSReleaseGathererName: map[string]FactGatherer{
 			"v1": NewDefaultOSReleaseGatherer(),
 		},
@@ -127,6 +150,9 @@ func StandardGatherers(config Config) FactGatherersTree {
 			"v1": NewDefaultSystemDGatherer(),
 			"v2": NewDefaultSystemDGathererV2(),
 		},
+		TimeSyncGathererName: map[string]FactGatherer{
+			"v1": NewDefaultTimeSyncGatherer(),
+		},
 		VerifyPasswordGathererName: map[string]FactGatherer{
 			"v1": NewDefaultPasswordGatherer(),
 		},
@@ -0,0 +1,281 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package gatherers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/trento-project/agent/v3/internal/core/sapsystem"
+	"github.com/trento-project/agent/v3/pkg/factsengine/entities"
+	"github.com/trento-project/agent/v3/pkg/utils"
+)
+
+const (
+	HdbsqlGathererName = "hdbsql"
+
+	// DefaultHdbsqlUserstoreKey is the hdbuserstore key used when none is configured
+	DefaultHdbsqlUserstoreKey = "TRENTO"
+
+	hdbsqlUnknownQueryMsg = "requested query not supported"
+	hdbsqlFileSystemMsg   = "error reading the file system"
+	hdbsqlInvalidKeyMsg   = "invalid hdbuserstore key"
+	hdbsqlDefaultTimeout  = 30 * time.Second
+)
+
+type hdbsqlQuery struct {
+	statement string
+	timeout   time.Duration
+}
+
+// hdbsqlQueries is the curated list of queries that can be requested by fact argument.
+// Statements must not contain double quotes, as they are quoted in the su command.
+//
+//nolint:gochecknoglobals
+var hdbsqlQueries = map[string]hdbsqlQuery{
+	"inifile_contents": {
+		statement: "SELECT FILE_NAME, LAYER_NAME, TENANT_NAME, HOST, SECTION, KEY, VALUE FROM M_INIFILE_CONTENTS",
+		timeout:   hdbsqlDefaultTimeout,
+	},
+	"system_replication": {
+		statement: "SELECT SITE_ID, SITE_NAME, SECONDARY_SITE_ID, SECONDARY_SITE_NAME, " +
+			"REPLICATION_MODE, OPERATION_MODE, REPLICATION_STATUS FROM M_SYSTEM_REPLICATION",
+		timeout: 10 * time.Second,
+	},
+	"services": {
+		statement: "SELECT HOST, PORT, SERVICE_NAME, PROCESS_ID, ACTIVE_STATUS, COORDINATOR_TYPE FROM M_SERVICES",
+		timeout:   10 * time.Second,
+	},
+	"license": {
+		statement: "SELECT HARDWARE_KEY, SYSTEM_ID, INSTALL_NO, PRODUCT_NAME, PRODUCT_LIMIT, " +
+			"PERMANENT, VALID, EXPIRATION_DATE FROM M_LICENSE",
+		timeout: 10 * time.Second,
+	},
+}
+
+//nolint:gochecknoglobals
+var (
+	HdbsqlMissingArgument = entities.FactGatheringError{
+		Type:    "hdbsql-missing-argument",
+		Message: missingRequiredArgument,
+	}
+
+	HdbsqlUnknownQueryError = entities.FactGatheringError{
+		Type:    "hdbsql-unknown-query",
+		Message: hdbsqlUnknownQueryMsg,
+	}
+
+	HdbsqlFileSystemError = entities.FactGatheringError{
+		Type:    "hdbsql-file-system-error",
+		Message: hdbsqlFileSystemMsg,
+	}
+
+	HdbsqlInvalidKeyError = entities.FactGatheringError{
+		Type:    "hdbsql-invalid-key",
+		Message: hdbsqlInvalidKeyMsg,
+	}
+
+	HdbsqlCommandError = entities.FactGatheringError{
+		Type:    "hdbsql-command-error",
+		Message: fmt.Sprintf(errRunningCommandFmt, "hdbsql"),
+	}
+
+	HdbsqlDecodingError = entities.FactGatheringError{
+		Type:    "hdbsql-decoding-error",
+		Message: fmt.Sprintf(errDecodingOutputFmt, "hdbsql"),
+	}
+
+	hdbsqlUserstoreKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+)
+
+// HdbsqlGatherer runs curated queries in the HANA databases installed in the host.
+// The argument is the query name, and the result is a map with the rows returned by each
+// database, keyed by SID. Queries run as <sid>adm using the configured hdbuserstore key.
+type HdbsqlGatherer struct {
+	fs           afero.Fs
+	executor     utils.CommandExecutor
+	userstoreKey string
+}
+
+type hanaDatabase struct {
+	sid        string
+	hdbsqlPath string
+}
+
+func NewDefaultHdbsqlGatherer(userstoreKey string) *HdbsqlGatherer {
+	return NewHdbsqlGatherer(afero.NewOsFs(), utils.Executor{}, userstoreKey)
+}
+
+func NewHdbsqlGatherer(fs afero.Fs, executor utils.CommandExecutor, userstoreKey string) *HdbsqlGatherer {
+	if userstoreKey == "" {
+		userstoreKey = DefaultHdbsqlUserstoreKey
+	}
+
+	return &HdbsqlGatherer{
+		fs:           fs,
+		executor:     executor,
+		userstoreKey: userstoreKey,
+	}
+}
+
+func (g *HdbsqlGatherer) Gather(ctx context.Context, factsRequests []entities.FactRequest) ([]entities.Fact, error) {
+	slog.Info("Starting facts gathering process", "gatherer", HdbsqlGathererName)
+
+	if !hdbsqlUserstoreKeyPattern.MatchString(g.userstoreKey) {
+		return nil, HdbsqlInvalidKeyError.Wrap(g.userstoreKey)
+	}
+
+	databases, err := findHanaDatabases(g.fs)
+	if err != nil {
+		return nil, HdbsqlFileSystemError.Wrap(err.Error())
+	}
+
+	facts := make([]entities.Fact, 0, len(factsRequests))
+
+	for _, factReq := range factsRequests {
+		var fact entities.Fact
+
+		factValue, err := g.runQuery(ctx, databases, factReq.Argument)
+
+		switch {
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case err != nil:
+			slog.Error(err.Error())
+			fact = entities.NewFactGatheredWithError(factReq, err)
+		default:
+			fact = entities.NewFactGatheredWithRequest(factReq, factValue)
+		}
+
+		facts = append(facts, fact)
+	}
+
+	slog.Info("Requested facts gathered", "gatherer", HdbsqlGathererName)
+
+	return facts, nil
+}
+
+func (g *HdbsqlGatherer) runQuery(
+	ctx context.Context,
+	databases []hanaDatabase,
+	queryName string,
+) (entities.FactValue, *entities.FactGatheringError) {
+	if queryName == "" {
+		return nil, &HdbsqlMissingArgument
+	}
+
+	query, found := hdbsqlQueries[queryName]
+	if !found {
+		return nil, HdbsqlUnknownQueryError.Wrap(queryName)
+	}
+
+	results := make(map[string]entities.FactValue, len(databases))
+
+	for _, database := range databases {
+		output, err := g.executeHdbsql(ctx, database, query)
+		if err != nil {
+			return nil, HdbsqlCommandError.Wrap(fmt.Sprintf("%s: %s", database.sid, err))
+		}
+
+		rows, err := parseHdbsqlOutput(output)
+		if err != nil {
+			return nil, HdbsqlDecodingError.Wrap(fmt.Sprintf("%s: %s", database.sid, err))
+		}
+
+		results[database.sid] = rows
+	}
+
+	return &entities.FactValueMap{Value: results}, nil
+}
+
+func (g *HdbsqlGatherer) executeHdbsql(
+	ctx context.Context,
+	database hanaDatabase,
+	query hdbsqlQuery,
+) ([]byte, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, query.timeout)
+	defer cancel()
+
+	user := strings.ToLower(database.sid) + "adm"
+	cmd := fmt.Sprintf("%s -U %s -x -j \"%s\"", database.hdbsqlPath, g.userstoreKey, query.statement)
+
+	output, err := g.executor.OutputContext(queryCtx, "/usr/bin/su", "-lc", cmd, user)
+	if errors.Is(queryCtx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("query timed out after %s", query.timeout)
+	}
+
+	return output, err
+}
+
+// findHanaDatabases returns the HANA databases installed in the host, identified
+// by the HDB instance folder in the /usr/sap/${SID} folder
+func findHanaDatabases(fs afero.Fs) ([]hanaDatabase, error) {
+	systemPaths, err := sapsystem.FindSystems(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	databases := []hanaDatabase{}
+
+	for _, systemPath := range systemPaths {
+		instances, err := sapsystem.FindInstances(fs, systemPath)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, instance := range instances {
+			if !strings.HasPrefix(instance[0], "HDB") {
+				continue
+			}
+
+			databases = append(databases, hanaDatabase{
+				sid:        filepath.Base(systemPath),
+				hdbsqlPath: path.Join(systemPath, instance[0], "exe", "hdbsql"),
+			})
+
+			break
+		}
+	}
+
+	return databases, nil
+}
+
+// parseHdbsqlOutput parses the hdbsql CSV output, where the first line has the column names,
+// into a list of maps with the lowercased column names as keys
+func parseHdbsqlOutput(output []byte) (*entities.FactValueList, error) {
+	records, err := csv.NewReader(bytes.NewReader(output)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []entities.FactValue{}
+
+	if len(records) == 0 {
+		return &entities.FactValueList{Value: rows}, nil
+	}
+
+	columns := records[0]
+
+	for _, record := range records[1:] {
+		row := make(map[string]entities.FactValue, len(columns))
+
+		for index, column := range columns {
+			row[strings.ToLower(column)] = entities.ParseStringToFactValue(record[index])
+		}
+
+		rows = append(rows, &entities.FactValueMap{Value: row})
+	}
+
+	return &entities.FactValueList{Value: rows}, nil
+}
@@ -0,0 +1,260 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package gatherers_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/trento-project/agent/v3/internal/factsengine/gatherers"
+	"github.com/trento-project/agent/v3/pkg/factsengine/entities"
+	utilsMocks "github.com/trento-project/agent/v3/pkg/utils/mocks"
+	"github.com/trento-project/agent/v3/test/helpers"
+)
+
+const hdbsqlServicesCommand = "/usr/sap/PRD/HDB00/exe/hdbsql -U TRENTO -x -j " +
+	"\"SELECT HOST, PORT, SERVICE_NAME, PROCESS_ID, ACTIVE_STATUS, COORDINATOR_TYPE FROM M_SERVICES\""
+
+type HdbsqlGathererTestSuite struct {
+	suite.Suite
+
+	fs           afero.Fs
+	mockExecutor *utilsMocks.MockCommandExecutor
+}
+
+func TestHdbsqlGathererSuite(t *testing.T) {
+	suite.Run(t, new(HdbsqlGathererTestSuite))
+}
+
+func (suite *HdbsqlGathererTestSuite) SetupTest() {
+	fs := afero.NewMemMapFs()
+	err := fs.MkdirAll("/usr/sap/PRD/HDB00", 0644)
+	suite.Require().NoError(err)
+	err = fs.MkdirAll("/usr/sap/NWP/ASCS01", 0644)
+	suite.Require().NoError(err)
+
+	suite.fs = fs
+	suite.mockExecutor = new(utilsMocks.MockCommandExecutor)
+}
+
+func (suite *HdbsqlGathererTestSuite) TestHdbsqlGatheringSuccess() {
+	output, err := os.ReadFile(helpers.GetFixturePath("gatherers/hdbsql-services.output"))
+	suite.Require().NoError(err)
+
+	suite.mockExecutor.
+		On("OutputContext", mock.Anything, "/usr/bin/su", "-lc", hdbsqlServicesCommand, "prdadm").
+		Return(output, nil)
+
+	g := gatherers.NewHdbsqlGatherer(suite.fs, suite.mockExecutor, "")
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "services",
+			Gatherer: "hdbsql",
+			Argument: "services",
+			CheckID:  "check1",
+		},
+	}
+
+	expectedResults := []entities.Fact{
+		{
+			Name:    "services",
+			CheckID: "check1",
+			Value: &entities.FactValueMap{
+				Value: map[string]entities.FactValue{
+					"PRD": &entities.FactValueList{
+						Value: []entities.FactValue{
+							&entities.FactValueMap{
+								Value: map[string]entities.FactValue{
+									"host":             &entities.FactValueString{Value: "vmhana01"},
+									"port":             &entities.FactValueInt{Value: 30001},
+									"service_name":     &entities.FactValueString{Value: "nameserver"},
+									"process_id":       &entities.FactValueInt{Value: 4811},
+									"active_status":    &entities.FactValueString{Value: "YES"},
+									"coordinator_type": &entities.FactValueString{Value: "MASTER"},
+								},
+							},
+							&entities.FactValueMap{
+								Value: map[string]entities.FactValue{
+									"host":             &entities.FactValueString{Value: "vmhana01"},
+									"port":             &entities.FactValueInt{Value: 30003},
+									"service_name":     &entities.FactValueString{Value: "indexserver"},
+									"process_id":       &entities.FactValueInt{Value: 5120},
+									"active_status":    &entities.FactValueString{Value: "YES"},
+									"coordinator_type": &entities.FactValueString{Value: "MASTER"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	factResults, err := g.Gather(context.Background(), factRequests)
+	suite.NoError(err)
+	suite.ElementsMatch(expectedResults, factResults)
+}
+
+func (suite *HdbsqlGathererTestSuite) TestHdbsqlGatheringCustomKey() {
+	suite.mockExecutor.
+		On("OutputContext", mock.Anything, "/usr/bin/su", "-lc",
+			"/usr/sap/PRD/HDB00/exe/hdbsql -U MONITORING -x -j "+
+				"\"SELECT HARDWARE_KEY, SYSTEM_ID, INSTALL_NO, PRODUCT_NAME, PRODUCT_LIMIT, "+
+				"PERMANENT, VALID, EXPIRATION_DATE FROM M_LICENSE\"",
+			"prdadm").
+		Return([]byte("HARDWARE_KEY,SYSTEM_ID,INSTALL_NO,PRODUCT_NAME,PRODUCT_LIMIT,PERMANENT,VALID,EXPIRATION_DATE\n"), nil)
+
+	g := gatherers.NewHdbsqlGatherer(suite.fs, suite.mockExecutor, "MONITORING")
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "license",
+			Gatherer: "hdbsql",
+			Argument: "license",
+			CheckID:  "check1",
+		},
+	}
+
+	expectedResults := []entities.Fact{
+		{
+			Name:    "license",
+			CheckID: "check1",
+			Value: &entities.FactValueMap{
+				Value: map[string]entities.FactValue{
+					"PRD": &entities.FactValueList{Value: []entities.FactValue{}},
+				},
+			},
+		},
+	}
+
+	factResults, err := g.Gather(context.Background(), factRequests)
+	suite.NoError(err)
+	suite.ElementsMatch(expectedResults, factResults)
+}
+
+func (suite *HdbsqlGathererTestSuite) TestHdbsqlGatheringErrors() {
+	suite.mockExecutor.
+		On("OutputContext", mock.Anything, "/usr/bin/su", "-lc", hdbsqlServicesCommand, "prdadm").
+		Return(nil, errors.New("* 10: invalid username or password"))
+
+	g := gatherers.NewHdbsqlGatherer(suite.fs, suite.mockExecutor, "")
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "missing",
+			Gatherer: "hdbsql",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "arbitrary",
+			Gatherer: "hdbsql",
+			Argument: "SELECT * FROM USERS",
+			CheckID:  "check2",
+		},
+		{
+			Name:     "services",
+			Gatherer: "hdbsql",
+			Argument: "services",
+			CheckID:  "check3",
+		},
+	}
+
+	expectedResults := []entities.Fact{
+		{
+			Name:    "missing",
+			CheckID: "check1",
+			Error: &entities.FactGatheringError{
+				Type:    "hdbsql-missing-argument",
+				Message: "missing required argument",
+			},
+		},
+		{
+			Name:    "arbitrary",
+			CheckID: "check2",
+			Error: &entities.FactGatheringError{
+				Type:    "hdbsql-unknown-query",
+				Message: "requested query not supported: SELECT * FROM USERS",
+			},
+		},
+		{
+			Name:    "services",
+			CheckID: "check3",
+			Error: &entities.FactGatheringError{
+				Type:    "hdbsql-command-error",
+				Message: "error running hdbsql command: PRD: * 10: invalid username or password",
+			},
+		},
+	}
+
+	factResults, err := g.Gather(context.Background(), factRequests)
+	suite.NoError(err)
+	suite.ElementsMatch(expectedResults, factResults)
+}
+
+func (suite *HdbsqlGathererTestSuite) TestHdbsqlGatheringDecodingError() {
+	suite.mockExecutor.
+		On("OutputContext", mock.Anything, "/usr/bin/su", "-lc", hdbsqlServicesCommand, "prdadm").
+		Return([]byte("HOST,PORT\n\"vmhana01\"\n"), nil)
+
+	g := gatherers.NewHdbsqlGatherer(suite.fs, suite.mockExecutor, "")
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "services",
+			Gatherer: "hdbsql",
+			Argument: "services",
+			CheckID:  "check1",
+		},
+	}
+
+	factResults, err := g.Gather(context.Background(), factRequests)
+	suite.NoError(err)
+	suite.Equal("hdbsql-decoding-error", factResults[0].Error.Type)
+}
+
+func (suite *HdbsqlGathererTestSuite) TestHdbsqlGatheringInvalidKey() {
+	g := gatherers.NewHdbsqlGatherer(suite.fs, suite.mockExecutor, "KEY; rm -rf /")
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "services",
+			Gatherer: "hdbsql",
+			Argument: "services",
+			CheckID:  "check1",
+		},
+	}
+
+	_, err := g.Gather(context.Background(), factRequests)
+	suite.EqualError(err, "fact gathering error: hdbsql-invalid-key - invalid hdbuserstore key: KEY; rm -rf /")
+}
+
+func (suite *HdbsqlGathererTestSuite) TestHdbsqlGatheringContextCancelled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	suite.mockExecutor.
+		On("OutputContext", mock.Anything, "/usr/bin/su", "-lc", hdbsqlServicesCommand, "prdadm").
+		Return(nil, context.Canceled)
+
+	g := gatherers.NewHdbsqlGatherer(suite.fs, suite.mockExecutor, "")
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "services",
+			Gatherer: "hdbsql",
+			Argument: "services",
+			CheckID:  "check1",
+		},
+	}
+
+	factResults, err := g.Gather(ctx, factRequests)
+	suite.Error(err)
+	suite.Empty(factResults)
+}
@@ -0,0 +1,355 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package gatherers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trento-project/agent/v3/pkg/factsengine/entities"
+	"github.com/trento-project/agent/v3/pkg/utils"
+)
+
+const (
+	JournalGathererName = "journal"
+
+	journalDefaultMaxEntries = 100
+	journalMaxEntriesLimit   = 1000
+	journalOutputEntries     = "entries"
+	journalOutputCount       = "count"
+)
+
+//nolint:gochecknoglobals
+var (
+	JournalMissingArgument = entities.FactGatheringError{
+		Type:    "journal-missing-argument",
+		Message: missingRequiredArgument,
+	}
+
+	JournalInvalidArgument = entities.FactGatheringError{
+		Type:    "journal-invalid-argument",
+		Message: "invalid journal argument",
+	}
+
+	JournalCommandError = entities.FactGatheringError{
+		Type:    "journal-command-error",
+		Message: fmt.Sprintf(errRunningCommandFmt, "journalctl"),
+	}
+
+	JournalDecodingError = entities.FactGatheringError{
+		Type:    "journal-decoding-error",
+		Message: fmt.Sprintf(errDecodingOutputFmt, "journalctl"),
+	}
+
+	journalUnitPattern = regexp.MustCompile(`^[A-Za-z0-9@:._\\-]+$`)
+	journalPriorities  = []string{"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug"}
+)
+
+// JournalGatherer queries the systemd journal using journalctl.
+// The argument is a comma separated list of key=value filters:
+//   - unit: systemd unit the entries belong to
+//   - priority: maximum priority of the entries, by name (err, warning...) or number
+//   - since: how far back to look, as a duration like 24h
+//   - grep: pattern the message must match. Commas are not supported
+//   - max_entries: maximum number of entries returned, the most recent first. Defaults to 100
+//   - output: entries (default) returns the list of entries, count returns the number of matching entries.
+//     The count is not limited by max_entries, so it requires since to bound the queried journal
+//
+// Example: unit=corosync.service,since=24h,grep=retransmit,output=count
+type JournalGatherer struct {
+	executor utils.CommandExecutor
+}
+
+type journalQuery struct {
+	unit       string
+	priority   string
+	since      time.Duration
+	grep       string
+	maxEntries int
+	output     string
+}
+
+func NewDefaultJournalGatherer() *JournalGatherer {
+	return NewJournalGatherer(utils.Executor{})
+}
+
+func NewJournalGatherer(executor utils.CommandExecutor) *JournalGatherer {
+	return &JournalGatherer{
+		executor: executor,
+	}
+}
+
+func (g *JournalGatherer) Gather(ctx context.Context, factsRequests []entities.FactRequest) ([]entities.Fact, error) {
+	slog.Info("Starting facts gathering process", "gatherer", JournalGathererName)
+
+	facts := make([]entities.Fact, 0, len(factsRequests))
+
+	for _, factReq := range factsRequests {
+		var fact entities.Fact
+
+		factValue, err := g.gatherJournal(ctx, factReq.Argument)
+
+		switch {
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case err != nil:
+			slog.Error(err.Error())
+			fact = entities.NewFactGatheredWithError(factReq, err)
+		default:
+			fact = entities.NewFactGatheredWithRequest(factReq, factValue)
+		}
+
+		facts = append(facts, fact)
+	}
+
+	slog.Info("Requested facts gathered", "gatherer", JournalGathererName)
+
+	return facts, nil
+}
+
+func (g *JournalGatherer) gatherJournal(
+	ctx context.Context,
+	argument string,
+) (entities.FactValue, *entities.FactGatheringError) {
+	if argument == "" {
+		return nil, &JournalMissingArgument
+	}
+
+	query, err := parseJournalQuery(argument)
+	if err != nil {
+		return nil, JournalInvalidArgument.Wrap(err.Error())
+	}
+
+	output, err := g.executor.OutputContext(ctx, "/usr/bin/journalctl", query.commandArgs()...)
+
+	var exitErr *exec.ExitError
+
+	switch {
+	// journalctl exits with 1 when the grep pattern doesn't match any entry
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && len(output) == 0:
+	case err != nil:
+		return nil, JournalCommandError.Wrap(err.Error())
+	}
+
+	if query.output == journalOutputCount {
+		return &entities.FactValueInt{Value: countJournalEntries(output)}, nil
+	}
+
+	entries, err := parseJournalEntries(output)
+	if err != nil {
+		return nil, JournalDecodingError.Wrap(err.Error())
+	}
+
+	if len(entries) > query.maxEntries {
+		entries = entries[:query.maxEntries]
+	}
+
+	return &entities.FactValueList{Value: entries}, nil
+}
+
+func parseJournalQuery(argument string) (*journalQuery, error) {
+	query := &journalQuery{
+		maxEntries: journalDefaultMaxEntries,
+		output:     journalOutputEntries,
+	}
+
+	for _, filter := range strings.Split(argument, ",") {
+		key, value, found := strings.Cut(filter, "=")
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		if !found || value == "" {
+			return nil, fmt.Errorf("filter %s must be a key=value pair", filter)
+		}
+
+		switch key {
+		case "unit":
+			if !journalUnitPattern.MatchString(value) {
+				return nil, fmt.Errorf("invalid unit %s", value)
+			}
+
+			query.unit = value
+		case "priority":
+			if !isJournalPriority(value) {
+				return nil, fmt.Errorf("invalid priority %s, supported values: %s or 0-7",
+					value, strings.Join(journalPriorities, ", "))
+			}
+
+			query.priority = value
+		case "since":
+			since, err := time.ParseDuration(value)
+			if err != nil || since <= 0 {
+				return nil, fmt.Errorf("invalid since %s, a positive duration like 24h expected", value)
+			}
+
+			query.since = since
+		case "grep":
+			query.grep = value
+		case "max_entries":
+			maxEntries, err := strconv.Atoi(value)
+			if err != nil || maxEntries <= 0 || maxEntries > journalMaxEntriesLimit {
+				return nil, fmt.Errorf("invalid max_entries %s, a number between 1 and %d expected",
+					value, journalMaxEntriesLimit)
+			}
+
+			query.maxEntries = maxEntries
+		case "output":
+			if value != journalOutputEntries && value != journalOutputCount {
+				return nil, fmt.Errorf("invalid output %s, supported values: %s, %s",
+					value, journalOutputEntries, journalOutputCount)
+			}
+
+			query.output = value
+		default:
+			return nil, fmt.Errorf("unknown filter %s", key)
+		}
+	}
+
+	if query.output == journalOutputCount && query.since == 0 {
+		return nil, errors.New("since is required with output=count")
+	}
+
+	return query, nil
+}
+
+func isJournalPriority(value string) bool {
+	number, err := strconv.Atoi(value)
+	if err == nil {
+		return number >= 0 && number < len(journalPriorities)
+	}
+
+	return slices.Contains(journalPriorities, value)
+}
+
+func (q *journalQuery) commandArgs() []string {
+	args := []string{"--output=json", "--no-pager", "--quiet", "--reverse"}
+
+	if q.unit != "" {
+		args = append(args, "--unit="+q.unit)
+	}
+
+	if q.priority != "" {
+		args = append(args, "--priority="+q.priority)
+	}
+
+	if q.since != 0 {
+		args = append(args, fmt.Sprintf("--since=-%ds", int(q.since.Seconds())))
+	}
+
+	if q.grep != "" {
+		args = append(args, "--grep="+q.grep)
+	}
+
+	// the count needs all the matching entries
+	if q.output == journalOutputEntries {
+		args = append(args, fmt.Sprintf("--lines=%d", q.maxEntries))
+	}
+
+	return args
+}
+
+// countJournalEntries counts the entries in the journalctl json output, one entry per line,
+// without decoding them
+func countJournalEntries(output []byte) int {
+	count := 0
+
+	for line := range bytes.Lines(output) {
+		if len(bytes.TrimSpace(line)) != 0 {
+			count++
+		}
+	}
+
+	return count
+}
+
+// parseJournalEntries parses the journalctl json output, one entry per line,
+// keeping the fields relevant for the checks
+func parseJournalEntries(output []byte) ([]entities.FactValue, error) {
+	entries := []entities.FactValue{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var fields map[string]any
+
+		err := json.Unmarshal(line, &fields)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, journalEntryToFactValue(fields))
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func journalEntryToFactValue(fields map[string]any) entities.FactValue {
+	entry := map[string]entities.FactValue{
+		"message":           &entities.FactValueString{Value: journalFieldString(fields["MESSAGE"])},
+		"unit":              &entities.FactValueString{Value: journalFieldString(fields["_SYSTEMD_UNIT"])},
+		"syslog_identifier": &entities.FactValueString{Value: journalFieldString(fields["SYSLOG_IDENTIFIER"])},
+		"hostname":          &entities.FactValueString{Value: journalFieldString(fields["_HOSTNAME"])},
+	}
+
+	priority, err := strconv.Atoi(journalFieldString(fields["PRIORITY"]))
+	if err == nil {
+		entry["priority"] = &entities.FactValueInt{Value: priority}
+	}
+
+	// realtime timestamp in microseconds since epoch
+	timestamp, err := strconv.ParseInt(journalFieldString(fields["__REALTIME_TIMESTAMP"]), 10, 64)
+	if err == nil {
+		entry["timestamp"] = &entities.FactValueString{
+			Value: time.UnixMicro(timestamp).UTC().Format(time.RFC3339),
+		}
+	}
+
+	return &entities.FactValueMap{Value: entry}
+}
+
+// journalFieldString returns the field value as string. Fields with non printable content
+// are exported as an array of bytes
+func journalFieldString(field any) string {
+	switch value := field.(type) {
+	case string:
+		return value
+	case []any:
+		content := make([]byte, 0, len(value))
+
+		for _, item := range value {
+			number, ok := item.(float64)
+			if !ok {
+				return ""
+			}
+
+			content = append(content, byte(number))
+		}
+
+		return string(content)
+	default:
+		return ""
+	}
+}
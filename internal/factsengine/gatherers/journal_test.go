@@ -0,0 +1,287 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package gatherers_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/trento-project/agent/v3/internal/factsengine/gatherers"
+	"github.com/trento-project/agent/v3/pkg/factsengine/entities"
+	utilsMocks "github.com/trento-project/agent/v3/pkg/utils/mocks"
+	"github.com/trento-project/agent/v3/test/helpers"
+)
+
+type JournalGathererTestSuite struct {
+	suite.Suite
+
+	mockExecutor  *utilsMocks.MockCommandExecutor
+	journalOutput []byte
+}
+
+func TestJournalGathererSuite(t *testing.T) {
+	suite.Run(t, new(JournalGathererTestSuite))
+}
+
+func (suite *JournalGathererTestSuite) SetupSuite() {
+	content, err := os.ReadFile(helpers.GetFixturePath("gatherers/journal-corosync.output"))
+	suite.Require().NoError(err)
+
+	suite.journalOutput = content
+}
+
+func (suite *JournalGathererTestSuite) SetupTest() {
+	suite.mockExecutor = new(utilsMocks.MockCommandExecutor)
+}
+
+func (suite *JournalGathererTestSuite) TestJournalGatheringSuccess() {
+	suite.mockExecutor.
+		On("OutputContext", mock.Anything, "/usr/bin/journalctl",
+			"--output=json", "--no-pager", "--quiet", "--reverse",
+			"--unit=corosync.service", "--priority=warning", "--since=-86400s", "--grep=Retransmit", "--lines=1").
+		Return(suite.journalOutput, nil).
+		On("OutputContext", mock.Anything, "/usr/bin/journalctl",
+			"--output=json", "--no-pager", "--quiet", "--reverse",
+			"--unit=corosync.service", "--since=-86400s", "--grep=Retransmit").
+		Return(suite.journalOutput, nil)
+
+	g := gatherers.NewJournalGatherer(suite.mockExecutor)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "retransmits",
+			Gatherer: "journal",
+			Argument: "unit=corosync.service,priority=warning,since=24h,grep=Retransmit,max_entries=1",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "retransmits_count",
+			Gatherer: "journal",
+			Argument: "unit=corosync.service, since=24h, grep=Retransmit, output=count",
+			CheckID:  "check2",
+		},
+	}
+
+	expectedResults := []entities.Fact{
+		{
+			Name:    "retransmits",
+			CheckID: "check1",
+			Value: &entities.FactValueList{
+				Value: []entities.FactValue{
+					&entities.FactValueMap{
+						Value: map[string]entities.FactValue{
+							"message":           &entities.FactValueString{Value: "[TOTEM ] Retransmit List: 1a 1b"},
+							"unit":              &entities.FactValueString{Value: "corosync.service"},
+							"syslog_identifier": &entities.FactValueString{Value: "corosync"},
+							"hostname":          &entities.FactValueString{Value: "vmhana01"},
+							"priority":          &entities.FactValueInt{Value: 4},
+							"timestamp":         &entities.FactValueString{Value: "2024-10-19T10:20:00Z"},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:    "retransmits_count",
+			CheckID: "check2",
+			Value:   &entities.FactValueInt{Value: 2},
+		},
+	}
+
+	factResults, err := g.Gather(context.Background(), factRequests)
+	suite.NoError(err)
+	suite.ElementsMatch(expectedResults, factResults)
+}
+
+func (suite *JournalGathererTestSuite) TestJournalGatheringBinaryMessage() {
+	suite.mockExecutor.
+		On("OutputContext", mock.Anything, "/usr/bin/journalctl",
+			"--output=json", "--no-pager", "--quiet", "--reverse", "--unit=corosync.service", "--lines=100").
+		Return(suite.journalOutput, nil)
+
+	g := gatherers.NewJournalGatherer(suite.mockExecutor)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "corosync",
+			Gatherer: "journal",
+			Argument: "unit=corosync.service",
+			CheckID:  "check1",
+		},
+	}
+
+	factResults, err := g.Gather(context.Background(), factRequests)
+	suite.Require().NoError(err)
+
+	entries, ok := factResults[0].Value.(*entities.FactValueList)
+	suite.Require().True(ok)
+	suite.Len(entries.Value, 2)
+
+	entry, ok := entries.Value[1].(*entities.FactValueMap)
+	suite.Require().True(ok)
+	suite.Equal(&entities.FactValueString{Value: "[TOTEM ] Retransmit"}, entry.Value["message"])
+}
+
+func (suite *JournalGathererTestSuite) TestJournalGatheringErrors() {
+	suite.mockExecutor.
+		On("OutputContext", mock.Anything, "/usr/bin/journalctl",
+			"--output=json", "--no-pager", "--quiet", "--reverse", "--unit=sbd.service", "--lines=100").
+		Return(nil, errors.New("permission denied")).
+		On("OutputContext", mock.Anything, "/usr/bin/journalctl",
+			"--output=json", "--no-pager", "--quiet", "--reverse", "--unit=pacemaker.service", "--lines=100").
+		Return([]byte("not json"), nil)
+
+	g := gatherers.NewJournalGatherer(suite.mockExecutor)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "missing",
+			Gatherer: "journal",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "invalid_unit",
+			Gatherer: "journal",
+			Argument: "unit=sbd.service;reboot",
+			CheckID:  "check2",
+		},
+		{
+			Name:     "invalid_priority",
+			Gatherer: "journal",
+			Argument: "priority=urgent",
+			CheckID:  "check3",
+		},
+		{
+			Name:     "invalid_since",
+			Gatherer: "journal",
+			Argument: "since=yesterday",
+			CheckID:  "check4",
+		},
+		{
+			Name:     "unknown_filter",
+			Gatherer: "journal",
+			Argument: "user=root",
+			CheckID:  "check5",
+		},
+		{
+			Name:     "command_error",
+			Gatherer: "journal",
+			Argument: "unit=sbd.service",
+			CheckID:  "check6",
+		},
+		{
+			Name:     "count_without_since",
+			Gatherer: "journal",
+			Argument: "unit=corosync.service,output=count",
+			CheckID:  "check8",
+		},
+		{
+			Name:     "decoding_error",
+			Gatherer: "journal",
+			Argument: "unit=pacemaker.service",
+			CheckID:  "check7",
+		},
+	}
+
+	expectedResults := []entities.Fact{
+		{
+			Name:    "missing",
+			CheckID: "check1",
+			Error: &entities.FactGatheringError{
+				Type:    "journal-missing-argument",
+				Message: "missing required argument",
+			},
+		},
+		{
+			Name:    "invalid_unit",
+			CheckID: "check2",
+			Error: &entities.FactGatheringError{
+				Type:    "journal-invalid-argument",
+				Message: "invalid journal argument: invalid unit sbd.service;reboot",
+			},
+		},
+		{
+			Name:    "invalid_priority",
+			CheckID: "check3",
+			Error: &entities.FactGatheringError{
+				Type: "journal-invalid-argument",
+				Message: "invalid journal argument: invalid priority urgent, supported values: " +
+					"emerg, alert, crit, err, warning, notice, info, debug or 0-7",
+			},
+		},
+		{
+			Name:    "invalid_since",
+			CheckID: "check4",
+			Error: &entities.FactGatheringError{
+				Type:    "journal-invalid-argument",
+				Message: "invalid journal argument: invalid since yesterday, a positive duration like 24h expected",
+			},
+		},
+		{
+			Name:    "unknown_filter",
+			CheckID: "check5",
+			Error: &entities.FactGatheringError{
+				Type:    "journal-invalid-argument",
+				Message: "invalid journal argument: unknown filter user",
+			},
+		},
+		{
+			Name:    "count_without_since",
+			CheckID: "check8",
+			Error: &entities.FactGatheringError{
+				Type:    "journal-invalid-argument",
+				Message: "invalid journal argument: since is required with output=count",
+			},
+		},
+		{
+			Name:    "command_error",
+			CheckID: "check6",
+			Error: &entities.FactGatheringError{
+				Type:    "journal-command-error",
+				Message: "error running journalctl command: permission denied",
+			},
+		},
+		{
+			Name:    "decoding_error",
+			CheckID: "check7",
+			Error: &entities.FactGatheringError{
+				Type:    "journal-decoding-error",
+				Message: "error decoding journalctl output: invalid character 'o' in literal null (expecting 'u')",
+			},
+		},
+	}
+
+	factResults, err := g.Gather(context.Background(), factRequests)
+	suite.NoError(err)
+	suite.ElementsMatch(expectedResults, factResults)
+}
+
+func (suite *JournalGathererTestSuite) TestJournalGatheringContextCancelled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	suite.mockExecutor.
+		On("OutputContext", mock.Anything, "/usr/bin/journalctl",
+			"--output=json", "--no-pager", "--quiet", "--reverse", "--unit=sbd.service", "--lines=100").
+		Return(nil, context.Canceled)
+
+	g := gatherers.NewJournalGatherer(suite.mockExecutor)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "sbd",
+			Gatherer: "journal",
+			Argument: "unit=sbd.service",
+			CheckID:  "check1",
+		},
+	}
+
+	factResults, err := g.Gather(ctx, factRequests)
+	suite.Error(err)
+	suite.Empty(factResults)
+}
@@ -0,0 +1,534 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package gatherers
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/netip"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/trento-project/agent/v3/pkg/factsengine/entities"
+)
+
+const (
+	NetworkGathererName = "network"
+
+	networkInterfacesArgument = "interfaces"
+	networkRoutesArgument     = "routes"
+	networkBondsArgument      = "bonds"
+
+	sysClassNetPath  = "/sys/class/net"
+	procBondingPath  = "/proc/net/bonding"
+	procRoutePath    = "/proc/net/route"
+	procIPv6Route    = "/proc/net/ipv6_route"
+	procVlanConfig   = "/proc/net/vlan/config"
+	ipv6AddressBytes = 16
+)
+
+//nolint:gochecknoglobals
+var (
+	NetworkMissingArgument = entities.FactGatheringError{
+		Type:    "network-missing-argument",
+		Message: missingRequiredArgument,
+	}
+
+	NetworkUnsupportedArgument = entities.FactGatheringError{
+		Type:    "network-unsupported-argument",
+		Message: "requested argument not supported",
+	}
+
+	NetworkInterfacesError = entities.FactGatheringError{
+		Type:    "network-interfaces-error",
+		Message: "error getting the network interfaces",
+	}
+
+	NetworkFileSystemError = entities.FactGatheringError{
+		Type:    "network-file-system-error",
+		Message: "error reading the file system",
+	}
+
+	NetworkDecodingError = entities.FactGatheringError{
+		Type:    "network-decoding-error",
+		Message: "error decoding network data",
+	}
+)
+
+// NetworkInterface is the link information of a network interface, as given by netlink
+type NetworkInterface struct {
+	Name               string
+	Index              int
+	MTU                int
+	HardwareAddr       string
+	Flags              []string
+	Addresses          []string
+	MulticastAddresses []string
+}
+
+// NetworkInterfacesProvider returns the network interfaces available in the host
+type NetworkInterfacesProvider func() ([]NetworkInterface, error)
+
+// NetworkGatherer returns the network configuration of the host.
+// Supported arguments:
+//   - interfaces: links with their MTU, state, addresses, multicast membership, VLAN and bond details
+//   - routes: IPv4 and IPv6 routes, flagging the default ones
+//   - bonds: bonding devices with their mode and slaves
+type NetworkGatherer struct {
+	fs         afero.Fs
+	interfaces NetworkInterfacesProvider
+}
+
+type networkVlan struct {
+	id     int
+	parent string
+}
+
+func NewDefaultNetworkGatherer() *NetworkGatherer {
+	return NewNetworkGatherer(afero.NewOsFs(), getNetworkInterfaces)
+}
+
+func NewNetworkGatherer(fs afero.Fs, interfaces NetworkInterfacesProvider) *NetworkGatherer {
+	return &NetworkGatherer{
+		fs:         fs,
+		interfaces: interfaces,
+	}
+}
+
+func (g *NetworkGatherer) Gather(ctx context.Context, factsRequests []entities.FactRequest) ([]entities.Fact, error) {
+	slog.Info("Starting facts gathering process", "gatherer", NetworkGathererName)
+
+	facts := make([]entities.Fact, 0, len(factsRequests))
+
+	for _, factReq := range factsRequests {
+		var fact entities.Fact
+
+		factValue, err := g.gatherNetwork(factReq.Argument)
+		if err != nil {
+			slog.Error(err.Error())
+			fact = entities.NewFactGatheredWithError(factReq, err)
+		} else {
+			fact = entities.NewFactGatheredWithRequest(factReq, factValue)
+		}
+
+		facts = append(facts, fact)
+	}
+
+	slog.Info("Requested facts gathered", "gatherer", NetworkGathererName)
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return facts, nil
+}
+
+func (g *NetworkGatherer) gatherNetwork(argument string) (entities.FactValue, *entities.FactGatheringError) {
+	switch argument {
+	case "":
+		return nil, &NetworkMissingArgument
+	case networkInterfacesArgument:
+		return g.gatherInterfaces()
+	case networkRoutesArgument:
+		return g.gatherRoutes()
+	case networkBondsArgument:
+		bonds, _, err := readBonds(g.fs)
+		if err != nil {
+			return nil, NetworkFileSystemError.Wrap(err.Error())
+		}
+
+		return &entities.FactValueList{Value: bonds}, nil
+	default:
+		return nil, NetworkUnsupportedArgument.Wrap(argument)
+	}
+}
+
+func (g *NetworkGatherer) gatherInterfaces() (entities.FactValue, *entities.FactGatheringError) {
+	interfaces, err := g.interfaces()
+	if err != nil {
+		return nil, NetworkInterfacesError.Wrap(err.Error())
+	}
+
+	vlans, err := readVlans(g.fs)
+	if err != nil {
+		return nil, NetworkFileSystemError.Wrap(err.Error())
+	}
+
+	_, bondMasters, err := readBonds(g.fs)
+	if err != nil {
+		return nil, NetworkFileSystemError.Wrap(err.Error())
+	}
+
+	result := make([]entities.FactValue, 0, len(interfaces))
+
+	for _, iface := range interfaces {
+		entry := map[string]entities.FactValue{
+			"name":                &entities.FactValueString{Value: iface.Name},
+			"index":               &entities.FactValueInt{Value: iface.Index},
+			"mtu":                 &entities.FactValueInt{Value: iface.MTU},
+			"hardware_address":    &entities.FactValueString{Value: iface.HardwareAddr},
+			"flags":               stringsToFactValueList(iface.Flags),
+			"addresses":           stringsToFactValueList(iface.Addresses),
+			"multicast_addresses": stringsToFactValueList(iface.MulticastAddresses),
+			"operstate":           &entities.FactValueString{Value: readOperState(g.fs, iface.Name)},
+		}
+
+		if vlan, found := vlans[iface.Name]; found {
+			entry["vlan_id"] = &entities.FactValueInt{Value: vlan.id}
+			entry["vlan_parent"] = &entities.FactValueString{Value: vlan.parent}
+		}
+
+		if master, found := bondMasters[iface.Name]; found {
+			entry["bond_master"] = &entities.FactValueString{Value: master}
+		}
+
+		result = append(result, &entities.FactValueMap{Value: entry})
+	}
+
+	return &entities.FactValueList{Value: result}, nil
+}
+
+func (g *NetworkGatherer) gatherRoutes() (entities.FactValue, *entities.FactGatheringError) {
+	routes, err := readIPv4Routes(g.fs)
+	if err != nil {
+		return nil, NetworkDecodingError.Wrap(err.Error())
+	}
+
+	ipv6Routes, err := readIPv6Routes(g.fs)
+	if err != nil {
+		return nil, NetworkDecodingError.Wrap(err.Error())
+	}
+
+	return &entities.FactValueList{Value: append(routes, ipv6Routes...)}, nil
+}
+
+func getNetworkInterfaces() ([]NetworkInterface, error) {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]NetworkInterface, 0, len(interfaces))
+
+	for _, iface := range interfaces {
+		addresses, err := iface.Addrs()
+		if err != nil {
+			return nil, fmt.Errorf("error getting %s addresses: %w", iface.Name, err)
+		}
+
+		multicastAddresses, err := iface.MulticastAddrs()
+		if err != nil {
+			return nil, fmt.Errorf("error getting %s multicast addresses: %w", iface.Name, err)
+		}
+
+		result = append(result, NetworkInterface{
+			Name:               iface.Name,
+			Index:              iface.Index,
+			MTU:                iface.MTU,
+			HardwareAddr:       iface.HardwareAddr.String(),
+			Flags:              strings.Split(iface.Flags.String(), "|"),
+			Addresses:          addressesToStrings(addresses),
+			MulticastAddresses: addressesToStrings(multicastAddresses),
+		})
+	}
+
+	return result, nil
+}
+
+func addressesToStrings(addresses []net.Addr) []string {
+	result := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		result = append(result, address.String())
+	}
+
+	return result
+}
+
+func stringsToFactValueList(values []string) *entities.FactValueList {
+	result := make([]entities.FactValue, 0, len(values))
+	for _, value := range values {
+		result = append(result, &entities.FactValueString{Value: value})
+	}
+
+	return &entities.FactValueList{Value: result}
+}
+
+func readOperState(fs afero.Fs, name string) string {
+	content, err := afero.ReadFile(fs, path.Join(sysClassNetPath, name, "operstate"))
+	if err != nil {
+		return "unknown"
+	}
+
+	return strings.TrimSpace(string(content))
+}
+
+// readVlans reads the VLAN devices from /proc/net/vlan/config, with lines like:
+// eth0.100       | 100  | eth0
+func readVlans(fs afero.Fs) (map[string]networkVlan, error) {
+	vlans := map[string]networkVlan{}
+
+	exists, _ := afero.Exists(fs, procVlanConfig)
+	if !exists {
+		return vlans, nil
+	}
+
+	content, err := afero.ReadFile(fs, procVlanConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Split(line, "|")
+		if len(fields) != 3 {
+			continue
+		}
+
+		id, err := strconv.Atoi(strings.TrimSpace(fields[1]))
+		if err != nil {
+			continue
+		}
+
+		vlans[strings.TrimSpace(fields[0])] = networkVlan{id: id, parent: strings.TrimSpace(fields[2])}
+	}
+
+	return vlans, nil
+}
+
+// readBonds reads the bonding devices from /proc/net/bonding, returning the bonds details
+// and the bond master of each slave interface
+func readBonds(fs afero.Fs) ([]entities.FactValue, map[string]string, error) {
+	bonds := []entities.FactValue{}
+	masters := map[string]string{}
+
+	exists, _ := afero.DirExists(fs, procBondingPath)
+	if !exists {
+		return bonds, masters, nil
+	}
+
+	files, err := afero.ReadDir(fs, procBondingPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, file := range files {
+		content, err := afero.ReadFile(fs, path.Join(procBondingPath, file.Name()))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		bond, slaves := parseBonding(file.Name(), string(content))
+		bonds = append(bonds, bond)
+
+		for _, slave := range slaves {
+			masters[slave] = file.Name()
+		}
+	}
+
+	return bonds, masters, nil
+}
+
+// parseBonding parses the /proc/net/bonding/<bond> content. The bond settings come first,
+// followed by a block for each slave starting with the "Slave Interface" key
+func parseBonding(name string, content string) (entities.FactValue, []string) {
+	bond := map[string]entities.FactValue{
+		"name": &entities.FactValueString{Value: name},
+	}
+	slaves := []entities.FactValue{}
+	slaveNames := []string{}
+
+	var currentSlave map[string]entities.FactValue
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			continue
+		}
+
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		if key == "Slave Interface" {
+			currentSlave = map[string]entities.FactValue{
+				"name": &entities.FactValueString{Value: value},
+			}
+			slaves = append(slaves, &entities.FactValueMap{Value: currentSlave})
+			slaveNames = append(slaveNames, value)
+
+			continue
+		}
+
+		target := bond
+		if currentSlave != nil {
+			target = currentSlave
+		}
+
+		target[bondingKey(key)] = bondingValue(key, value)
+	}
+
+	bond["slaves"] = &entities.FactValueList{Value: slaves}
+
+	return &entities.FactValueMap{Value: bond}, slaveNames
+}
+
+func bondingKey(key string) string {
+	key = strings.NewReplacer("(", "", ")", "").Replace(strings.ToLower(key))
+
+	return strings.Join(strings.Fields(key), "_")
+}
+
+func bondingValue(key string, value string) entities.FactValue {
+	// the mode has a description and the identifier, like: fault-tolerance (active-backup)
+	if key == "Bonding Mode" {
+		if start, end := strings.LastIndex(value, "("), strings.LastIndex(value, ")"); start != -1 && end > start {
+			return &entities.FactValueString{Value: value[start+1 : end]}
+		}
+	}
+
+	// the speed includes the unit, like: 1000 Mbps
+	if key == "Speed" {
+		value = strings.TrimSuffix(value, " Mbps")
+	}
+
+	return entities.ParseStringToFactValue(value)
+}
+
+// readIPv4Routes parses /proc/net/route, where the addresses are little endian hex values
+func readIPv4Routes(fs afero.Fs) ([]entities.FactValue, error) {
+	routes := []entities.FactValue{}
+
+	content, err := afero.ReadFile(fs, procRoutePath)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) < 8 {
+			return nil, fmt.Errorf("unexpected route line: %s", line)
+		}
+
+		destination, err := parseProcIPv4(fields[1])
+		if err != nil {
+			return nil, err
+		}
+
+		gateway, err := parseProcIPv4(fields[2])
+		if err != nil {
+			return nil, err
+		}
+
+		mask, err := parseProcIPv4(fields[7])
+		if err != nil {
+			return nil, err
+		}
+
+		metric, err := strconv.Atoi(fields[6])
+		if err != nil {
+			return nil, err
+		}
+
+		prefixLength, _ := net.IPMask(mask.AsSlice()).Size()
+
+		routes = append(routes, &entities.FactValueMap{Value: map[string]entities.FactValue{
+			"family":      &entities.FactValueString{Value: "ipv4"},
+			"interface":   &entities.FactValueString{Value: fields[0]},
+			"destination": &entities.FactValueString{Value: netip.PrefixFrom(destination, prefixLength).String()},
+			"gateway":     &entities.FactValueString{Value: gateway.String()},
+			"metric":      &entities.FactValueInt{Value: metric},
+			"default":     &entities.FactValueBool{Value: prefixLength == 0},
+		}})
+	}
+
+	return routes, nil
+}
+
+func parseProcIPv4(value string) (netip.Addr, error) {
+	decoded, err := hex.DecodeString(value)
+	if err != nil || len(decoded) != 4 {
+		return netip.Addr{}, fmt.Errorf("invalid address %s", value)
+	}
+
+	var address [4]byte
+
+	binary.BigEndian.PutUint32(address[:], binary.LittleEndian.Uint32(decoded))
+
+	return netip.AddrFrom4(address), nil
+}
+
+// readIPv6Routes parses /proc/net/ipv6_route, with the fields: destination, prefix length,
+// source, source prefix length, next hop, metric, reference count, use count, flags and interface
+func readIPv6Routes(fs afero.Fs) ([]entities.FactValue, error) {
+	routes := []entities.FactValue{}
+
+	exists, _ := afero.Exists(fs, procIPv6Route)
+	if !exists {
+		return routes, nil
+	}
+
+	content, err := afero.ReadFile(fs, procIPv6Route)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 10 {
+			continue
+		}
+
+		// skip the loopback and local routes
+		if fields[9] == "lo" {
+			continue
+		}
+
+		destination, err := parseProcIPv6(fields[0])
+		if err != nil {
+			return nil, err
+		}
+
+		prefixLength, err := strconv.ParseInt(fields[1], 16, 32)
+		if err != nil {
+			return nil, err
+		}
+
+		gateway, err := parseProcIPv6(fields[4])
+		if err != nil {
+			return nil, err
+		}
+
+		metric, err := strconv.ParseInt(fields[5], 16, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		routes = append(routes, &entities.FactValueMap{Value: map[string]entities.FactValue{
+			"family":      &entities.FactValueString{Value: "ipv6"},
+			"interface":   &entities.FactValueString{Value: fields[9]},
+			"destination": &entities.FactValueString{Value: netip.PrefixFrom(destination, int(prefixLength)).String()},
+			"gateway":     &entities.FactValueString{Value: gateway.String()},
+			"metric":      &entities.FactValueInt{Value: int(metric)},
+			"default":     &entities.FactValueBool{Value: prefixLength == 0},
+		}})
+	}
+
+	return routes, nil
+}
+
+func parseProcIPv6(value string) (netip.Addr, error) {
+	decoded, err := hex.DecodeString(value)
+	if err != nil || len(decoded) != ipv6AddressBytes {
+		return netip.Addr{}, fmt.Errorf("invalid address %s", value)
+	}
+
+	return netip.AddrFrom16([ipv6AddressBytes]byte(decoded)), nil
+}
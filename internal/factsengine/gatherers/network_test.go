@@ -0,0 +1,351 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package gatherers_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+	"github.com/trento-project/agent/v3/internal/factsengine/gatherers"
+	"github.com/trento-project/agent/v3/pkg/factsengine/entities"
+	"github.com/trento-project/agent/v3/test/helpers"
+)
+
+type NetworkGathererTestSuite struct {
+	suite.Suite
+
+	fs afero.Fs
+}
+
+func TestNetworkGathererSuite(t *testing.T) {
+	suite.Run(t, new(NetworkGathererTestSuite))
+}
+
+func (suite *NetworkGathererTestSuite) SetupTest() {
+	suite.fs = afero.NewMemMapFs()
+
+	fixtures := map[string]string{
+		"/proc/net/route":         "gatherers/network/route",
+		"/proc/net/ipv6_route":    "gatherers/network/ipv6_route",
+		"/proc/net/bonding/bond0": "gatherers/network/bond0",
+		"/proc/net/vlan/config":   "gatherers/network/vlan_config",
+	}
+
+	for target, fixture := range fixtures {
+		content, err := os.ReadFile(helpers.GetFixturePath(fixture))
+		suite.Require().NoError(err)
+		suite.Require().NoError(afero.WriteFile(suite.fs, target, content, 0644))
+	}
+
+	suite.Require().NoError(afero.WriteFile(suite.fs, "/sys/class/net/bond0/operstate", []byte("up\n"), 0644))
+	suite.Require().NoError(afero.WriteFile(suite.fs, "/sys/class/net/eth1/operstate", []byte("down\n"), 0644))
+}
+
+func networkInterfaces() ([]gatherers.NetworkInterface, error) {
+	return []gatherers.NetworkInterface{
+		{
+			Name:               "bond0",
+			Index:              4,
+			MTU:                9000,
+			HardwareAddr:       "52:54:00:aa:bb:01",
+			Flags:              []string{"up", "broadcast", "running", "multicast"},
+			Addresses:          []string{"10.0.0.10/24"},
+			MulticastAddresses: []string{"239.192.1.1"},
+		},
+		{
+			Name:         "eth1",
+			Index:        3,
+			MTU:          1500,
+			HardwareAddr: "52:54:00:aa:bb:01",
+			Flags:        []string{"broadcast", "multicast"},
+		},
+		{
+			Name:         "bond0.100",
+			Index:        5,
+			MTU:          9000,
+			HardwareAddr: "52:54:00:aa:bb:01",
+			Flags:        []string{"up"},
+		},
+	}, nil
+}
+
+func (suite *NetworkGathererTestSuite) TestNetworkGatheringInterfaces() {
+	g := gatherers.NewNetworkGatherer(suite.fs, networkInterfaces)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "interfaces",
+			Gatherer: "network",
+			Argument: "interfaces",
+			CheckID:  "check1",
+		},
+	}
+
+	expectedResults := []entities.Fact{
+		{
+			Name:    "interfaces",
+			CheckID: "check1",
+			Value: &entities.FactValueList{
+				Value: []entities.FactValue{
+					&entities.FactValueMap{
+						Value: map[string]entities.FactValue{
+							"name":             &entities.FactValueString{Value: "bond0"},
+							"index":            &entities.FactValueInt{Value: 4},
+							"mtu":              &entities.FactValueInt{Value: 9000},
+							"hardware_address": &entities.FactValueString{Value: "52:54:00:aa:bb:01"},
+							"flags": &entities.FactValueList{Value: []entities.FactValue{
+								&entities.FactValueString{Value: "up"},
+								&entities.FactValueString{Value: "broadcast"},
+								&entities.FactValueString{Value: "running"},
+								&entities.FactValueString{Value: "multicast"},
+							}},
+							"addresses": &entities.FactValueList{Value: []entities.FactValue{
+								&entities.FactValueString{Value: "10.0.0.10/24"},
+							}},
+							"multicast_addresses": &entities.FactValueList{Value: []entities.FactValue{
+								&entities.FactValueString{Value: "239.192.1.1"},
+							}},
+							"operstate": &entities.FactValueString{Value: "up"},
+						},
+					},
+					&entities.FactValueMap{
+						Value: map[string]entities.FactValue{
+							"name":             &entities.FactValueString{Value: "eth1"},
+							"index":            &entities.FactValueInt{Value: 3},
+							"mtu":              &entities.FactValueInt{Value: 1500},
+							"hardware_address": &entities.FactValueString{Value: "52:54:00:aa:bb:01"},
+							"flags": &entities.FactValueList{Value: []entities.FactValue{
+								&entities.FactValueString{Value: "broadcast"},
+								&entities.FactValueString{Value: "multicast"},
+							}},
+							"addresses":           &entities.FactValueList{Value: []entities.FactValue{}},
+							"multicast_addresses": &entities.FactValueList{Value: []entities.FactValue{}},
+							"operstate":           &entities.FactValueString{Value: "down"},
+							"bond_master":         &entities.FactValueString{Value: "bond0"},
+						},
+					},
+					&entities.FactValueMap{
+						Value: map[string]entities.FactValue{
+							"name":             &entities.FactValueString{Value: "bond0.100"},
+							"index":            &entities.FactValueInt{Value: 5},
+							"mtu":              &entities.FactValueInt{Value: 9000},
+							"hardware_address": &entities.FactValueString{Value: "52:54:00:aa:bb:01"},
+							"flags": &entities.FactValueList{Value: []entities.FactValue{
+								&entities.FactValueString{Value: "up"},
+							}},
+							"addresses":           &entities.FactValueList{Value: []entities.FactValue{}},
+							"multicast_addresses": &entities.FactValueList{Value: []entities.FactValue{}},
+							"operstate":           &entities.FactValueString{Value: "unknown"},
+							"vlan_id":             &entities.FactValueInt{Value: 100},
+							"vlan_parent":         &entities.FactValueString{Value: "bond0"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	factResults, err := g.Gather(context.Background(), factRequests)
+	suite.NoError(err)
+	suite.ElementsMatch(expectedResults, factResults)
+}
+
+func (suite *NetworkGathererTestSuite) TestNetworkGatheringRoutes() {
+	g := gatherers.NewNetworkGatherer(suite.fs, networkInterfaces)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "routes",
+			Gatherer: "network",
+			Argument: "routes",
+			CheckID:  "check1",
+		},
+	}
+
+	route := func(family, iface, destination, gateway string, metric int, isDefault bool) entities.FactValue {
+		return &entities.FactValueMap{
+			Value: map[string]entities.FactValue{
+				"family":      &entities.FactValueString{Value: family},
+				"interface":   &entities.FactValueString{Value: iface},
+				"destination": &entities.FactValueString{Value: destination},
+				"gateway":     &entities.FactValueString{Value: gateway},
+				"metric":      &entities.FactValueInt{Value: metric},
+				"default":     &entities.FactValueBool{Value: isDefault},
+			},
+		}
+	}
+
+	expectedResults := []entities.Fact{
+		{
+			Name:    "routes",
+			CheckID: "check1",
+			Value: &entities.FactValueList{
+				Value: []entities.FactValue{
+					route("ipv4", "bond0", "0.0.0.0/0", "10.0.0.1", 100, true),
+					route("ipv4", "bond0", "10.0.0.0/24", "0.0.0.0", 100, false),
+					route("ipv6", "bond0", "fe80::/64", "::", 256, false),
+					route("ipv6", "bond0", "::/0", "fe80::1", 1024, true),
+				},
+			},
+		},
+	}
+
+	factResults, err := g.Gather(context.Background(), factRequests)
+	suite.NoError(err)
+	suite.ElementsMatch(expectedResults, factResults)
+}
+
+func (suite *NetworkGathererTestSuite) TestNetworkGatheringBonds() {
+	g := gatherers.NewNetworkGatherer(suite.fs, networkInterfaces)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "bonds",
+			Gatherer: "network",
+			Argument: "bonds",
+			CheckID:  "check1",
+		},
+	}
+
+	expectedResults := []entities.Fact{
+		{
+			Name:    "bonds",
+			CheckID: "check1",
+			Value: &entities.FactValueList{
+				Value: []entities.FactValue{
+					&entities.FactValueMap{
+						Value: map[string]entities.FactValue{
+							"name":                            &entities.FactValueString{Value: "bond0"},
+							"ethernet_channel_bonding_driver": &entities.FactValueString{Value: "v5.14.21"},
+							"bonding_mode":                    &entities.FactValueString{Value: "active-backup"},
+							"primary_slave":                   &entities.FactValueString{Value: "None"},
+							"currently_active_slave":          &entities.FactValueString{Value: "eth0"},
+							"mii_status":                      &entities.FactValueString{Value: "up"},
+							"mii_polling_interval_ms":         &entities.FactValueInt{Value: 100},
+							"up_delay_ms":                     &entities.FactValueInt{Value: 0},
+							"down_delay_ms":                   &entities.FactValueInt{Value: 0},
+							"peer_notification_delay_ms":      &entities.FactValueInt{Value: 0},
+							"slaves": &entities.FactValueList{
+								Value: []entities.FactValue{
+									&entities.FactValueMap{
+										Value: map[string]entities.FactValue{
+											"name":               &entities.FactValueString{Value: "eth0"},
+											"mii_status":         &entities.FactValueString{Value: "up"},
+											"speed":              &entities.FactValueInt{Value: 10000},
+											"duplex":             &entities.FactValueString{Value: "full"},
+											"link_failure_count": &entities.FactValueInt{Value: 0},
+											"permanent_hw_addr":  &entities.FactValueString{Value: "52:54:00:aa:bb:01"},
+											"slave_queue_id":     &entities.FactValueInt{Value: 0},
+										},
+									},
+									&entities.FactValueMap{
+										Value: map[string]entities.FactValue{
+											"name":               &entities.FactValueString{Value: "eth1"},
+											"mii_status":         &entities.FactValueString{Value: "down"},
+											"speed":              &entities.FactValueString{Value: "Unknown"},
+											"duplex":             &entities.FactValueString{Value: "Unknown"},
+											"link_failure_count": &entities.FactValueInt{Value: 2},
+											"permanent_hw_addr":  &entities.FactValueString{Value: "52:54:00:aa:bb:02"},
+											"slave_queue_id":     &entities.FactValueInt{Value: 0},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	factResults, err := g.Gather(context.Background(), factRequests)
+	suite.NoError(err)
+	suite.ElementsMatch(expectedResults, factResults)
+}
+
+func (suite *NetworkGathererTestSuite) TestNetworkGatheringErrors() {
+	g := gatherers.NewNetworkGatherer(afero.NewMemMapFs(), func() ([]gatherers.NetworkInterface, error) {
+		return nil, errors.New("netlink error")
+	})
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "missing",
+			Gatherer: "network",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "unsupported",
+			Gatherer: "network",
+			Argument: "firewall",
+			CheckID:  "check2",
+		},
+		{
+			Name:     "interfaces",
+			Gatherer: "network",
+			Argument: "interfaces",
+			CheckID:  "check3",
+		},
+		{
+			Name:     "routes",
+			Gatherer: "network",
+			Argument: "routes",
+			CheckID:  "check4",
+		},
+		{
+			Name:     "bonds",
+			Gatherer: "network",
+			Argument: "bonds",
+			CheckID:  "check5",
+		},
+	}
+
+	expectedResults := []entities.Fact{
+		{
+			Name:    "missing",
+			CheckID: "check1",
+			Error: &entities.FactGatheringError{
+				Type:    "network-missing-argument",
+				Message: "missing required argument",
+			},
+		},
+		{
+			Name:    "unsupported",
+			CheckID: "check2",
+			Error: &entities.FactGatheringError{
+				Type:    "network-unsupported-argument",
+				Message: "requested argument not supported: firewall",
+			},
+		},
+		{
+			Name:    "interfaces",
+			CheckID: "check3",
+			Error: &entities.FactGatheringError{
+				Type:    "network-interfaces-error",
+				Message: "error getting the network interfaces: netlink error",
+			},
+		},
+		{
+			Name:    "routes",
+			CheckID: "check4",
+			Error: &entities.FactGatheringError{
+				Type:    "network-decoding-error",
+				Message: "error decoding network data: open /proc/net/route: file does not exist",
+			},
+		},
+		{
+			Name:    "bonds",
+			CheckID: "check5",
+			Value:   &entities.FactValueList{Value: []entities.FactValue{}},
+		},
+	}
+
+	factResults, err := g.Gather(context.Background(), factRequests)
+	suite.NoError(err)
+	suite.ElementsMatch(expectedResults, factResults)
+}
@@ -4,11 +4,13 @@
 package gatherers
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"strings"
 
 	"log/slog"
 
@@ -25,11 +27,22 @@ const (
 
 	sapcontrolFileSystemMsg = "error in the SAP file system"
 	sapcontrolWebmethodMsg  = "error executing sapcontrol webmethod"
+
+	// sapcontrolParameterValueArgument requests a profile parameter, with the ParameterValue:<parameter> format
+	sapcontrolParameterValueArgument = "ParameterValue"
 )
 
+type sapcontrolWebmethod = func(context.Context, sapcontrolapi.WebService) (any, error)
+
 //nolint:gochecknoglobals
-var whitelistedSapControlArguments = map[string]func(context.Context, sapcontrolapi.WebService) (any, error){
+var whitelistedSapControlArguments = map[string]sapcontrolWebmethod{
+	"ABAPGetWPTable":        mapABAPGetWPTable,
+	"EnqGetStatistic":       mapEnqGetStatistic,
+	"GetAlerts":             mapGetAlerts,
+	"GetAlertTree":          mapGetAlertTree,
+	"GetEnvironment":        mapGetEnvironment,
 	"GetProcessList":        mapGetProcessList,
+	"GetQueueStatistic":     mapGetQueueStatistic,
 	"GetSystemInstanceList": mapGetSystemInstanceList,
 	"GetVersionInfo":        mapGetVersionInfo,
 	"HACheckConfig":         mapHACheckConfig,
@@ -87,6 +100,11 @@ type failoverConfig struct {
 	HANodes               []string `json:"ha_nodes"`
 }
 
+type environmentVariable struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
 type SapControlMap map[string][]SapControlInstance
 
 type SapControlInstance struct {
@@ -139,7 +157,7 @@ func memoizeSapcontrol(args ...any) (any, error) {
 		return nil, ImplementationError.Wrap("error using memoizeSapcontrol. string must be 3rd argument")
 	}
 
-	webmethod, ok := args[3].(func(ctx context.Context, conn sapcontrolapi.WebService) (any, error))
+	webmethod, ok := args[3].(sapcontrolWebmethod)
 	if !ok {
 		return nil, ImplementationError.Wrap("error using memoizeSapcontrol. webmethod func must be 4th argument")
 	}
@@ -187,10 +205,8 @@ func (s *SapControlGatherer) gatherSingle(
 		return entities.NewFactGatheredWithError(factReq, &SapcontrolMissingArgument)
 	}
 
-	webmethod, ok := whitelistedSapControlArguments[factReq.Argument]
-
-	if !ok {
-		gatheringError := SapcontrolArgumentUnsupported.Wrap(factReq.Argument)
+	webmethod, gatheringError := getSapControlWebmethod(factReq.Argument)
+	if gatheringError != nil {
 		slog.Error(gatheringError.Error())
 
 		return entities.NewFactGatheredWithError(factReq, gatheringError)
@@ -250,6 +266,25 @@ func (s *SapControlGatherer) gatherSingle(
 	return entities.NewFactGatheredWithRequest(factReq, factValue)
 }
 
+func getSapControlWebmethod(argument string) (sapcontrolWebmethod, *entities.FactGatheringError) {
+	webmethodName, parameter, hasParameter := strings.Cut(argument, ":")
+
+	if webmethodName == sapcontrolParameterValueArgument {
+		if parameter == "" {
+			return nil, SapcontrolMissingArgument.Wrap("ParameterValue requires a parameter, e.g. ParameterValue:SAPSYSTEMNAME")
+		}
+
+		return mapParameterValue(parameter), nil
+	}
+
+	webmethod, ok := whitelistedSapControlArguments[webmethodName]
+	if !ok || hasParameter {
+		return nil, SapcontrolArgumentUnsupported.Wrap(argument)
+	}
+
+	return webmethod, nil
+}
+
 func initSystemsMap(fs afero.Fs) (map[string][][]string, error) {
 	foundSystems := make(map[string][][]string)
 
@@ -351,6 +386,80 @@ func mapHAGetFailoverConfig(ctx context.Context, conn sapcontrolapi.WebService)
 	return config, nil
 }
 
+func mapABAPGetWPTable(ctx context.Context, conn sapcontrolapi.WebService) (any, error) {
+	output, err := conn.ABAPGetWPTableContext(ctx, new(sapcontrolapi.ABAPGetWPTable))
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Workprocesses, nil
+}
+
+func mapEnqGetStatistic(ctx context.Context, conn sapcontrolapi.WebService) (any, error) {
+	output, err := conn.EnqGetStatisticContext(ctx, new(sapcontrolapi.EnqGetStatistic))
+	if err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+func mapGetQueueStatistic(ctx context.Context, conn sapcontrolapi.WebService) (any, error) {
+	output, err := conn.GetQueueStatisticContext(ctx, new(sapcontrolapi.GetQueueStatistic))
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Queues, nil
+}
+
+func mapGetAlertTree(ctx context.Context, conn sapcontrolapi.WebService) (any, error) {
+	output, err := conn.GetAlertTreeContext(ctx, new(sapcontrolapi.GetAlertTree))
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Tree, nil
+}
+
+func mapGetAlerts(ctx context.Context, conn sapcontrolapi.WebService) (any, error) {
+	output, err := conn.GetAlertsContext(ctx, new(sapcontrolapi.GetAlerts))
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Alerts, nil
+}
+
+func mapGetEnvironment(ctx context.Context, conn sapcontrolapi.WebService) (any, error) {
+	output, err := conn.GetEnvironmentContext(ctx, new(sapcontrolapi.GetEnvironment))
+	if err != nil {
+		return nil, err
+	}
+
+	// the variables are returned as NAME=value entries. They are mapped to name/value pairs,
+	// as the variable names would be changed by the snake case conversion if used as keys
+	variables := []environmentVariable{}
+
+	for _, entry := range output.Env {
+		name, value, _ := strings.Cut(entry, "=")
+		variables = append(variables, environmentVariable{Name: name, Value: value})
+	}
+
+	return variables, nil
+}
+
+func mapParameterValue(parameter string) sapcontrolWebmethod {
+	return func(ctx context.Context, conn sapcontrolapi.WebService) (any, error) {
+		output, err := conn.ParameterValueContext(ctx, &sapcontrolapi.ParameterValue{Parameter: parameter})
+		if err != nil {
+			return nil, err
+		}
+
+		return output.Value, nil
+	}
+}
+
 func outputToFactValue(output any) (*entities.FactValueMap, error) {
 	marshalled, err := json.Marshal(&output)
 	if err != nil {
@@ -359,7 +468,11 @@ func outputToFactValue(output any) (*entities.FactValueMap, error) {
 
 	var unmarshalled map[string]any
 
-	err = json.Unmarshal(marshalled, &unmarshalled)
+	// numbers are decoded as json numbers, so big counters are not converted to floats
+	decoder := json.NewDecoder(bytes.NewReader(marshalled))
+	decoder.UseNumber()
+
+	err = decoder.Decode(&unmarshalled)
 	if err != nil {
 		return nil, err
 	}
@@ -368,7 +481,7 @@ func outputToFactValue(output any) (*entities.FactValueMap, error) {
 	result := &entities.FactValueMap{Value: make(map[string]entities.FactValue)}
 
 	for key, value := range unmarshalled {
-		factValue, err := entities.NewFactValue(value, entities.WithSnakeCaseKeys())
+		factValue, err := entities.NewFactValue(convertJSONNumbers(value), entities.WithSnakeCaseKeys())
 		if err != nil {
 			return nil, err
 		}
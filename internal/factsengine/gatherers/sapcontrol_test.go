@@ -664,6 +664,393 @@ func (suite *SapControlGathererSuite) TestSapControlGathererHAGetFailoverConfig(
 	suite.Equal(expectedFacts, results)
 }
 
+func (suite *SapControlGathererSuite) TestSapControlGathererABAPGetWPTable() {
+	ctx := context.Background()
+	mockWebService := new(sapControlMocks.MockWebService)
+	mockWebService.
+		On("ABAPGetWPTableContext", ctx, mock.Anything).
+		Return(&sapcontrol.ABAPGetWPTableResponse{
+			Workprocesses: []*sapcontrol.WorkProcess{
+				{
+					No:     0,
+					Typ:    "DIA",
+					Pid:    12345,
+					Status: "Run",
+					Time:   "4",
+					Client: "000",
+					User:   "SAPSYS",
+				},
+				{
+					No:     1,
+					Typ:    "BTC",
+					Pid:    12346,
+					Status: "Wait",
+				},
+			},
+		}, nil)
+
+	suite.webService.On("New", "00").Return(mockWebService)
+
+	gatherer := gatherers.NewSapControlGatherer(suite.webService, suite.testFS, nil)
+
+	fr := []entities.FactRequest{
+		{
+			Name:     "sapcontrol",
+			Gatherer: "sapcontrol",
+			CheckID:  "check1",
+			Argument: "ABAPGetWPTable",
+		},
+	}
+
+	expectedFacts := []entities.Fact{
+		{
+			Name:    "sapcontrol",
+			CheckID: "check1",
+			Value: &entities.FactValueMap{
+				Value: map[string]entities.FactValue{
+					"PRD": &entities.FactValueList{
+						Value: []entities.FactValue{
+							&entities.FactValueMap{
+								Value: map[string]entities.FactValue{
+									"instance_nr": &entities.FactValueString{Value: "00"},
+									"name":        &entities.FactValueString{Value: "ASCS00"},
+									"output": &entities.FactValueList{
+										Value: []entities.FactValue{
+											&entities.FactValueMap{
+												Value: map[string]entities.FactValue{
+													"no":     &entities.FactValueInt{Value: 0},
+													"typ":    &entities.FactValueString{Value: "DIA"},
+													"pid":    &entities.FactValueInt{Value: 12345},
+													"status": &entities.FactValueString{Value: "Run"},
+													"time":   &entities.FactValueString{Value: "4"},
+													"client": &entities.FactValueString{Value: "000"},
+													"user":   &entities.FactValueString{Value: "SAPSYS"},
+												},
+											},
+											&entities.FactValueMap{
+												Value: map[string]entities.FactValue{
+													"no":     &entities.FactValueInt{Value: 1},
+													"typ":    &entities.FactValueString{Value: "BTC"},
+													"pid":    &entities.FactValueInt{Value: 12346},
+													"status": &entities.FactValueString{Value: "Wait"},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			Error: nil,
+		},
+	}
+
+	results, err := gatherer.Gather(context.Background(), fr)
+	suite.Require().NoError(err)
+	suite.Equal(expectedFacts, results)
+}
+
+func (suite *SapControlGathererSuite) TestSapControlGathererEnqGetStatistic() {
+	ctx := context.Background()
+	mockWebService := new(sapControlMocks.MockWebService)
+	mockWebService.
+		On("EnqGetStatisticContext", ctx, mock.Anything).
+		Return(&sapcontrol.EnqGetStatisticResponse{
+			OwnerNow:         2,
+			OwnerHigh:        10,
+			OwnerMax:         4096,
+			OwnerState:       sapcontrol.STATECOLOR_GREEN,
+			ArgumentsNow:     3,
+			ArgumentsHigh:    12,
+			ArgumentsMax:     4096,
+			ArgumentsState:   sapcontrol.STATECOLOR_GREEN,
+			LocksNow:         4000,
+			LocksHigh:        4090,
+			LocksMax:         4096,
+			LocksState:       sapcontrol.STATECOLOR_YELLOW,
+			EnqueueRequests:  123456789,
+			EnqueueRejects:   15,
+			LockTime:         1.5,
+			ReplicationState: sapcontrol.STATECOLOR_GREEN,
+		}, nil)
+
+	suite.webService.On("New", "00").Return(mockWebService)
+
+	gatherer := gatherers.NewSapControlGatherer(suite.webService, suite.testFS, nil)
+
+	fr := []entities.FactRequest{
+		{
+			Name:     "sapcontrol",
+			Gatherer: "sapcontrol",
+			CheckID:  "check1",
+			Argument: "EnqGetStatistic",
+		},
+	}
+
+	results, err := gatherer.Gather(context.Background(), fr)
+	suite.Require().NoError(err)
+	suite.Require().Len(results, 1)
+
+	output, gatheringErr := results[0].Value.(*entities.FactValueMap).GetValue("PRD.0.output")
+	suite.Require().Nil(gatheringErr)
+
+	statistic, ok := output.(*entities.FactValueMap)
+	suite.Require().True(ok)
+	suite.Len(statistic.Value, 27)
+	suite.Equal(&entities.FactValueInt{Value: 4000}, statistic.Value["locks_now"])
+	suite.Equal(&entities.FactValueInt{Value: 4096}, statistic.Value["locks_max"])
+	suite.Equal(&entities.FactValueString{Value: "SAPControl-YELLOW"}, statistic.Value["locks_state"])
+	suite.Equal(&entities.FactValueInt{Value: 123456789}, statistic.Value["enqueue_requests"])
+	suite.Equal(&entities.FactValueInt{Value: 0}, statistic.Value["dequeue_errors"])
+	suite.Equal(&entities.FactValueFloat{Value: 1.5}, statistic.Value["lock_time"])
+	suite.Equal(&entities.FactValueString{Value: "SAPControl-GREEN"}, statistic.Value["replication_state"])
+}
+
+func (suite *SapControlGathererSuite) TestSapControlGathererQueuesAndAlerts() {
+	ctx := context.Background()
+	mockWebService := new(sapControlMocks.MockWebService)
+	mockWebService.
+		On("GetQueueStatisticContext", ctx, mock.Anything).
+		Return(&sapcontrol.GetQueueStatisticResponse{
+			Queues: []*sapcontrol.TaskHandlerQueue{
+				{Typ: "ABAP/DIA", Now: 5, High: 30, Max: 14000, Writes: 1000, Reads: 995},
+			},
+		}, nil).
+		On("GetAlertTreeContext", ctx, mock.Anything).
+		Return(&sapcontrol.GetAlertTreeResponse{
+			Tree: []*sapcontrol.AlertNode{
+				{Name: "PRD", Parent: -1, ActualValue: sapcontrol.STATECOLOR_RED, Tid: "tid1"},
+			},
+		}, nil).
+		On("GetAlertsContext", ctx, &sapcontrol.GetAlerts{}).
+		Return(&sapcontrol.GetAlertsResponse{
+			Alerts: []*sapcontrol.Alert{
+				{
+					Object:      "Enqueue Server",
+					Attribute:   "LocksNow",
+					Value:       sapcontrol.STATECOLOR_RED,
+					Description: "Lock table overflow",
+				},
+			},
+		}, nil)
+
+	suite.webService.On("New", "00").Return(mockWebService)
+
+	gatherer := gatherers.NewSapControlGatherer(suite.webService, suite.testFS, nil)
+
+	fr := []entities.FactRequest{
+		{
+			Name:     "queues",
+			Gatherer: "sapcontrol",
+			CheckID:  "check1",
+			Argument: "GetQueueStatistic",
+		},
+		{
+			Name:     "alert_tree",
+			Gatherer: "sapcontrol",
+			CheckID:  "check1",
+			Argument: "GetAlertTree",
+		},
+		{
+			Name:     "alerts",
+			Gatherer: "sapcontrol",
+			CheckID:  "check1",
+			Argument: "GetAlerts",
+		},
+	}
+
+	instanceOutput := func(output entities.FactValue) entities.FactValue {
+		return &entities.FactValueMap{
+			Value: map[string]entities.FactValue{
+				"PRD": &entities.FactValueList{
+					Value: []entities.FactValue{
+						&entities.FactValueMap{
+							Value: map[string]entities.FactValue{
+								"instance_nr": &entities.FactValueString{Value: "00"},
+								"name":        &entities.FactValueString{Value: "ASCS00"},
+								"output":      &entities.FactValueList{Value: []entities.FactValue{output}},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	expectedFacts := []entities.Fact{
+		{
+			Name:    "queues",
+			CheckID: "check1",
+			Value: instanceOutput(&entities.FactValueMap{
+				Value: map[string]entities.FactValue{
+					"typ":    &entities.FactValueString{Value: "ABAP/DIA"},
+					"now":    &entities.FactValueInt{Value: 5},
+					"high":   &entities.FactValueInt{Value: 30},
+					"max":    &entities.FactValueInt{Value: 14000},
+					"writes": &entities.FactValueInt{Value: 1000},
+					"reads":  &entities.FactValueInt{Value: 995},
+				},
+			}),
+		},
+		{
+			Name:    "alert_tree",
+			CheckID: "check1",
+			Value: instanceOutput(&entities.FactValueMap{
+				Value: map[string]entities.FactValue{
+					"name":         &entities.FactValueString{Value: "PRD"},
+					"parent":       &entities.FactValueInt{Value: -1},
+					"actual_value": &entities.FactValueString{Value: "SAPControl-RED"},
+					"tid":          &entities.FactValueString{Value: "tid1"},
+				},
+			}),
+		},
+		{
+			Name:    "alerts",
+			CheckID: "check1",
+			Value: instanceOutput(&entities.FactValueMap{
+				Value: map[string]entities.FactValue{
+					"object":      &entities.FactValueString{Value: "Enqueue Server"},
+					"attribute":   &entities.FactValueString{Value: "LocksNow"},
+					"value":       &entities.FactValueString{Value: "SAPControl-RED"},
+					"description": &entities.FactValueString{Value: "Lock table overflow"},
+				},
+			}),
+		},
+	}
+
+	results, err := gatherer.Gather(context.Background(), fr)
+	suite.Require().NoError(err)
+	suite.Equal(expectedFacts, results)
+}
+
+func (suite *SapControlGathererSuite) TestSapControlGathererEnvironmentAndParameters() {
+	ctx := context.Background()
+	mockWebService := new(sapControlMocks.MockWebService)
+	mockWebService.
+		On("GetEnvironmentContext", ctx, mock.Anything).
+		Return(&sapcontrol.GetEnvironmentResponse{
+			Env: []string{
+				"SAPSYSTEMNAME=PRD",
+				"LD_LIBRARY_PATH=/usr/sap/PRD/ASCS00/exe",
+				"dbms_type=HDB",
+			},
+		}, nil).
+		On("ParameterValueContext", ctx, &sapcontrol.ParameterValue{Parameter: "enque/table_size"}).
+		Return(&sapcontrol.ParameterValueResponse{
+			Value: "64000",
+		}, nil)
+
+	suite.webService.On("New", "00").Return(mockWebService)
+
+	gatherer := gatherers.NewSapControlGatherer(suite.webService, suite.testFS, nil)
+
+	fr := []entities.FactRequest{
+		{
+			Name:     "environment",
+			Gatherer: "sapcontrol",
+			CheckID:  "check1",
+			Argument: "GetEnvironment",
+		},
+		{
+			Name:     "parameter",
+			Gatherer: "sapcontrol",
+			CheckID:  "check1",
+			Argument: "ParameterValue:enque/table_size",
+		},
+		{
+			Name:     "parameter_missing",
+			Gatherer: "sapcontrol",
+			CheckID:  "check1",
+			Argument: "ParameterValue",
+		},
+		{
+			Name:     "unexpected_parameter",
+			Gatherer: "sapcontrol",
+			CheckID:  "check1",
+			Argument: "GetEnvironment:PATH",
+		},
+	}
+
+	environmentVariable := func(name, value string) entities.FactValue {
+		return &entities.FactValueMap{
+			Value: map[string]entities.FactValue{
+				"name":  &entities.FactValueString{Value: name},
+				"value": &entities.FactValueString{Value: value},
+			},
+		}
+	}
+
+	expectedFacts := []entities.Fact{
+		{
+			Name:    "environment",
+			CheckID: "check1",
+			Value: &entities.FactValueMap{
+				Value: map[string]entities.FactValue{
+					"PRD": &entities.FactValueList{
+						Value: []entities.FactValue{
+							&entities.FactValueMap{
+								Value: map[string]entities.FactValue{
+									"instance_nr": &entities.FactValueString{Value: "00"},
+									"name":        &entities.FactValueString{Value: "ASCS00"},
+									"output": &entities.FactValueList{
+										Value: []entities.FactValue{
+											environmentVariable("SAPSYSTEMNAME", "PRD"),
+											environmentVariable("LD_LIBRARY_PATH", "/usr/sap/PRD/ASCS00/exe"),
+											environmentVariable("dbms_type", "HDB"),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:    "parameter",
+			CheckID: "check1",
+			Value: &entities.FactValueMap{
+				Value: map[string]entities.FactValue{
+					"PRD": &entities.FactValueList{
+						Value: []entities.FactValue{
+							&entities.FactValueMap{
+								Value: map[string]entities.FactValue{
+									"instance_nr": &entities.FactValueString{Value: "00"},
+									"name":        &entities.FactValueString{Value: "ASCS00"},
+									"output":      &entities.FactValueString{Value: "64000"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:    "parameter_missing",
+			CheckID: "check1",
+			Error: &entities.FactGatheringError{
+				Message: "missing required argument: ParameterValue requires a parameter, " +
+					"e.g. ParameterValue:SAPSYSTEMNAME",
+				Type: "sapcontrol-missing-argument",
+			},
+		},
+		{
+			Name:    "unexpected_parameter",
+			CheckID: "check1",
+			Error: &entities.FactGatheringError{
+				Message: "the requested argument is not currently supported: GetEnvironment:PATH",
+				Type:    "sapcontrol-unsupported-argument",
+			},
+		},
+	}
+
+	results, err := gatherer.Gather(context.Background(), fr)
+	suite.Require().NoError(err)
+	suite.Equal(expectedFacts, results)
+}
+
 func (suite *SapControlGathererSuite) TestSapControlGathererContextCancelled() {
 	gatherer := gatherers.NewSapControlGatherer(suite.webService, suite.testFS, nil)
 
@@ -7,9 +7,11 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
 	"regexp"
 	"strings"
 
+	"github.com/iancoleman/strcase"
 	"github.com/trento-project/agent/v3/pkg/factsengine/entities"
 	"github.com/trento-project/agent/v3/pkg/utils"
 )
@@ -19,6 +21,14 @@ const (
 
 	sapHostCtrlUnsupportedFunctionMsg = "requested webmethod not supported"
 	sapHostCtrlParseMsg               = "error while parsing saphostctrl output"
+
+	sapHostCtrlPath = "/usr/sap/hostctrl/exe/saphostctrl"
+	sapHostExecPath = "/usr/sap/hostctrl/exe/saphostexec"
+
+	// sapHostCtrlCIMObjectWebmethod requests the instances of a CIM class, with the GetCIMObject:<class> format
+	sapHostCtrlCIMObjectWebmethod      = "GetCIMObject"
+	sapHostCtrlDatabaseStatusWebmethod = "GetDatabaseStatus"
+	sapHostCtrlVersionInfoArgument     = "VersionInfo"
 )
 
 var (
@@ -26,12 +36,22 @@ var (
 		`:\s*([^-]+?)\s*-\s*(\d+)\s*-\s*([^,]+?)` +
 		`\s*-\s*(\d+),\s*patch\s*(\d+),\s*changelist\s*(\d+)$`)
 	saphostCtrlPingParsingRegexp = regexp.MustCompile(`(SUCCESS|FAILED) \( *(\d+) usec\)`)
+	// Instance name: HDB00, Hostname: vmhana01, Vendor: HDB
+	saphostCtrlKeyValueRegexp  = regexp.MustCompile(`(?:^|,\s*)([A-Za-z][A-Za-z ]*?):\s`)
+	saphostCtrlCIMClassRegexp  = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+	saphostCtrlCIMIntegerTypes = regexp.MustCompile(`^[SU][Ii]nt(8|16|32|64)$`)
+	saphostCtrlCIMRealTypes    = regexp.MustCompile(`^Real(32|64)$`)
+	// kernel release                7.22
+	saphostExecVersionRegexp = regexp.MustCompile(`^(kernel release|kernel make variant|compiled on|compiled for|` +
+		`compilation mode|compile time|patch number|latest change number)\s{2,}(.*?)\s*$`)
 )
 
 //nolint:gochecknoglobals
 var whitelistedWebmethods = map[string]func(string) (entities.FactValue, *entities.FactGatheringError){
-	"Ping":          parsePing,
-	"ListInstances": parseInstances,
+	"Ping":                parsePing,
+	"ListInstances":       parseInstances,
+	"ListDatabases":       parseDatabases,
+	"ListDatabaseSystems": parseDatabaseSystems,
 }
 
 //nolint:gochecknoglobals
@@ -112,6 +132,22 @@ func handleWebmethod(
 	executor utils.CommandExecutor,
 	webMethod string,
 ) (entities.FactValue, *entities.FactGatheringError) {
+	webMethodName, parameter, hasParameter := strings.Cut(webMethod, ":")
+
+	switch {
+	case webMethodName == sapHostCtrlCIMObjectWebmethod && saphostCtrlCIMClassRegexp.MatchString(parameter):
+		return handleCIMObject(ctx, executor, parameter)
+	case hasParameter:
+		gatheringError := SapHostCtrlUnsupportedFunction.Wrap(webMethod)
+		slog.Error(gatheringError.Error())
+
+		return nil, gatheringError
+	case webMethod == sapHostCtrlDatabaseStatusWebmethod:
+		return handleDatabaseStatus(ctx, executor)
+	case webMethod == sapHostCtrlVersionInfoArgument:
+		return handleVersionInfo(ctx, executor)
+	}
+
 	webMethodHandler, ok := whitelistedWebmethods[webMethod]
 
 	if !ok {
@@ -135,8 +171,11 @@ func executeSapHostCtrlCommand(
 	ctx context.Context,
 	executor utils.CommandExecutor,
 	command string,
+	args ...string,
 ) (string, *entities.FactGatheringError) {
-	saphostctlOutput, err := executor.OutputContext(ctx, "/usr/sap/hostctrl/exe/saphostctrl", "-function", command)
+	commandArgs := append([]string{"-function", command}, args...)
+
+	saphostctlOutput, err := executor.OutputContext(ctx, sapHostCtrlPath, commandArgs...)
 	if err != nil {
 		gatheringError := SapHostCtrlCommandError.Wrap(err.Error())
 		slog.Error(gatheringError.Error())
@@ -191,3 +230,305 @@ func parseInstances(commandOutput string) (entities.FactValue, *entities.FactGat
 
 	return result, nil
 }
+
+// parseDatabases parses the ListDatabases output, with the database instances
+// and their databases and components indented below:
+//
+//	Instance name: HDB00, Hostname: vmhana01, Vendor: HDB, Type: hdb, Release: 2.00.070.00
+//	  Database name: PRD, Status: Running
+//	    Component name: System (PRD), Status: Running
+func parseDatabases(commandOutput string) (entities.FactValue, *entities.FactGatheringError) {
+	return parseSapHostCtrlTree(commandOutput, []string{"databases", "components"})
+}
+
+// parseDatabaseSystems parses the ListDatabaseSystems output, with the database systems
+// and their instances and databases indented below:
+//
+//	Database System: PRD, Vendor: SYB, Type: syb, Release: 16.0.04.04
+//	  Instance name: PRD, Hostname: aseprd
+//	    Database name: PRD, Status: Running
+func parseDatabaseSystems(commandOutput string) (entities.FactValue, *entities.FactGatheringError) {
+	return parseSapHostCtrlTree(commandOutput, []string{"instances", "databases"})
+}
+
+// parseSapHostCtrlTree parses the indented "Key: value, Key: value" lines into a list of maps,
+// where the entries of each indentation level are stored in the given child list of their parent
+func parseSapHostCtrlTree(
+	commandOutput string,
+	childrenNames []string,
+) (entities.FactValue, *entities.FactGatheringError) {
+	type treeLevel struct {
+		indentation int
+		entry       map[string]entities.FactValue
+	}
+
+	entries := []entities.FactValue{}
+	levels := []treeLevel{}
+
+	for _, line := range strings.Split(commandOutput, "\n") {
+		values := parseSapHostCtrlKeyValues(line)
+		if len(values) == 0 {
+			continue
+		}
+
+		indentation := len(line) - len(strings.TrimLeft(line, " \t"))
+		for len(levels) > 0 && levels[len(levels)-1].indentation >= indentation {
+			levels = levels[:len(levels)-1]
+		}
+
+		entry := &entities.FactValueMap{Value: values}
+
+		if len(levels) == 0 {
+			entries = append(entries, entry)
+		} else {
+			if len(levels) > len(childrenNames) {
+				return nil, SapHostCtrlParseError.Wrap(fmt.Sprintf("unexpected nested line: %s", line))
+			}
+
+			parent := levels[len(levels)-1].entry
+			childrenName := childrenNames[len(levels)-1]
+
+			children, ok := parent[childrenName].(*entities.FactValueList)
+			if !ok {
+				children = &entities.FactValueList{Value: []entities.FactValue{}}
+				parent[childrenName] = children
+			}
+
+			children.AppendValue(entry)
+		}
+
+		levels = append(levels, treeLevel{indentation: indentation, entry: values})
+	}
+
+	return &entities.FactValueList{Value: entries}, nil
+}
+
+// parseSapHostCtrlKeyValues parses a "Key: value, Key: value" line, using snake case keys
+func parseSapHostCtrlKeyValues(line string) map[string]entities.FactValue {
+	values := map[string]entities.FactValue{}
+
+	for key, value := range parseSapHostCtrlKeyValueStrings(line) {
+		values[key] = &entities.FactValueString{Value: value}
+	}
+
+	return values
+}
+
+func parseSapHostCtrlKeyValueStrings(line string) map[string]string {
+	line = strings.TrimSpace(line)
+	values := map[string]string{}
+
+	matches := saphostCtrlKeyValueRegexp.FindAllStringSubmatchIndex(line, -1)
+	for index, match := range matches {
+		valueEnd := len(line)
+		if index+1 < len(matches) {
+			valueEnd = matches[index+1][0]
+		}
+
+		key := strcase.ToSnake(line[match[2]:match[3]])
+		values[key] = strings.TrimSpace(line[match[1]:valueEnd])
+	}
+
+	return values
+}
+
+type sapHostCtrlDatabase struct {
+	instanceName string
+	name         string
+	databaseType string
+}
+
+// handleDatabaseStatus gets the status of all the databases found by ListDatabases, so the
+// databases of any vendor are covered without knowing their names in advance.
+// A database whose status cannot be retrieved is reported with an error field,
+// without discarding the status of the other databases
+func handleDatabaseStatus(
+	ctx context.Context,
+	executor utils.CommandExecutor,
+) (entities.FactValue, *entities.FactGatheringError) {
+	listOutput, commandError := executeSapHostCtrlCommand(ctx, executor, "ListDatabases")
+	if commandError != nil {
+		return nil, commandError
+	}
+
+	statuses := []entities.FactValue{}
+
+	for _, database := range listSapHostCtrlDatabases(listOutput) {
+		status := map[string]entities.FactValue{
+			"instance_name": &entities.FactValueString{Value: database.instanceName},
+			"database_name": &entities.FactValueString{Value: database.name},
+			"type":          &entities.FactValueString{Value: database.databaseType},
+		}
+
+		statusOutput, commandError := executeSapHostCtrlCommand(ctx, executor, sapHostCtrlDatabaseStatusWebmethod,
+			"-dbname", database.name,
+			"-dbtype", database.databaseType)
+		if commandError != nil {
+			status["error"] = &entities.FactValueString{Value: commandError.Message}
+		} else {
+			maps.Copy(status, parseDatabaseStatus(statusOutput))
+		}
+
+		statuses = append(statuses, &entities.FactValueMap{Value: status})
+	}
+
+	return &entities.FactValueList{Value: statuses}, nil
+}
+
+// listSapHostCtrlDatabases returns the databases of the ListDatabases output, as parsed by parseDatabases,
+// along with the instance they belong to. Databases of instances without type are skipped,
+// as the type is required to get their status
+func listSapHostCtrlDatabases(commandOutput string) []sapHostCtrlDatabase {
+	databases := []sapHostCtrlDatabase{}
+
+	var instanceName, databaseType string
+
+	for _, line := range strings.Split(commandOutput, "\n") {
+		values := parseSapHostCtrlKeyValueStrings(line)
+
+		if name, found := values["instance_name"]; found {
+			instanceName = name
+			databaseType = values["type"]
+
+			continue
+		}
+
+		name, found := values["database_name"]
+		if !found || name == "" || databaseType == "" {
+			continue
+		}
+
+		databases = append(databases, sapHostCtrlDatabase{
+			instanceName: instanceName,
+			name:         name,
+			databaseType: databaseType,
+		})
+	}
+
+	return databases
+}
+
+// parseDatabaseStatus parses the GetDatabaseStatus output:
+//
+//	Database Status: Running
+//	  Component name: System (PRD), Status: Running (Database is running)
+func parseDatabaseStatus(commandOutput string) map[string]entities.FactValue {
+	status := map[string]entities.FactValue{}
+	components := []entities.FactValue{}
+
+	for _, line := range strings.Split(commandOutput, "\n") {
+		values := parseSapHostCtrlKeyValues(line)
+
+		switch {
+		case values["database_status"] != nil:
+			status["status"] = values["database_status"]
+		case values["component_name"] != nil:
+			components = append(components, &entities.FactValueMap{Value: values})
+		}
+	}
+
+	status["components"] = &entities.FactValueList{Value: components}
+
+	return status
+}
+
+// handleCIMObject enumerates the instances of a CIM class. Each instance properties are
+// printed in "Name , Type , Value" lines, and the instances are separated by lines of asterisks:
+//
+//	*********************************************************
+//	 CreationClassName , String , SAPOSCol
+//	 NumberOfProcessors , UInt32 , 4
+func handleCIMObject(
+	ctx context.Context,
+	executor utils.CommandExecutor,
+	className string,
+) (entities.FactValue, *entities.FactGatheringError) {
+	output, commandError := executeSapHostCtrlCommand(ctx, executor, sapHostCtrlCIMObjectWebmethod,
+		"-enuminstances", className)
+	if commandError != nil {
+		return nil, commandError
+	}
+
+	objects := []entities.FactValue{}
+
+	var current map[string]entities.FactValue
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+
+		if strings.HasPrefix(line, "***") {
+			current = nil
+
+			continue
+		}
+
+		fields := strings.SplitN(line, " , ", 3)
+		if len(fields) != 3 {
+			continue
+		}
+
+		if current == nil {
+			current = map[string]entities.FactValue{}
+			objects = append(objects, &entities.FactValueMap{Value: current})
+		}
+
+		current[strcase.ToSnake(fields[0])] = cimPropertyToFactValue(fields[1], fields[2])
+	}
+
+	return &entities.FactValueList{Value: objects}, nil
+}
+
+func cimPropertyToFactValue(propertyType, value string) entities.FactValue {
+	switch {
+	case propertyType == "Boolean",
+		saphostCtrlCIMIntegerTypes.MatchString(propertyType),
+		saphostCtrlCIMRealTypes.MatchString(propertyType):
+		return entities.ParseStringToFactValue(value)
+	default:
+		return &entities.FactValueString{Value: value}
+	}
+}
+
+// handleVersionInfo gets the SAP host agent version from the saphostexec -version output:
+//
+//	kernel release                7.22
+//	patch number                  52
+func handleVersionInfo(
+	ctx context.Context,
+	executor utils.CommandExecutor,
+) (entities.FactValue, *entities.FactGatheringError) {
+	output, err := executor.OutputContext(ctx, sapHostExecPath, "-version")
+	if err != nil {
+		gatheringError := SapHostCtrlCommandError.Wrap(err.Error())
+		slog.Error(gatheringError.Error())
+
+		return nil, gatheringError
+	}
+
+	versionInfo := map[string]entities.FactValue{}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := saphostExecVersionRegexp.FindStringSubmatch(strings.TrimSpace(line))
+		if fields == nil {
+			continue
+		}
+
+		key := strcase.ToSnake(fields[1])
+		if _, found := versionInfo[key]; found {
+			continue
+		}
+
+		switch key {
+		case "patch_number", "latest_change_number":
+			versionInfo[key] = entities.ParseStringToFactValue(fields[2])
+		default:
+			versionInfo[key] = &entities.FactValueString{Value: fields[2]}
+		}
+	}
+
+	if len(versionInfo) == 0 {
+		return nil, SapHostCtrlParseError.Wrap(string(output))
+	}
+
+	return &entities.FactValueMap{Value: versionInfo}, nil
+}
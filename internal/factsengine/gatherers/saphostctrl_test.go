@@ -197,6 +197,290 @@ func (suite *SapHostCtrlTestSuite) TestSapHostCtrlGatherPingFailed() {
 	suite.ElementsMatch(expectedResults, factResults)
 }
 
+func (suite *SapHostCtrlTestSuite) TestSapHostCtrlGatherListDatabases() {
+	suite.mockExecutor.On("OutputContext", mock.Anything, "/usr/sap/hostctrl/exe/saphostctrl", "-function", "ListDatabases").Return(
+		[]byte("Instance name: PRD, Hostname: aseprd, Vendor: SYB, Type: syb, Release: 16.0.04.04\n"+
+			"  Database name: PRD, Status: Running\n"+
+			"    Component name: PRD (Database), Status: Running\n"+
+			"  Database name: saptools, Status: Running\n"), nil)
+
+	p := gatherers.NewSapHostCtrlGatherer(suite.mockExecutor)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "list_databases",
+			Gatherer: "saphostctrl",
+			Argument: "ListDatabases",
+			CheckID:  "check1",
+		},
+	}
+
+	factResults, err := p.Gather(context.Background(), factRequests)
+
+	expectedResults := []entities.Fact{
+		{
+			Name: "list_databases",
+			Value: &entities.FactValueList{
+				Value: []entities.FactValue{
+					&entities.FactValueMap{
+						Value: map[string]entities.FactValue{
+							"instance_name": &entities.FactValueString{Value: "PRD"},
+							"hostname":      &entities.FactValueString{Value: "aseprd"},
+							"vendor":        &entities.FactValueString{Value: "SYB"},
+							"type":          &entities.FactValueString{Value: "syb"},
+							"release":       &entities.FactValueString{Value: "16.0.04.04"},
+							"databases": &entities.FactValueList{
+								Value: []entities.FactValue{
+									&entities.FactValueMap{
+										Value: map[string]entities.FactValue{
+											"database_name": &entities.FactValueString{Value: "PRD"},
+											"status":        &entities.FactValueString{Value: "Running"},
+											"components": &entities.FactValueList{
+												Value: []entities.FactValue{
+													&entities.FactValueMap{
+														Value: map[string]entities.FactValue{
+															"component_name": &entities.FactValueString{Value: "PRD (Database)"},
+															"status":         &entities.FactValueString{Value: "Running"},
+														},
+													},
+												},
+											},
+										},
+									},
+									&entities.FactValueMap{
+										Value: map[string]entities.FactValue{
+											"database_name": &entities.FactValueString{Value: "saptools"},
+											"status":        &entities.FactValueString{Value: "Running"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			CheckID: "check1",
+		},
+	}
+
+	suite.Require().NoError(err)
+	suite.ElementsMatch(expectedResults, factResults)
+}
+
+func (suite *SapHostCtrlTestSuite) TestSapHostCtrlGatherDatabaseStatus() {
+	suite.mockExecutor.On("OutputContext", mock.Anything, "/usr/sap/hostctrl/exe/saphostctrl", "-function", "ListDatabases").Return(
+		[]byte("Instance name: PRD, Hostname: oraprd, Vendor: ORA, Type: ora, Release: 19.0.0.0.0\n"+
+			"  Database name: PRD, Status: Running\n"), nil)
+	suite.mockExecutor.On("OutputContext", mock.Anything, "/usr/sap/hostctrl/exe/saphostctrl",
+		"-function", "GetDatabaseStatus", "-dbname", "PRD", "-dbtype", "ora").Return(
+		[]byte("Database Status: Running\n"+
+			" Component name: Instance (Database Instance), Status: Running (Database instance is running)\n"+
+			" Component name: Listener (Oracle Listener), Status: Running\n"), nil)
+
+	p := gatherers.NewSapHostCtrlGatherer(suite.mockExecutor)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "database_status",
+			Gatherer: "saphostctrl",
+			Argument: "GetDatabaseStatus",
+			CheckID:  "check1",
+		},
+	}
+
+	factResults, err := p.Gather(context.Background(), factRequests)
+
+	expectedResults := []entities.Fact{
+		{
+			Name: "database_status",
+			Value: &entities.FactValueList{
+				Value: []entities.FactValue{
+					&entities.FactValueMap{
+						Value: map[string]entities.FactValue{
+							"instance_name": &entities.FactValueString{Value: "PRD"},
+							"database_name": &entities.FactValueString{Value: "PRD"},
+							"type":          &entities.FactValueString{Value: "ora"},
+							"status":        &entities.FactValueString{Value: "Running"},
+							"components": &entities.FactValueList{
+								Value: []entities.FactValue{
+									&entities.FactValueMap{
+										Value: map[string]entities.FactValue{
+											"component_name": &entities.FactValueString{Value: "Instance (Database Instance)"},
+											"status":         &entities.FactValueString{Value: "Running (Database instance is running)"},
+										},
+									},
+									&entities.FactValueMap{
+										Value: map[string]entities.FactValue{
+											"component_name": &entities.FactValueString{Value: "Listener (Oracle Listener)"},
+											"status":         &entities.FactValueString{Value: "Running"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			CheckID: "check1",
+		},
+	}
+
+	suite.Require().NoError(err)
+	suite.ElementsMatch(expectedResults, factResults)
+}
+
+func (suite *SapHostCtrlTestSuite) TestSapHostCtrlGatherDatabaseStatusError() {
+	suite.mockExecutor.On("OutputContext", mock.Anything, "/usr/sap/hostctrl/exe/saphostctrl", "-function", "ListDatabases").Return(
+		[]byte("Instance name: HDB00, Hostname: vmhana01, Vendor: HDB, Type: hdb, Release: 2.00.070.00\n"+
+			"  Database name: SYSTEMDB, Status: Running\n"+
+			"    Component name: System (SYSTEMDB), Status: Running\n"+
+			"  Database name: PRD, Status: Error\n"), nil)
+	suite.mockExecutor.On("OutputContext", mock.Anything, "/usr/sap/hostctrl/exe/saphostctrl",
+		"-function", "GetDatabaseStatus", "-dbname", "SYSTEMDB", "-dbtype", "hdb").Return(
+		[]byte("Database Status: Running\n"), nil)
+	suite.mockExecutor.On("OutputContext", mock.Anything, "/usr/sap/hostctrl/exe/saphostctrl",
+		"-function", "GetDatabaseStatus", "-dbname", "PRD", "-dbtype", "hdb").Return(
+		nil, errors.New("exit status 1"))
+
+	p := gatherers.NewSapHostCtrlGatherer(suite.mockExecutor)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "database_status",
+			Gatherer: "saphostctrl",
+			Argument: "GetDatabaseStatus",
+			CheckID:  "check1",
+		},
+	}
+
+	factResults, err := p.Gather(context.Background(), factRequests)
+
+	expectedResults := []entities.Fact{
+		{
+			Name: "database_status",
+			Value: &entities.FactValueList{
+				Value: []entities.FactValue{
+					&entities.FactValueMap{
+						Value: map[string]entities.FactValue{
+							"instance_name": &entities.FactValueString{Value: "HDB00"},
+							"database_name": &entities.FactValueString{Value: "SYSTEMDB"},
+							"type":          &entities.FactValueString{Value: "hdb"},
+							"status":        &entities.FactValueString{Value: "Running"},
+							"components":    &entities.FactValueList{Value: []entities.FactValue{}},
+						},
+					},
+					&entities.FactValueMap{
+						Value: map[string]entities.FactValue{
+							"instance_name": &entities.FactValueString{Value: "HDB00"},
+							"database_name": &entities.FactValueString{Value: "PRD"},
+							"type":          &entities.FactValueString{Value: "hdb"},
+							"error":         &entities.FactValueString{Value: "error executing saphostctrl command: exit status 1"},
+						},
+					},
+				},
+			},
+			CheckID: "check1",
+		},
+	}
+
+	suite.Require().NoError(err)
+	suite.ElementsMatch(expectedResults, factResults)
+}
+
+func (suite *SapHostCtrlTestSuite) TestSapHostCtrlGatherCIMObject() {
+	suite.mockExecutor.On("OutputContext", mock.Anything, "/usr/sap/hostctrl/exe/saphostctrl",
+		"-function", "GetCIMObject", "-enuminstances", "SAPOSCol").Return(
+		[]byte("*********************************************************\n"+
+			" CreationClassName , String , SAPOSCol\n"+
+			" NumberOfProcessors , UInt32 , 4\n"+
+			" CPULoad , Real64 , 0.5\n"+
+			" IsVirtual , Boolean , true\n"+
+			"*********************************************************\n"), nil)
+
+	p := gatherers.NewSapHostCtrlGatherer(suite.mockExecutor)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "os_collector",
+			Gatherer: "saphostctrl",
+			Argument: "GetCIMObject:SAPOSCol",
+			CheckID:  "check1",
+		},
+	}
+
+	factResults, err := p.Gather(context.Background(), factRequests)
+
+	expectedResults := []entities.Fact{
+		{
+			Name: "os_collector",
+			Value: &entities.FactValueList{
+				Value: []entities.FactValue{
+					&entities.FactValueMap{
+						Value: map[string]entities.FactValue{
+							"creation_class_name":  &entities.FactValueString{Value: "SAPOSCol"},
+							"number_of_processors": &entities.FactValueInt{Value: 4},
+							"cpu_load":             &entities.FactValueFloat{Value: 0.5},
+							"is_virtual":           &entities.FactValueBool{Value: true},
+						},
+					},
+				},
+			},
+			CheckID: "check1",
+		},
+	}
+
+	suite.Require().NoError(err)
+	suite.ElementsMatch(expectedResults, factResults)
+}
+
+func (suite *SapHostCtrlTestSuite) TestSapHostCtrlGatherVersionInfo() {
+	suite.mockExecutor.On("OutputContext", mock.Anything, "/usr/sap/hostctrl/exe/saphostexec", "-version").Return(
+		[]byte("/usr/sap/hostctrl/exe/saphostexec information\n"+
+			"=============================================\n"+
+			"------------------------------------------------------------\n"+
+			"|  SAPHOSTAGENT information\n"+
+			"------------------------------------------------------------\n"+
+			"kernel release                7.22\n"+
+			"kernel make variant           722_REL\n"+
+			"compiled for                  64 BIT\n"+
+			"compile time                  Jul 27 2020 16:26:47\n"+
+			"patch number                  52\n"+
+			"latest change number          2022424\n"), nil)
+
+	p := gatherers.NewSapHostCtrlGatherer(suite.mockExecutor)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "version_info",
+			Gatherer: "saphostctrl",
+			Argument: "VersionInfo",
+			CheckID:  "check1",
+		},
+	}
+
+	factResults, err := p.Gather(context.Background(), factRequests)
+
+	expectedResults := []entities.Fact{
+		{
+			Name: "version_info",
+			Value: &entities.FactValueMap{
+				Value: map[string]entities.FactValue{
+					"kernel_release":       &entities.FactValueString{Value: "7.22"},
+					"kernel_make_variant":  &entities.FactValueString{Value: "722_REL"},
+					"compiled_for":         &entities.FactValueString{Value: "64 BIT"},
+					"compile_time":         &entities.FactValueString{Value: "Jul 27 2020 16:26:47"},
+					"patch_number":         &entities.FactValueInt{Value: 52},
+					"latest_change_number": &entities.FactValueInt{Value: 2022424},
+				},
+			},
+			CheckID: "check1",
+		},
+	}
+
+	suite.Require().NoError(err)
+	suite.ElementsMatch(expectedResults, factResults)
+}
+
 func (suite *SapHostCtrlTestSuite) TestSapHostCtrlGatherError() {
 	suite.mockExecutor.On("OutputContext", mock.Anything, "/usr/sap/hostctrl/exe/saphostctrl", "-function", "Ping").Return(
 		[]byte("Unexpected output\n"), nil)
@@ -224,6 +508,12 @@ func (suite *SapHostCtrlTestSuite) TestSapHostCtrlGatherError() {
 			Argument: "ListInstances",
 			CheckID:  "check3",
 		},
+		{
+			Name:     "cim_object",
+			Gatherer: "saphostctrl",
+			Argument: "GetCIMObject:SAPOSCol;ls",
+			CheckID:  "check4",
+		},
 	}
 
 	factResults, err := p.Gather(context.Background(), factRequests)
@@ -256,6 +546,15 @@ func (suite *SapHostCtrlTestSuite) TestSapHostCtrlGatherError() {
 			},
 			CheckID: "check3",
 		},
+		{
+			Name:  "cim_object",
+			Value: nil,
+			Error: &entities.FactGatheringError{
+				Message: "requested webmethod not supported: GetCIMObject:SAPOSCol;ls",
+				Type:    "saphostctrl-webmethod-error",
+			},
+			CheckID: "check4",
+		},
 	}
 
 	suite.Require().NoError(err)
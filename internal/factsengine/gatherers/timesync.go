@@ -0,0 +1,288 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package gatherers
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"github.com/trento-project/agent/v3/pkg/factsengine/entities"
+	"github.com/trento-project/agent/v3/pkg/utils"
+)
+
+const (
+	TimeSyncGathererName = "time_sync"
+
+	timeSyncTrackingArgument    = "tracking"
+	timeSyncSourcesArgument     = "sources"
+	timeSyncTimedatectlArgument = "timedatectl"
+
+	chronycTrackingFields = 14
+	chronycSourcesFields  = 10
+	chronyNotSynchronised = "Not synchronised"
+	chronycReachBase      = 8
+	chronycReachBitSize   = 32
+)
+
+//nolint:gochecknoglobals
+var (
+	TimeSyncMissingArgument = entities.FactGatheringError{
+		Type:    "time_sync-missing-argument",
+		Message: missingRequiredArgument,
+	}
+
+	TimeSyncUnsupportedArgument = entities.FactGatheringError{
+		Type:    "time_sync-unsupported-argument",
+		Message: "requested argument not supported",
+	}
+
+	TimeSyncCommandError = entities.FactGatheringError{
+		Type:    "time_sync-command-error",
+		Message: "error running time synchronisation command",
+	}
+
+	TimeSyncDecodingError = entities.FactGatheringError{
+		Type:    "time_sync-decoding-error",
+		Message: "error decoding time synchronisation output",
+	}
+
+	chronySourceModes = map[string]string{
+		"^": "server",
+		"=": "peer",
+		"#": "refclock",
+	}
+
+	chronySourceStates = map[string]string{
+		"*": "selected",
+		"+": "combined",
+		"-": "not_combined",
+		"?": "unreachable",
+		"x": "falseticker",
+		"~": "too_variable",
+	}
+)
+
+// TimeSyncGatherer reports the time synchronisation status of the host.
+// Supported arguments:
+//   - tracking: chronyd synchronisation status, with the reference, stratum, offset and leap status
+//   - sources: configured chronyd servers, peers and reference clocks with their state
+//   - timedatectl: systemd time settings, including whether NTP is enabled and the clock synchronised
+type TimeSyncGatherer struct {
+	executor utils.CommandExecutor
+}
+
+func NewDefaultTimeSyncGatherer() *TimeSyncGatherer {
+	return NewTimeSyncGatherer(utils.Executor{})
+}
+
+func NewTimeSyncGatherer(executor utils.CommandExecutor) *TimeSyncGatherer {
+	return &TimeSyncGatherer{
+		executor: executor,
+	}
+}
+
+func (g *TimeSyncGatherer) Gather(ctx context.Context, factsRequests []entities.FactRequest) ([]entities.Fact, error) {
+	slog.Info("Starting facts gathering process", "gatherer", TimeSyncGathererName)
+
+	facts := make([]entities.Fact, 0, len(factsRequests))
+
+	for _, factReq := range factsRequests {
+		var fact entities.Fact
+
+		factValue, err := g.gatherTimeSync(ctx, factReq.Argument)
+
+		switch {
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case err != nil:
+			slog.Error(err.Error())
+			fact = entities.NewFactGatheredWithError(factReq, err)
+		default:
+			fact = entities.NewFactGatheredWithRequest(factReq, factValue)
+		}
+
+		facts = append(facts, fact)
+	}
+
+	slog.Info("Requested facts gathered", "gatherer", TimeSyncGathererName)
+
+	return facts, nil
+}
+
+func (g *TimeSyncGatherer) gatherTimeSync(
+	ctx context.Context,
+	argument string,
+) (entities.FactValue, *entities.FactGatheringError) {
+	var (
+		command string
+		args    []string
+		parser  func([]byte) (entities.FactValue, error)
+	)
+
+	switch argument {
+	case "":
+		return nil, &TimeSyncMissingArgument
+	case timeSyncTrackingArgument:
+		command, args, parser = "/usr/bin/chronyc", []string{"-c", "tracking"}, parseChronycTracking
+	case timeSyncSourcesArgument:
+		command, args, parser = "/usr/bin/chronyc", []string{"-c", "sources"}, parseChronycSources
+	case timeSyncTimedatectlArgument:
+		command, args, parser = "/usr/bin/timedatectl", []string{"show"}, parseTimedatectlShow
+	default:
+		return nil, TimeSyncUnsupportedArgument.Wrap(argument)
+	}
+
+	output, err := g.executor.OutputContext(ctx, command, args...)
+	if err != nil {
+		return nil, TimeSyncCommandError.Wrap(fmt.Sprintf("%s %s: %s", command, strings.Join(args, " "), err))
+	}
+
+	factValue, err := parser(output)
+	if err != nil {
+		return nil, TimeSyncDecodingError.Wrap(err.Error())
+	}
+
+	return factValue, nil
+}
+
+func readChronycRecords(output []byte, fields int) ([][]string, error) {
+	reader := csv.NewReader(strings.NewReader(string(output)))
+	reader.FieldsPerRecord = fields
+
+	return reader.ReadAll()
+}
+
+// parseChronycTracking parses the chronyc -c tracking output, a single CSV line with the fields:
+// reference id, reference name, stratum, reference time, system time offset, last offset, RMS offset,
+// frequency, residual frequency, skew, root delay, root dispersion, update interval and leap status
+func parseChronycTracking(output []byte) (entities.FactValue, error) {
+	records, err := readChronycRecords(output, chronycTrackingFields)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) != 1 {
+		return nil, fmt.Errorf("unexpected chronyc tracking output: %s", string(output))
+	}
+
+	record := records[0]
+
+	numbers := make([]float64, 0, len(record))
+
+	for _, field := range record[3:13] {
+		number, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chronyc tracking value %s: %w", field, err)
+		}
+
+		numbers = append(numbers, number)
+	}
+
+	stratum, err := strconv.Atoi(record[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid chronyc tracking stratum %s: %w", record[2], err)
+	}
+
+	return &entities.FactValueMap{Value: map[string]entities.FactValue{
+		"reference_id":       &entities.FactValueString{Value: record[0]},
+		"reference_name":     &entities.FactValueString{Value: record[1]},
+		"stratum":            &entities.FactValueInt{Value: stratum},
+		"reference_time":     &entities.FactValueFloat{Value: numbers[0]},
+		"system_time_offset": &entities.FactValueFloat{Value: numbers[1]},
+		"last_offset":        &entities.FactValueFloat{Value: numbers[2]},
+		"rms_offset":         &entities.FactValueFloat{Value: numbers[3]},
+		"frequency":          &entities.FactValueFloat{Value: numbers[4]},
+		"residual_frequency": &entities.FactValueFloat{Value: numbers[5]},
+		"skew":               &entities.FactValueFloat{Value: numbers[6]},
+		"root_delay":         &entities.FactValueFloat{Value: numbers[7]},
+		"root_dispersion":    &entities.FactValueFloat{Value: numbers[8]},
+		"update_interval":    &entities.FactValueFloat{Value: numbers[9]},
+		"leap_status":        &entities.FactValueString{Value: record[13]},
+		"synchronised":       &entities.FactValueBool{Value: record[13] != chronyNotSynchronised},
+	}}, nil
+}
+
+// parseChronycSources parses the chronyc -c sources output, a CSV line per source with the fields:
+// mode, state, name, stratum, poll, reach, last rx, last offset, measured offset and error
+func parseChronycSources(output []byte) (entities.FactValue, error) {
+	records, err := readChronycRecords(output, chronycSourcesFields)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]entities.FactValue, 0, len(records))
+
+	for _, record := range records {
+		mode, found := chronySourceModes[record[0]]
+		if !found {
+			return nil, fmt.Errorf("unknown chronyc source mode %s", record[0])
+		}
+
+		state, found := chronySourceStates[record[1]]
+		if !found {
+			return nil, fmt.Errorf("unknown chronyc source state %s", record[1])
+		}
+
+		// the reach is the octal register of the last 8 polls
+		reach, err := strconv.ParseInt(record[5], chronycReachBase, chronycReachBitSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chronyc source reach %s: %w", record[5], err)
+		}
+
+		sources = append(sources, &entities.FactValueMap{Value: map[string]entities.FactValue{
+			"mode":            &entities.FactValueString{Value: mode},
+			"state":           &entities.FactValueString{Value: state},
+			"name":            &entities.FactValueString{Value: record[2]},
+			"stratum":         entities.ParseStringToFactValue(record[3]),
+			"poll":            entities.ParseStringToFactValue(record[4]),
+			"reach":           &entities.FactValueInt{Value: int(reach)},
+			"last_rx":         entities.ParseStringToFactValue(record[6]),
+			"last_offset":     entities.ParseStringToFactValue(record[7]),
+			"measured_offset": entities.ParseStringToFactValue(record[8]),
+			"error":           entities.ParseStringToFactValue(record[9]),
+		}})
+	}
+
+	return &entities.FactValueList{Value: sources}, nil
+}
+
+// parseTimedatectlShow parses the timedatectl show key=value output,
+// converting the keys to snake case, e.g. NTPSynchronized to ntp_synchronized
+// and TimeUSec to time_usec
+func parseTimedatectlShow(output []byte) (entities.FactValue, error) {
+	values := map[string]entities.FactValue{}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("unexpected timedatectl line: %s", line)
+		}
+
+		switch value {
+		case "yes":
+			values[timedatectlKey(key)] = &entities.FactValueBool{Value: true}
+		case "no":
+			values[timedatectlKey(key)] = &entities.FactValueBool{Value: false}
+		default:
+			values[timedatectlKey(key)] = entities.ParseStringToFactValue(value)
+		}
+	}
+
+	return &entities.FactValueMap{Value: values}, nil
+}
+
+func timedatectlKey(key string) string {
+	// systemd uses the USec suffix for microseconds
+	return strcase.ToSnake(strings.ReplaceAll(key, "USec", "Usec"))
+}
@@ -0,0 +1,253 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package gatherers_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/trento-project/agent/v3/internal/factsengine/gatherers"
+	"github.com/trento-project/agent/v3/pkg/factsengine/entities"
+	utilsMocks "github.com/trento-project/agent/v3/pkg/utils/mocks"
+	"github.com/trento-project/agent/v3/test/helpers"
+)
+
+type TimeSyncGathererTestSuite struct {
+	suite.Suite
+
+	mockExecutor *utilsMocks.MockCommandExecutor
+}
+
+func TestTimeSyncGathererSuite(t *testing.T) {
+	suite.Run(t, new(TimeSyncGathererTestSuite))
+}
+
+func (suite *TimeSyncGathererTestSuite) SetupTest() {
+	suite.mockExecutor = new(utilsMocks.MockCommandExecutor)
+}
+
+func (suite *TimeSyncGathererTestSuite) readFixture(name string) []byte {
+	content, err := os.ReadFile(helpers.GetFixturePath("gatherers/timesync/" + name))
+	suite.Require().NoError(err)
+
+	return content
+}
+
+func (suite *TimeSyncGathererTestSuite) TestTimeSyncGatheringSuccess() {
+	suite.mockExecutor.
+		On("OutputContext", mock.Anything, "/usr/bin/chronyc", "-c", "tracking").
+		Return(suite.readFixture("tracking"), nil).
+		On("OutputContext", mock.Anything, "/usr/bin/chronyc", "-c", "sources").
+		Return(suite.readFixture("sources"), nil).
+		On("OutputContext", mock.Anything, "/usr/bin/timedatectl", "show").
+		Return(suite.readFixture("timedatectl"), nil)
+
+	g := gatherers.NewTimeSyncGatherer(suite.mockExecutor)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "tracking",
+			Gatherer: "time_sync",
+			Argument: "tracking",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "sources",
+			Gatherer: "time_sync",
+			Argument: "sources",
+			CheckID:  "check2",
+		},
+		{
+			Name:     "timedatectl",
+			Gatherer: "time_sync",
+			Argument: "timedatectl",
+			CheckID:  "check3",
+		},
+	}
+
+	expectedResults := []entities.Fact{
+		{
+			Name:    "tracking",
+			CheckID: "check1",
+			Value: &entities.FactValueMap{
+				Value: map[string]entities.FactValue{
+					"reference_id":       &entities.FactValueString{Value: "A9FEA97B"},
+					"reference_name":     &entities.FactValueString{Value: "169.254.169.123"},
+					"stratum":            &entities.FactValueInt{Value: 4},
+					"reference_time":     &entities.FactValueFloat{Value: 1729333200.123456789},
+					"system_time_offset": &entities.FactValueFloat{Value: -0.000001234},
+					"last_offset":        &entities.FactValueFloat{Value: 0.000000567},
+					"rms_offset":         &entities.FactValueFloat{Value: 0.000012345},
+					"frequency":          &entities.FactValueFloat{Value: -12.345},
+					"residual_frequency": &entities.FactValueFloat{Value: 0.001},
+					"skew":               &entities.FactValueFloat{Value: 0.012},
+					"root_delay":         &entities.FactValueFloat{Value: 0.000123},
+					"root_dispersion":    &entities.FactValueFloat{Value: 0.000456},
+					"update_interval":    &entities.FactValueFloat{Value: 64.2},
+					"leap_status":        &entities.FactValueString{Value: "Normal"},
+					"synchronised":       &entities.FactValueBool{Value: true},
+				},
+			},
+		},
+		{
+			Name:    "sources",
+			CheckID: "check2",
+			Value: &entities.FactValueList{
+				Value: []entities.FactValue{
+					&entities.FactValueMap{
+						Value: map[string]entities.FactValue{
+							"mode":            &entities.FactValueString{Value: "server"},
+							"state":           &entities.FactValueString{Value: "selected"},
+							"name":            &entities.FactValueString{Value: "169.254.169.123"},
+							"stratum":         &entities.FactValueInt{Value: 3},
+							"poll":            &entities.FactValueInt{Value: 6},
+							"reach":           &entities.FactValueInt{Value: 255},
+							"last_rx":         &entities.FactValueInt{Value: 23},
+							"last_offset":     &entities.FactValueFloat{Value: -0.000001234},
+							"measured_offset": &entities.FactValueFloat{Value: -0.000002345},
+							"error":           &entities.FactValueFloat{Value: 0.000123456},
+						},
+					},
+					&entities.FactValueMap{
+						Value: map[string]entities.FactValue{
+							"mode":            &entities.FactValueString{Value: "server"},
+							"state":           &entities.FactValueString{Value: "unreachable"},
+							"name":            &entities.FactValueString{Value: "ntp.example.com"},
+							"stratum":         &entities.FactValueInt{Value: 0},
+							"poll":            &entities.FactValueInt{Value: 6},
+							"reach":           &entities.FactValueInt{Value: 0},
+							"last_rx":         &entities.FactValueString{Value: "-"},
+							"last_offset":     &entities.FactValueFloat{Value: 0},
+							"measured_offset": &entities.FactValueFloat{Value: 0},
+							"error":           &entities.FactValueFloat{Value: 0},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:    "timedatectl",
+			CheckID: "check3",
+			Value: &entities.FactValueMap{
+				Value: map[string]entities.FactValue{
+					"timezone":         &entities.FactValueString{Value: "Europe/Berlin"},
+					"local_rtc":        &entities.FactValueBool{Value: false},
+					"can_ntp":          &entities.FactValueBool{Value: true},
+					"ntp":              &entities.FactValueBool{Value: true},
+					"ntp_synchronized": &entities.FactValueBool{Value: false},
+					"time_usec":        &entities.FactValueString{Value: "Sat 2024-10-19 12:20:00 CEST"},
+					"rtc_time_usec":    &entities.FactValueString{Value: "Sat 2024-10-19 10:20:00 CEST"},
+				},
+			},
+		},
+	}
+
+	factResults, err := g.Gather(context.Background(), factRequests)
+	suite.NoError(err)
+	suite.ElementsMatch(expectedResults, factResults)
+}
+
+func (suite *TimeSyncGathererTestSuite) TestTimeSyncGatheringUnsynchronised() {
+	suite.mockExecutor.
+		On("OutputContext", mock.Anything, "/usr/bin/chronyc", "-c", "tracking").
+		Return(suite.readFixture("tracking-unsynchronised"), nil)
+
+	g := gatherers.NewTimeSyncGatherer(suite.mockExecutor)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "tracking",
+			Gatherer: "time_sync",
+			Argument: "tracking",
+			CheckID:  "check1",
+		},
+	}
+
+	factResults, err := g.Gather(context.Background(), factRequests)
+	suite.Require().NoError(err)
+
+	tracking, ok := factResults[0].Value.(*entities.FactValueMap)
+	suite.Require().True(ok)
+	suite.Equal(&entities.FactValueString{Value: "Not synchronised"}, tracking.Value["leap_status"])
+	suite.Equal(&entities.FactValueBool{Value: false}, tracking.Value["synchronised"])
+}
+
+func (suite *TimeSyncGathererTestSuite) TestTimeSyncGatheringErrors() {
+	suite.mockExecutor.
+		On("OutputContext", mock.Anything, "/usr/bin/chronyc", "-c", "tracking").
+		Return([]byte("506 Cannot talk to daemon\n"), errors.New("exit status 1")).
+		On("OutputContext", mock.Anything, "/usr/bin/chronyc", "-c", "sources").
+		Return([]byte("^,*,169.254.169.123\n"), nil)
+
+	g := gatherers.NewTimeSyncGatherer(suite.mockExecutor)
+
+	factRequests := []entities.FactRequest{
+		{
+			Name:     "missing",
+			Gatherer: "time_sync",
+			CheckID:  "check1",
+		},
+		{
+			Name:     "unsupported",
+			Gatherer: "time_sync",
+			Argument: "ntpq",
+			CheckID:  "check2",
+		},
+		{
+			Name:     "tracking",
+			Gatherer: "time_sync",
+			Argument: "tracking",
+			CheckID:  "check3",
+		},
+		{
+			Name:     "sources",
+			Gatherer: "time_sync",
+			Argument: "sources",
+			CheckID:  "check4",
+		},
+	}
+
+	expectedResults := []entities.Fact{
+		{
+			Name:    "missing",
+			CheckID: "check1",
+			Error: &entities.FactGatheringError{
+				Type:    "time_sync-missing-argument",
+				Message: "missing required argument",
+			},
+		},
+		{
+			Name:    "unsupported",
+			CheckID: "check2",
+			Error: &entities.FactGatheringError{
+				Type:    "time_sync-unsupported-argument",
+				Message: "requested argument not supported: ntpq",
+			},
+		},
+		{
+			Name:    "tracking",
+			CheckID: "check3",
+			Error: &entities.FactGatheringError{
+				Type:    "time_sync-command-error",
+				Message: "error running time synchronisation command: /usr/bin/chronyc -c tracking: exit status 1",
+			},
+		},
+		{
+			Name:    "sources",
+			CheckID: "check4",
+			Error: &entities.FactGatheringError{
+				Type:    "time_sync-decoding-error",
+				Message: "error decoding time synchronisation output: record on line 1: wrong number of fields",
+			},
+		},
+	}
+
+	factResults, err := g.Gather(context.Background(), factRequests)
+	suite.NoError(err)
+	suite.ElementsMatch(expectedResults, factResults)
+}
@@ -0,0 +1,223 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operations
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// canonicalJSON encodes the value following the JSON Canonicalization Scheme (RFC 8785),
+// so the server and the agent build the same signed payload whatever JSON library they use:
+//   - object keys are sorted by their UTF-16 code units
+//   - there are no insignificant spaces
+//   - strings only escape the quotation mark, the reverse solidus and the control characters,
+//     using the short escapes when available and lowercase \u00xx otherwise.
+//     HTML characters and U+2028/U+2029 are not escaped
+//   - numbers are written as ECMAScript does, integer values without fraction nor exponent
+//     below 1e21
+//
+// Integer types are written with all their digits. Values not supported natively
+// are encoded with encoding/json and canonicalized afterwards.
+func canonicalJSON(value any) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+
+	err := writeCanonicalJSON(buffer, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func writeCanonicalJSON(buffer *bytes.Buffer, value any) error {
+	switch v := value.(type) {
+	case nil:
+		buffer.WriteString("null")
+	case bool:
+		buffer.WriteString(strconv.FormatBool(v))
+	case string:
+		return writeCanonicalString(buffer, v)
+	case float64:
+		return writeCanonicalFloat(buffer, v)
+	case float32:
+		return writeCanonicalFloat(buffer, float64(v))
+	case int, int8, int16, int32, int64:
+		buffer.WriteString(strconv.FormatInt(reflect.ValueOf(v).Int(), 10))
+	case uint, uint8, uint16, uint32, uint64:
+		buffer.WriteString(strconv.FormatUint(reflect.ValueOf(v).Uint(), 10))
+	case json.Number:
+		return writeCanonicalNumber(buffer, v)
+	case map[string]any:
+		return writeCanonicalObject(buffer, v)
+	case []any:
+		buffer.WriteByte('[')
+
+		for i, item := range v {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+
+			err := writeCanonicalJSON(buffer, item)
+			if err != nil {
+				return err
+			}
+		}
+
+		buffer.WriteByte(']')
+	default:
+		return writeCanonicalFallback(buffer, v)
+	}
+
+	return nil
+}
+
+func writeCanonicalObject(buffer *bytes.Buffer, object map[string]any) error {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+
+	slices.SortFunc(keys, func(a, b string) int {
+		return slices.Compare(utf16.Encode([]rune(a)), utf16.Encode([]rune(b)))
+	})
+
+	buffer.WriteByte('{')
+
+	for i, key := range keys {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+
+		err := writeCanonicalString(buffer, key)
+		if err != nil {
+			return err
+		}
+
+		buffer.WriteByte(':')
+
+		err = writeCanonicalJSON(buffer, object[key])
+		if err != nil {
+			return err
+		}
+	}
+
+	buffer.WriteByte('}')
+
+	return nil
+}
+
+func writeCanonicalString(buffer *bytes.Buffer, value string) error {
+	if !utf8.ValidString(value) {
+		return fmt.Errorf("invalid UTF-8 string %q", value)
+	}
+
+	buffer.WriteByte('"')
+
+	for _, r := range value {
+		switch r {
+		case '"':
+			buffer.WriteString(`\"`)
+		case '\\':
+			buffer.WriteString(`\\`)
+		case '\b':
+			buffer.WriteString(`\b`)
+		case '\f':
+			buffer.WriteString(`\f`)
+		case '\n':
+			buffer.WriteString(`\n`)
+		case '\r':
+			buffer.WriteString(`\r`)
+		case '\t':
+			buffer.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(buffer, `\u%04x`, r)
+			} else {
+				buffer.WriteRune(r)
+			}
+		}
+	}
+
+	buffer.WriteByte('"')
+
+	return nil
+}
+
+// writeCanonicalFloat writes the number as the ECMAScript Number.prototype.toString,
+// the same conversion done by encoding/json for float64 values.
+func writeCanonicalFloat(buffer *bytes.Buffer, value float64) error {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return fmt.Errorf("unsupported number %v", value)
+	}
+
+	if value == 0 {
+		// negative zero is written as 0 too
+		buffer.WriteByte('0')
+
+		return nil
+	}
+
+	format := byte('f')
+	if abs := math.Abs(value); abs < 1e-6 || abs >= 1e21 {
+		format = 'e'
+	}
+
+	number := strconv.AppendFloat(nil, value, format, -1, 64)
+
+	if format == 'e' {
+		// clean up e-09 to e-9
+		n := len(number)
+		if n >= 4 && number[n-4] == 'e' && number[n-3] == '-' && number[n-2] == '0' {
+			number[n-2] = number[n-1]
+			number = number[:n-1]
+		}
+	}
+
+	buffer.Write(number)
+
+	return nil
+}
+
+// writeCanonicalNumber keeps the integer numbers as they are and writes the rest as float64.
+func writeCanonicalNumber(buffer *bytes.Buffer, value json.Number) error {
+	if integer, err := value.Int64(); err == nil {
+		buffer.WriteString(strconv.FormatInt(integer, 10))
+
+		return nil
+	}
+
+	float, err := value.Float64()
+	if err != nil {
+		return fmt.Errorf("invalid number %s: %w", value, err)
+	}
+
+	return writeCanonicalFloat(buffer, float)
+}
+
+func writeCanonicalFallback(buffer *bytes.Buffer, value any) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+
+	var decoded any
+
+	err = decoder.Decode(&decoded)
+	if err != nil {
+		return err
+	}
+
+	return writeCanonicalJSON(buffer, decoded)
+}
@@ -17,6 +17,7 @@ const (
 	agentsQueue            string = "trento.operations.agents.%s"
 	agentsEventsRoutingKey string = "agents"
 	operationsRoutingKey   string = "requests"
+	catalogueRoutingKey    string = "catalogue"
 )
 
 type Engine struct {
@@ -24,14 +25,20 @@ type Engine struct {
 	amqpServiceURL   string
 	amqpAdapter      messaging.Adapter
 	operatorRegistry operator.Registry
+	handleOptions    []HandleEventOption
 }
 
-func NewOperationsEngine(agentID, amqpServiceURL string, registry operator.Registry) *Engine {
+func NewOperationsEngine(
+	agentID, amqpServiceURL string,
+	registry operator.Registry,
+	options ...HandleEventOption,
+) *Engine {
 	return &Engine{
 		agentID:          agentID,
 		amqpServiceURL:   amqpServiceURL,
 		amqpAdapter:      nil,
 		operatorRegistry: registry,
+		handleOptions:    options,
 	}
 }
 
@@ -53,6 +60,8 @@ func (e *Engine) Subscribe() error {
 	}
 
 	e.amqpAdapter = amqpAdapter
+	e.publishCatalogue()
+
 	slog.Info("Subscription to the operations engine by agent done",
 		"agent_id", e.agentID,
 		"amqp_service_url", e.amqpServiceURL)
@@ -60,6 +69,26 @@ func (e *Engine) Subscribe() error {
 	return nil
 }
 
+// publishCatalogue announces the operators available in the agent with their arguments schema.
+// A failure is not fatal, as the operations can still be run.
+func (e *Engine) publishCatalogue() {
+	event, err := OperatorsCatalogueToEvent(e.agentID, e.operatorRegistry.Catalogue())
+	if err != nil {
+		slog.Error("Error building operators catalogue event", "error", err)
+
+		return
+	}
+
+	err = e.amqpAdapter.Publish(catalogueRoutingKey, operatorsCatalogueContentType, event)
+	if err != nil {
+		slog.Error("Error publishing operators catalogue", "error", err)
+
+		return
+	}
+
+	slog.Info("Operators catalogue published", "agent_id", e.agentID)
+}
+
 func (e *Engine) Unsubscribe() error {
 	slog.Info("Unsubscribing agent from the operations engine service", "agent_id", e.agentID)
 
@@ -79,6 +108,8 @@ func (e *Engine) Listen(ctx context.Context) error {
 	slog.Info("Listening for operation events...")
 
 	defer func() {
+		operator.CleanupPlugins()
+
 		err = e.Unsubscribe()
 		if err != nil {
 			slog.Error("Error during unsubscription", "error", err)
@@ -90,7 +121,15 @@ func (e *Engine) Listen(ctx context.Context) error {
 		e.agentID,
 		e.amqpAdapter,
 		e.operatorRegistry,
-		HandleEvent,
+		func(
+			ctx context.Context,
+			event []byte,
+			agentID string,
+			adapter messaging.Adapter,
+			registry operator.Registry,
+		) error {
+			return HandleEvent(ctx, event, agentID, adapter, registry, e.handleOptions...)
+		},
 	)
 
 	err = e.amqpAdapter.Listen(eventHandler)
@@ -0,0 +1,431 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+// Package localpolicy evaluates the operator requests against a policy file owned by the
+// host administrators, so critical hosts can veto operations regardless of the server requests.
+//
+// The policy file is a YAML document like:
+//
+//	default: allow
+//	rules:
+//	  - name: reboot-in-maintenance-window
+//	    effect: allow
+//	    operators: [hostreboot]
+//	    windows:
+//	      - days: [sat, sun]
+//	        start: "22:00"
+//	        end: "04:00"
+//	        timezone: Europe/Berlin
+//	  - name: no-reboots
+//	    effect: deny
+//	    operators: [hostreboot]
+//	  - name: production-sap
+//	    effect: deny
+//	    operators: [sapsystemstop, sapinstancestop, hanastop]
+//	    hosts: ["prd-*"]
+//	    sids: [PRD]
+//
+// The rules are evaluated in order and the first matching rule decides. A rule matches when all
+// of its criteria match, empty criteria match everything:
+//   - operators: operator names, without version. Shell file name patterns are supported.
+//   - hosts: host names. Shell file name patterns are supported.
+//   - sids: SAP system IDs. The SID of the request is taken from the sid argument, or
+//     looked up in /usr/sap using the instance_number argument, comparing the exact instance number.
+//     Requests without SID don't match.
+//   - windows: time windows where the rule applies. The days refer to the window start,
+//     so a window ending before its start finishes the next day. The timezone defaults to the
+//     host local time.
+//
+// The policy file is read on each evaluation, so changes apply without restarting the agent.
+// The agent checks the policy file when it starts and fails if it cannot be read or is invalid.
+// If the file is removed or becomes invalid afterwards, everything is denied.
+// Each step of a runbook is evaluated as well, and the runbook is denied if any of them is.
+package localpolicy
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+)
+
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+
+	runbookOperatorName = "runbook"
+	sapInstancesPattern = "/usr/sap/[A-Z0-9][A-Z0-9][A-Z0-9]/*[0-9][0-9]"
+)
+
+//nolint:gochecknoglobals
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+type Window struct {
+	Days     []string `mapstructure:"days"`
+	Start    string   `mapstructure:"start"`
+	End      string   `mapstructure:"end"`
+	Timezone string   `mapstructure:"timezone"`
+}
+
+type Rule struct {
+	Name      string   `mapstructure:"name"`
+	Effect    string   `mapstructure:"effect"`
+	Operators []string `mapstructure:"operators"`
+	Hosts     []string `mapstructure:"hosts"`
+	SIDs      []string `mapstructure:"sids"`
+	Windows   []Window `mapstructure:"windows"`
+}
+
+type Document struct {
+	Default string `mapstructure:"default"`
+	Rules   []Rule `mapstructure:"rules"`
+}
+
+// Decision is the result of evaluating a request against the policy.
+type Decision struct {
+	Allowed bool
+	Reason  string
+}
+
+type Option func(*Policy)
+
+type Policy struct {
+	path     string
+	fs       afero.Fs
+	hostname string
+	now      func() time.Time
+}
+
+func WithCustomFs(fileSystem afero.Fs) Option {
+	return func(p *Policy) {
+		p.fs = fileSystem
+	}
+}
+
+func WithCustomHostname(hostname string) Option {
+	return func(p *Policy) {
+		p.hostname = hostname
+	}
+}
+
+func WithCustomClock(now func() time.Time) Option {
+	return func(p *Policy) {
+		p.now = now
+	}
+}
+
+func NewPolicy(policyPath string, options ...Option) *Policy {
+	hostname, _ := os.Hostname()
+
+	policy := &Policy{
+		path:     policyPath,
+		fs:       afero.NewOsFs(),
+		hostname: hostname,
+		now:      time.Now,
+	}
+
+	for _, opt := range options {
+		opt(policy)
+	}
+
+	return policy
+}
+
+// Check reads and validates the policy file, so a policy configured with an unreadable
+// or invalid file is detected before evaluating any request.
+func (p *Policy) Check() error {
+	_, err := p.load()
+	if err != nil {
+		return fmt.Errorf("invalid local policy %s: %w", p.path, err)
+	}
+
+	return nil
+}
+
+// Evaluate decides if the operator can run with the given arguments.
+func (p *Policy) Evaluate(operatorName string, arguments map[string]any) Decision {
+	document, err := p.load()
+	if err != nil {
+		return Decision{Allowed: false, Reason: fmt.Sprintf("invalid local policy: %s", err)}
+	}
+
+	now := p.now()
+
+	decision := p.evaluateOperator(document, operatorName, arguments, now)
+	if !decision.Allowed || operatorBaseName(operatorName) != runbookOperatorName {
+		return decision
+	}
+
+	for index, step := range runbookSteps(arguments) {
+		stepDecision := p.evaluateOperator(document, step.operator, step.arguments, now)
+		if !stepDecision.Allowed {
+			return Decision{
+				Allowed: false,
+				Reason:  fmt.Sprintf("step %d (%s): %s", index, step.operator, stepDecision.Reason),
+			}
+		}
+	}
+
+	return decision
+}
+
+func (p *Policy) evaluateOperator(
+	document *Document,
+	operatorName string,
+	arguments map[string]any,
+	now time.Time,
+) Decision {
+	name := operatorBaseName(operatorName)
+	sids := p.requestSIDs(arguments)
+
+	for index, rule := range document.Rules {
+		if !matchesAny(rule.Operators, name) ||
+			!matchesAny(rule.Hosts, p.hostname) ||
+			!matchesSIDs(rule.SIDs, sids) ||
+			!inAnyWindow(rule.Windows, now) {
+			continue
+		}
+
+		ruleName := rule.Name
+		if ruleName == "" {
+			ruleName = fmt.Sprintf("#%d", index)
+		}
+
+		if rule.Effect == EffectDeny {
+			return Decision{Allowed: false, Reason: fmt.Sprintf("denied by rule %s", ruleName)}
+		}
+
+		return Decision{Allowed: true, Reason: fmt.Sprintf("allowed by rule %s", ruleName)}
+	}
+
+	if document.Default == EffectDeny {
+		return Decision{Allowed: false, Reason: "denied by default"}
+	}
+
+	return Decision{Allowed: true, Reason: "allowed by default"}
+}
+
+func (p *Policy) load() (*Document, error) {
+	_, err := p.fs.Stat(p.path)
+	if err != nil {
+		return nil, err
+	}
+
+	v := viper.New()
+	v.SetFs(p.fs)
+	v.SetConfigFile(p.path)
+	v.SetConfigType("yaml")
+
+	err = v.ReadInConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	document := &Document{}
+
+	err = v.Unmarshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	err = document.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+func (d *Document) validate() error {
+	if d.Default != "" && d.Default != EffectAllow && d.Default != EffectDeny {
+		return fmt.Errorf("invalid default effect %s", d.Default)
+	}
+
+	for index, rule := range d.Rules {
+		if rule.Effect != EffectAllow && rule.Effect != EffectDeny {
+			return fmt.Errorf("rule %d: invalid effect %s", index, rule.Effect)
+		}
+
+		for _, window := range rule.Windows {
+			err := window.validate()
+			if err != nil {
+				return fmt.Errorf("rule %d: %w", index, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (w Window) validate() error {
+	for _, day := range w.Days {
+		if _, found := weekdays[strings.ToLower(day)]; !found {
+			return fmt.Errorf("invalid window day %s", day)
+		}
+	}
+
+	if _, err := time.Parse("15:04", w.Start); err != nil {
+		return fmt.Errorf("invalid window start %s", w.Start)
+	}
+
+	if _, err := time.Parse("15:04", w.End); err != nil {
+		return fmt.Errorf("invalid window end %s", w.End)
+	}
+
+	if _, err := time.LoadLocation(w.Timezone); err != nil {
+		return fmt.Errorf("invalid window timezone %s", w.Timezone)
+	}
+
+	return nil
+}
+
+// contains checks if the time is within the window. The validation guarantees
+// that the window values are parseable.
+func (w Window) contains(now time.Time) bool {
+	location := time.Local
+	if w.Timezone != "" {
+		location, _ = time.LoadLocation(w.Timezone)
+	}
+
+	localNow := now.In(location)
+
+	start, _ := time.Parse("15:04", w.Start)
+	end, _ := time.Parse("15:04", w.End)
+
+	minutes := localNow.Hour()*60 + localNow.Minute()
+	startMinutes := start.Hour()*60 + start.Minute()
+	endMinutes := end.Hour()*60 + end.Minute()
+
+	if startMinutes <= endMinutes {
+		return minutes >= startMinutes && minutes < endMinutes && w.onDay(localNow.Weekday())
+	}
+
+	// the window crosses midnight, the part after midnight belongs to the previous day window
+	if minutes >= startMinutes {
+		return w.onDay(localNow.Weekday())
+	}
+
+	return minutes < endMinutes && w.onDay((localNow.Weekday()+6)%7)
+}
+
+func (w Window) onDay(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(w.Days, func(windowDay string) bool {
+		return weekdays[strings.ToLower(windowDay)] == day
+	})
+}
+
+func inAnyWindow(windows []Window, now time.Time) bool {
+	if len(windows) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(windows, func(window Window) bool {
+		return window.contains(now)
+	})
+}
+
+func matchesAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(patterns, func(pattern string) bool {
+		matched, err := path.Match(pattern, value)
+
+		return err == nil && matched
+	})
+}
+
+func matchesSIDs(ruleSIDs []string, requestSIDs []string) bool {
+	if len(ruleSIDs) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(requestSIDs, func(sid string) bool {
+		return slices.Contains(ruleSIDs, sid)
+	})
+}
+
+// requestSIDs returns the SAP systems affected by the request.
+func (p *Policy) requestSIDs(arguments map[string]any) []string {
+	if sid, ok := arguments["sid"].(string); ok && sid != "" {
+		return []string{sid}
+	}
+
+	instanceNumber, ok := arguments["instance_number"].(string)
+	if !ok || instanceNumber == "" {
+		return nil
+	}
+
+	instances, err := afero.Glob(p.fs, sapInstancesPattern)
+	if err != nil {
+		return nil
+	}
+
+	sids := []string{}
+
+	for _, instance := range instances {
+		// the pattern guarantees that the instance name ends with the two digits instance number
+		if instance[len(instance)-2:] != instanceNumber {
+			continue
+		}
+
+		sid := path.Base(path.Dir(instance))
+		if !slices.Contains(sids, sid) {
+			sids = append(sids, sid)
+		}
+	}
+
+	return sids
+}
+
+type runbookStep struct {
+	operator  string
+	arguments map[string]any
+}
+
+// runbookSteps extracts the runbook steps leniently, the runbook operator validates them.
+func runbookSteps(arguments map[string]any) []runbookStep {
+	rawSteps, _ := arguments["steps"].([]any)
+	steps := make([]runbookStep, 0, len(rawSteps))
+
+	for _, rawStep := range rawSteps {
+		stepMap, ok := rawStep.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		operatorName, _ := stepMap["operator"].(string)
+		stepArguments, _ := stepMap["arguments"].(map[string]any)
+
+		steps = append(steps, runbookStep{
+			operator:  operatorName,
+			arguments: stepArguments,
+		})
+	}
+
+	return steps
+}
+
+func operatorBaseName(operatorName string) string {
+	name, _, _ := strings.Cut(operatorName, "@")
+
+	return name
+}
@@ -0,0 +1,257 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package localpolicy_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/trento-project/agent/v3/internal/operations/localpolicy"
+)
+
+const (
+	policyPath = "/etc/trento/operations-policy.yaml"
+
+	testPolicy = `
+default: allow
+rules:
+  - name: reboot-window
+    effect: allow
+    operators: [hostreboot]
+    windows:
+      - days: [sat]
+        start: "22:00"
+        end: "04:00"
+        timezone: UTC
+  - name: no-reboots
+    effect: deny
+    operators: [hostreboot]
+  - name: production-sap
+    effect: deny
+    operators: ["sap*stop", hanastop]
+    hosts: ["prd-*"]
+    sids: [PRD]
+`
+)
+
+type LocalPolicyTestSuite struct {
+	suite.Suite
+
+	fs afero.Fs
+}
+
+func TestLocalPolicyTestSuite(t *testing.T) {
+	suite.Run(t, new(LocalPolicyTestSuite))
+}
+
+func (suite *LocalPolicyTestSuite) SetupTest() {
+	suite.fs = afero.NewMemMapFs()
+	suite.Require().NoError(afero.WriteFile(suite.fs, policyPath, []byte(testPolicy), 0644))
+	suite.Require().NoError(suite.fs.MkdirAll("/usr/sap/PRD/HDB00", 0755))
+	suite.Require().NoError(suite.fs.MkdirAll("/usr/sap/QAS/ASCS01", 0755))
+	suite.Require().NoError(suite.fs.MkdirAll("/usr/sap/DEV/HDB10", 0755))
+}
+
+func (suite *LocalPolicyTestSuite) newPolicy(hostname string, now time.Time) *localpolicy.Policy {
+	return localpolicy.NewPolicy(
+		policyPath,
+		localpolicy.WithCustomFs(suite.fs),
+		localpolicy.WithCustomHostname(hostname),
+		localpolicy.WithCustomClock(func() time.Time { return now }),
+	)
+}
+
+func (suite *LocalPolicyTestSuite) TestEvaluateWindows() {
+	cases := []struct {
+		now      time.Time
+		expected localpolicy.Decision
+	}{
+		{
+			// Saturday inside the window
+			now:      time.Date(2025, 1, 4, 23, 0, 0, 0, time.UTC),
+			expected: localpolicy.Decision{Allowed: true, Reason: "allowed by rule reboot-window"},
+		},
+		{
+			// Sunday morning, the Saturday window is still open
+			now:      time.Date(2025, 1, 5, 3, 59, 0, 0, time.UTC),
+			expected: localpolicy.Decision{Allowed: true, Reason: "allowed by rule reboot-window"},
+		},
+		{
+			// Sunday morning, the window is closed
+			now:      time.Date(2025, 1, 5, 4, 0, 0, 0, time.UTC),
+			expected: localpolicy.Decision{Allowed: false, Reason: "denied by rule no-reboots"},
+		},
+		{
+			// Saturday morning belongs to the Friday window
+			now:      time.Date(2025, 1, 4, 1, 0, 0, 0, time.UTC),
+			expected: localpolicy.Decision{Allowed: false, Reason: "denied by rule no-reboots"},
+		},
+	}
+
+	for _, tc := range cases {
+		decision := suite.newPolicy("host", tc.now).Evaluate("hostreboot@v1", map[string]any{})
+		suite.Equal(tc.expected, decision, tc.now)
+	}
+}
+
+func (suite *LocalPolicyTestSuite) TestEvaluateSIDs() {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		hostname  string
+		operator  string
+		arguments map[string]any
+		expected  localpolicy.Decision
+	}{
+		{
+			hostname:  "prd-hana01",
+			operator:  "hanastop",
+			arguments: map[string]any{"sid": "PRD", "instance_number": "00"},
+			expected:  localpolicy.Decision{Allowed: false, Reason: "denied by rule production-sap"},
+		},
+		{
+			hostname:  "prd-hana01",
+			operator:  "sapsystemstop@v1",
+			arguments: map[string]any{"instance_number": "00"},
+			expected:  localpolicy.Decision{Allowed: false, Reason: "denied by rule production-sap"},
+		},
+		{
+			hostname:  "prd-hana01",
+			operator:  "sapsystemstop@v1",
+			arguments: map[string]any{"instance_number": "01"},
+			expected:  localpolicy.Decision{Allowed: true, Reason: "allowed by default"},
+		},
+		{
+			hostname:  "prd-hana01",
+			operator:  "sapsystemstop@v1",
+			arguments: map[string]any{"instance_number": "0"},
+			expected:  localpolicy.Decision{Allowed: true, Reason: "allowed by default"},
+		},
+		{
+			hostname:  "prd-hana01",
+			operator:  "sapsystemstop@v1",
+			arguments: map[string]any{"instance_number": "10"},
+			expected:  localpolicy.Decision{Allowed: true, Reason: "allowed by default"},
+		},
+		{
+			hostname:  "qas-hana01",
+			operator:  "hanastop",
+			arguments: map[string]any{"sid": "PRD", "instance_number": "00"},
+			expected:  localpolicy.Decision{Allowed: true, Reason: "allowed by default"},
+		},
+		{
+			hostname:  "prd-hana01",
+			operator:  "hanastart",
+			arguments: map[string]any{"sid": "PRD", "instance_number": "00"},
+			expected:  localpolicy.Decision{Allowed: true, Reason: "allowed by default"},
+		},
+	}
+
+	for _, tc := range cases {
+		decision := suite.newPolicy(tc.hostname, now).Evaluate(tc.operator, tc.arguments)
+		suite.Equal(tc.expected, decision, tc)
+	}
+}
+
+func (suite *LocalPolicyTestSuite) TestEvaluateRunbook() {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	arguments := map[string]any{
+		"steps": []any{
+			map[string]any{
+				"operator":  "clustermaintenancechange@v1",
+				"arguments": map[string]any{"maintenance": true},
+			},
+			map[string]any{
+				"operator": "hostreboot@v1",
+			},
+		},
+	}
+
+	decision := suite.newPolicy("host", now).Evaluate("runbook@v1", arguments)
+
+	suite.Equal(localpolicy.Decision{
+		Allowed: false,
+		Reason:  "step 1 (hostreboot@v1): denied by rule no-reboots",
+	}, decision)
+}
+
+func (suite *LocalPolicyTestSuite) TestEvaluateMissingPolicy() {
+	policy := localpolicy.NewPolicy(
+		"/etc/trento/other.yaml",
+		localpolicy.WithCustomFs(suite.fs),
+	)
+
+	suite.EqualError(
+		policy.Check(),
+		"invalid local policy /etc/trento/other.yaml: open /etc/trento/other.yaml: file does not exist",
+	)
+	suite.Equal(
+		localpolicy.Decision{
+			Allowed: false,
+			Reason:  "invalid local policy: open /etc/trento/other.yaml: file does not exist",
+		},
+		policy.Evaluate("hostreboot", map[string]any{}),
+	)
+}
+
+func (suite *LocalPolicyTestSuite) TestCheck() {
+	suite.NoError(suite.newPolicy("host", time.Now()).Check())
+
+	suite.Require().NoError(afero.WriteFile(suite.fs, policyPath, []byte("default: maybe\n"), 0644))
+	suite.EqualError(
+		suite.newPolicy("host", time.Now()).Check(),
+		"invalid local policy /etc/trento/operations-policy.yaml: invalid default effect maybe",
+	)
+}
+
+func (suite *LocalPolicyTestSuite) TestEvaluateDefaultDeny() {
+	suite.Require().NoError(afero.WriteFile(suite.fs, policyPath, []byte("default: deny\n"), 0644))
+
+	suite.Equal(
+		localpolicy.Decision{Allowed: false, Reason: "denied by default"},
+		suite.newPolicy("host", time.Now()).Evaluate("saptuneapplysolution", map[string]any{}),
+	)
+}
+
+func (suite *LocalPolicyTestSuite) TestEvaluateInvalidPolicy() {
+	cases := []struct {
+		policy string
+		reason string
+	}{
+		{
+			policy: "default: maybe\n",
+			reason: "invalid local policy: invalid default effect maybe",
+		},
+		{
+			policy: "rules:\n  - effect: block\n",
+			reason: "invalid local policy: rule 0: invalid effect block",
+		},
+		{
+			policy: "rules:\n  - effect: allow\n    windows:\n      - days: [someday]\n",
+			reason: "invalid local policy: rule 0: invalid window day someday",
+		},
+		{
+			policy: "rules:\n  - effect: allow\n    windows:\n      - start: \"25:00\"\n        end: \"04:00\"\n",
+			reason: "invalid local policy: rule 0: invalid window start 25:00",
+		},
+		{
+			policy: "rules:\n  - effect: allow\n    windows:\n      - start: \"22:00\"\n        end: \"04:00\"\n" +
+				"        timezone: Nowhere/Land\n",
+			reason: "invalid local policy: rule 0: invalid window timezone Nowhere/Land",
+		},
+	}
+
+	for _, tc := range cases {
+		suite.Require().NoError(afero.WriteFile(suite.fs, policyPath, []byte(tc.policy), 0644))
+
+		suite.Equal(
+			localpolicy.Decision{Allowed: false, Reason: tc.reason},
+			suite.newPolicy("host", time.Now()).Evaluate("hostreboot", map[string]any{}),
+		)
+	}
+}
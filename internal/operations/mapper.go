@@ -4,6 +4,7 @@
 package operations
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -16,8 +17,35 @@ import (
 
 const (
 	EventSource = "https://github.com/trento-project/agent"
+
+	// OperatorsCatalogueEventType identifies the operators catalogue payload.
+	// The catalogue is published as plain JSON until a dedicated contracts message exists.
+	OperatorsCatalogueEventType   = "Trento.Operations.V1.OperatorsCatalogue"
+	operatorsCatalogueContentType = "application/json"
+
+	// OperatorRollbackRequestedV1 identifies the requests to run a deferred rollback.
+	// The request is received as plain JSON until a dedicated contracts message exists.
+	OperatorRollbackRequestedV1 = "Trento.Operations.V1.OperatorRollbackRequested"
 )
 
+type OperatorsCatalogue struct {
+	ID        string                    `json:"id"`
+	Type      string                    `json:"type"`
+	Source    string                    `json:"source"`
+	AgentID   string                    `json:"agent_id"`
+	Operators []operator.CatalogueEntry `json:"operators"`
+}
+
+type OperatorRollbackRequested struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	OperationID string `json:"operation_id"`
+	AgentID     string `json:"agent_id"`
+	Signature   string `json:"_signature,omitempty"`
+	Timestamp   string `json:"_timestamp,omitempty"`
+	Nonce       string `json:"_nonce,omitempty"`
+}
+
 type OperatorExecutionRequestedTarget struct {
 	AgentID   string
 	Arguments map[string]any
@@ -142,3 +170,54 @@ func OperatorExecutionCompletedToEvent(
 
 	return eventBytes, nil
 }
+
+func OperatorsCatalogueToEvent(agentID string, catalogue []operator.CatalogueEntry) ([]byte, error) {
+	event := OperatorsCatalogue{
+		ID:        uuid.New().String(),
+		Type:      OperatorsCatalogueEventType,
+		Source:    EventSource,
+		AgentID:   agentID,
+		Operators: catalogue,
+	}
+
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("error creating operators catalogue event: %w", err)
+	}
+
+	return eventBytes, nil
+}
+
+// jsonEventType returns the type of the events received as plain JSON.
+// It returns false if the event is not a JSON event, like the contracts events.
+func jsonEventType(event []byte) (string, bool) {
+	var jsonEvent struct {
+		Type string `json:"type"`
+	}
+
+	err := json.Unmarshal(event, &jsonEvent)
+	if err != nil || jsonEvent.Type == "" {
+		return "", false
+	}
+
+	return jsonEvent.Type, true
+}
+
+func OperatorRollbackRequestedFromEvent(event []byte) (*OperatorRollbackRequested, error) {
+	var request OperatorRollbackRequested
+
+	err := json.Unmarshal(event, &request)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding operator rollback request: %w", err)
+	}
+
+	if request.Type != OperatorRollbackRequestedV1 {
+		return nil, fmt.Errorf("invalid operator rollback request type: %s", request.Type)
+	}
+
+	if request.OperationID == "" {
+		return nil, errors.New("operator rollback request without operation id")
+	}
+
+	return &request, nil
+}
@@ -4,6 +4,7 @@
 package operations_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/google/uuid"
@@ -245,3 +246,30 @@ func (suite *MapperTestSuite) TestOperatorExecutionCompletedToEventFailure() {
 	suite.Equal(suite.stepNumber, operation.GetStepNumber())
 	suite.Equal(expectedResult, operation.GetResult())
 }
+
+func (suite *MapperTestSuite) TestOperatorsCatalogueToEvent() {
+	catalogue := []operator.CatalogueEntry{
+		{
+			Name:    "some-operator",
+			Version: "v1",
+			Schema: operator.Schema{
+				Description: "some operator",
+				Arguments: []operator.ArgumentSchema{
+					{Name: "name", Type: operator.ArgumentTypeString, Required: true},
+				},
+			},
+		},
+	}
+
+	event, err := operations.OperatorsCatalogueToEvent(suite.agentID, catalogue)
+	suite.NoError(err)
+
+	var result operations.OperatorsCatalogue
+	suite.NoError(json.Unmarshal(event, &result))
+
+	suite.NotEmpty(result.ID)
+	suite.Equal(operations.OperatorsCatalogueEventType, result.Type)
+	suite.Equal(operations.EventSource, result.Source)
+	suite.Equal(suite.agentID, result.AgentID)
+	suite.Equal(catalogue, result.Operators)
+}
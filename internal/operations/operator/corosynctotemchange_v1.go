@@ -0,0 +1,379 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/trento-project/agent/v3/internal/core/cluster/corosync"
+	"github.com/trento-project/agent/v3/pkg/utils"
+)
+
+const (
+	CorosyncTotemChangeOperatorName = "corosynctotemchange"
+	corosyncConfPath                = "/etc/corosync/corosync.conf"
+	corosyncCfgtoolPath             = "corosync-cfgtool"
+	corosyncCmapctlPath             = "corosync-cmapctl"
+)
+
+// corosyncTotemOptions are the totem options the operator can change.
+// The arguments schema is built from this list as well.
+//
+//nolint:gochecknoglobals
+var corosyncTotemOptions = []string{
+	"token",
+	"token_coefficient",
+	"token_retransmit",
+	"token_retransmits_before_loss_const",
+	"consensus",
+	"join",
+	"send_join",
+	"max_messages",
+	"window_size",
+	"hold",
+	"merge",
+	"downcheck",
+	"fail_recv_const",
+	"seqno_unchanged_const",
+}
+
+type CorosyncTotemChangeOption Option[CorosyncTotemChange]
+
+type corosyncTotemChangeArguments struct {
+	options map[string]string
+}
+
+type corosyncTotemDiffOutput struct {
+	Totem map[string]string `json:"totem"`
+}
+
+// CorosyncTotemChange is an operator responsible for changing the totem options of the
+// corosync configuration of the local node, such as token or consensus timeouts.
+//
+// The operator accepts the totem options to change as arguments, each of them with
+// its desired unsigned integer value. Supported options:
+// token, token_coefficient, token_retransmit, token_retransmits_before_loss_const,
+// consensus, join, send_join, max_messages, window_size, hold, merge, downcheck,
+// fail_recv_const and seqno_unchanged_const.
+//
+// Example: {"token": 30000, "consensus": 36000}
+//
+// corosync-cfgtool -R reloads the configuration on every cluster node, and each of them
+// reads its own corosync.conf file, so the operator must be run in all the cluster nodes
+// to keep the configuration consistent.
+//
+// # Execution Phases
+//
+// - PLAN:
+//   The current corosync.conf file is parsed and the current values of the requested
+//   options are stored as the "before" diff. If all of them already have the requested
+//   value, the operation is skipped.
+//   Otherwise, the corosync.conf file is backed up next to the original file. The backup is removed
+//   once the changes are verified or rolled back.
+//
+// - COMMIT:
+//   The totem options are updated in the corosync.conf file. Comments and formatting of the
+//   file are preserved. Options not found in the file are added at the end of the totem section.
+//
+// - VERIFY:
+//   The configuration is reloaded with `corosync-cfgtool -R` and the runtime values are read
+//   with `corosync-cmapctl`. The operation fails if any of them differs from the requested value.
+//
+// - ROLLBACK:
+//   The backed up corosync.conf file is restored and the configuration reloaded again.
+
+type CorosyncTotemChange struct {
+	baseOperator
+
+	executor   utils.CommandExecutor
+	configPath string
+	backupPath string
+	// keepBackup is set while the changes are neither verified nor rolled back
+	keepBackup      bool
+	parsedArguments *corosyncTotemChangeArguments
+}
+
+func WithCustomCorosyncTotemChangeExecutor(executor utils.CommandExecutor) CorosyncTotemChangeOption {
+	return func(o *CorosyncTotemChange) {
+		o.executor = executor
+	}
+}
+
+func WithCustomCorosyncConfPath(configPath string) CorosyncTotemChangeOption {
+	return func(o *CorosyncTotemChange) {
+		o.configPath = configPath
+	}
+}
+
+func NewCorosyncTotemChange(
+	arguments Arguments,
+	operationID string,
+	options Options[CorosyncTotemChange],
+) *Executor {
+	corosyncTotemChange := &CorosyncTotemChange{
+		baseOperator: newBaseOperator(
+			CorosyncTotemChangeOperatorName, operationID, arguments, options.BaseOperatorOptions...,
+		),
+		executor:   utils.Executor{},
+		configPath: corosyncConfPath,
+	}
+
+	for _, opt := range options.OperatorOptions {
+		opt(corosyncTotemChange)
+	}
+
+	corosyncTotemChange.backupPath = backupFilePath(corosyncTotemChange.configPath, operationID)
+
+	return &Executor{
+		phaser:      corosyncTotemChange,
+		operationID: operationID,
+		logger:      corosyncTotemChange.logger,
+	}
+}
+
+func (c *CorosyncTotemChange) plan(_ context.Context) (bool, error) {
+	opArguments, err := parseCorosyncTotemChangeArguments(c.arguments)
+	if err != nil {
+		return false, err
+	}
+
+	c.parsedArguments = opArguments
+
+	conf, err := corosync.NewCorosyncParser(c.configPath).Parse()
+	if err != nil {
+		return false, err
+	}
+
+	currentOptions := make(map[string]string)
+	alreadyApplied := true
+
+	for option, value := range c.parsedArguments.options {
+		currentValue, found := conf.Totem[option].(string)
+		if found {
+			currentOptions[option] = currentValue
+		}
+
+		if currentValue != value {
+			alreadyApplied = false
+		}
+	}
+
+	c.resources[beforeDiffField] = currentOptions
+
+	if alreadyApplied {
+		c.logger.Info("totem options already set, skipping operation", "options", c.parsedArguments.options)
+		c.resources[afterDiffField] = currentOptions
+
+		return true, nil
+	}
+
+	err = backupFile(c.configPath, c.backupPath)
+	if err != nil {
+		return false, fmt.Errorf("error backing up corosync configuration: %w", err)
+	}
+
+	c.logger.Info("corosync configuration backed up", "backup", c.backupPath)
+
+	return false, nil
+}
+
+func (c *CorosyncTotemChange) commit(_ context.Context) error {
+	c.keepBackup = true
+
+	content, err := os.ReadFile(c.configPath)
+	if err != nil {
+		return fmt.Errorf("error reading corosync configuration: %w", err)
+	}
+
+	updatedContent, err := corosync.SetTotemOptions(content, c.parsedArguments.options)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomically(c.configPath, updatedContent)
+}
+
+func (c *CorosyncTotemChange) verify(ctx context.Context) error {
+	err := c.reloadCorosync(ctx)
+	if err != nil {
+		return err
+	}
+
+	runtimeOptions := make(map[string]string)
+
+	for option, value := range c.parsedArguments.options {
+		runtimeValue, err := c.getRuntimeTotemOption(ctx, option)
+		if err != nil {
+			return err
+		}
+
+		if runtimeValue != value {
+			return fmt.Errorf(
+				"totem option %s has runtime value %s, expected %s",
+				option, runtimeValue, value,
+			)
+		}
+
+		runtimeOptions[option] = runtimeValue
+	}
+
+	c.resources[afterDiffField] = runtimeOptions
+
+	c.keepBackup = false
+
+	return nil
+}
+
+func (c *CorosyncTotemChange) rollback(ctx context.Context) error {
+	err := restoreFile(c.backupPath, c.configPath)
+	if err != nil {
+		return fmt.Errorf("error restoring corosync configuration: %w", err)
+	}
+
+	c.keepBackup = false
+
+	return c.reloadCorosync(ctx)
+}
+
+func (c *CorosyncTotemChange) after(_ context.Context) {
+	removeBackupFile(c.backupPath, c.keepBackup, c.logger)
+}
+
+func (c *CorosyncTotemChange) operationDiff(_ context.Context) map[string]any {
+	diff := make(map[string]any)
+
+	beforeOptions, ok := c.resources[beforeDiffField].(map[string]string)
+	if !ok {
+		panic(fmt.Sprintf("invalid beforeOptions value: cannot parse '%v' to map",
+			c.resources[beforeDiffField]))
+	}
+
+	afterOptions, ok := c.resources[afterDiffField].(map[string]string)
+	if !ok {
+		panic(fmt.Sprintf("invalid afterOptions value: cannot parse '%v' to map",
+			c.resources[afterDiffField]))
+	}
+
+	before, err := json.Marshal(corosyncTotemDiffOutput{Totem: beforeOptions})
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling before diff output: %v", err))
+	}
+
+	diff[beforeDiffField] = string(before)
+
+	after, err := json.Marshal(corosyncTotemDiffOutput{Totem: afterOptions})
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling after diff output: %v", err))
+	}
+
+	diff[afterDiffField] = string(after)
+
+	return diff
+}
+
+func (c *CorosyncTotemChange) reloadCorosync(ctx context.Context) error {
+	output, err := c.executor.CombinedOutputContext(ctx, corosyncCfgtoolPath, "-R")
+	if err != nil {
+		return fmt.Errorf("error reloading corosync configuration: %w, output: %s", err, string(output))
+	}
+
+	return nil
+}
+
+// getRuntimeTotemOption gets the runtime value of a totem option.
+// Example output:
+// totem.token (u32) = 30000
+func (c *CorosyncTotemChange) getRuntimeTotemOption(ctx context.Context, option string) (string, error) {
+	key := "totem." + option
+
+	output, err := c.executor.CombinedOutputContext(ctx, corosyncCmapctlPath, "-g", key)
+	if err != nil {
+		return "", fmt.Errorf("error getting runtime value of %s: %w, output: %s", key, err, string(output))
+	}
+
+	_, value, found := strings.Cut(string(output), "=")
+	if !found {
+		return "", fmt.Errorf("unexpected corosync-cmapctl output for %s: %s", key, string(output))
+	}
+
+	return strings.TrimSpace(value), nil
+}
+
+func isSupportedTotemOption(option string) bool {
+	return slices.Contains(corosyncTotemOptions, option)
+}
+
+func parseCorosyncTotemChangeArguments(rawArguments Arguments) (*corosyncTotemChangeArguments, error) {
+	if len(rawArguments) == 0 {
+		return nil, errors.New("no totem options provided, could not use the operator")
+	}
+
+	options := make(map[string]string, len(rawArguments))
+
+	for option, argument := range rawArguments {
+		if !isSupportedTotemOption(option) {
+			return nil, fmt.Errorf("unsupported totem option: %s", option)
+		}
+
+		value, err := parseUnsignedIntegerArgument(argument)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse %s argument: %w", option, err)
+		}
+
+		options[option] = strconv.FormatUint(value, 10)
+	}
+
+	return &corosyncTotemChangeArguments{
+		options: options,
+	}, nil
+}
+
+// parseUnsignedIntegerArgument parses arguments that must be unsigned 32 bits integers,
+// the widest type used by corosync and sbd for their options.
+// Numbers coming from JSON payloads are decoded as float64, so they are accepted
+// as long as they don't have decimals.
+func parseUnsignedIntegerArgument(argument any) (uint64, error) {
+	invalidValueErr := fmt.Errorf("value %v is not a valid unsigned integer", argument)
+
+	var value uint64
+
+	switch typedArgument := argument.(type) {
+	case float64:
+		// checked before the conversion, as it overflows with the largest floats
+		if typedArgument < 0 || typedArgument != math.Trunc(typedArgument) || typedArgument > math.MaxUint32 {
+			return 0, invalidValueErr
+		}
+
+		value = uint64(typedArgument)
+	case int:
+		if typedArgument < 0 {
+			return 0, invalidValueErr
+		}
+
+		value = uint64(typedArgument)
+	case string:
+		parsedValue, err := strconv.ParseUint(typedArgument, 10, 64)
+		if err != nil {
+			return 0, invalidValueErr
+		}
+
+		value = parsedValue
+	default:
+		return 0, invalidValueErr
+	}
+
+	if value > math.MaxUint32 {
+		return 0, invalidValueErr
+	}
+
+	return value, nil
+}
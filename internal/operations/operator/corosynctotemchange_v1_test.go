@@ -0,0 +1,383 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/trento-project/agent/v3/internal/operations/operator"
+	"github.com/trento-project/agent/v3/pkg/utils"
+	utilsMocks "github.com/trento-project/agent/v3/pkg/utils/mocks"
+)
+
+const corosyncTotemTestConf = `# corosync configuration
+totem {
+	version: 2
+	# timeouts
+	token: 5000
+	consensus: 6000
+	interface {
+		ringnumber: 0
+	}
+}
+
+quorum {
+	provider: corosync_votequorum
+}
+`
+
+type CorosyncTotemChangeOperatorTestSuite struct {
+	suite.Suite
+
+	logger          *slog.Logger
+	mockExecutor    *utilsMocks.MockCommandExecutor
+	configPath      string
+	backupPath      string
+	originalContent []byte
+}
+
+func TestCorosyncTotemChangeOperator(t *testing.T) {
+	suite.Run(t, new(CorosyncTotemChangeOperatorTestSuite))
+}
+
+func (suite *CorosyncTotemChangeOperatorTestSuite) SetupTest() {
+	suite.logger = utils.NewDefaultLogger("info")
+	suite.mockExecutor = utilsMocks.NewMockCommandExecutor(suite.T())
+	suite.configPath = path.Join(suite.T().TempDir(), "corosync.conf")
+	suite.backupPath = suite.configPath + ".trento-test-op.bak"
+	suite.originalContent = []byte(corosyncTotemTestConf)
+
+	err := os.WriteFile(suite.configPath, suite.originalContent, 0o644)
+	suite.Require().NoError(err)
+}
+
+func (suite *CorosyncTotemChangeOperatorTestSuite) TestCorosyncTotemChangePlanErrorNoArguments() {
+	report := operator.NewCorosyncTotemChange(
+		operator.Arguments{},
+		"test-op",
+		operator.Options[operator.CorosyncTotemChange]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.CorosyncTotemChange]{
+				operator.Option[operator.CorosyncTotemChange](
+					operator.WithCustomCorosyncTotemChangeExecutor(suite.mockExecutor),
+				),
+				operator.Option[operator.CorosyncTotemChange](operator.WithCustomCorosyncConfPath(suite.configPath)),
+			},
+		},
+	).Run(context.Background())
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal("plan: no totem options provided, could not use the operator", report.Error.Message)
+}
+
+func (suite *CorosyncTotemChangeOperatorTestSuite) TestCorosyncTotemChangePlanErrorUnsupportedOption() {
+	report := operator.NewCorosyncTotemChange(
+		operator.Arguments{"crypto_cipher": "none"},
+		"test-op",
+		operator.Options[operator.CorosyncTotemChange]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.CorosyncTotemChange]{
+				operator.Option[operator.CorosyncTotemChange](
+					operator.WithCustomCorosyncTotemChangeExecutor(suite.mockExecutor),
+				),
+				operator.Option[operator.CorosyncTotemChange](operator.WithCustomCorosyncConfPath(suite.configPath)),
+			},
+		},
+	).Run(context.Background())
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal("plan: unsupported totem option: crypto_cipher", report.Error.Message)
+}
+
+func (suite *CorosyncTotemChangeOperatorTestSuite) TestCorosyncTotemChangePlanErrorInvalidValue() {
+	cases := []struct {
+		value        any
+		errorMessage string
+	}{
+		{
+			value:        -1.0,
+			errorMessage: "plan: could not parse token argument: value -1 is not a valid unsigned integer",
+		},
+		{
+			value:        4294967296.0,
+			errorMessage: "plan: could not parse token argument: value 4.294967296e+09 is not a valid unsigned integer",
+		},
+		{
+			value:        4294967296,
+			errorMessage: "plan: could not parse token argument: value 4294967296 is not a valid unsigned integer",
+		},
+		{
+			value:        "4294967296",
+			errorMessage: "plan: could not parse token argument: value 4294967296 is not a valid unsigned integer",
+		},
+	}
+
+	for _, tc := range cases {
+		report := operator.NewCorosyncTotemChange(
+			operator.Arguments{"token": tc.value},
+			"test-op",
+			operator.Options[operator.CorosyncTotemChange]{
+				BaseOperatorOptions: []operator.BaseOperatorOption{
+					operator.WithCustomLogger(suite.logger),
+				},
+				OperatorOptions: []operator.Option[operator.CorosyncTotemChange]{
+					operator.Option[operator.CorosyncTotemChange](
+						operator.WithCustomCorosyncTotemChangeExecutor(suite.mockExecutor),
+					),
+					operator.Option[operator.CorosyncTotemChange](operator.WithCustomCorosyncConfPath(suite.configPath)),
+				},
+			},
+		).Run(context.Background())
+
+		suite.Nil(report.Success)
+		suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+		suite.Equal(tc.errorMessage, report.Error.Message)
+	}
+}
+
+func (suite *CorosyncTotemChangeOperatorTestSuite) TestCorosyncTotemChangePlanErrorMissingFile() {
+	err := os.Remove(suite.configPath)
+	suite.Require().NoError(err)
+
+	report := operator.NewCorosyncTotemChange(
+		operator.Arguments{"token": 30000.0},
+		"test-op",
+		operator.Options[operator.CorosyncTotemChange]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.CorosyncTotemChange]{
+				operator.Option[operator.CorosyncTotemChange](
+					operator.WithCustomCorosyncTotemChangeExecutor(suite.mockExecutor),
+				),
+				operator.Option[operator.CorosyncTotemChange](operator.WithCustomCorosyncConfPath(suite.configPath)),
+			},
+		},
+	).Run(context.Background())
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Contains(report.Error.Message, "plan: error opening corosync.conf")
+}
+
+func (suite *CorosyncTotemChangeOperatorTestSuite) TestCorosyncTotemChangeAlreadyApplied() {
+	report := operator.NewCorosyncTotemChange(
+		operator.Arguments{"token": 5000.0, "consensus": "6000"},
+		"test-op",
+		operator.Options[operator.CorosyncTotemChange]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.CorosyncTotemChange]{
+				operator.Option[operator.CorosyncTotemChange](
+					operator.WithCustomCorosyncTotemChangeExecutor(suite.mockExecutor),
+				),
+				operator.Option[operator.CorosyncTotemChange](operator.WithCustomCorosyncConfPath(suite.configPath)),
+			},
+		},
+	).Run(context.Background())
+
+	expectedDiff := map[string]any{
+		"before": `{"totem":{"consensus":"6000","token":"5000"}}`,
+		"after":  `{"totem":{"consensus":"6000","token":"5000"}}`,
+	}
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.PLAN, report.Success.LastPhase)
+	suite.Equal(expectedDiff, report.Success.Diff)
+	suite.NoFileExists(suite.backupPath)
+}
+
+func (suite *CorosyncTotemChangeOperatorTestSuite) TestCorosyncTotemChangeSuccess() {
+	ctx := context.Background()
+
+	reloadCall := suite.mockExecutor.On("CombinedOutputContext", ctx, "corosync-cfgtool", "-R").
+		Return([]byte("Reloading corosync.conf..."), nil).
+		Once()
+
+	suite.mockExecutor.On("CombinedOutputContext", ctx, "corosync-cmapctl", "-g", "totem.token").
+		Return([]byte("totem.token (u32) = 30000\n"), nil).
+		Once().
+		NotBefore(reloadCall)
+
+	suite.mockExecutor.On("CombinedOutputContext", ctx, "corosync-cmapctl", "-g", "totem.max_messages").
+		Return([]byte("totem.max_messages (u32) = 20\n"), nil).
+		Once().
+		NotBefore(reloadCall)
+
+	report := operator.NewCorosyncTotemChange(
+		operator.Arguments{"token": 30000.0, "max_messages": 20.0},
+		"test-op",
+		operator.Options[operator.CorosyncTotemChange]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.CorosyncTotemChange]{
+				operator.Option[operator.CorosyncTotemChange](
+					operator.WithCustomCorosyncTotemChangeExecutor(suite.mockExecutor),
+				),
+				operator.Option[operator.CorosyncTotemChange](operator.WithCustomCorosyncConfPath(suite.configPath)),
+			},
+		},
+	).Run(ctx)
+
+	expectedDiff := map[string]any{
+		"before": `{"totem":{"token":"5000"}}`,
+		"after":  `{"totem":{"max_messages":"20","token":"30000"}}`,
+	}
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.VERIFY, report.Success.LastPhase)
+	suite.Equal(expectedDiff, report.Success.Diff)
+
+	suite.NoFileExists(suite.backupPath)
+
+	expectedContent := `# corosync configuration
+totem {
+	version: 2
+	# timeouts
+	token: 30000
+	consensus: 6000
+	interface {
+		ringnumber: 0
+	}
+	max_messages: 20
+}
+
+quorum {
+	provider: corosync_votequorum
+}
+`
+
+	content, err := os.ReadFile(suite.configPath)
+	suite.Require().NoError(err)
+	suite.Equal(expectedContent, string(content))
+}
+
+func (suite *CorosyncTotemChangeOperatorTestSuite) TestCorosyncTotemChangeVerifyErrorRollback() {
+	ctx := context.Background()
+
+	firstReloadCall := suite.mockExecutor.On("CombinedOutputContext", ctx, "corosync-cfgtool", "-R").
+		Return([]byte("Reloading corosync.conf..."), nil).
+		Once()
+
+	cmapctlCall := suite.mockExecutor.On("CombinedOutputContext", ctx, "corosync-cmapctl", "-g", "totem.token").
+		Return([]byte("totem.token (u32) = 5000\n"), nil).
+		Once().
+		NotBefore(firstReloadCall)
+
+	suite.mockExecutor.On("CombinedOutputContext", ctx, "corosync-cfgtool", "-R").
+		Return([]byte("Reloading corosync.conf..."), nil).
+		Once().
+		NotBefore(cmapctlCall)
+
+	report := operator.NewCorosyncTotemChange(
+		operator.Arguments{"token": 30000.0},
+		"test-op",
+		operator.Options[operator.CorosyncTotemChange]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.CorosyncTotemChange]{
+				operator.Option[operator.CorosyncTotemChange](
+					operator.WithCustomCorosyncTotemChangeExecutor(suite.mockExecutor),
+				),
+				operator.Option[operator.CorosyncTotemChange](operator.WithCustomCorosyncConfPath(suite.configPath)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.VERIFY, report.Error.ErrorPhase)
+	suite.Equal("verify: totem option token has runtime value 5000, expected 30000", report.Error.Message)
+
+	content, err := os.ReadFile(suite.configPath)
+	suite.Require().NoError(err)
+	suite.Equal(suite.originalContent, content)
+	suite.NoFileExists(suite.backupPath)
+}
+
+func (suite *CorosyncTotemChangeOperatorTestSuite) TestCorosyncTotemChangeVerifyErrorRollbackSkippedKeepsBackup() {
+	ctx := operator.ContextWithRollbackPolicy(context.Background(), operator.RollbackPolicyNever)
+
+	reloadCall := suite.mockExecutor.On("CombinedOutputContext", ctx, "corosync-cfgtool", "-R").
+		Return([]byte("Reloading corosync.conf..."), nil).
+		Once()
+
+	suite.mockExecutor.On("CombinedOutputContext", ctx, "corosync-cmapctl", "-g", "totem.token").
+		Return([]byte("totem.token (u32) = 5000\n"), nil).
+		Once().
+		NotBefore(reloadCall)
+
+	report := operator.NewCorosyncTotemChange(
+		operator.Arguments{"token": 30000.0},
+		"test-op",
+		operator.Options[operator.CorosyncTotemChange]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.CorosyncTotemChange]{
+				operator.Option[operator.CorosyncTotemChange](
+					operator.WithCustomCorosyncTotemChangeExecutor(suite.mockExecutor),
+				),
+				operator.Option[operator.CorosyncTotemChange](operator.WithCustomCorosyncConfPath(suite.configPath)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.VERIFY, report.Error.ErrorPhase)
+
+	backupContent, err := os.ReadFile(suite.backupPath)
+	suite.Require().NoError(err)
+	suite.Equal(suite.originalContent, backupContent)
+}
+
+func (suite *CorosyncTotemChangeOperatorTestSuite) TestCorosyncTotemChangeVerifyErrorReloadRollbackError() {
+	ctx := context.Background()
+
+	suite.mockExecutor.On("CombinedOutputContext", ctx, "corosync-cfgtool", "-R").
+		Return([]byte("Could not reload configuration"), errors.New("exit status 1")).
+		Twice()
+
+	report := operator.NewCorosyncTotemChange(
+		operator.Arguments{"token": 30000.0},
+		"test-op",
+		operator.Options[operator.CorosyncTotemChange]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.CorosyncTotemChange]{
+				operator.Option[operator.CorosyncTotemChange](
+					operator.WithCustomCorosyncTotemChangeExecutor(suite.mockExecutor),
+				),
+				operator.Option[operator.CorosyncTotemChange](operator.WithCustomCorosyncConfPath(suite.configPath)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.ROLLBACK, report.Error.ErrorPhase)
+	suite.Equal(
+		"verify: error reloading corosync configuration: exit status 1, output: Could not reload configuration; "+
+			"rollback: error reloading corosync configuration: exit status 1, output: Could not reload configuration",
+		report.Error.Message,
+	)
+
+	content, err := os.ReadFile(suite.configPath)
+	suite.Require().NoError(err)
+	suite.Equal(suite.originalContent, content)
+}
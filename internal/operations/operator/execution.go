@@ -29,6 +29,8 @@ type ExecutionReport struct {
 	OperationID string
 	Success     *ExecutionSuccess
 	Error       *ExecutionError
+	// RollbackState is the state captured during PLAN when the rollback was deferred
+	RollbackState map[string]any
 }
 
 func executionReportWithError(err error, phase PhaseName, operationID string) *ExecutionReport {
@@ -5,6 +5,7 @@ package operator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 )
@@ -23,13 +24,19 @@ type Executor struct {
 	phaser       phaser
 	operationID  string
 	logger       *slog.Logger
+	// delayAfter keeps the operator resources after the run, until runAfter is called,
+	// so the operator running this executor can still roll it back
+	delayAfter   bool
+	afterPending bool
 }
 
 const (
-	RUN     = "Executor.Run"
-	BEGIN   = "BEGIN"
-	SUCCESS = "SUCCESS"
-	FAILURE = "FAILURE"
+	RUN      = "Executor.Run"
+	BEGIN    = "BEGIN"
+	SUCCESS  = "SUCCESS"
+	FAILURE  = "FAILURE"
+	SKIPPED  = "SKIPPED"
+	DEFERRED = "DEFERRED"
 )
 
 func NewExecutor(phaser phaser, operationID string, logger *slog.Logger) *Executor {
@@ -49,6 +56,14 @@ func (e *Executor) Run(ctx context.Context) *ExecutionReport {
 	e.currentPhase = PLAN
 	e.logger.Info(RUN, "phase", e.currentPhase, "event", BEGIN)
 
+	// The request is refused before any change if its rollback cannot be deferred as requested
+	if rollbackPolicyFromContext(ctx) == RollbackPolicyDeferred && !e.SupportsDeferredRollback() {
+		err := errors.New("deferred rollback policy not supported by the operator")
+		e.logger.Info(RUN, "phase", e.currentPhase, "event", FAILURE, "error", err)
+
+		return executionReportWithError(fmt.Errorf("plan: %w", err), e.currentPhase, e.operationID)
+	}
+
 	alreadyApplied, err := e.phaser.plan(ctx)
 	if err != nil {
 		e.logger.Info(RUN, "phase", e.currentPhase, "event", FAILURE, "error", err)
@@ -57,7 +72,11 @@ func (e *Executor) Run(ctx context.Context) *ExecutionReport {
 		return executionReportWithError(planError, e.currentPhase, e.operationID)
 	}
 
-	defer e.phaser.after(ctx)
+	if e.delayAfter {
+		e.afterPending = true
+	} else {
+		defer e.phaser.after(ctx)
+	}
 
 	if alreadyApplied {
 		diff := e.phaser.operationDiff(ctx)
@@ -100,6 +119,20 @@ func (e *Executor) Run(ctx context.Context) *ExecutionReport {
 }
 
 func (e *Executor) handleRollback(ctx context.Context, err error) *ExecutionReport {
+	switch rollbackPolicyFromContext(ctx) {
+	case RollbackPolicyNever:
+		e.logger.Info(RUN, "phase", ROLLBACK, "event", SKIPPED)
+
+		return executionReportWithError(
+			fmt.Errorf("%w; rollback skipped by rollback policy", err),
+			e.currentPhase,
+			e.operationID,
+		)
+	case RollbackPolicyDeferred:
+		return e.deferRollback(err)
+	case RollbackPolicyAuto:
+	}
+
 	e.logger.Info(RUN, "phase", ROLLBACK, "event", BEGIN)
 
 	rollbackError := e.phaser.rollback(ctx)
@@ -119,6 +152,91 @@ func (e *Executor) handleRollback(ctx context.Context, err error) *ExecutionRepo
 	return executionReportWithError(err, e.currentPhase, e.operationID)
 }
 
+// SupportsDeferredRollback tells if the operator can be run with the deferred rollback policy.
+func (e *Executor) SupportsDeferredRollback() bool {
+	_, ok := e.phaser.(deferredRollbacker)
+
+	return ok
+}
+
+// deferRollback keeps the PLAN state in the report, so the caller can persist it
+// and run the rollback later. Run refuses the operators without deferred rollback support.
+func (e *Executor) deferRollback(err error) *ExecutionReport {
+	deferrable, ok := e.phaser.(deferredRollbacker)
+	if !ok {
+		return executionReportWithError(
+			fmt.Errorf("%w; deferred rollback not supported", err),
+			e.currentPhase,
+			e.operationID,
+		)
+	}
+
+	e.logger.Info(RUN, "phase", ROLLBACK, "event", DEFERRED)
+
+	report := executionReportWithError(
+		fmt.Errorf("%w; rollback deferred", err),
+		e.currentPhase,
+		e.operationID,
+	)
+	report.RollbackState = deferrable.rollbackState()
+
+	return report
+}
+
+// RunRollback runs the deferred rollback of a failed operation.
+// The operator must be built with the same operation ID and arguments as the failed one,
+// and the state is the one reported when the rollback was deferred.
+func (e *Executor) RunRollback(ctx context.Context, state map[string]any) *ExecutionReport {
+	e.currentPhase = ROLLBACK
+	e.logger.Info(RUN, "phase", e.currentPhase, "event", BEGIN)
+
+	deferrable, ok := e.phaser.(deferredRollbacker)
+	if !ok {
+		err := errors.New("rollback: operator does not support deferred rollbacks")
+		e.logger.Info(RUN, "phase", e.currentPhase, "event", FAILURE, "error", err)
+
+		return executionReportWithError(err, e.currentPhase, e.operationID)
+	}
+
+	err := deferrable.setup(ctx)
+	if err != nil {
+		e.logger.Info(RUN, "phase", e.currentPhase, "event", FAILURE, "error", err)
+
+		return executionReportWithError(fmt.Errorf("rollback: %w", err), e.currentPhase, e.operationID)
+	}
+
+	defer e.phaser.after(ctx)
+
+	deferrable.restoreRollbackState(state)
+
+	err = e.phaser.rollback(ctx)
+	if err != nil {
+		e.logger.Info(RUN, "phase", e.currentPhase, "event", FAILURE, "error", err)
+
+		return executionReportWithError(fmt.Errorf("rollback: %w", err), e.currentPhase, e.operationID)
+	}
+
+	e.logger.Info(RUN, "phase", e.currentPhase, "event", SUCCESS)
+
+	// the rolled back operation has no diff, the host is back to its previous state
+	diff := map[string]any{
+		beforeDiffField: nil,
+		afterDiffField:  nil,
+	}
+
+	return executionReportWithSuccess(diff, e.currentPhase, e.operationID)
+}
+
+// runAfter runs the after phase delayed by delayAfter, if the PLAN phase succeeded.
+func (e *Executor) runAfter(ctx context.Context) {
+	if !e.afterPending {
+		return
+	}
+
+	e.afterPending = false
+	e.phaser.after(ctx)
+}
+
 func wrapRollbackError(phaseError error, rollbackError error) error {
 	return fmt.Errorf("%w; rollback: %w", phaseError, rollbackError)
 }
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const backupTimestampFormat = "20060102150405"
+
+// backupFilePath returns the location of the backup of the given file for an operation.
+// The backup lives next to the original file so administrators can find it easily
+// if a manual recovery is needed.
+func backupFilePath(path, operationID string) string {
+	suffix := operationID
+	if suffix == "" {
+		suffix = time.Now().Format(backupTimestampFormat)
+	}
+
+	return fmt.Sprintf("%s.trento-%s.bak", path, suffix)
+}
+
+// backupFile copies the given file to its backup location, keeping the original permissions.
+func backupFile(path, backupPath string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("error getting %s file information: %w", path, err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading %s file: %w", path, err)
+	}
+
+	err = os.WriteFile(backupPath, content, info.Mode().Perm())
+	if err != nil {
+		return fmt.Errorf("error writing backup file %s: %w", backupPath, err)
+	}
+
+	return nil
+}
+
+// removeBackupFile removes the backup of an operation once it is not needed anymore.
+// The backup is kept if the changes were neither verified nor rolled back, so the file
+// can be recovered manually.
+func removeBackupFile(backupPath string, keep bool, logger *slog.Logger) {
+	if keep {
+		logger.Warn("changes were neither verified nor rolled back, keeping the backup file", "backup", backupPath)
+
+		return
+	}
+
+	err := os.Remove(backupPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		logger.Warn("error removing the backup file", "backup", backupPath, "error", err)
+	}
+}
+
+// restoreFile replaces the given file with the content of its backup.
+func restoreFile(backupPath, path string) error {
+	content, err := os.ReadFile(backupPath)
+	if err != nil {
+		return fmt.Errorf("error reading backup file %s: %w", backupPath, err)
+	}
+
+	return writeFileAtomically(path, content)
+}
+
+// writeFileAtomically writes the content in a temporary file in the same directory
+// and renames it to the final location, so readers never see a partially written file.
+// The permissions of the existing file are kept.
+func writeFileAtomically(path string, content []byte) error {
+	mode := os.FileMode(0o644)
+
+	info, err := os.Stat(path)
+	if err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("error creating temporary file for %s: %w", path, err)
+	}
+
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath)
+
+	_, err = tmpFile.Write(content)
+	if err != nil {
+		tmpFile.Close()
+
+		return fmt.Errorf("error writing temporary file for %s: %w", path, err)
+	}
+
+	err = tmpFile.Close()
+	if err != nil {
+		return fmt.Errorf("error closing temporary file for %s: %w", path, err)
+	}
+
+	err = os.Chmod(tmpPath, mode)
+	if err != nil {
+		return fmt.Errorf("error setting permissions of %s: %w", path, err)
+	}
+
+	err = os.Rename(tmpPath, path)
+	if err != nil {
+		return fmt.Errorf("error replacing %s: %w", path, err)
+	}
+
+	return nil
+}
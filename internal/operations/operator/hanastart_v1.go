@@ -0,0 +1,529 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/trento-project/agent/v3/internal/core/cluster/cib"
+	"github.com/trento-project/agent/v3/internal/core/cluster/crmmon"
+	"github.com/trento-project/agent/v3/internal/core/sapsystem/sapcontrolapi"
+	"github.com/trento-project/agent/v3/pkg/utils"
+)
+
+const (
+	HanaStartOperatorName = "hanastart"
+
+	hanaProcessPrefix     = "hdb"
+	hanaCibadminPath      = "/usr/sbin/cibadmin"
+	hanaCrmMonPath        = "/usr/sbin/crm_mon"
+	hanaSRStateCommand    = "hdbnsutil -sr_stateConfiguration -sapcontrol=1"
+	hanaSRModeNone        = "none"
+	hanaSRModePrimary     = "primary"
+	saphanaResourceType   = "SAPHana"
+	saphanaControllerType = "SAPHanaController"
+
+	// pacemaker exit code when the cluster daemons are not running
+	crmExitNotConnected = 102
+)
+
+var (
+	hanaSRModePattern = regexp.MustCompile(`(?m)^mode=(.*)$`)
+	hanaSIDPattern    = regexp.MustCompile(`^[A-Z][A-Z0-9]{2}$`)
+	// the instance number is matched against the InstanceNumber attribute of the cluster resources
+	hanaInstanceNumberPattern = regexp.MustCompile(`^\d{2}$`)
+)
+
+type hanaStateChangeArguments struct {
+	sid        string
+	instNumber string
+	timeout    time.Duration
+}
+
+type hanaStartDiffOutput struct {
+	Started bool   `json:"started"`
+	SRMode  string `json:"sr_mode,omitempty"`
+}
+
+type HanaStartOption Option[HanaStart]
+
+type HanaStart struct {
+	baseOperator
+
+	parsedArguments     *hanaStateChangeArguments
+	sapControlConnector sapcontrolapi.WebService
+	executor            utils.CommandExecutor
+	hostname            string
+	interval            time.Duration
+	srMode              string
+}
+
+func WithCustomHanaStartSapcontrol(sapControlConnector sapcontrolapi.WebService) HanaStartOption {
+	return func(o *HanaStart) {
+		o.sapControlConnector = sapControlConnector
+	}
+}
+
+func WithCustomHanaStartExecutor(executor utils.CommandExecutor) HanaStartOption {
+	return func(o *HanaStart) {
+		o.executor = executor
+	}
+}
+
+func WithCustomHanaStartHostname(hostname string) HanaStartOption {
+	return func(o *HanaStart) {
+		o.hostname = hostname
+	}
+}
+
+func WithCustomHanaStartInterval(interval time.Duration) HanaStartOption {
+	return func(o *HanaStart) {
+		o.interval = interval
+	}
+}
+
+// NewHanaStart operator starts a SAP HANA database instance.
+//
+// Arguments:
+//  sid (required): String with the SID of the HANA database, e.g. PRD
+//  instance_number (required): String with the two digits instance number of the HANA instance to start
+//  timeout: Timeout in seconds to wait until all the hdb* processes are started
+//
+// # Execution Phases
+//
+// - PLAN:
+//   The operator gets the current state of the hdb* processes and stores it.
+//   The operation is skipped if the HANA instance is already started.
+//   The system replication mode of the instance is stored as well.
+//   The operation fails if the instance is controlled by a managed SAPHana/SAPHanaController
+//   cluster resource, as starting it outside of the cluster interferes with pacemaker.
+//   The resource is considered unmanaged if the cluster, the resource or the node
+//   are in maintenance, or if pacemaker is not installed or not running.
+//
+// - COMMIT:
+//   It starts the HANA instance using the sapcontrol Start command.
+//
+// - VERIFY:
+//   Verify if all the hdb* processes of the instance are started.
+//
+// - ROLLBACK:
+//   If an error occurs during the COMMIT or VERIFY phase, the instance is stopped back again.
+
+func NewHanaStart(
+	arguments Arguments,
+	operationID string,
+	options Options[HanaStart],
+) *Executor {
+	hanaStart := &HanaStart{
+		baseOperator: newBaseOperator(
+			HanaStartOperatorName, operationID, arguments, options.BaseOperatorOptions...,
+		),
+		executor: utils.Executor{},
+		interval: defaultSapInstanceStateInterval,
+	}
+
+	hanaStart.hostname, _ = os.Hostname()
+
+	for _, opt := range options.OperatorOptions {
+		opt(hanaStart)
+	}
+
+	return &Executor{
+		phaser:      hanaStart,
+		operationID: operationID,
+		logger:      hanaStart.logger,
+	}
+}
+
+func (h *HanaStart) setup(_ context.Context) error {
+	opArguments, err := parseHanaStateChangeArguments(h.arguments)
+	if err != nil {
+		return err
+	}
+
+	h.parsedArguments = opArguments
+
+	// Use custom sapControlConnector or create a new one based on the instance_number argument
+	if h.sapControlConnector == nil {
+		h.sapControlConnector = sapcontrolapi.NewWebServiceUnix(h.parsedArguments.instNumber)
+	}
+
+	return nil
+}
+
+func (h *HanaStart) plan(ctx context.Context) (bool, error) {
+	err := h.setup(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	started, err := allHanaProcessesInState(ctx, h.sapControlConnector, sapcontrolapi.STATECOLOR_GREEN)
+	if err != nil {
+		return false, fmt.Errorf("error checking processes state: %w", err)
+	}
+
+	h.resources[beforeDiffField] = started
+
+	if started {
+		h.logger.Info("HANA instance already started, skipping operation")
+		h.resources[afterDiffField] = started
+
+		return true, nil
+	}
+
+	h.srMode, err = getHanaSRMode(ctx, h.executor, h.parsedArguments)
+	if err != nil {
+		return false, err
+	}
+
+	resourceID, managed, err := getHanaClusterResourceManaged(
+		ctx, h.executor, h.parsedArguments, h.hostname,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	if managed {
+		return false, fmt.Errorf(
+			"HANA instance %s is managed by the cluster resource %s, "+
+				"start it using the cluster or set it in maintenance first",
+			h.parsedArguments.sid, resourceID,
+		)
+	}
+
+	return false, nil
+}
+
+func (h *HanaStart) commit(ctx context.Context) error {
+	request := new(sapcontrolapi.Start)
+
+	_, err := h.sapControlConnector.StartContext(ctx, request)
+	if err != nil {
+		return fmt.Errorf("error starting HANA instance: %w", err)
+	}
+
+	return nil
+}
+
+func (h *HanaStart) verify(ctx context.Context) error {
+	err := waitUntilHanaInstanceState(
+		ctx,
+		h.sapControlConnector,
+		sapcontrolapi.STATECOLOR_GREEN,
+		h.parsedArguments.timeout,
+		h.interval,
+	)
+	if err != nil {
+		return err
+	}
+
+	h.resources[afterDiffField] = true
+
+	return nil
+}
+
+func (h *HanaStart) rollback(ctx context.Context) error {
+	request := new(sapcontrolapi.Stop)
+
+	_, err := h.sapControlConnector.StopContext(ctx, request)
+	if err != nil {
+		return fmt.Errorf("error stopping HANA instance: %w", err)
+	}
+
+	return waitUntilHanaInstanceState(
+		ctx,
+		h.sapControlConnector,
+		sapcontrolapi.STATECOLOR_GRAY,
+		h.parsedArguments.timeout,
+		h.interval,
+	)
+}
+
+// operationDiff needs to be refactored, ignoring duplication issues for now
+//
+//nolint:dupl
+func (h *HanaStart) operationDiff(_ context.Context) map[string]any {
+	diff := make(map[string]any)
+
+	beforeStarted, ok := h.resources[beforeDiffField].(bool)
+	if !ok {
+		panic(fmt.Sprintf("invalid beforeStarted value: cannot parse '%s' to bool",
+			h.resources[beforeDiffField]))
+	}
+
+	afterStarted, ok := h.resources[afterDiffField].(bool)
+	if !ok {
+		panic(fmt.Sprintf("invalid afterStarted value: cannot parse '%s' to bool",
+			h.resources[afterDiffField]))
+	}
+
+	beforeDiffOutput := hanaStartDiffOutput{
+		Started: beforeStarted,
+		SRMode:  h.srMode,
+	}
+
+	before, err := json.Marshal(beforeDiffOutput)
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling before diff output: %v", err))
+	}
+
+	diff[beforeDiffField] = string(before)
+
+	afterDiffOutput := hanaStartDiffOutput{
+		Started: afterStarted,
+		SRMode:  h.srMode,
+	}
+
+	after, err := json.Marshal(afterDiffOutput)
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling after diff output: %v", err))
+	}
+
+	diff[afterDiffField] = string(after)
+
+	return diff
+}
+
+// allHanaProcessesInState checks the state of the hdb* processes of the instance.
+// Other processes, like the ones of the XS advanced runtime, are ignored.
+func allHanaProcessesInState(
+	ctx context.Context,
+	connector sapcontrolapi.WebService,
+	expectedState sapcontrolapi.STATECOLOR,
+) (bool, error) {
+	request := new(sapcontrolapi.GetProcessList)
+
+	response, err := connector.GetProcessListContext(ctx, request)
+	if err != nil {
+		return false, fmt.Errorf("error getting instance process list: %w", err)
+	}
+
+	hanaProcesses := 0
+
+	for _, process := range response.Processes {
+		if !strings.HasPrefix(process.Name, hanaProcessPrefix) {
+			continue
+		}
+
+		hanaProcesses++
+
+		if process.Dispstatus != expectedState {
+			return false, nil
+		}
+	}
+
+	// GetProcessList can return an empty list for some seconds when the instance
+	// is started. Discard this scenario.
+	return hanaProcesses > 0, nil
+}
+
+func waitUntilHanaInstanceState(
+	ctx context.Context,
+	connector sapcontrolapi.WebService,
+	expectedState sapcontrolapi.STATECOLOR,
+	timeout time.Duration,
+	interval time.Duration,
+) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		inState, err := allHanaProcessesInState(timeoutCtx, connector, expectedState)
+		if err != nil {
+			return err
+		}
+
+		if timeoutCtx.Err() != nil {
+			return errors.New("error waiting until HANA instance is in desired state")
+		}
+
+		if inState {
+			return nil
+		}
+
+		err = sleepContext(timeoutCtx, interval)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// getHanaSRMode returns the configured system replication mode of the instance using hdbnsutil.
+// The configuration is available even if the instance is stopped.
+// hdbnsutil is found in the login environment of the <sid>adm user, so the command
+// given to the shell is constant and no argument is interpolated in it.
+func getHanaSRMode(
+	ctx context.Context,
+	executor utils.CommandExecutor,
+	args *hanaStateChangeArguments,
+) (string, error) {
+	user := strings.ToLower(args.sid) + "adm"
+
+	output, err := executor.OutputContext(ctx, "/usr/bin/su", "-l", user, "-c", hanaSRStateCommand)
+	if err != nil {
+		return "", fmt.Errorf("error getting system replication state: %w", err)
+	}
+
+	match := hanaSRModePattern.FindSubmatch(output)
+	if match == nil {
+		return hanaSRModeNone, nil
+	}
+
+	return strings.TrimSpace(string(match[1])), nil
+}
+
+// getHanaClusterResourceManaged looks for the SAPHana/SAPHanaController resource controlling
+// the instance and returns whether pacemaker is currently managing it in the given host.
+// If pacemaker is not installed or not running in the host, the instance is not managed.
+// Any other error getting the cluster information base is returned, as the instance
+// might be managed.
+func getHanaClusterResourceManaged(
+	ctx context.Context,
+	executor utils.CommandExecutor,
+	args *hanaStateChangeArguments,
+	hostname string,
+) (string, bool, error) {
+	cibOutput, err := executor.OutputContext(ctx, hanaCibadminPath, "--query", "--local")
+	if err != nil && isPacemakerUnavailable(err) {
+		return "", false, nil
+	}
+
+	if err != nil {
+		return "", false, fmt.Errorf("error getting cluster information base: %w", err)
+	}
+
+	var cibRoot cib.Root
+	if err := xml.Unmarshal(cibOutput, &cibRoot); err != nil {
+		return "", false, fmt.Errorf("error decoding cibadmin output: %w", err)
+	}
+
+	resourceID := findHanaCloneID(cibRoot, args.sid, args.instNumber)
+	if resourceID == "" {
+		return "", false, nil
+	}
+
+	crmMonOutput, err := executor.OutputContext(ctx, hanaCrmMonPath, "-X", "--inactive")
+	if err != nil {
+		return "", false, fmt.Errorf("error getting cluster status: %w", err)
+	}
+
+	var crmMonRoot crmmon.Root
+	if err := xml.Unmarshal(crmMonOutput, &crmMonRoot); err != nil {
+		return "", false, fmt.Errorf("error decoding crm_mon output: %w", err)
+	}
+
+	for _, node := range crmMonRoot.Nodes {
+		if node.Name == hostname && node.Maintenance {
+			return resourceID, false, nil
+		}
+	}
+
+	for _, clone := range crmMonRoot.Clones {
+		if clone.ID != resourceID {
+			continue
+		}
+
+		if !clone.Managed {
+			return resourceID, false, nil
+		}
+
+		for _, resource := range clone.Resources {
+			if resource.Node != nil && resource.Node.Name == hostname && !resource.Managed {
+				return resourceID, false, nil
+			}
+		}
+
+		return resourceID, true, nil
+	}
+
+	return "", false, fmt.Errorf("cluster resource %s not found in the cluster status", resourceID)
+}
+
+// isPacemakerUnavailable checks if a pacemaker command failed because pacemaker
+// is not installed or its daemons are not running.
+func isPacemakerUnavailable(err error) bool {
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, exec.ErrNotFound) {
+		return true
+	}
+
+	var exitErr *exec.ExitError
+
+	return errors.As(err, &exitErr) && exitErr.ExitCode() == crmExitNotConnected
+}
+
+func findHanaCloneID(cibRoot cib.Root, sid, instNumber string) string {
+	clones := slices.Concat(cibRoot.Configuration.Resources.Masters, cibRoot.Configuration.Resources.Clones)
+
+	for _, clone := range clones {
+		if clone.Primitive.Type != saphanaResourceType && clone.Primitive.Type != saphanaControllerType {
+			continue
+		}
+
+		instanceAttributes := make(map[string]string)
+		for _, attribute := range clone.Primitive.InstanceAttributes {
+			instanceAttributes[attribute.Name] = attribute.Value
+		}
+
+		if instanceAttributes["SID"] == sid && instanceAttributes["InstanceNumber"] == instNumber {
+			return clone.ID
+		}
+	}
+
+	return ""
+}
+
+func parseHanaStateChangeArguments(rawArguments Arguments) (*hanaStateChangeArguments, error) {
+	sidArgument, found := rawArguments["sid"]
+	if !found {
+		return nil, errors.New("argument sid not provided, could not use the operator")
+	}
+
+	sid, ok := sidArgument.(string)
+	if !ok {
+		return nil, fmt.Errorf(
+			"could not parse sid argument as string, argument provided: %v",
+			sidArgument,
+		)
+	}
+
+	if sid == "" {
+		return nil, errors.New("sid argument is empty")
+	}
+
+	if !hanaSIDPattern.MatchString(sid) {
+		return nil, fmt.Errorf(
+			"sid argument must be 3 uppercase alphanumeric characters starting with a letter, argument provided: %s",
+			sid,
+		)
+	}
+
+	sapArguments, err := parseSAPStateChangeArguments(rawArguments)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hanaInstanceNumberPattern.MatchString(sapArguments.instNumber) {
+		return nil, fmt.Errorf(
+			"instance_number argument must be two digits, argument provided: %s",
+			sapArguments.instNumber,
+		)
+	}
+
+	return &hanaStateChangeArguments{
+		sid:        sid,
+		instNumber: sapArguments.instNumber,
+		timeout:    sapArguments.timeout,
+	}, nil
+}
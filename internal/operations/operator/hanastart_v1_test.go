@@ -0,0 +1,337 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/trento-project/agent/v3/internal/core/sapsystem/sapcontrolapi"
+	sapcontrolMocks "github.com/trento-project/agent/v3/internal/core/sapsystem/sapcontrolapi/mocks"
+	"github.com/trento-project/agent/v3/internal/operations/operator"
+	"github.com/trento-project/agent/v3/pkg/utils/mocks"
+)
+
+type HanaStartOperatorTestSuite struct {
+	suite.Suite
+
+	mockSapcontrol  *sapcontrolMocks.MockWebService
+	mockCmdExecutor *mocks.MockCommandExecutor
+}
+
+func TestHanaStartOperator(t *testing.T) {
+	suite.Run(t, new(HanaStartOperatorTestSuite))
+}
+
+func (suite *HanaStartOperatorTestSuite) SetupTest() {
+	suite.mockSapcontrol = sapcontrolMocks.NewMockWebService(suite.T())
+	suite.mockCmdExecutor = mocks.NewMockCommandExecutor(suite.T())
+}
+
+func commandExitError(exitCode int) error {
+	return exec.Command("sh", "-c", fmt.Sprintf("exit %d", exitCode)).Run()
+}
+
+func (suite *HanaStartOperatorTestSuite) TestHanaStartPlanErrorParsingArguments() {
+	cases := []struct {
+		arguments    operator.Arguments
+		errorMessage string
+	}{
+		{
+			arguments:    operator.Arguments{"instance_number": "00"},
+			errorMessage: "plan: argument sid not provided, could not use the operator",
+		},
+		{
+			arguments: operator.Arguments{"sid": "prd", "instance_number": "00"},
+			errorMessage: "plan: sid argument must be 3 uppercase alphanumeric characters starting with a letter, " +
+				"argument provided: prd",
+		},
+		{
+			arguments: operator.Arguments{"sid": "PRD;id", "instance_number": "00"},
+			errorMessage: "plan: sid argument must be 3 uppercase alphanumeric characters starting with a letter, " +
+				"argument provided: PRD;id",
+		},
+		{
+			arguments:    operator.Arguments{"sid": "PRD", "instance_number": "00;id"},
+			errorMessage: "plan: instance_number argument must be two digits, argument provided: 00;id",
+		},
+	}
+
+	for _, tc := range cases {
+		report := operator.NewHanaStart(
+			tc.arguments,
+			"test-op",
+			operator.Options[operator.HanaStart]{
+				OperatorOptions: []operator.Option[operator.HanaStart]{
+					operator.Option[operator.HanaStart](operator.WithCustomHanaStartSapcontrol(suite.mockSapcontrol)),
+					operator.Option[operator.HanaStart](operator.WithCustomHanaStartExecutor(suite.mockCmdExecutor)),
+					operator.Option[operator.HanaStart](operator.WithCustomHanaStartHostname("vmhana01")),
+					operator.Option[operator.HanaStart](operator.WithCustomHanaStartInterval(0 * time.Second)),
+				},
+			},
+		).Run(context.Background())
+
+		suite.Nil(report.Success)
+		suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+		suite.Equal(tc.errorMessage, report.Error.Message)
+	}
+}
+
+func (suite *HanaStartOperatorTestSuite) TestHanaStartPlanErrorProcesses() {
+	ctx := context.Background()
+
+	suite.mockSapcontrol.
+		On("GetProcessListContext", ctx, mock.Anything).
+		Return(nil, errors.New("error getting processes")).
+		Once()
+
+	report := operator.NewHanaStart(
+		operator.Arguments{"sid": "PRD", "instance_number": "00"},
+		"test-op",
+		operator.Options[operator.HanaStart]{
+			OperatorOptions: []operator.Option[operator.HanaStart]{
+				operator.Option[operator.HanaStart](operator.WithCustomHanaStartSapcontrol(suite.mockSapcontrol)),
+				operator.Option[operator.HanaStart](operator.WithCustomHanaStartExecutor(suite.mockCmdExecutor)),
+				operator.Option[operator.HanaStart](operator.WithCustomHanaStartHostname("vmhana01")),
+				operator.Option[operator.HanaStart](operator.WithCustomHanaStartInterval(0 * time.Second)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal(
+		"plan: error checking processes state: error getting instance process list: error getting processes",
+		report.Error.Message,
+	)
+}
+
+func (suite *HanaStartOperatorTestSuite) TestHanaStartAlreadyStarted() {
+	ctx := context.Background()
+
+	suite.mockSapcontrol.
+		On("GetProcessListContext", ctx, mock.Anything).
+		Return(hanaProcessList(sapcontrolapi.STATECOLOR_GREEN), nil).
+		Once()
+
+	report := operator.NewHanaStart(
+		operator.Arguments{"sid": "PRD", "instance_number": "00"},
+		"test-op",
+		operator.Options[operator.HanaStart]{
+			OperatorOptions: []operator.Option[operator.HanaStart]{
+				operator.Option[operator.HanaStart](operator.WithCustomHanaStartSapcontrol(suite.mockSapcontrol)),
+				operator.Option[operator.HanaStart](operator.WithCustomHanaStartExecutor(suite.mockCmdExecutor)),
+				operator.Option[operator.HanaStart](operator.WithCustomHanaStartHostname("vmhana01")),
+				operator.Option[operator.HanaStart](operator.WithCustomHanaStartInterval(0 * time.Second)),
+			},
+		},
+	).Run(ctx)
+
+	expectedDiff := map[string]any{
+		"before": `{"started":true}`,
+		"after":  `{"started":true}`,
+	}
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.PLAN, report.Success.LastPhase)
+	suite.Equal(expectedDiff, report.Success.Diff)
+}
+
+func (suite *HanaStartOperatorTestSuite) TestHanaStartPlanErrorClusterManaged() {
+	ctx := context.Background()
+
+	suite.mockSapcontrol.
+		On("GetProcessListContext", ctx, mock.Anything).
+		Return(hanaProcessList(sapcontrolapi.STATECOLOR_GRAY), nil).
+		Once()
+
+	suite.mockCmdExecutor.
+		On("OutputContext", ctx, "/usr/bin/su", "-l", "prdadm", "-c", hanaHdbnsutilCommand).
+		Return([]byte(hanaSRStatePrimaryOutput), nil).
+		Once()
+
+	suite.mockCmdExecutor.
+		On("OutputContext", ctx, "/usr/sbin/cibadmin", "--query", "--local").
+		Return([]byte(hanaCibOutput), nil).
+		Once()
+
+	suite.mockCmdExecutor.
+		On("OutputContext", ctx, "/usr/sbin/crm_mon", "-X", "--inactive").
+		Return([]byte(hanaCrmMonManagedOutput), nil).
+		Once()
+
+	report := operator.NewHanaStart(
+		operator.Arguments{"sid": "PRD", "instance_number": "00"},
+		"test-op",
+		operator.Options[operator.HanaStart]{
+			OperatorOptions: []operator.Option[operator.HanaStart]{
+				operator.Option[operator.HanaStart](operator.WithCustomHanaStartSapcontrol(suite.mockSapcontrol)),
+				operator.Option[operator.HanaStart](operator.WithCustomHanaStartExecutor(suite.mockCmdExecutor)),
+				operator.Option[operator.HanaStart](operator.WithCustomHanaStartHostname("vmhana01")),
+				operator.Option[operator.HanaStart](operator.WithCustomHanaStartInterval(0 * time.Second)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal(
+		"plan: HANA instance PRD is managed by the cluster resource msl_SAPHana_PRD_HDB00, "+
+			"start it using the cluster or set it in maintenance first",
+		report.Error.Message,
+	)
+}
+
+func (suite *HanaStartOperatorTestSuite) TestHanaStartPlanErrorClusterInformationBase() {
+	ctx := context.Background()
+
+	suite.mockSapcontrol.
+		On("GetProcessListContext", ctx, mock.Anything).
+		Return(hanaProcessList(sapcontrolapi.STATECOLOR_GRAY), nil).
+		Once()
+
+	suite.mockCmdExecutor.
+		On("OutputContext", ctx, "/usr/bin/su", "-l", "prdadm", "-c", hanaHdbnsutilCommand).
+		Return([]byte(hanaSRStatePrimaryOutput), nil).
+		Once()
+
+	suite.mockCmdExecutor.
+		On("OutputContext", ctx, "/usr/sbin/cibadmin", "--query", "--local").
+		Return(nil, commandExitError(1)).
+		Once()
+
+	report := operator.NewHanaStart(
+		operator.Arguments{"sid": "PRD", "instance_number": "00"},
+		"test-op",
+		operator.Options[operator.HanaStart]{
+			OperatorOptions: []operator.Option[operator.HanaStart]{
+				operator.Option[operator.HanaStart](operator.WithCustomHanaStartSapcontrol(suite.mockSapcontrol)),
+				operator.Option[operator.HanaStart](operator.WithCustomHanaStartExecutor(suite.mockCmdExecutor)),
+				operator.Option[operator.HanaStart](operator.WithCustomHanaStartHostname("vmhana01")),
+				operator.Option[operator.HanaStart](operator.WithCustomHanaStartInterval(0 * time.Second)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal("plan: error getting cluster information base: exit status 1", report.Error.Message)
+}
+
+func (suite *HanaStartOperatorTestSuite) TestHanaStartSuccessInMaintenance() {
+	ctx := context.Background()
+
+	planProcessesCall := suite.mockSapcontrol.
+		On("GetProcessListContext", ctx, mock.Anything).
+		Return(hanaProcessList(sapcontrolapi.STATECOLOR_GRAY), nil).
+		Once()
+
+	suite.mockCmdExecutor.
+		On("OutputContext", ctx, "/usr/bin/su", "-l", "prdadm", "-c", hanaHdbnsutilCommand).
+		Return([]byte(hanaSRStatePrimaryOutput), nil).
+		Once()
+
+	suite.mockCmdExecutor.
+		On("OutputContext", ctx, "/usr/sbin/cibadmin", "--query", "--local").
+		Return([]byte(hanaCibOutput), nil).
+		Once()
+
+	suite.mockCmdExecutor.
+		On("OutputContext", ctx, "/usr/sbin/crm_mon", "-X", "--inactive").
+		Return([]byte(hanaCrmMonMaintenanceOutput), nil).
+		Once()
+
+	startCall := suite.mockSapcontrol.
+		On("StartContext", ctx, mock.Anything).
+		Return(nil, nil).
+		Once().
+		NotBefore(planProcessesCall)
+
+	suite.mockSapcontrol.
+		On("GetProcessListContext", mock.Anything, mock.Anything).
+		Return(hanaProcessList(sapcontrolapi.STATECOLOR_GREEN), nil).
+		Once().
+		NotBefore(startCall)
+
+	report := operator.NewHanaStart(
+		operator.Arguments{"sid": "PRD", "instance_number": "00"},
+		"test-op",
+		operator.Options[operator.HanaStart]{
+			OperatorOptions: []operator.Option[operator.HanaStart]{
+				operator.Option[operator.HanaStart](operator.WithCustomHanaStartSapcontrol(suite.mockSapcontrol)),
+				operator.Option[operator.HanaStart](operator.WithCustomHanaStartExecutor(suite.mockCmdExecutor)),
+				operator.Option[operator.HanaStart](operator.WithCustomHanaStartHostname("vmhana01")),
+				operator.Option[operator.HanaStart](operator.WithCustomHanaStartInterval(0 * time.Second)),
+			},
+		},
+	).Run(ctx)
+
+	expectedDiff := map[string]any{
+		"before": `{"started":false,"sr_mode":"primary"}`,
+		"after":  `{"started":true,"sr_mode":"primary"}`,
+	}
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.VERIFY, report.Success.LastPhase)
+	suite.Equal(expectedDiff, report.Success.Diff)
+}
+
+func (suite *HanaStartOperatorTestSuite) TestHanaStartCommitErrorRollback() {
+	ctx := context.Background()
+
+	suite.mockSapcontrol.
+		On("GetProcessListContext", ctx, mock.Anything).
+		Return(hanaProcessList(sapcontrolapi.STATECOLOR_GRAY), nil).
+		Once()
+
+	suite.mockCmdExecutor.
+		On("OutputContext", ctx, "/usr/bin/su", "-l", "prdadm", "-c", hanaHdbnsutilCommand).
+		Return([]byte(hanaSRStateNoneOutput), nil).
+		Once()
+
+	suite.mockCmdExecutor.
+		On("OutputContext", ctx, "/usr/sbin/cibadmin", "--query", "--local").
+		Return(nil, cibadminNotInstalledError()).
+		Once()
+
+	startCall := suite.mockSapcontrol.
+		On("StartContext", ctx, mock.Anything).
+		Return(nil, errors.New("error starting")).
+		Once()
+
+	stopCall := suite.mockSapcontrol.
+		On("StopContext", ctx, mock.Anything).
+		Return(nil, nil).
+		Once().
+		NotBefore(startCall)
+
+	suite.mockSapcontrol.
+		On("GetProcessListContext", mock.Anything, mock.Anything).
+		Return(hanaProcessList(sapcontrolapi.STATECOLOR_GRAY), nil).
+		Once().
+		NotBefore(stopCall)
+
+	report := operator.NewHanaStart(
+		operator.Arguments{"sid": "PRD", "instance_number": "00"},
+		"test-op",
+		operator.Options[operator.HanaStart]{
+			OperatorOptions: []operator.Option[operator.HanaStart]{
+				operator.Option[operator.HanaStart](operator.WithCustomHanaStartSapcontrol(suite.mockSapcontrol)),
+				operator.Option[operator.HanaStart](operator.WithCustomHanaStartExecutor(suite.mockCmdExecutor)),
+				operator.Option[operator.HanaStart](operator.WithCustomHanaStartHostname("vmhana01")),
+				operator.Option[operator.HanaStart](operator.WithCustomHanaStartInterval(0 * time.Second)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.COMMIT, report.Error.ErrorPhase)
+	suite.Equal("commit: error starting HANA instance: error starting", report.Error.Message)
+}
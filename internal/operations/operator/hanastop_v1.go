@@ -0,0 +1,284 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/trento-project/agent/v3/internal/core/sapsystem/sapcontrolapi"
+	"github.com/trento-project/agent/v3/pkg/utils"
+)
+
+const (
+	HanaStopOperatorName = "hanastop"
+)
+
+type hanaStopDiffOutput struct {
+	Stopped bool   `json:"stopped"`
+	SRMode  string `json:"sr_mode,omitempty"`
+}
+
+type HanaStopOption Option[HanaStop]
+
+type HanaStop struct {
+	baseOperator
+
+	parsedArguments     *hanaStateChangeArguments
+	allowSRPrimary      bool
+	sapControlConnector sapcontrolapi.WebService
+	executor            utils.CommandExecutor
+	hostname            string
+	interval            time.Duration
+	srMode              string
+}
+
+func WithCustomHanaStopSapcontrol(sapControlConnector sapcontrolapi.WebService) HanaStopOption {
+	return func(o *HanaStop) {
+		o.sapControlConnector = sapControlConnector
+	}
+}
+
+func WithCustomHanaStopExecutor(executor utils.CommandExecutor) HanaStopOption {
+	return func(o *HanaStop) {
+		o.executor = executor
+	}
+}
+
+func WithCustomHanaStopHostname(hostname string) HanaStopOption {
+	return func(o *HanaStop) {
+		o.hostname = hostname
+	}
+}
+
+func WithCustomHanaStopInterval(interval time.Duration) HanaStopOption {
+	return func(o *HanaStop) {
+		o.interval = interval
+	}
+}
+
+// NewHanaStop operator stops a SAP HANA database instance.
+//
+// Arguments:
+//  sid (required): String with the SID of the HANA database, e.g. PRD
+//  instance_number (required): String with the two digits instance number of the HANA instance to stop
+//  timeout: Timeout in seconds to wait until all the hdb* processes are stopped
+//  allow_sr_primary: Boolean to allow stopping a system replication primary instance, false by default
+//
+// # Execution Phases
+//
+// - PLAN:
+//   The operator gets the current state of the hdb* processes and stores it.
+//   The operation is skipped if the HANA instance is already stopped.
+//   The system replication mode of the instance is stored as well.
+//   The operation fails if the instance is controlled by a managed SAPHana/SAPHanaController
+//   cluster resource, as stopping it outside of cluster maintenance triggers a failover.
+//   The resource is considered unmanaged if the cluster, the resource or the node
+//   are in maintenance, or if pacemaker is not installed or not running.
+//   Stopping a system replication primary stops the replication to its secondaries,
+//   so it fails unless allow_sr_primary is set.
+//
+// - COMMIT:
+//   It stops the HANA instance using the sapcontrol Stop command.
+//
+// - VERIFY:
+//   Verify if all the hdb* processes of the instance are stopped.
+//
+// - ROLLBACK:
+//   If an error occurs during the COMMIT or VERIFY phase, the instance is started back again.
+
+func NewHanaStop(
+	arguments Arguments,
+	operationID string,
+	options Options[HanaStop],
+) *Executor {
+	hanaStop := &HanaStop{
+		baseOperator: newBaseOperator(
+			HanaStopOperatorName, operationID, arguments, options.BaseOperatorOptions...,
+		),
+		executor: utils.Executor{},
+		interval: defaultSapInstanceStateInterval,
+	}
+
+	hanaStop.hostname, _ = os.Hostname()
+
+	for _, opt := range options.OperatorOptions {
+		opt(hanaStop)
+	}
+
+	return &Executor{
+		phaser:      hanaStop,
+		operationID: operationID,
+		logger:      hanaStop.logger,
+	}
+}
+
+func (h *HanaStop) setup(_ context.Context) error {
+	opArguments, err := parseHanaStateChangeArguments(h.arguments)
+	if err != nil {
+		return err
+	}
+
+	h.parsedArguments = opArguments
+
+	h.allowSRPrimary, err = parseOptionalBoolArgument(h.arguments, "allow_sr_primary")
+	if err != nil {
+		return err
+	}
+
+	// Use custom sapControlConnector or create a new one based on the instance_number argument
+	if h.sapControlConnector == nil {
+		h.sapControlConnector = sapcontrolapi.NewWebServiceUnix(h.parsedArguments.instNumber)
+	}
+
+	return nil
+}
+
+func (h *HanaStop) plan(ctx context.Context) (bool, error) {
+	err := h.setup(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	stopped, err := allHanaProcessesInState(ctx, h.sapControlConnector, sapcontrolapi.STATECOLOR_GRAY)
+	if err != nil {
+		return false, fmt.Errorf("error checking processes state: %w", err)
+	}
+
+	h.resources[beforeDiffField] = stopped
+
+	if stopped {
+		h.logger.Info("HANA instance already stopped, skipping operation")
+		h.resources[afterDiffField] = stopped
+
+		return true, nil
+	}
+
+	h.srMode, err = getHanaSRMode(ctx, h.executor, h.parsedArguments)
+	if err != nil {
+		return false, err
+	}
+
+	resourceID, managed, err := getHanaClusterResourceManaged(
+		ctx, h.executor, h.parsedArguments, h.hostname,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	if managed {
+		return false, fmt.Errorf(
+			"HANA instance %s is managed by the cluster resource %s, "+
+				"set the cluster, the resource or the node in maintenance before stopping it",
+			h.parsedArguments.sid, resourceID,
+		)
+	}
+
+	if h.srMode == hanaSRModePrimary && !h.allowSRPrimary {
+		return false, fmt.Errorf(
+			"HANA instance %s is a system replication primary, set allow_sr_primary to stop it",
+			h.parsedArguments.sid,
+		)
+	}
+
+	if h.srMode == hanaSRModePrimary {
+		h.logger.Warn("stopping a system replication primary HANA instance",
+			"sid", h.parsedArguments.sid)
+	}
+
+	return false, nil
+}
+
+func (h *HanaStop) commit(ctx context.Context) error {
+	request := new(sapcontrolapi.Stop)
+
+	_, err := h.sapControlConnector.StopContext(ctx, request)
+	if err != nil {
+		return fmt.Errorf("error stopping HANA instance: %w", err)
+	}
+
+	return nil
+}
+
+func (h *HanaStop) verify(ctx context.Context) error {
+	err := waitUntilHanaInstanceState(
+		ctx,
+		h.sapControlConnector,
+		sapcontrolapi.STATECOLOR_GRAY,
+		h.parsedArguments.timeout,
+		h.interval,
+	)
+	if err != nil {
+		return err
+	}
+
+	h.resources[afterDiffField] = true
+
+	return nil
+}
+
+func (h *HanaStop) rollback(ctx context.Context) error {
+	request := new(sapcontrolapi.Start)
+
+	_, err := h.sapControlConnector.StartContext(ctx, request)
+	if err != nil {
+		return fmt.Errorf("error starting HANA instance: %w", err)
+	}
+
+	return waitUntilHanaInstanceState(
+		ctx,
+		h.sapControlConnector,
+		sapcontrolapi.STATECOLOR_GREEN,
+		h.parsedArguments.timeout,
+		h.interval,
+	)
+}
+
+// operationDiff needs to be refactored, ignoring duplication issues for now
+//
+//nolint:dupl
+func (h *HanaStop) operationDiff(_ context.Context) map[string]any {
+	diff := make(map[string]any)
+
+	beforeStopped, ok := h.resources[beforeDiffField].(bool)
+	if !ok {
+		panic(fmt.Sprintf("invalid beforeStopped value: cannot parse '%s' to bool",
+			h.resources[beforeDiffField]))
+	}
+
+	afterStopped, ok := h.resources[afterDiffField].(bool)
+	if !ok {
+		panic(fmt.Sprintf("invalid afterStopped value: cannot parse '%s' to bool",
+			h.resources[afterDiffField]))
+	}
+
+	beforeDiffOutput := hanaStopDiffOutput{
+		Stopped: beforeStopped,
+		SRMode:  h.srMode,
+	}
+
+	before, err := json.Marshal(beforeDiffOutput)
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling before diff output: %v", err))
+	}
+
+	diff[beforeDiffField] = string(before)
+
+	afterDiffOutput := hanaStopDiffOutput{
+		Stopped: afterStopped,
+		SRMode:  h.srMode,
+	}
+
+	after, err := json.Marshal(afterDiffOutput)
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling after diff output: %v", err))
+	}
+
+	diff[afterDiffField] = string(after)
+
+	return diff
+}
@@ -0,0 +1,622 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/trento-project/agent/v3/internal/core/sapsystem/sapcontrolapi"
+	sapcontrolMocks "github.com/trento-project/agent/v3/internal/core/sapsystem/sapcontrolapi/mocks"
+	"github.com/trento-project/agent/v3/internal/operations/operator"
+	"github.com/trento-project/agent/v3/pkg/utils/mocks"
+)
+
+const hanaSRStatePrimaryOutput = `SAPCONTROL-OK: <begin>
+mode=primary
+site id=1
+site name=NUREMBERG
+SAPCONTROL-OK: <end>
+`
+
+const hanaSRStateNoneOutput = `SAPCONTROL-OK: <begin>
+mode=none
+SAPCONTROL-OK: <end>
+`
+
+const hanaCibOutput = `<cib crm_feature_set="3.19.0">
+  <configuration>
+    <resources>
+      <clone id="msl_SAPHana_PRD_HDB00">
+        <primitive id="rsc_SAPHana_PRD_HDB00" class="ocf" provider="suse" type="SAPHana">
+          <instance_attributes id="rsc_sap_PRD_HDB00-instance_attributes">
+            <nvpair name="SID" value="PRD" id="rsc_sap_PRD_HDB00-instance_attributes-SID"/>
+            <nvpair name="InstanceNumber" value="00" id="rsc_sap_PRD_HDB00-instance_attributes-InstanceNumber"/>
+          </instance_attributes>
+        </primitive>
+      </clone>
+    </resources>
+  </configuration>
+</cib>`
+
+const hanaCrmMonManagedOutput = `<pacemaker-result api-version="2.30" request="crm_mon -X --inactive">
+  <nodes>
+    <node name="vmhana01" id="1" online="true" maintenance="false"/>
+    <node name="vmhana02" id="2" online="true" maintenance="false"/>
+  </nodes>
+  <resources>
+    <clone id="msl_SAPHana_PRD_HDB00" multi_state="true" managed="true">
+      <resource id="rsc_SAPHana_PRD_HDB00" resource_agent="ocf:suse:SAPHana" role="Promoted" managed="true">
+        <node name="vmhana01" id="1" cached="true"/>
+      </resource>
+      <resource id="rsc_SAPHana_PRD_HDB00" resource_agent="ocf:suse:SAPHana" role="Unpromoted" managed="true">
+        <node name="vmhana02" id="2" cached="true"/>
+      </resource>
+    </clone>
+  </resources>
+</pacemaker-result>`
+
+const hanaCrmMonMaintenanceOutput = `<pacemaker-result api-version="2.30" request="crm_mon -X --inactive">
+  <nodes>
+    <node name="vmhana01" id="1" online="true" maintenance="false"/>
+    <node name="vmhana02" id="2" online="true" maintenance="false"/>
+  </nodes>
+  <resources>
+    <clone id="msl_SAPHana_PRD_HDB00" multi_state="true" managed="false">
+      <resource id="rsc_SAPHana_PRD_HDB00" resource_agent="ocf:suse:SAPHana" role="Promoted" managed="false">
+        <node name="vmhana01" id="1" cached="true"/>
+      </resource>
+      <resource id="rsc_SAPHana_PRD_HDB00" resource_agent="ocf:suse:SAPHana" role="Unpromoted" managed="false">
+        <node name="vmhana02" id="2" cached="true"/>
+      </resource>
+    </clone>
+  </resources>
+</pacemaker-result>`
+
+const hanaCrmMonNodeMaintenanceOutput = `<pacemaker-result api-version="2.30" request="crm_mon -X --inactive">
+  <nodes>
+    <node name="vmhana01" id="1" online="true" maintenance="true"/>
+    <node name="vmhana02" id="2" online="true" maintenance="false"/>
+  </nodes>
+  <resources>
+    <clone id="msl_SAPHana_PRD_HDB00" multi_state="true" managed="true">
+      <resource id="rsc_SAPHana_PRD_HDB00" resource_agent="ocf:suse:SAPHana" role="Promoted" managed="false">
+        <node name="vmhana01" id="1" cached="true"/>
+      </resource>
+      <resource id="rsc_SAPHana_PRD_HDB00" resource_agent="ocf:suse:SAPHana" role="Unpromoted" managed="true">
+        <node name="vmhana02" id="2" cached="true"/>
+      </resource>
+    </clone>
+  </resources>
+</pacemaker-result>`
+
+const hanaHdbnsutilCommand = "hdbnsutil -sr_stateConfiguration -sapcontrol=1"
+
+func cibadminNotInstalledError() error {
+	return &fs.PathError{Op: "fork/exec", Path: "/usr/sbin/cibadmin", Err: syscall.ENOENT}
+}
+
+func hanaProcessList(state sapcontrolapi.STATECOLOR) *sapcontrolapi.GetProcessListResponse {
+	return &sapcontrolapi.GetProcessListResponse{
+		Processes: []*sapcontrolapi.OSProcess{
+			{
+				Name:       "hdbdaemon",
+				Dispstatus: state,
+			},
+			{
+				Name:       "hdbnameserver",
+				Dispstatus: state,
+			},
+			{
+				Name:       "esserver",
+				Dispstatus: sapcontrolapi.STATECOLOR_YELLOW,
+			},
+		},
+	}
+}
+
+type HanaStopOperatorTestSuite struct {
+	suite.Suite
+
+	mockSapcontrol  *sapcontrolMocks.MockWebService
+	mockCmdExecutor *mocks.MockCommandExecutor
+}
+
+func TestHanaStopOperator(t *testing.T) {
+	suite.Run(t, new(HanaStopOperatorTestSuite))
+}
+
+func (suite *HanaStopOperatorTestSuite) SetupTest() {
+	suite.mockSapcontrol = sapcontrolMocks.NewMockWebService(suite.T())
+	suite.mockCmdExecutor = mocks.NewMockCommandExecutor(suite.T())
+}
+
+func (suite *HanaStopOperatorTestSuite) TestHanaStopPlanErrorParsingArguments() {
+	cases := []struct {
+		arguments    operator.Arguments
+		errorMessage string
+	}{
+		{
+			arguments:    operator.Arguments{"instance_number": "00"},
+			errorMessage: "plan: argument sid not provided, could not use the operator",
+		},
+		{
+			arguments:    operator.Arguments{"sid": 1, "instance_number": "00"},
+			errorMessage: "plan: could not parse sid argument as string, argument provided: 1",
+		},
+		{
+			arguments:    operator.Arguments{"sid": "", "instance_number": "00"},
+			errorMessage: "plan: sid argument is empty",
+		},
+		{
+			arguments: operator.Arguments{"sid": "PRD0", "instance_number": "00"},
+			errorMessage: "plan: sid argument must be 3 uppercase alphanumeric characters starting with a letter, " +
+				"argument provided: PRD0",
+		},
+		{
+			arguments:    operator.Arguments{"sid": "PRD"},
+			errorMessage: "plan: argument instance_number not provided, could not use the operator",
+		},
+		{
+			arguments:    operator.Arguments{"sid": "PRD", "instance_number": "0"},
+			errorMessage: "plan: instance_number argument must be two digits, argument provided: 0",
+		},
+		{
+			arguments:    operator.Arguments{"sid": "PRD", "instance_number": "00", "allow_sr_primary": "yes"},
+			errorMessage: "plan: could not parse allow_sr_primary argument as bool, argument provided: yes",
+		},
+	}
+
+	for _, tc := range cases {
+		report := operator.NewHanaStop(
+			tc.arguments,
+			"test-op",
+			operator.Options[operator.HanaStop]{
+				OperatorOptions: []operator.Option[operator.HanaStop]{
+					operator.Option[operator.HanaStop](operator.WithCustomHanaStopSapcontrol(suite.mockSapcontrol)),
+					operator.Option[operator.HanaStop](operator.WithCustomHanaStopExecutor(suite.mockCmdExecutor)),
+					operator.Option[operator.HanaStop](operator.WithCustomHanaStopHostname("vmhana01")),
+					operator.Option[operator.HanaStop](operator.WithCustomHanaStopInterval(0 * time.Second)),
+				},
+			},
+		).Run(context.Background())
+
+		suite.Nil(report.Success)
+		suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+		suite.Equal(tc.errorMessage, report.Error.Message)
+	}
+}
+
+func (suite *HanaStopOperatorTestSuite) TestHanaStopPlanErrorSRState() {
+	ctx := context.Background()
+
+	suite.mockSapcontrol.
+		On("GetProcessListContext", ctx, mock.Anything).
+		Return(hanaProcessList(sapcontrolapi.STATECOLOR_GREEN), nil).
+		Once()
+
+	suite.mockCmdExecutor.
+		On("OutputContext", ctx, "/usr/bin/su", "-l", "prdadm", "-c", hanaHdbnsutilCommand).
+		Return(nil, errors.New("exit status 1")).
+		Once()
+
+	report := operator.NewHanaStop(
+		operator.Arguments{"sid": "PRD", "instance_number": "00"},
+		"test-op",
+		operator.Options[operator.HanaStop]{
+			OperatorOptions: []operator.Option[operator.HanaStop]{
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopSapcontrol(suite.mockSapcontrol)),
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopExecutor(suite.mockCmdExecutor)),
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopHostname("vmhana01")),
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopInterval(0 * time.Second)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal("plan: error getting system replication state: exit status 1", report.Error.Message)
+}
+
+func (suite *HanaStopOperatorTestSuite) TestHanaStopAlreadyStopped() {
+	ctx := context.Background()
+
+	suite.mockSapcontrol.
+		On("GetProcessListContext", ctx, mock.Anything).
+		Return(hanaProcessList(sapcontrolapi.STATECOLOR_GRAY), nil).
+		Once()
+
+	report := operator.NewHanaStop(
+		operator.Arguments{"sid": "PRD", "instance_number": "00"},
+		"test-op",
+		operator.Options[operator.HanaStop]{
+			OperatorOptions: []operator.Option[operator.HanaStop]{
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopSapcontrol(suite.mockSapcontrol)),
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopExecutor(suite.mockCmdExecutor)),
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopHostname("vmhana01")),
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopInterval(0 * time.Second)),
+			},
+		},
+	).Run(ctx)
+
+	expectedDiff := map[string]any{
+		"before": `{"stopped":true}`,
+		"after":  `{"stopped":true}`,
+	}
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.PLAN, report.Success.LastPhase)
+	suite.Equal(expectedDiff, report.Success.Diff)
+}
+
+func (suite *HanaStopOperatorTestSuite) TestHanaStopPlanErrorClusterManaged() {
+	ctx := context.Background()
+
+	suite.mockSapcontrol.
+		On("GetProcessListContext", ctx, mock.Anything).
+		Return(hanaProcessList(sapcontrolapi.STATECOLOR_GREEN), nil).
+		Once()
+
+	suite.mockCmdExecutor.
+		On("OutputContext", ctx, "/usr/bin/su", "-l", "prdadm", "-c", hanaHdbnsutilCommand).
+		Return([]byte(hanaSRStatePrimaryOutput), nil).
+		Once()
+
+	suite.mockCmdExecutor.
+		On("OutputContext", ctx, "/usr/sbin/cibadmin", "--query", "--local").
+		Return([]byte(hanaCibOutput), nil).
+		Once()
+
+	suite.mockCmdExecutor.
+		On("OutputContext", ctx, "/usr/sbin/crm_mon", "-X", "--inactive").
+		Return([]byte(hanaCrmMonManagedOutput), nil).
+		Once()
+
+	report := operator.NewHanaStop(
+		operator.Arguments{"sid": "PRD", "instance_number": "00"},
+		"test-op",
+		operator.Options[operator.HanaStop]{
+			OperatorOptions: []operator.Option[operator.HanaStop]{
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopSapcontrol(suite.mockSapcontrol)),
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopExecutor(suite.mockCmdExecutor)),
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopHostname("vmhana01")),
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopInterval(0 * time.Second)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal(
+		"plan: HANA instance PRD is managed by the cluster resource msl_SAPHana_PRD_HDB00, "+
+			"set the cluster, the resource or the node in maintenance before stopping it",
+		report.Error.Message,
+	)
+}
+
+func (suite *HanaStopOperatorTestSuite) TestHanaStopPlanErrorClusterStatus() {
+	ctx := context.Background()
+
+	suite.mockSapcontrol.
+		On("GetProcessListContext", ctx, mock.Anything).
+		Return(hanaProcessList(sapcontrolapi.STATECOLOR_GREEN), nil).
+		Once()
+
+	suite.mockCmdExecutor.
+		On("OutputContext", ctx, "/usr/bin/su", "-l", "prdadm", "-c", hanaHdbnsutilCommand).
+		Return([]byte(hanaSRStatePrimaryOutput), nil).
+		Once()
+
+	suite.mockCmdExecutor.
+		On("OutputContext", ctx, "/usr/sbin/cibadmin", "--query", "--local").
+		Return([]byte(hanaCibOutput), nil).
+		Once()
+
+	suite.mockCmdExecutor.
+		On("OutputContext", ctx, "/usr/sbin/crm_mon", "-X", "--inactive").
+		Return(nil, errors.New("exit status 102")).
+		Once()
+
+	report := operator.NewHanaStop(
+		operator.Arguments{"sid": "PRD", "instance_number": "00"},
+		"test-op",
+		operator.Options[operator.HanaStop]{
+			OperatorOptions: []operator.Option[operator.HanaStop]{
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopSapcontrol(suite.mockSapcontrol)),
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopExecutor(suite.mockCmdExecutor)),
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopHostname("vmhana01")),
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopInterval(0 * time.Second)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal("plan: error getting cluster status: exit status 102", report.Error.Message)
+}
+
+func (suite *HanaStopOperatorTestSuite) TestHanaStopSuccessInMaintenance() {
+	cases := []struct {
+		crmMonOutput string
+	}{
+		{
+			crmMonOutput: hanaCrmMonMaintenanceOutput,
+		},
+		{
+			crmMonOutput: hanaCrmMonNodeMaintenanceOutput,
+		},
+	}
+
+	for _, tc := range cases {
+		suite.SetupTest()
+
+		ctx := context.Background()
+
+		planProcessesCall := suite.mockSapcontrol.
+			On("GetProcessListContext", ctx, mock.Anything).
+			Return(hanaProcessList(sapcontrolapi.STATECOLOR_GREEN), nil).
+			Once()
+
+		suite.mockCmdExecutor.
+			On("OutputContext", ctx, "/usr/bin/su", "-l", "prdadm", "-c", hanaHdbnsutilCommand).
+			Return([]byte(hanaSRStatePrimaryOutput), nil).
+			Once()
+
+		suite.mockCmdExecutor.
+			On("OutputContext", ctx, "/usr/sbin/cibadmin", "--query", "--local").
+			Return([]byte(hanaCibOutput), nil).
+			Once()
+
+		suite.mockCmdExecutor.
+			On("OutputContext", ctx, "/usr/sbin/crm_mon", "-X", "--inactive").
+			Return([]byte(tc.crmMonOutput), nil).
+			Once()
+
+		stopCall := suite.mockSapcontrol.
+			On("StopContext", ctx, mock.Anything).
+			Return(nil, nil).
+			Once().
+			NotBefore(planProcessesCall)
+
+		suite.mockSapcontrol.
+			On("GetProcessListContext", mock.Anything, mock.Anything).
+			Return(hanaProcessList(sapcontrolapi.STATECOLOR_GRAY), nil).
+			Once().
+			NotBefore(stopCall)
+
+		report := operator.NewHanaStop(
+			operator.Arguments{"sid": "PRD", "instance_number": "00", "allow_sr_primary": true},
+			"test-op",
+			operator.Options[operator.HanaStop]{
+				OperatorOptions: []operator.Option[operator.HanaStop]{
+					operator.Option[operator.HanaStop](operator.WithCustomHanaStopSapcontrol(suite.mockSapcontrol)),
+					operator.Option[operator.HanaStop](operator.WithCustomHanaStopExecutor(suite.mockCmdExecutor)),
+					operator.Option[operator.HanaStop](operator.WithCustomHanaStopHostname("vmhana01")),
+					operator.Option[operator.HanaStop](operator.WithCustomHanaStopInterval(0 * time.Second)),
+				},
+			},
+		).Run(ctx)
+
+		expectedDiff := map[string]any{
+			"before": `{"stopped":false,"sr_mode":"primary"}`,
+			"after":  `{"stopped":true,"sr_mode":"primary"}`,
+		}
+
+		suite.Nil(report.Error)
+		suite.Equal(operator.VERIFY, report.Success.LastPhase)
+		suite.Equal(expectedDiff, report.Success.Diff)
+	}
+}
+
+func (suite *HanaStopOperatorTestSuite) TestHanaStopPlanErrorSRPrimary() {
+	ctx := context.Background()
+
+	suite.mockSapcontrol.
+		On("GetProcessListContext", ctx, mock.Anything).
+		Return(hanaProcessList(sapcontrolapi.STATECOLOR_GREEN), nil).
+		Once()
+
+	suite.mockCmdExecutor.
+		On("OutputContext", ctx, "/usr/bin/su", "-l", "prdadm", "-c", hanaHdbnsutilCommand).
+		Return([]byte(hanaSRStatePrimaryOutput), nil).
+		Once()
+
+	suite.mockCmdExecutor.
+		On("OutputContext", ctx, "/usr/sbin/cibadmin", "--query", "--local").
+		Return(nil, cibadminNotInstalledError()).
+		Once()
+
+	report := operator.NewHanaStop(
+		operator.Arguments{"sid": "PRD", "instance_number": "00"},
+		"test-op",
+		operator.Options[operator.HanaStop]{
+			OperatorOptions: []operator.Option[operator.HanaStop]{
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopSapcontrol(suite.mockSapcontrol)),
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopExecutor(suite.mockCmdExecutor)),
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopHostname("vmhana01")),
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopInterval(0 * time.Second)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal(
+		"plan: HANA instance PRD is a system replication primary, set allow_sr_primary to stop it",
+		report.Error.Message,
+	)
+	suite.mockSapcontrol.AssertNotCalled(suite.T(), "StopContext", mock.Anything, mock.Anything)
+}
+
+func (suite *HanaStopOperatorTestSuite) TestHanaStopPlanErrorClusterInformationBase() {
+	ctx := context.Background()
+
+	suite.mockSapcontrol.
+		On("GetProcessListContext", ctx, mock.Anything).
+		Return(hanaProcessList(sapcontrolapi.STATECOLOR_GREEN), nil).
+		Once()
+
+	suite.mockCmdExecutor.
+		On("OutputContext", ctx, "/usr/bin/su", "-l", "prdadm", "-c", hanaHdbnsutilCommand).
+		Return([]byte(hanaSRStateNoneOutput), nil).
+		Once()
+
+	suite.mockCmdExecutor.
+		On("OutputContext", ctx, "/usr/sbin/cibadmin", "--query", "--local").
+		Return(nil, errors.New("signal: killed")).
+		Once()
+
+	report := operator.NewHanaStop(
+		operator.Arguments{"sid": "PRD", "instance_number": "00"},
+		"test-op",
+		operator.Options[operator.HanaStop]{
+			OperatorOptions: []operator.Option[operator.HanaStop]{
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopSapcontrol(suite.mockSapcontrol)),
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopExecutor(suite.mockCmdExecutor)),
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopHostname("vmhana01")),
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopInterval(0 * time.Second)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal("plan: error getting cluster information base: signal: killed", report.Error.Message)
+}
+
+func (suite *HanaStopOperatorTestSuite) TestHanaStopSuccessWithoutCluster() {
+	cases := []struct {
+		cibadminError error
+	}{
+		{
+			cibadminError: cibadminNotInstalledError(),
+		},
+		{
+			cibadminError: commandExitError(102),
+		},
+	}
+
+	for _, tc := range cases {
+		suite.SetupTest()
+
+		ctx := context.Background()
+
+		planProcessesCall := suite.mockSapcontrol.
+			On("GetProcessListContext", ctx, mock.Anything).
+			Return(hanaProcessList(sapcontrolapi.STATECOLOR_GREEN), nil).
+			Once()
+
+		suite.mockCmdExecutor.
+			On("OutputContext", ctx, "/usr/bin/su", "-l", "prdadm", "-c", hanaHdbnsutilCommand).
+			Return([]byte(hanaSRStateNoneOutput), nil).
+			Once()
+
+		suite.mockCmdExecutor.
+			On("OutputContext", ctx, "/usr/sbin/cibadmin", "--query", "--local").
+			Return(nil, tc.cibadminError).
+			Once()
+
+		stopCall := suite.mockSapcontrol.
+			On("StopContext", ctx, mock.Anything).
+			Return(nil, nil).
+			Once().
+			NotBefore(planProcessesCall)
+
+		suite.mockSapcontrol.
+			On("GetProcessListContext", mock.Anything, mock.Anything).
+			Return(hanaProcessList(sapcontrolapi.STATECOLOR_GRAY), nil).
+			Once().
+			NotBefore(stopCall)
+
+		report := operator.NewHanaStop(
+			operator.Arguments{"sid": "PRD", "instance_number": "00"},
+			"test-op",
+			operator.Options[operator.HanaStop]{
+				OperatorOptions: []operator.Option[operator.HanaStop]{
+					operator.Option[operator.HanaStop](operator.WithCustomHanaStopSapcontrol(suite.mockSapcontrol)),
+					operator.Option[operator.HanaStop](operator.WithCustomHanaStopExecutor(suite.mockCmdExecutor)),
+					operator.Option[operator.HanaStop](operator.WithCustomHanaStopHostname("vmhana01")),
+					operator.Option[operator.HanaStop](operator.WithCustomHanaStopInterval(0 * time.Second)),
+				},
+			},
+		).Run(ctx)
+
+		expectedDiff := map[string]any{
+			"before": `{"stopped":false,"sr_mode":"none"}`,
+			"after":  `{"stopped":true,"sr_mode":"none"}`,
+		}
+
+		suite.Nil(report.Error)
+		suite.Equal(operator.VERIFY, report.Success.LastPhase)
+		suite.Equal(expectedDiff, report.Success.Diff)
+	}
+}
+
+func (suite *HanaStopOperatorTestSuite) TestHanaStopVerifyErrorRollback() {
+	ctx := context.Background()
+
+	suite.mockSapcontrol.
+		On("GetProcessListContext", ctx, mock.Anything).
+		Return(hanaProcessList(sapcontrolapi.STATECOLOR_GREEN), nil).
+		Once()
+
+	suite.mockCmdExecutor.
+		On("OutputContext", ctx, "/usr/bin/su", "-l", "prdadm", "-c", hanaHdbnsutilCommand).
+		Return([]byte(hanaSRStateNoneOutput), nil).
+		Once()
+
+	suite.mockCmdExecutor.
+		On("OutputContext", ctx, "/usr/sbin/cibadmin", "--query", "--local").
+		Return(nil, cibadminNotInstalledError()).
+		Once()
+
+	stopCall := suite.mockSapcontrol.
+		On("StopContext", ctx, mock.Anything).
+		Return(nil, nil).
+		Once()
+
+	verifyCall := suite.mockSapcontrol.
+		On("GetProcessListContext", mock.Anything, mock.Anything).
+		Return(nil, errors.New("error getting processes")).
+		Once().
+		NotBefore(stopCall)
+
+	startCall := suite.mockSapcontrol.
+		On("StartContext", ctx, mock.Anything).
+		Return(nil, nil).
+		Once().
+		NotBefore(verifyCall)
+
+	suite.mockSapcontrol.
+		On("GetProcessListContext", mock.Anything, mock.Anything).
+		Return(hanaProcessList(sapcontrolapi.STATECOLOR_GREEN), nil).
+		Once().
+		NotBefore(startCall)
+
+	report := operator.NewHanaStop(
+		operator.Arguments{"sid": "PRD", "instance_number": "00"},
+		"test-op",
+		operator.Options[operator.HanaStop]{
+			OperatorOptions: []operator.Option[operator.HanaStop]{
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopSapcontrol(suite.mockSapcontrol)),
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopExecutor(suite.mockCmdExecutor)),
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopHostname("vmhana01")),
+				operator.Option[operator.HanaStop](operator.WithCustomHanaStopInterval(0 * time.Second)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.VERIFY, report.Error.ErrorPhase)
+	suite.Equal(
+		"verify: error getting instance process list: error getting processes",
+		report.Error.Message,
+	)
+}
@@ -0,0 +1,529 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/trento-project/agent/v3/internal/factsengine/gatherers"
+)
+
+const (
+	HostsEntryChangeOperatorName = "hostsentrychange"
+	hostsFilePath                = "/etc/hosts"
+	hostsManagedBlockBegin       = "# BEGIN Trento managed block"
+	hostsManagedBlockEnd         = "# END Trento managed block"
+	hostsEntryStatePresent       = "present"
+	hostsEntryStateAbsent        = "absent"
+)
+
+type HostsEntryChangeOption Option[HostsEntryChange]
+
+type hostsEntry struct {
+	IP        string   `json:"ip"`
+	Hostnames []string `json:"hostnames"`
+}
+
+type hostsEntryArgument struct {
+	hostsEntry
+	state string
+}
+
+type hostsEntryChangeArguments struct {
+	entries []hostsEntryArgument
+}
+
+type hostsEntryDiffOutput struct {
+	Entries []hostsEntry `json:"entries"`
+}
+
+// hostsFile is a parsed /etc/hosts file. The lines of the Trento managed block are kept
+// apart, so the rest of the file is written back untouched.
+type hostsFile struct {
+	head            []string
+	tail            []string
+	unmanaged       []hostsEntry
+	managed         []hostsEntry
+	hasBlock        bool
+	trailingNewLine bool
+}
+
+// HostsEntryChange is an operator responsible for adding, updating or removing entries
+// in /etc/hosts, typically used for the virtual hostnames of SAP instances.
+// The entries are written inside a delimited block managed by Trento, and the entries
+// outside of the block are never changed.
+//
+// The operator accepts the next arguments:
+// - entries (list): The entries to change. Each entry is a map with the next fields:
+//   - hostnames (list): The hostnames of the entry. The first one identifies the entry
+//                       in the managed block. Whitespace, control characters and # are refused.
+//   - ip (string): The IP address of the entry. Required if the state is present.
+//                  It is written to the hosts file in its canonical form.
+//   - state (string): present or absent. Defaults to present.
+//
+// Example: {"entries": [{"ip": "10.80.1.25", "hostnames": ["sapha1as", "sapha1as.example.com"]}]}
+//
+// # Execution Phases
+//
+// - PLAN:
+//   The hosts file is parsed and the current managed entries are stored as the "before" diff.
+//   The operation fails if any of the hostnames to add is already defined outside of the managed
+//   block with a different IP address, as the resolution would depend on the order of the entries.
+//   If the managed block already has the requested entries, the operation is skipped.
+//   Otherwise, the hosts file is backed up next to the original file. The backup is removed
+//   once the changes are verified or rolled back.
+//
+// - COMMIT:
+//   The managed block is updated with the requested entries. The block is appended to the file
+//   if it doesn't exist, and removed if it becomes empty.
+//
+// - VERIFY:
+//   The hosts file is parsed again and the managed entries are compared with the expected ones.
+//   The hostnames of the present entries are resolved using the same resolver as the
+//   sapinstance_hostname_resolver gatherer, and they must resolve to the requested IP address.
+//
+// - ROLLBACK:
+//   The backed up hosts file is restored.
+
+type HostsEntryChange struct {
+	baseOperator
+
+	resolver   gatherers.HostnameResolver
+	hostsPath  string
+	backupPath string
+	// keepBackup is set while the changes are neither verified nor rolled back
+	keepBackup      bool
+	parsedArguments *hostsEntryChangeArguments
+	expectedEntries []hostsEntry
+}
+
+func WithCustomHostsEntryResolver(resolver gatherers.HostnameResolver) HostsEntryChangeOption {
+	return func(o *HostsEntryChange) {
+		o.resolver = resolver
+	}
+}
+
+func WithCustomHostsFilePath(hostsPath string) HostsEntryChangeOption {
+	return func(o *HostsEntryChange) {
+		o.hostsPath = hostsPath
+	}
+}
+
+func NewHostsEntryChange(
+	arguments Arguments,
+	operationID string,
+	options Options[HostsEntryChange],
+) *Executor {
+	hostsEntryChange := &HostsEntryChange{
+		baseOperator: newBaseOperator(
+			HostsEntryChangeOperatorName, operationID, arguments, options.BaseOperatorOptions...,
+		),
+		resolver:  &gatherers.Resolver{},
+		hostsPath: hostsFilePath,
+	}
+
+	for _, opt := range options.OperatorOptions {
+		opt(hostsEntryChange)
+	}
+
+	hostsEntryChange.backupPath = backupFilePath(hostsEntryChange.hostsPath, operationID)
+
+	return &Executor{
+		phaser:      hostsEntryChange,
+		operationID: operationID,
+		logger:      hostsEntryChange.logger,
+	}
+}
+
+func (h *HostsEntryChange) plan(_ context.Context) (bool, error) {
+	opArguments, err := parseHostsEntryChangeArguments(h.arguments)
+	if err != nil {
+		return false, err
+	}
+
+	h.parsedArguments = opArguments
+
+	hosts, err := loadHostsFile(h.hostsPath)
+	if err != nil {
+		return false, err
+	}
+
+	h.resources[beforeDiffField] = hosts.managed
+
+	err = checkHostsConflicts(hosts, h.parsedArguments.entries)
+	if err != nil {
+		return false, err
+	}
+
+	h.expectedEntries = applyHostsEntries(hosts.managed, h.parsedArguments.entries)
+
+	if slices.EqualFunc(hosts.managed, h.expectedEntries, equalHostsEntries) {
+		h.logger.Info("hosts entries already set, skipping operation")
+		h.resources[afterDiffField] = hosts.managed
+
+		return true, nil
+	}
+
+	err = backupFile(h.hostsPath, h.backupPath)
+	if err != nil {
+		return false, fmt.Errorf("error backing up hosts file: %w", err)
+	}
+
+	h.logger.Info("hosts file backed up", "backup", h.backupPath)
+
+	return false, nil
+}
+
+func (h *HostsEntryChange) commit(_ context.Context) error {
+	h.keepBackup = true
+
+	hosts, err := loadHostsFile(h.hostsPath)
+	if err != nil {
+		return err
+	}
+
+	hosts.managed = h.expectedEntries
+
+	return writeFileAtomically(h.hostsPath, hosts.render())
+}
+
+func (h *HostsEntryChange) verify(_ context.Context) error {
+	hosts, err := loadHostsFile(h.hostsPath)
+	if err != nil {
+		return err
+	}
+
+	if !slices.EqualFunc(hosts.managed, h.expectedEntries, equalHostsEntries) {
+		return errors.New("managed hosts entries don't match the requested entries")
+	}
+
+	for _, entry := range h.parsedArguments.entries {
+		if entry.state != hostsEntryStatePresent {
+			continue
+		}
+
+		for _, hostname := range entry.Hostnames {
+			addresses, err := h.resolver.LookupHost(hostname)
+			if err != nil {
+				return fmt.Errorf("error resolving hostname %s: %w", hostname, err)
+			}
+
+			if !slices.ContainsFunc(addresses, func(address string) bool {
+				return equalIPAddresses(address, entry.IP)
+			}) {
+				return fmt.Errorf(
+					"hostname %s resolves to %s, expected %s",
+					hostname, strings.Join(addresses, ", "), entry.IP,
+				)
+			}
+		}
+	}
+
+	h.resources[afterDiffField] = hosts.managed
+
+	h.keepBackup = false
+
+	return nil
+}
+
+func (h *HostsEntryChange) rollback(_ context.Context) error {
+	err := restoreFile(h.backupPath, h.hostsPath)
+	if err != nil {
+		return fmt.Errorf("error restoring hosts file: %w", err)
+	}
+
+	h.keepBackup = false
+
+	return nil
+}
+
+func (h *HostsEntryChange) after(_ context.Context) {
+	removeBackupFile(h.backupPath, h.keepBackup, h.logger)
+}
+
+func (h *HostsEntryChange) operationDiff(_ context.Context) map[string]any {
+	diff := make(map[string]any)
+
+	beforeEntries, ok := h.resources[beforeDiffField].([]hostsEntry)
+	if !ok {
+		panic(fmt.Sprintf("invalid beforeEntries value: cannot parse '%v' to hosts entries",
+			h.resources[beforeDiffField]))
+	}
+
+	afterEntries, ok := h.resources[afterDiffField].([]hostsEntry)
+	if !ok {
+		panic(fmt.Sprintf("invalid afterEntries value: cannot parse '%v' to hosts entries",
+			h.resources[afterDiffField]))
+	}
+
+	before, err := json.Marshal(hostsEntryDiffOutput{Entries: beforeEntries})
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling before diff output: %v", err))
+	}
+
+	diff[beforeDiffField] = string(before)
+
+	after, err := json.Marshal(hostsEntryDiffOutput{Entries: afterEntries})
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling after diff output: %v", err))
+	}
+
+	diff[afterDiffField] = string(after)
+
+	return diff
+}
+
+// checkHostsConflicts fails if any hostname to add is defined outside of the managed block
+// with a different IP address.
+func checkHostsConflicts(hosts *hostsFile, entries []hostsEntryArgument) error {
+	for _, entry := range entries {
+		if entry.state != hostsEntryStatePresent {
+			continue
+		}
+
+		for _, unmanagedEntry := range hosts.unmanaged {
+			if equalIPAddresses(unmanagedEntry.IP, entry.IP) {
+				continue
+			}
+
+			for _, hostname := range entry.Hostnames {
+				if slices.Contains(unmanagedEntry.Hostnames, hostname) {
+					return fmt.Errorf(
+						"hostname %s is already defined with IP %s outside of the managed block",
+						hostname, unmanagedEntry.IP,
+					)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// applyHostsEntries returns the managed entries after applying the requested changes.
+// Entries are identified by their first hostname. Updated entries keep their position
+// and new entries are appended.
+func applyHostsEntries(managed []hostsEntry, entries []hostsEntryArgument) []hostsEntry {
+	result := slices.Clone(managed)
+
+	for _, entry := range entries {
+		index := slices.IndexFunc(result, func(managedEntry hostsEntry) bool {
+			return managedEntry.Hostnames[0] == entry.Hostnames[0]
+		})
+
+		switch {
+		case entry.state == hostsEntryStateAbsent && index >= 0:
+			result = slices.Delete(result, index, index+1)
+		case entry.state == hostsEntryStatePresent && index >= 0:
+			result[index] = entry.hostsEntry
+		case entry.state == hostsEntryStatePresent:
+			result = append(result, entry.hostsEntry)
+		}
+	}
+
+	return result
+}
+
+func equalHostsEntries(a, b hostsEntry) bool {
+	return equalIPAddresses(a.IP, b.IP) && slices.Equal(a.Hostnames, b.Hostnames)
+}
+
+// equalIPAddresses compares two textual IP addresses, so different notations
+// of the same address, like ::1 and 0:0:0:0:0:0:0:1, are equal.
+func equalIPAddresses(a, b string) bool {
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	if ipA == nil || ipB == nil {
+		return a == b
+	}
+
+	return ipA.Equal(ipB)
+}
+
+func loadHostsFile(path string) (*hostsFile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading hosts file %s: %w", path, err)
+	}
+
+	hosts := &hostsFile{
+		managed:         []hostsEntry{},
+		trailingNewLine: len(content) == 0 || bytes.HasSuffix(content, []byte("\n")),
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if len(content) == 0 {
+		lines = []string{}
+	}
+
+	inBlock := false
+	blockClosed := false
+
+	for _, line := range lines {
+		trimmedLine := strings.TrimSpace(line)
+
+		switch {
+		case trimmedLine == hostsManagedBlockBegin:
+			if hosts.hasBlock {
+				return nil, fmt.Errorf("hosts file %s has more than one managed block", path)
+			}
+
+			hosts.hasBlock = true
+			inBlock = true
+		case trimmedLine == hostsManagedBlockEnd:
+			if !inBlock {
+				return nil, fmt.Errorf("hosts file %s has a managed block end without begin", path)
+			}
+
+			inBlock = false
+			blockClosed = true
+		case inBlock:
+			if entry, ok := parseHostsLine(line); ok {
+				hosts.managed = append(hosts.managed, entry)
+			}
+		default:
+			if entry, ok := parseHostsLine(line); ok {
+				hosts.unmanaged = append(hosts.unmanaged, entry)
+			}
+
+			if blockClosed {
+				hosts.tail = append(hosts.tail, line)
+			} else {
+				hosts.head = append(hosts.head, line)
+			}
+		}
+	}
+
+	if inBlock {
+		return nil, fmt.Errorf("hosts file %s has a managed block without end", path)
+	}
+
+	return hosts, nil
+}
+
+func parseHostsLine(line string) (hostsEntry, bool) {
+	line, _, _ = strings.Cut(line, "#")
+	fields := strings.Fields(line)
+
+	if len(fields) < 2 {
+		return hostsEntry{}, false
+	}
+
+	return hostsEntry{IP: fields[0], Hostnames: fields[1:]}, true
+}
+
+func (h *hostsFile) render() []byte {
+	lines := slices.Clone(h.head)
+
+	if len(h.managed) > 0 {
+		lines = append(lines, hostsManagedBlockBegin)
+		for _, entry := range h.managed {
+			lines = append(lines, entry.IP+"\t"+strings.Join(entry.Hostnames, " "))
+		}
+
+		lines = append(lines, hostsManagedBlockEnd)
+	}
+
+	lines = append(lines, h.tail...)
+
+	content := strings.Join(lines, "\n")
+	if h.trailingNewLine || len(h.managed) > 0 && len(h.tail) == 0 {
+		content += "\n"
+	}
+
+	return []byte(content)
+}
+
+func parseHostsEntryChangeArguments(rawArguments Arguments) (*hostsEntryChangeArguments, error) {
+	entriesArgument, found := rawArguments["entries"]
+	if !found {
+		return nil, errors.New("argument entries not provided, could not use the operator")
+	}
+
+	rawEntries, ok := entriesArgument.([]any)
+	if !ok || len(rawEntries) == 0 {
+		return nil, fmt.Errorf(
+			"could not parse entries argument as a non empty list, argument provided: %v",
+			entriesArgument,
+		)
+	}
+
+	entries := make([]hostsEntryArgument, 0, len(rawEntries))
+
+	for _, rawEntry := range rawEntries {
+		entry, err := parseHostsEntryArgument(rawEntry)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, *entry)
+	}
+
+	return &hostsEntryChangeArguments{
+		entries: entries,
+	}, nil
+}
+
+func parseHostsEntryArgument(rawEntry any) (*hostsEntryArgument, error) {
+	entryMap, ok := rawEntry.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("could not parse entry as a map, entry provided: %v", rawEntry)
+	}
+
+	entry := &hostsEntryArgument{state: hostsEntryStatePresent}
+
+	if rawState, found := entryMap["state"]; found {
+		state, ok := rawState.(string)
+		if !ok || (state != hostsEntryStatePresent && state != hostsEntryStateAbsent) {
+			return nil, fmt.Errorf("invalid entry state %v, allowed values: present, absent", rawState)
+		}
+
+		entry.state = state
+	}
+
+	rawHostnames, ok := entryMap["hostnames"].([]any)
+	if !ok || len(rawHostnames) == 0 {
+		return nil, fmt.Errorf(
+			"could not parse entry hostnames as a non empty list, hostnames provided: %v",
+			entryMap["hostnames"],
+		)
+	}
+
+	for _, rawHostname := range rawHostnames {
+		hostname, ok := rawHostname.(string)
+		if !ok || hostname == "" || strings.ContainsFunc(hostname, invalidHostnameRune) {
+			return nil, fmt.Errorf("invalid hostname %v", rawHostname)
+		}
+
+		entry.Hostnames = append(entry.Hostnames, hostname)
+	}
+
+	if entry.state == hostsEntryStateAbsent {
+		return entry, nil
+	}
+
+	rawIP, _ := entryMap["ip"].(string)
+	ip := net.ParseIP(rawIP)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address %v for hostname %s", entryMap["ip"], entry.Hostnames[0])
+	}
+
+	entry.IP = ip.String()
+
+	return entry, nil
+}
+
+// invalidHostnameRune reports the characters that would break the hosts file
+// line format: whitespace, control characters and the comment character.
+func invalidHostnameRune(r rune) bool {
+	return r == '#' || unicode.IsSpace(r) || unicode.IsControl(r)
+}
@@ -0,0 +1,374 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/trento-project/agent/v3/internal/factsengine/gatherers/mocks"
+	"github.com/trento-project/agent/v3/internal/operations/operator"
+)
+
+const hostsTestFile = `127.0.0.1	localhost
+10.80.1.11	vmhana01 # primary
+10.80.1.12	vmhana02
+`
+
+const hostsWithBlockTestFile = `127.0.0.1	localhost
+# BEGIN Trento managed block
+10.80.1.25	sapha1as sapha1as.example.com
+10.80.1.26	sapha1er
+# END Trento managed block
+10.80.1.11	vmhana01
+`
+
+type HostsEntryChangeOperatorTestSuite struct {
+	suite.Suite
+
+	mockResolver *mocks.MockHostnameResolver
+	hostsPath    string
+	backupPath   string
+}
+
+func TestHostsEntryChangeOperator(t *testing.T) {
+	suite.Run(t, new(HostsEntryChangeOperatorTestSuite))
+}
+
+func (suite *HostsEntryChangeOperatorTestSuite) SetupTest() {
+	suite.mockResolver = mocks.NewMockHostnameResolver(suite.T())
+	suite.hostsPath = path.Join(suite.T().TempDir(), "hosts")
+	suite.backupPath = suite.hostsPath + ".trento-test-op.bak"
+}
+
+func (suite *HostsEntryChangeOperatorTestSuite) writeHostsFile(content string) {
+	err := os.WriteFile(suite.hostsPath, []byte(content), 0o644)
+	suite.Require().NoError(err)
+}
+
+func (suite *HostsEntryChangeOperatorTestSuite) readHostsFile() string {
+	content, err := os.ReadFile(suite.hostsPath)
+	suite.Require().NoError(err)
+
+	return string(content)
+}
+
+func (suite *HostsEntryChangeOperatorTestSuite) TestHostsEntryChangePlanErrorParsingArguments() {
+	suite.writeHostsFile(hostsTestFile)
+
+	cases := []struct {
+		arguments    operator.Arguments
+		errorMessage string
+	}{
+		{
+			arguments:    operator.Arguments{},
+			errorMessage: "plan: argument entries not provided, could not use the operator",
+		},
+		{
+			arguments:    operator.Arguments{"entries": []any{}},
+			errorMessage: "plan: could not parse entries argument as a non empty list, argument provided: []",
+		},
+		{
+			arguments:    operator.Arguments{"entries": []any{"sapha1as"}},
+			errorMessage: "plan: could not parse entry as a map, entry provided: sapha1as",
+		},
+		{
+			arguments: operator.Arguments{"entries": []any{
+				map[string]any{"ip": "10.80.1.25"},
+			}},
+			errorMessage: "plan: could not parse entry hostnames as a non empty list, hostnames provided: <nil>",
+		},
+		{
+			arguments: operator.Arguments{"entries": []any{
+				map[string]any{"ip": "10.80.1.25", "hostnames": []any{"sap ha1as"}},
+			}},
+			errorMessage: "plan: invalid hostname sap ha1as",
+		},
+		{
+			arguments: operator.Arguments{"entries": []any{
+				map[string]any{"ip": "10.80.1.25", "hostnames": []any{"sapha1as\x00"}},
+			}},
+			errorMessage: "plan: invalid hostname sapha1as\x00",
+		},
+		{
+			arguments: operator.Arguments{"entries": []any{
+				map[string]any{"ip": "10.80.1", "hostnames": []any{"sapha1as"}},
+			}},
+			errorMessage: "plan: invalid IP address 10.80.1 for hostname sapha1as",
+		},
+		{
+			arguments: operator.Arguments{"entries": []any{
+				map[string]any{"hostnames": []any{"sapha1as"}, "state": "removed"},
+			}},
+			errorMessage: "plan: invalid entry state removed, allowed values: present, absent",
+		},
+	}
+
+	for _, tc := range cases {
+		report := operator.NewHostsEntryChange(
+			tc.arguments,
+			"test-op",
+			operator.Options[operator.HostsEntryChange]{
+				OperatorOptions: []operator.Option[operator.HostsEntryChange]{
+					operator.Option[operator.HostsEntryChange](operator.WithCustomHostsEntryResolver(suite.mockResolver)),
+					operator.Option[operator.HostsEntryChange](operator.WithCustomHostsFilePath(suite.hostsPath)),
+				},
+			},
+		).Run(context.Background())
+
+		suite.Nil(report.Success)
+		suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+		suite.Equal(tc.errorMessage, report.Error.Message)
+	}
+}
+
+func (suite *HostsEntryChangeOperatorTestSuite) TestHostsEntryChangePlanErrorConflict() {
+	suite.writeHostsFile(hostsTestFile)
+
+	report := operator.NewHostsEntryChange(
+		operator.Arguments{"entries": []any{
+			map[string]any{"ip": "10.80.1.25", "hostnames": []any{"vmhana01"}},
+		}},
+		"test-op",
+		operator.Options[operator.HostsEntryChange]{
+			OperatorOptions: []operator.Option[operator.HostsEntryChange]{
+				operator.Option[operator.HostsEntryChange](operator.WithCustomHostsEntryResolver(suite.mockResolver)),
+				operator.Option[operator.HostsEntryChange](operator.WithCustomHostsFilePath(suite.hostsPath)),
+			},
+		},
+	).Run(context.Background())
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal(
+		"plan: hostname vmhana01 is already defined with IP 10.80.1.11 outside of the managed block",
+		report.Error.Message,
+	)
+	suite.Equal(hostsTestFile, suite.readHostsFile())
+	suite.NoFileExists(suite.backupPath)
+}
+
+func (suite *HostsEntryChangeOperatorTestSuite) TestHostsEntryChangePlanErrorMalformedBlock() {
+	suite.writeHostsFile("127.0.0.1 localhost\n# BEGIN Trento managed block\n10.80.1.25 sapha1as\n")
+
+	report := operator.NewHostsEntryChange(
+		operator.Arguments{"entries": []any{
+			map[string]any{"ip": "10.80.1.25", "hostnames": []any{"sapha1as"}},
+		}},
+		"test-op",
+		operator.Options[operator.HostsEntryChange]{
+			OperatorOptions: []operator.Option[operator.HostsEntryChange]{
+				operator.Option[operator.HostsEntryChange](operator.WithCustomHostsEntryResolver(suite.mockResolver)),
+				operator.Option[operator.HostsEntryChange](operator.WithCustomHostsFilePath(suite.hostsPath)),
+			},
+		},
+	).Run(context.Background())
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal("plan: hosts file "+suite.hostsPath+" has a managed block without end", report.Error.Message)
+}
+
+func (suite *HostsEntryChangeOperatorTestSuite) TestHostsEntryChangeAlreadyApplied() {
+	suite.writeHostsFile(hostsWithBlockTestFile)
+
+	report := operator.NewHostsEntryChange(
+		operator.Arguments{"entries": []any{
+			map[string]any{"ip": "10.80.1.26", "hostnames": []any{"sapha1er"}},
+			map[string]any{"hostnames": []any{"sapha1db"}, "state": "absent"},
+		}},
+		"test-op",
+		operator.Options[operator.HostsEntryChange]{
+			OperatorOptions: []operator.Option[operator.HostsEntryChange]{
+				operator.Option[operator.HostsEntryChange](operator.WithCustomHostsEntryResolver(suite.mockResolver)),
+				operator.Option[operator.HostsEntryChange](operator.WithCustomHostsFilePath(suite.hostsPath)),
+			},
+		},
+	).Run(context.Background())
+
+	expectedEntries := `{"entries":[` +
+		`{"ip":"10.80.1.25","hostnames":["sapha1as","sapha1as.example.com"]},` +
+		`{"ip":"10.80.1.26","hostnames":["sapha1er"]}]}`
+	expectedDiff := map[string]any{
+		"before": expectedEntries,
+		"after":  expectedEntries,
+	}
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.PLAN, report.Success.LastPhase)
+	suite.Equal(expectedDiff, report.Success.Diff)
+	suite.NoFileExists(suite.backupPath)
+}
+
+func (suite *HostsEntryChangeOperatorTestSuite) TestHostsEntryChangeAddSuccess() {
+	suite.writeHostsFile(hostsTestFile)
+
+	suite.mockResolver.On("LookupHost", "sapha1as").Return([]string{"10.80.1.25"}, nil).Once()
+	suite.mockResolver.On("LookupHost", "sapha1as.example.com").Return([]string{"10.80.1.25"}, nil).Once()
+
+	report := operator.NewHostsEntryChange(
+		operator.Arguments{"entries": []any{
+			map[string]any{"ip": "10.80.1.25", "hostnames": []any{"sapha1as", "sapha1as.example.com"}},
+		}},
+		"test-op",
+		operator.Options[operator.HostsEntryChange]{
+			OperatorOptions: []operator.Option[operator.HostsEntryChange]{
+				operator.Option[operator.HostsEntryChange](operator.WithCustomHostsEntryResolver(suite.mockResolver)),
+				operator.Option[operator.HostsEntryChange](operator.WithCustomHostsFilePath(suite.hostsPath)),
+			},
+		},
+	).Run(context.Background())
+
+	expectedDiff := map[string]any{
+		"before": `{"entries":[]}`,
+		"after":  `{"entries":[{"ip":"10.80.1.25","hostnames":["sapha1as","sapha1as.example.com"]}]}`,
+	}
+
+	expectedContent := hostsTestFile +
+		"# BEGIN Trento managed block\n" +
+		"10.80.1.25\tsapha1as sapha1as.example.com\n" +
+		"# END Trento managed block\n"
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.VERIFY, report.Success.LastPhase)
+	suite.Equal(expectedDiff, report.Success.Diff)
+	suite.Equal(expectedContent, suite.readHostsFile())
+	suite.NoFileExists(suite.backupPath)
+}
+
+func (suite *HostsEntryChangeOperatorTestSuite) TestHostsEntryChangeAddIPv6Success() {
+	suite.writeHostsFile(hostsTestFile)
+
+	suite.mockResolver.On("LookupHost", "sapha1as").Return([]string{"fd00:0:0:0:0:0:0:25"}, nil).Once()
+
+	report := operator.NewHostsEntryChange(
+		operator.Arguments{"entries": []any{
+			map[string]any{"ip": "FD00:0000::0025", "hostnames": []any{"sapha1as"}},
+		}},
+		"test-op",
+		operator.Options[operator.HostsEntryChange]{
+			OperatorOptions: []operator.Option[operator.HostsEntryChange]{
+				operator.Option[operator.HostsEntryChange](operator.WithCustomHostsEntryResolver(suite.mockResolver)),
+				operator.Option[operator.HostsEntryChange](operator.WithCustomHostsFilePath(suite.hostsPath)),
+			},
+		},
+	).Run(context.Background())
+
+	expectedContent := hostsTestFile +
+		"# BEGIN Trento managed block\n" +
+		"fd00::25\tsapha1as\n" +
+		"# END Trento managed block\n"
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.VERIFY, report.Success.LastPhase)
+	suite.Equal(expectedContent, suite.readHostsFile())
+}
+
+func (suite *HostsEntryChangeOperatorTestSuite) TestHostsEntryChangeUpdateAndRemoveSuccess() {
+	suite.writeHostsFile(hostsWithBlockTestFile)
+
+	suite.mockResolver.On("LookupHost", "sapha1as").Return([]string{"10.80.1.35"}, nil).Once()
+
+	report := operator.NewHostsEntryChange(
+		operator.Arguments{"entries": []any{
+			map[string]any{"ip": "10.80.1.35", "hostnames": []any{"sapha1as"}},
+			map[string]any{"hostnames": []any{"sapha1er"}, "state": "absent"},
+		}},
+		"test-op",
+		operator.Options[operator.HostsEntryChange]{
+			OperatorOptions: []operator.Option[operator.HostsEntryChange]{
+				operator.Option[operator.HostsEntryChange](operator.WithCustomHostsEntryResolver(suite.mockResolver)),
+				operator.Option[operator.HostsEntryChange](operator.WithCustomHostsFilePath(suite.hostsPath)),
+			},
+		},
+	).Run(context.Background())
+
+	expectedDiff := map[string]any{
+		"before": `{"entries":[` +
+			`{"ip":"10.80.1.25","hostnames":["sapha1as","sapha1as.example.com"]},` +
+			`{"ip":"10.80.1.26","hostnames":["sapha1er"]}]}`,
+		"after": `{"entries":[{"ip":"10.80.1.35","hostnames":["sapha1as"]}]}`,
+	}
+
+	expectedContent := "127.0.0.1\tlocalhost\n" +
+		"# BEGIN Trento managed block\n" +
+		"10.80.1.35\tsapha1as\n" +
+		"# END Trento managed block\n" +
+		"10.80.1.11\tvmhana01\n"
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.VERIFY, report.Success.LastPhase)
+	suite.Equal(expectedDiff, report.Success.Diff)
+	suite.Equal(expectedContent, suite.readHostsFile())
+}
+
+func (suite *HostsEntryChangeOperatorTestSuite) TestHostsEntryChangeRemoveBlockSuccess() {
+	suite.writeHostsFile(hostsWithBlockTestFile)
+
+	report := operator.NewHostsEntryChange(
+		operator.Arguments{"entries": []any{
+			map[string]any{"hostnames": []any{"sapha1as"}, "state": "absent"},
+			map[string]any{"hostnames": []any{"sapha1er"}, "state": "absent"},
+		}},
+		"test-op",
+		operator.Options[operator.HostsEntryChange]{
+			OperatorOptions: []operator.Option[operator.HostsEntryChange]{
+				operator.Option[operator.HostsEntryChange](operator.WithCustomHostsEntryResolver(suite.mockResolver)),
+				operator.Option[operator.HostsEntryChange](operator.WithCustomHostsFilePath(suite.hostsPath)),
+			},
+		},
+	).Run(context.Background())
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.VERIFY, report.Success.LastPhase)
+	suite.Equal("127.0.0.1\tlocalhost\n10.80.1.11\tvmhana01\n", suite.readHostsFile())
+}
+
+func (suite *HostsEntryChangeOperatorTestSuite) TestHostsEntryChangeVerifyErrorRollback() {
+	cases := []struct {
+		addresses    []string
+		lookupError  error
+		errorMessage string
+	}{
+		{
+			addresses:    nil,
+			lookupError:  errors.New("no such host"),
+			errorMessage: "verify: error resolving hostname sapha1as: no such host",
+		},
+		{
+			addresses:    []string{"10.80.1.99"},
+			lookupError:  nil,
+			errorMessage: "verify: hostname sapha1as resolves to 10.80.1.99, expected 10.80.1.25",
+		},
+	}
+
+	for _, tc := range cases {
+		suite.SetupTest()
+		suite.writeHostsFile(hostsTestFile)
+
+		suite.mockResolver.On("LookupHost", "sapha1as").Return(tc.addresses, tc.lookupError).Once()
+
+		report := operator.NewHostsEntryChange(
+			operator.Arguments{"entries": []any{
+				map[string]any{"ip": "10.80.1.25", "hostnames": []any{"sapha1as"}},
+			}},
+			"test-op",
+			operator.Options[operator.HostsEntryChange]{
+				OperatorOptions: []operator.Option[operator.HostsEntryChange]{
+					operator.Option[operator.HostsEntryChange](operator.WithCustomHostsEntryResolver(suite.mockResolver)),
+					operator.Option[operator.HostsEntryChange](operator.WithCustomHostsFilePath(suite.hostsPath)),
+				},
+			},
+		).Run(context.Background())
+
+		suite.Nil(report.Success)
+		suite.Equal(operator.VERIFY, report.Error.ErrorPhase)
+		suite.Equal(tc.errorMessage, report.Error.Message)
+		suite.Equal(hostsTestFile, suite.readHostsFile())
+	}
+}
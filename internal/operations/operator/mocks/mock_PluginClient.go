@@ -0,0 +1,340 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockPluginClient is an autogenerated mock type for the PluginClient type
+type MockPluginClient struct {
+	mock.Mock
+}
+
+type MockPluginClient_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockPluginClient) EXPECT() *MockPluginClient_Expecter {
+	return &MockPluginClient_Expecter{mock: &_m.Mock}
+}
+
+// Commit provides a mock function with given fields: ctx, operationID
+func (_m *MockPluginClient) Commit(ctx context.Context, operationID string) error {
+	ret := _m.Called(ctx, operationID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Commit")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, operationID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockPluginClient_Commit_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Commit'
+type MockPluginClient_Commit_Call struct {
+	*mock.Call
+}
+
+// Commit is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operationID string
+func (_e *MockPluginClient_Expecter) Commit(ctx interface{}, operationID interface{}) *MockPluginClient_Commit_Call {
+	return &MockPluginClient_Commit_Call{Call: _e.mock.On("Commit", ctx, operationID)}
+}
+
+func (_c *MockPluginClient_Commit_Call) Run(run func(ctx context.Context, operationID string)) *MockPluginClient_Commit_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockPluginClient_Commit_Call) Return(_a0 error) *MockPluginClient_Commit_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPluginClient_Commit_Call) RunAndReturn(run func(context.Context, string) error) *MockPluginClient_Commit_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// OperationDiff provides a mock function with given fields: ctx, operationID
+func (_m *MockPluginClient) OperationDiff(ctx context.Context, operationID string) (map[string]interface{}, error) {
+	ret := _m.Called(ctx, operationID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OperationDiff")
+	}
+
+	var r0 map[string]interface{}
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (map[string]interface{}, error)); ok {
+		return rf(ctx, operationID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) map[string]interface{}); ok {
+		r0 = rf(ctx, operationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]interface{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, operationID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPluginClient_OperationDiff_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'OperationDiff'
+type MockPluginClient_OperationDiff_Call struct {
+	*mock.Call
+}
+
+// OperationDiff is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operationID string
+func (_e *MockPluginClient_Expecter) OperationDiff(ctx interface{}, operationID interface{}) *MockPluginClient_OperationDiff_Call {
+	return &MockPluginClient_OperationDiff_Call{Call: _e.mock.On("OperationDiff", ctx, operationID)}
+}
+
+func (_c *MockPluginClient_OperationDiff_Call) Run(run func(ctx context.Context, operationID string)) *MockPluginClient_OperationDiff_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockPluginClient_OperationDiff_Call) Return(_a0 map[string]interface{}, _a1 error) *MockPluginClient_OperationDiff_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPluginClient_OperationDiff_Call) RunAndReturn(run func(context.Context, string) (map[string]interface{}, error)) *MockPluginClient_OperationDiff_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Plan provides a mock function with given fields: ctx, operationID, arguments
+func (_m *MockPluginClient) Plan(ctx context.Context, operationID string, arguments map[string]interface{}) (bool, error) {
+	ret := _m.Called(ctx, operationID, arguments)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Plan")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, map[string]interface{}) (bool, error)); ok {
+		return rf(ctx, operationID, arguments)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, map[string]interface{}) bool); ok {
+		r0 = rf(ctx, operationID, arguments)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, map[string]interface{}) error); ok {
+		r1 = rf(ctx, operationID, arguments)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPluginClient_Plan_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Plan'
+type MockPluginClient_Plan_Call struct {
+	*mock.Call
+}
+
+// Plan is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operationID string
+//   - arguments map[string]interface{}
+func (_e *MockPluginClient_Expecter) Plan(ctx interface{}, operationID interface{}, arguments interface{}) *MockPluginClient_Plan_Call {
+	return &MockPluginClient_Plan_Call{Call: _e.mock.On("Plan", ctx, operationID, arguments)}
+}
+
+func (_c *MockPluginClient_Plan_Call) Run(run func(ctx context.Context, operationID string, arguments map[string]interface{})) *MockPluginClient_Plan_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(map[string]interface{}))
+	})
+	return _c
+}
+
+func (_c *MockPluginClient_Plan_Call) Return(_a0 bool, _a1 error) *MockPluginClient_Plan_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPluginClient_Plan_Call) RunAndReturn(run func(context.Context, string, map[string]interface{}) (bool, error)) *MockPluginClient_Plan_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Release provides a mock function with given fields: operationID
+func (_m *MockPluginClient) Release(operationID string) error {
+	ret := _m.Called(operationID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Release")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(operationID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockPluginClient_Release_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Release'
+type MockPluginClient_Release_Call struct {
+	*mock.Call
+}
+
+// Release is a helper method to define mock.On call
+//   - operationID string
+func (_e *MockPluginClient_Expecter) Release(operationID interface{}) *MockPluginClient_Release_Call {
+	return &MockPluginClient_Release_Call{Call: _e.mock.On("Release", operationID)}
+}
+
+func (_c *MockPluginClient_Release_Call) Run(run func(operationID string)) *MockPluginClient_Release_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockPluginClient_Release_Call) Return(_a0 error) *MockPluginClient_Release_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPluginClient_Release_Call) RunAndReturn(run func(string) error) *MockPluginClient_Release_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Rollback provides a mock function with given fields: ctx, operationID
+func (_m *MockPluginClient) Rollback(ctx context.Context, operationID string) error {
+	ret := _m.Called(ctx, operationID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Rollback")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, operationID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockPluginClient_Rollback_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Rollback'
+type MockPluginClient_Rollback_Call struct {
+	*mock.Call
+}
+
+// Rollback is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operationID string
+func (_e *MockPluginClient_Expecter) Rollback(ctx interface{}, operationID interface{}) *MockPluginClient_Rollback_Call {
+	return &MockPluginClient_Rollback_Call{Call: _e.mock.On("Rollback", ctx, operationID)}
+}
+
+func (_c *MockPluginClient_Rollback_Call) Run(run func(ctx context.Context, operationID string)) *MockPluginClient_Rollback_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockPluginClient_Rollback_Call) Return(_a0 error) *MockPluginClient_Rollback_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPluginClient_Rollback_Call) RunAndReturn(run func(context.Context, string) error) *MockPluginClient_Rollback_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Verify provides a mock function with given fields: ctx, operationID
+func (_m *MockPluginClient) Verify(ctx context.Context, operationID string) error {
+	ret := _m.Called(ctx, operationID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Verify")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, operationID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockPluginClient_Verify_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Verify'
+type MockPluginClient_Verify_Call struct {
+	*mock.Call
+}
+
+// Verify is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operationID string
+func (_e *MockPluginClient_Expecter) Verify(ctx interface{}, operationID interface{}) *MockPluginClient_Verify_Call {
+	return &MockPluginClient_Verify_Call{Call: _e.mock.On("Verify", ctx, operationID)}
+}
+
+func (_c *MockPluginClient_Verify_Call) Run(run func(ctx context.Context, operationID string)) *MockPluginClient_Verify_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockPluginClient_Verify_Call) Return(_a0 error) *MockPluginClient_Verify_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPluginClient_Verify_Call) RunAndReturn(run func(context.Context, string) error) *MockPluginClient_Verify_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockPluginClient creates a new instance of MockPluginClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockPluginClient(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockPluginClient {
+	mock := &MockPluginClient{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
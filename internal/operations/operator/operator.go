@@ -23,6 +23,11 @@ type Operator interface {
 	Run(ctx context.Context) *ExecutionReport
 }
 
+// RollbackOperator is implemented by the operators able to run a deferred rollback.
+type RollbackOperator interface {
+	RunRollback(ctx context.Context, state map[string]any) *ExecutionReport
+}
+
 type Options[T any] struct {
 	BaseOperatorOptions []BaseOperatorOption
 	OperatorOptions     []Option[T]
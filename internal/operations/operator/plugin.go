@@ -0,0 +1,200 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	goplugin "github.com/hashicorp/go-plugin"
+	"github.com/trento-project/agent/v3/pkg/operations/plugininterface"
+)
+
+const defaultPluginVersion = "v1"
+
+// PluginClient is the agent side of an operator plugin.
+// Each call receives the operation ID, as a plugin serves all the operations using it.
+type PluginClient interface {
+	Plan(ctx context.Context, operationID string, arguments map[string]any) (bool, error)
+	Commit(ctx context.Context, operationID string) error
+	Verify(ctx context.Context, operationID string) error
+	Rollback(ctx context.Context, operationID string) error
+	OperationDiff(ctx context.Context, operationID string) (map[string]any, error)
+	Release(operationID string) error
+}
+
+type PluginLoader interface {
+	Load(pluginPath string) (PluginClient, error)
+}
+
+type PluginLoaders map[string]PluginLoader
+
+// GetOperatorsFromPlugins loads the operator plugins found in the plugins folder.
+// The operator name is the plugin file name without extension.
+func GetOperatorsFromPlugins(
+	loaders PluginLoaders,
+	pluginsFolder string,
+	options ...BaseOperatorOption,
+) (BuildersTree, error) {
+	pluginOperators := make(BuildersTree)
+
+	slog.Debug("Loading operator plugins...")
+
+	plugins, err := filepath.Glob(pluginsFolder + "/*")
+	if err != nil {
+		return nil, fmt.Errorf("error running glob operation in the operator plugins folder: %w", err)
+	}
+
+	for _, filePath := range plugins {
+		slog.Debug("Loading operator plugin", "filePath", filePath)
+
+		client, err := loaders["rpc"].Load(filePath)
+		if err != nil {
+			slog.Warn("Error loading operator plugin", "filePath", filePath, "error", err)
+
+			continue
+		}
+
+		name := path.Base(filePath)
+		name = strings.TrimSuffix(name, path.Ext(name))
+
+		pluginOperators[name] = map[string]Builder{
+			defaultPluginVersion: func(operationID string, arguments Arguments) Operator {
+				return NewPluggedOperator(name, client, arguments, operationID, Options[PluggedOperator]{
+					BaseOperatorOptions: options,
+				})
+			},
+		}
+
+		slog.Debug("Operator plugin loaded properly", "filePath", filePath)
+	}
+
+	return pluginOperators, nil
+}
+
+func CleanupPlugins() {
+	goplugin.CleanupClients()
+}
+
+type RPCPluginLoader struct{}
+
+func (l *RPCPluginLoader) Load(pluginPath string) (PluginClient, error) {
+	pluginMap := map[string]goplugin.Plugin{
+		"operator": &plugininterface.OperatorPlugin{Builder: nil},
+	}
+
+	client := goplugin.NewClient(&goplugin.ClientConfig{
+		HandshakeConfig: plugininterface.Handshake,
+		Plugins:         pluginMap,
+		Cmd:             exec.Command(pluginPath),
+		Managed:         true,
+		AllowedProtocols: []goplugin.Protocol{
+			goplugin.ProtocolNetRPC,
+		},
+		SyncStdout: os.Stdout,
+		SyncStderr: os.Stderr,
+	})
+
+	rpcClient, err := client.Client()
+	if err != nil {
+		return nil, fmt.Errorf("error starting the rpc client: %w", err)
+	}
+
+	raw, err := rpcClient.Dispense("operator")
+	if err != nil {
+		return nil, fmt.Errorf("error dispensing plugin: %w", err)
+	}
+
+	pluginClient, ok := raw.(*plugininterface.OperatorRPC)
+	if !ok {
+		return nil, fmt.Errorf("error asserting operator plugin type, got %T", raw)
+	}
+
+	return pluginClient, nil
+}
+
+type PluggedOperatorOption Option[PluggedOperator]
+
+// PluggedOperator runs the phases of an operator plugin.
+// The Executor drives the phases, so the plugins get the same guarantees as the built-in operators.
+// The plugin operator is released once the operation is finished.
+type PluggedOperator struct {
+	baseOperator
+
+	operationID string
+	client      PluginClient
+}
+
+func NewPluggedOperator(
+	name string,
+	client PluginClient,
+	arguments Arguments,
+	operationID string,
+	options Options[PluggedOperator],
+) *Executor {
+	pluggedOperator := &PluggedOperator{
+		baseOperator: newBaseOperator(name, operationID, arguments, options.BaseOperatorOptions...),
+		operationID:  operationID,
+		client:       client,
+	}
+
+	for _, opt := range options.OperatorOptions {
+		opt(pluggedOperator)
+	}
+
+	return &Executor{
+		phaser:      pluggedOperator,
+		operationID: operationID,
+		logger:      pluggedOperator.logger,
+	}
+}
+
+func (p *PluggedOperator) plan(ctx context.Context) (bool, error) {
+	return p.client.Plan(ctx, p.operationID, p.arguments)
+}
+
+func (p *PluggedOperator) commit(ctx context.Context) error {
+	return p.client.Commit(ctx, p.operationID)
+}
+
+func (p *PluggedOperator) verify(ctx context.Context) error {
+	return p.client.Verify(ctx, p.operationID)
+}
+
+func (p *PluggedOperator) rollback(ctx context.Context) error {
+	return p.client.Rollback(ctx, p.operationID)
+}
+
+func (p *PluggedOperator) operationDiff(ctx context.Context) map[string]any {
+	diff, err := p.client.OperationDiff(ctx, p.operationID)
+	if err != nil {
+		p.logger.Error("error getting the plugin operation diff", "error", err)
+	}
+
+	if diff == nil {
+		diff = make(map[string]any)
+	}
+
+	// the operation report always has both diff fields
+	for _, field := range []string{beforeDiffField, afterDiffField} {
+		if _, found := diff[field]; !found {
+			diff[field] = nil
+		}
+	}
+
+	return diff
+}
+
+func (p *PluggedOperator) after(_ context.Context) {
+	err := p.client.Release(p.operationID)
+	if err != nil {
+		p.logger.Warn("error releasing the plugin operator", "error", err)
+	}
+}
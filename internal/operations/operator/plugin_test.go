@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/trento-project/agent/v3/internal/operations/operator"
+	"github.com/trento-project/agent/v3/internal/operations/operator/mocks"
+)
+
+type PluginTestSuite struct {
+	suite.Suite
+
+	mockClient    *mocks.MockPluginClient
+	pluginsFolder string
+}
+
+func TestPluginTestSuite(t *testing.T) {
+	suite.Run(t, new(PluginTestSuite))
+}
+
+type testPluginLoader struct {
+	client operator.PluginClient
+}
+
+func (l *testPluginLoader) Load(pluginPath string) (operator.PluginClient, error) {
+	if path.Base(pluginPath) == "broken" {
+		return nil, errors.New("kaboom")
+	}
+
+	return l.client, nil
+}
+
+func (suite *PluginTestSuite) SetupTest() {
+	suite.mockClient = mocks.NewMockPluginClient(suite.T())
+	suite.pluginsFolder = suite.T().TempDir()
+
+	for _, name := range []string{"customremediation", "broken"} {
+		suite.Require().NoError(os.WriteFile(path.Join(suite.pluginsFolder, name), []byte{}, 0600))
+	}
+}
+
+func (suite *PluginTestSuite) loadPluginOperator() operator.Builder {
+	loaders := operator.PluginLoaders{
+		"rpc": &testPluginLoader{client: suite.mockClient},
+	}
+
+	operators, err := operator.GetOperatorsFromPlugins(loaders, suite.pluginsFolder)
+	suite.Require().NoError(err)
+	suite.Require().Len(operators, 1)
+
+	builder, found := operators["customremediation"]["v1"]
+	suite.Require().True(found)
+
+	return builder
+}
+
+func (suite *PluginTestSuite) TestPluginOperatorSuccess() {
+	ctx := context.Background()
+	arguments := operator.Arguments{"target": "value"}
+
+	suite.mockClient.On("Plan", ctx, "operation-id", map[string]any(arguments)).Return(false, nil).Once()
+	suite.mockClient.On("Commit", ctx, "operation-id").Return(nil).Once()
+	suite.mockClient.On("Verify", ctx, "operation-id").Return(nil).Once()
+	suite.mockClient.On("OperationDiff", ctx, "operation-id").Return(map[string]any{
+		"before": `{"applied":false}`,
+		"after":  `{"applied":true}`,
+	}, nil).Once()
+	suite.mockClient.On("Release", "operation-id").Return(nil).Once()
+
+	report := suite.loadPluginOperator()("operation-id", arguments).Run(ctx)
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.VERIFY, report.Success.LastPhase)
+	suite.EqualValues(map[string]any{
+		"before": `{"applied":false}`,
+		"after":  `{"applied":true}`,
+	}, report.Success.Diff)
+}
+
+func (suite *PluginTestSuite) TestPluginOperatorPlanError() {
+	ctx := context.Background()
+
+	suite.mockClient.On("Plan", ctx, "operation-id", mock.Anything).Return(false, errors.New("plan failed")).Once()
+
+	report := suite.loadPluginOperator()("operation-id", operator.Arguments{}).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal("plan: plan failed", report.Error.Message)
+	suite.mockClient.AssertNotCalled(suite.T(), "Release", mock.Anything)
+}
+
+func (suite *PluginTestSuite) TestPluginOperatorCommitErrorRollback() {
+	ctx := context.Background()
+
+	suite.mockClient.On("Plan", ctx, "operation-id", mock.Anything).Return(false, nil).Once()
+	suite.mockClient.On("Commit", ctx, "operation-id").Return(errors.New("commit failed")).Once()
+	suite.mockClient.On("Rollback", ctx, "operation-id").Return(nil).Once()
+	suite.mockClient.On("Release", "operation-id").Return(nil).Once()
+
+	report := suite.loadPluginOperator()("operation-id", operator.Arguments{}).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.COMMIT, report.Error.ErrorPhase)
+	suite.Equal("commit: commit failed", report.Error.Message)
+}
+
+func (suite *PluginTestSuite) TestPluginOperatorDiffError() {
+	ctx := context.Background()
+
+	suite.mockClient.On("Plan", ctx, "operation-id", mock.Anything).Return(true, nil).Once()
+	suite.mockClient.On("OperationDiff", ctx, "operation-id").Return(nil, errors.New("diff failed")).Once()
+	suite.mockClient.On("Release", "operation-id").Return(nil).Once()
+
+	report := suite.loadPluginOperator()("operation-id", operator.Arguments{}).Run(ctx)
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.PLAN, report.Success.LastPhase)
+	suite.Equal(map[string]any{"before": nil, "after": nil}, report.Success.Diff)
+}
+
+func (suite *PluginTestSuite) TestRegistryAddOperatorsDoesNotOverride() {
+	builtinOperator := mocks.NewMockOperator(suite.T())
+	pluginOperator := mocks.NewMockOperator(suite.T())
+
+	registry := operator.NewRegistry(operator.BuildersTree{
+		"builtin": map[string]operator.Builder{
+			"v1": func(_ string, _ operator.Arguments) operator.Operator { return builtinOperator },
+		},
+	})
+
+	registry.AddOperators(operator.BuildersTree{
+		"builtin": map[string]operator.Builder{
+			"v1": func(_ string, _ operator.Arguments) operator.Operator { return pluginOperator },
+		},
+		"plugin": map[string]operator.Builder{
+			"v1": func(_ string, _ operator.Arguments) operator.Operator { return pluginOperator },
+		},
+	})
+
+	builder, err := registry.GetOperatorBuilder("builtin")
+	suite.Require().NoError(err)
+	suite.Equal(builtinOperator, builder("", operator.Arguments{}))
+
+	builder, err = registry.GetOperatorBuilder("plugin@v1")
+	suite.Require().NoError(err)
+	suite.Equal(pluginOperator, builder("", operator.Arguments{}))
+}
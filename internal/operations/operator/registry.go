@@ -5,6 +5,7 @@ package operator
 
 import (
 	"fmt"
+	"log/slog"
 	"sort"
 	"strings"
 )
@@ -41,11 +42,22 @@ func extractOperatorNameAndVersion(operatorName string) (string, string, error)
 
 type Registry struct {
 	operators BuildersTree
+	schemas   SchemasTree
 }
 
 func NewRegistry(operators BuildersTree) *Registry {
 	return &Registry{
 		operators: operators,
+		schemas:   SchemasTree{},
+	}
+}
+
+// NewRegistryWithSchemas creates a registry where the arguments given to the builders
+// are validated against the operator schema before running the operator.
+func NewRegistryWithSchemas(operators BuildersTree, schemas SchemasTree) *Registry {
+	return &Registry{
+		operators: operators,
+		schemas:   schemas,
 	}
 }
 
@@ -64,11 +76,42 @@ func (m *Registry) GetOperatorBuilder(name string) (Builder, error) {
 		version = latestVersion
 	}
 
-	if g, found := m.operators[operatorName][version]; found {
-		return g, nil
+	g, found := m.operators[operatorName][version]
+	if !found {
+		return nil, &NotFoundError{Name: name}
 	}
 
-	return nil, &NotFoundError{Name: name}
+	if schema, found := m.schemas[operatorName][version]; found {
+		return withSchemaValidation(g, schema), nil
+	}
+
+	return g, nil
+}
+
+// Catalogue returns the available operator versions with their arguments schema,
+// sorted by name and version.
+func (m *Registry) Catalogue() []CatalogueEntry {
+	catalogue := []CatalogueEntry{}
+
+	for operatorName, versions := range m.operators {
+		for version := range versions {
+			catalogue = append(catalogue, CatalogueEntry{
+				Name:    operatorName,
+				Version: version,
+				Schema:  m.schemas[operatorName][version],
+			})
+		}
+	}
+
+	sort.Slice(catalogue, func(i, j int) bool {
+		if catalogue[i].Name != catalogue[j].Name {
+			return catalogue[i].Name < catalogue[j].Name
+		}
+
+		return catalogue[i].Version < catalogue[j].Version
+	})
+
+	return catalogue
 }
 
 func (m *Registry) AvailableOperators() []string {
@@ -90,6 +133,21 @@ func (m *Registry) AvailableOperators() []string {
 	return operatorList
 }
 
+// AddOperators adds the given operators to the registry.
+// Operators already in the registry are not replaced, so plugins cannot override them.
+// This is not safe, please not use concurrently.
+func (m *Registry) AddOperators(operators BuildersTree) {
+	for operatorName, versions := range operators {
+		if _, found := m.operators[operatorName]; found {
+			slog.Warn("Operator already registered, skipping", "operator", operatorName)
+
+			continue
+		}
+
+		m.operators[operatorName] = versions
+	}
+}
+
 func (m *Registry) getLatestVersionForOperator(name string) (string, error) {
 	availableOperators, found := m.operators[name]
 	if !found {
@@ -106,8 +164,33 @@ func (m *Registry) getLatestVersionForOperator(name string) (string, error) {
 	return versions[len(versions)-1], nil
 }
 
-func StandardRegistry(options ...BaseOperatorOption) *Registry {
-	return &Registry{
+// Config holds the agent configuration used by the standard operators.
+type Config struct {
+	// ServiceStateAllowedUnits overrides the units that the servicestate operator can manage.
+	ServiceStateAllowedUnits []string
+	// ArtifactUploader uploads the archives collected by the supportarchive operator.
+	ArtifactUploader ArtifactUploader
+}
+
+func StandardRegistry(config Config, options ...BaseOperatorOption) *Registry {
+	serviceStateOptions := []Option[ServiceState]{}
+	if len(config.ServiceStateAllowedUnits) > 0 {
+		serviceStateOptions = append(
+			serviceStateOptions,
+			Option[ServiceState](WithServiceStateAllowedUnits(config.ServiceStateAllowedUnits)),
+		)
+	}
+
+	supportArchiveOptions := []Option[SupportArchive]{}
+	if config.ArtifactUploader != nil {
+		supportArchiveOptions = append(
+			supportArchiveOptions,
+			Option[SupportArchive](WithSupportArchiveUploader(config.ArtifactUploader)),
+		)
+	}
+
+	registry := &Registry{
+		schemas: standardSchemas(),
 		operators: BuildersTree{
 			ClusterMaintenanceChangeOperatorName: map[string]Builder{
 				"v1": func(operationID string, arguments Arguments) Operator {
@@ -123,6 +206,13 @@ func StandardRegistry(options ...BaseOperatorOption) *Registry {
 					})
 				},
 			},
+			CorosyncTotemChangeOperatorName: map[string]Builder{
+				"v1": func(operationID string, arguments Arguments) Operator {
+					return NewCorosyncTotemChange(arguments, operationID, Options[CorosyncTotemChange]{
+						BaseOperatorOptions: options,
+					})
+				},
+			},
 			CrmClusterStartOperatorName: map[string]Builder{
 				"v1": func(operationID string, arguments Arguments) Operator {
 					return NewCrmClusterStart(arguments, operationID, Options[CrmClusterStart]{
@@ -137,6 +227,27 @@ func StandardRegistry(options ...BaseOperatorOption) *Registry {
 					})
 				},
 			},
+			HanaStartOperatorName: map[string]Builder{
+				"v1": func(operationID string, arguments Arguments) Operator {
+					return NewHanaStart(arguments, operationID, Options[HanaStart]{
+						BaseOperatorOptions: options,
+					})
+				},
+			},
+			HanaStopOperatorName: map[string]Builder{
+				"v1": func(operationID string, arguments Arguments) Operator {
+					return NewHanaStop(arguments, operationID, Options[HanaStop]{
+						BaseOperatorOptions: options,
+					})
+				},
+			},
+			HostsEntryChangeOperatorName: map[string]Builder{
+				"v1": func(operationID string, arguments Arguments) Operator {
+					return NewHostsEntryChange(arguments, operationID, Options[HostsEntryChange]{
+						BaseOperatorOptions: options,
+					})
+				},
+			},
 			HostRebootOperatorName: map[string]Builder{
 				"v1": func(operationID string, arguments Arguments) Operator {
 					return NewHostReboot(arguments, operationID, Options[HostReboot]{
@@ -179,6 +290,13 @@ func StandardRegistry(options ...BaseOperatorOption) *Registry {
 					})
 				},
 			},
+			SaptuneApplyNoteOperatorName: map[string]Builder{
+				"v1": func(operationID string, arguments Arguments) Operator {
+					return NewSaptuneApplyNote(arguments, operationID, Options[SaptuneApplyNote]{
+						BaseOperatorOptions: options,
+					})
+				},
+			},
 			SaptuneChangeSolutionOperatorName: map[string]Builder{
 				"v1": func(operationID string, arguments Arguments) Operator {
 					return NewSaptuneChangeSolution(arguments, operationID, Options[SaptuneChangeSolution]{
@@ -186,6 +304,57 @@ func StandardRegistry(options ...BaseOperatorOption) *Registry {
 					})
 				},
 			},
+			SaptuneRevertNoteOperatorName: map[string]Builder{
+				"v1": func(operationID string, arguments Arguments) Operator {
+					return NewSaptuneRevertNote(arguments, operationID, Options[SaptuneRevertNote]{
+						BaseOperatorOptions: options,
+					})
+				},
+			},
+			SaptuneRevertSolutionOperatorName: map[string]Builder{
+				"v1": func(operationID string, arguments Arguments) Operator {
+					return NewSaptuneRevertSolution(arguments, operationID, Options[SaptuneRevertSolution]{
+						BaseOperatorOptions: options,
+					})
+				},
+			},
+			SbdConfigChangeOperatorName: map[string]Builder{
+				"v1": func(operationID string, arguments Arguments) Operator {
+					return NewSbdConfigChange(arguments, operationID, Options[SbdConfigChange]{
+						BaseOperatorOptions: options,
+					})
+				},
+			},
+			ServiceStateOperatorName: map[string]Builder{
+				"v1": func(operationID string, arguments Arguments) Operator {
+					return NewServiceState(arguments, operationID, Options[ServiceState]{
+						BaseOperatorOptions: options,
+						OperatorOptions:     serviceStateOptions,
+					})
+				},
+			},
+			SupportArchiveOperatorName: map[string]Builder{
+				"v1": func(operationID string, arguments Arguments) Operator {
+					return NewSupportArchive(arguments, operationID, Options[SupportArchive]{
+						BaseOperatorOptions: options,
+						OperatorOptions:     supportArchiveOptions,
+					})
+				},
+			},
+			SysctlApplyOperatorName: map[string]Builder{
+				"v1": func(operationID string, arguments Arguments) Operator {
+					return NewSysctlApply(arguments, operationID, Options[SysctlApply]{
+						BaseOperatorOptions: options,
+					})
+				},
+			},
+			ZypperPatchOperatorName: map[string]Builder{
+				"v1": func(operationID string, arguments Arguments) Operator {
+					return NewZypperPatch(arguments, operationID, Options[ZypperPatch]{
+						BaseOperatorOptions: options,
+					})
+				},
+			},
 			PacemakerEnableOperatorName: map[string]Builder{
 				"v1": func(operationID string, arguments Arguments) Operator {
 					return NewServiceEnable(PacemakerEnableOperatorName, arguments, operationID, Options[ServiceEnable]{
@@ -208,4 +377,18 @@ func StandardRegistry(options ...BaseOperatorOption) *Registry {
 			},
 		},
 	}
+
+	// The runbook operator builds its steps from the registry itself
+	registry.operators[RunbookOperatorName] = map[string]Builder{
+		"v1": func(operationID string, arguments Arguments) Operator {
+			return NewRunbook(arguments, operationID, Options[Runbook]{
+				BaseOperatorOptions: options,
+				OperatorOptions: []Option[Runbook]{
+					Option[Runbook](WithRunbookRegistry(registry)),
+				},
+			})
+		},
+	}
+
+	return registry
 }
@@ -4,6 +4,7 @@
 package operator_test
 
 import (
+	"context"
 	"sort"
 	"testing"
 
@@ -112,3 +113,78 @@ func (suite *RegistryTest) TestGetOperatorBuilderFoundWithoutVersionGetLast() {
 	suite.Require().NoError(err)
 	suite.Equal(b("", nil), foundOperator)
 }
+
+func (suite *RegistryTest) TestGetOperatorBuilderSchemaValidation() {
+	foundOperator := mocks.NewMockOperator(suite.T())
+	registry := operator.NewRegistryWithSchemas(
+		operator.BuildersTree{
+			"test": map[string]operator.Builder{
+				"v1": func(_ string, _ operator.Arguments) operator.Operator { return foundOperator },
+			},
+		},
+		operator.SchemasTree{
+			"test": map[string]operator.Schema{
+				"v1": {
+					Arguments: []operator.ArgumentSchema{
+						{Name: "name", Type: operator.ArgumentTypeString, Required: true},
+					},
+				},
+			},
+		},
+	)
+
+	builder, err := registry.GetOperatorBuilder("test@v1")
+	suite.Require().NoError(err)
+
+	suite.Equal(foundOperator, builder("operation-id", operator.Arguments{"name": "value"}))
+
+	report := builder("operation-id", operator.Arguments{}).Run(context.Background())
+
+	suite.Nil(report.Success)
+	suite.Equal("operation-id", report.OperationID)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal("plan: invalid arguments: argument name is required", report.Error.Message)
+}
+
+func (suite *RegistryTest) TestCatalogue() {
+	schema := operator.Schema{
+		Description: "test operator",
+		Arguments: []operator.ArgumentSchema{
+			{Name: "name", Type: operator.ArgumentTypeString, Required: true},
+		},
+	}
+
+	registry := operator.NewRegistryWithSchemas(
+		operator.BuildersTree{
+			"test": map[string]operator.Builder{
+				"v2": func(_ string, _ operator.Arguments) operator.Operator { return nil },
+				"v1": func(_ string, _ operator.Arguments) operator.Operator { return nil },
+			},
+			"other": map[string]operator.Builder{
+				"v1": func(_ string, _ operator.Arguments) operator.Operator { return nil },
+			},
+		},
+		operator.SchemasTree{
+			"test": map[string]operator.Schema{
+				"v1": schema,
+			},
+		},
+	)
+
+	expectedCatalogue := []operator.CatalogueEntry{
+		{Name: "other", Version: "v1"},
+		{Name: "test", Version: "v1", Schema: schema},
+		{Name: "test", Version: "v2"},
+	}
+
+	suite.Equal(expectedCatalogue, registry.Catalogue())
+}
+
+func (suite *RegistryTest) TestStandardRegistryCatalogueHasSchemas() {
+	registry := operator.StandardRegistry(operator.Config{})
+
+	for _, entry := range registry.Catalogue() {
+		suite.NotEmpty(entry.Description, "operator %s@%s has no schema", entry.Name, entry.Version)
+		suite.NotNil(entry.Arguments, "operator %s@%s has no arguments schema", entry.Name, entry.Version)
+	}
+}
@@ -0,0 +1,203 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	deferredRollbackFileExtension = ".json"
+	deferredRollbackFileMode      = 0600
+	deferredRollbackDirMode       = 0700
+)
+
+var ErrDeferredRollbackNotFound = errors.New("deferred rollback not found")
+
+// RollbackPolicy decides what happens when the COMMIT or VERIFY phases of an operation fail.
+//   - auto: the operation is rolled back right away. This is the default policy.
+//   - never: the operation is not rolled back, leaving the host as the failure left it.
+//   - deferred: the state captured in the PLAN phase is kept, so the operation can be
+//     rolled back later on request.
+//
+// The deferred policy applies to every operator implementing deferredRollbacker, which run
+// their usual ROLLBACK phase from the stored state. The other operators refuse it before PLAN.
+type RollbackPolicy string
+
+const (
+	RollbackPolicyAuto     RollbackPolicy = "auto"
+	RollbackPolicyNever    RollbackPolicy = "never"
+	RollbackPolicyDeferred RollbackPolicy = "deferred"
+)
+
+func ParseRollbackPolicy(policy string) (RollbackPolicy, error) {
+	switch RollbackPolicy(policy) {
+	case "", RollbackPolicyAuto:
+		return RollbackPolicyAuto, nil
+	case RollbackPolicyNever, RollbackPolicyDeferred:
+		return RollbackPolicy(policy), nil
+	default:
+		return "", fmt.Errorf("invalid rollback policy %s, supported values: auto, never, deferred", policy)
+	}
+}
+
+type rollbackPolicyKey struct{}
+
+// ContextWithRollbackPolicy sets the rollback policy used by the operators run with the returned context.
+func ContextWithRollbackPolicy(ctx context.Context, policy RollbackPolicy) context.Context {
+	return context.WithValue(ctx, rollbackPolicyKey{}, policy)
+}
+
+func rollbackPolicyFromContext(ctx context.Context) RollbackPolicy {
+	policy, ok := ctx.Value(rollbackPolicyKey{}).(RollbackPolicy)
+	if !ok {
+		return RollbackPolicyAuto
+	}
+
+	return policy
+}
+
+// deferredRollbacker is implemented by the operators which can be rolled back
+// by a new operator instance, built with the same operation ID and arguments.
+// setup prepares everything the rollback needs except the state captured during PLAN,
+// which is restored afterwards.
+type deferredRollbacker interface {
+	setup(ctx context.Context) error
+	rollbackState() map[string]any
+	restoreRollbackState(state map[string]any)
+}
+
+func (b *baseOperator) rollbackState() map[string]any {
+	return maps.Clone(b.resources)
+}
+
+func (b *baseOperator) restoreRollbackState(state map[string]any) {
+	b.resources = maps.Clone(state)
+	if b.resources == nil {
+		b.resources = make(map[string]any)
+	}
+}
+
+// DeferredRollback is the record of an operation whose rollback was deferred.
+type DeferredRollback struct {
+	OperationID string         `json:"operation_id"`
+	GroupID     string         `json:"group_id"`
+	StepNumber  int32          `json:"step_number"`
+	AgentID     string         `json:"agent_id"`
+	Operator    string         `json:"operator"`
+	Arguments   Arguments      `json:"arguments"`
+	State       map[string]any `json:"state"`
+	Error       string         `json:"error"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+// RunDeferredRollback builds the operator of a deferred rollback from the registry
+// and runs its rollback with the stored PLAN state.
+func RunDeferredRollback(ctx context.Context, registry *Registry, rollback *DeferredRollback) *ExecutionReport {
+	builder, err := registry.GetOperatorBuilder(rollback.Operator)
+	if err != nil {
+		return executionReportWithError(fmt.Errorf("rollback: %w", err), ROLLBACK, rollback.OperationID)
+	}
+
+	rollbackOperator, ok := builder(rollback.OperationID, rollback.Arguments).(RollbackOperator)
+	if !ok {
+		return executionReportWithError(
+			errors.New("rollback: operator does not support deferred rollbacks"),
+			ROLLBACK,
+			rollback.OperationID,
+		)
+	}
+
+	return rollbackOperator.RunRollback(ctx, rollback.State)
+}
+
+// DeferredRollbackStore persists the deferred rollbacks as JSON files in a directory,
+// one file per operation, so they survive agent restarts.
+type DeferredRollbackStore struct {
+	directory string
+}
+
+func NewDeferredRollbackStore(directory string) *DeferredRollbackStore {
+	return &DeferredRollbackStore{directory: directory}
+}
+
+func (s *DeferredRollbackStore) Save(rollback DeferredRollback) error {
+	path, err := s.path(rollback.OperationID)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.Marshal(rollback)
+	if err != nil {
+		return fmt.Errorf("error encoding deferred rollback: %w", err)
+	}
+
+	err = os.MkdirAll(s.directory, deferredRollbackDirMode)
+	if err != nil {
+		return fmt.Errorf("error creating deferred rollbacks directory %s: %w", s.directory, err)
+	}
+
+	err = os.WriteFile(path, content, deferredRollbackFileMode)
+	if err != nil {
+		return fmt.Errorf("error writing deferred rollback file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func (s *DeferredRollbackStore) Load(operationID string) (*DeferredRollback, error) {
+	path, err := s.path(operationID)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrDeferredRollbackNotFound, operationID)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error reading deferred rollback file %s: %w", path, err)
+	}
+
+	var rollback DeferredRollback
+
+	err = json.Unmarshal(content, &rollback)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding deferred rollback file %s: %w", path, err)
+	}
+
+	return &rollback, nil
+}
+
+func (s *DeferredRollbackStore) Delete(operationID string) error {
+	path, err := s.path(operationID)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error removing deferred rollback file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func (s *DeferredRollbackStore) path(operationID string) (string, error) {
+	if operationID == "" || operationID == "." || operationID == ".." ||
+		strings.ContainsAny(operationID, `/\`) {
+		return "", fmt.Errorf("invalid operation id %q", operationID)
+	}
+
+	return filepath.Join(s.directory, operationID+deferredRollbackFileExtension), nil
+}
@@ -0,0 +1,247 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/trento-project/agent/v3/internal/core/sapsystem/sapcontrolapi"
+	"github.com/trento-project/agent/v3/internal/core/sapsystem/sapcontrolapi/mocks"
+	"github.com/trento-project/agent/v3/internal/operations/operator"
+)
+
+type RollbackPolicyTestSuite struct {
+	suite.Suite
+
+	mockSapcontrol *mocks.MockWebService
+}
+
+func TestRollbackPolicy(t *testing.T) {
+	suite.Run(t, new(RollbackPolicyTestSuite))
+}
+
+func (suite *RollbackPolicyTestSuite) SetupTest() {
+	suite.mockSapcontrol = mocks.NewMockWebService(suite.T())
+}
+
+func (suite *RollbackPolicyTestSuite) newSAPSystemStart() *operator.Executor {
+	return operator.NewSAPSystemStart(
+		operator.Arguments{
+			"instance_number": "00",
+		},
+		"test-op",
+		operator.Options[operator.SAPSystemStart]{
+			OperatorOptions: []operator.Option[operator.SAPSystemStart]{
+				operator.Option[operator.SAPSystemStart](operator.WithCustomStartSystemSapcontrol(suite.mockSapcontrol)),
+				operator.Option[operator.SAPSystemStart](operator.WithCustomStartSystemInterval(0 * time.Second)),
+			},
+		},
+	)
+}
+
+func (suite *RollbackPolicyTestSuite) TestParseRollbackPolicy() {
+	cases := map[string]operator.RollbackPolicy{
+		"":         operator.RollbackPolicyAuto,
+		"auto":     operator.RollbackPolicyAuto,
+		"never":    operator.RollbackPolicyNever,
+		"deferred": operator.RollbackPolicyDeferred,
+	}
+
+	for value, expected := range cases {
+		policy, err := operator.ParseRollbackPolicy(value)
+		suite.NoError(err)
+		suite.Equal(expected, policy)
+	}
+
+	_, err := operator.ParseRollbackPolicy("sometimes")
+	suite.EqualError(err, "invalid rollback policy sometimes, supported values: auto, never, deferred")
+}
+
+func (suite *RollbackPolicyTestSuite) TestRollbackPolicyNever() {
+	ctx := operator.ContextWithRollbackPolicy(context.Background(), operator.RollbackPolicyNever)
+	phaser := operator.NewMockphaser(suite.T())
+
+	planCall := phaser.On("plan", ctx).Return(false, nil)
+	commitCall := phaser.On("commit", ctx).Return(errors.New("error during commit")).NotBefore(planCall)
+	phaser.On("after", ctx).Return().Once().NotBefore(commitCall)
+
+	report := operator.NewExecutor(phaser, "operation-id", slog.Default()).Run(ctx)
+
+	phaser.AssertNotCalled(suite.T(), "rollback", mock.Anything)
+	suite.Nil(report.Success)
+	suite.Nil(report.RollbackState)
+	suite.Equal(operator.COMMIT, report.Error.ErrorPhase)
+	suite.Equal("commit: error during commit; rollback skipped by rollback policy", report.Error.Message)
+}
+
+func (suite *RollbackPolicyTestSuite) TestRollbackPolicyDeferredNotSupported() {
+	ctx := operator.ContextWithRollbackPolicy(context.Background(), operator.RollbackPolicyDeferred)
+	phaser := operator.NewMockphaser(suite.T())
+
+	executor := operator.NewExecutor(phaser, "operation-id", slog.Default())
+	report := executor.Run(ctx)
+
+	phaser.AssertNotCalled(suite.T(), "plan", mock.Anything)
+	suite.False(executor.SupportsDeferredRollback())
+	suite.Nil(report.Success)
+	suite.Nil(report.RollbackState)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal("plan: deferred rollback policy not supported by the operator", report.Error.Message)
+}
+
+func (suite *RollbackPolicyTestSuite) TestRollbackPolicyDeferred() {
+	ctx := operator.ContextWithRollbackPolicy(context.Background(), operator.RollbackPolicyDeferred)
+
+	planGetInstances := suite.mockSapcontrol.
+		On("GetSystemInstanceListContext", ctx, mock.Anything).
+		Return(
+			&sapcontrolapi.GetSystemInstanceListResponse{
+				Instances: []*sapcontrolapi.SAPInstance{
+					{
+						Dispstatus: sapcontrolapi.STATECOLOR_GRAY,
+					},
+				},
+			}, nil,
+		).
+		Once()
+
+	suite.mockSapcontrol.
+		On("StartSystemContext", ctx, mock.Anything).
+		Return(nil, errors.New("error starting")).
+		Once().
+		NotBefore(planGetInstances)
+
+	executor := suite.newSAPSystemStart()
+	report := executor.Run(ctx)
+
+	suite.True(executor.SupportsDeferredRollback())
+	suite.mockSapcontrol.AssertNotCalled(suite.T(), "StopSystemContext", mock.Anything, mock.Anything)
+	suite.Nil(report.Success)
+	suite.Equal(operator.COMMIT, report.Error.ErrorPhase)
+	suite.Equal("commit: error starting system: error starting; rollback deferred", report.Error.Message)
+	suite.Equal(map[string]any{"before": false}, report.RollbackState)
+}
+
+func (suite *RollbackPolicyTestSuite) TestRunRollback() {
+	ctx := context.Background()
+
+	stopSystem := suite.mockSapcontrol.
+		On("StopSystemContext", ctx, mock.Anything).
+		Return(nil, nil).
+		Once()
+
+	suite.mockSapcontrol.
+		On("GetSystemInstanceListContext", mock.Anything, mock.Anything).
+		Return(
+			&sapcontrolapi.GetSystemInstanceListResponse{
+				Instances: []*sapcontrolapi.SAPInstance{
+					{
+						Dispstatus: sapcontrolapi.STATECOLOR_GRAY,
+					},
+				},
+			}, nil,
+		).
+		Once().
+		NotBefore(stopSystem)
+
+	report := suite.newSAPSystemStart().RunRollback(ctx, map[string]any{"before": false})
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.ROLLBACK, report.Success.LastPhase)
+	suite.Equal(map[string]any{"before": nil, "after": nil}, report.Success.Diff)
+}
+
+func (suite *RollbackPolicyTestSuite) TestRunRollbackError() {
+	ctx := context.Background()
+
+	suite.mockSapcontrol.
+		On("StopSystemContext", ctx, mock.Anything).
+		Return(nil, errors.New("error stopping")).
+		Once()
+
+	report := suite.newSAPSystemStart().RunRollback(ctx, map[string]any{"before": false})
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.ROLLBACK, report.Error.ErrorPhase)
+	suite.Equal("rollback: error stopping system: error stopping", report.Error.Message)
+}
+
+func (suite *RollbackPolicyTestSuite) TestRunRollbackNotSupported() {
+	phaser := operator.NewMockphaser(suite.T())
+
+	report := operator.NewExecutor(phaser, "operation-id", slog.Default()).
+		RunRollback(context.Background(), map[string]any{})
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.ROLLBACK, report.Error.ErrorPhase)
+	suite.Equal("rollback: operator does not support deferred rollbacks", report.Error.Message)
+}
+
+func (suite *RollbackPolicyTestSuite) TestRunDeferredRollback() {
+	ctx := context.Background()
+
+	suite.mockSapcontrol.
+		On("StopSystemContext", ctx, mock.Anything).
+		Return(nil, errors.New("error stopping")).
+		Once()
+
+	registry := operator.NewRegistry(operator.BuildersTree{
+		operator.SapSystemStartOperatorName: map[string]operator.Builder{
+			"v1": func(_ string, _ operator.Arguments) operator.Operator {
+				return suite.newSAPSystemStart()
+			},
+		},
+	})
+
+	rollback := &operator.DeferredRollback{
+		OperationID: "test-op",
+		Operator:    "sapsystemstart@v1",
+		Arguments:   operator.Arguments{"instance_number": "00"},
+		State:       map[string]any{"before": false},
+	}
+
+	report := operator.RunDeferredRollback(ctx, registry, rollback)
+	suite.Equal("rollback: error stopping system: error stopping", report.Error.Message)
+
+	rollback.Operator = "unknown@v1"
+	report = operator.RunDeferredRollback(ctx, registry, rollback)
+	suite.Equal(operator.ROLLBACK, report.Error.ErrorPhase)
+	suite.Contains(report.Error.Message, "rollback: ")
+}
+
+func (suite *RollbackPolicyTestSuite) TestDeferredRollbackStore() {
+	store := operator.NewDeferredRollbackStore(suite.T().TempDir() + "/rollbacks")
+
+	rollback := operator.DeferredRollback{
+		OperationID: "test-op",
+		GroupID:     "test-group",
+		StepNumber:  1,
+		AgentID:     "agent-id",
+		Operator:    "sapsystemstart@v1",
+		Arguments:   operator.Arguments{"instance_number": "00"},
+		State:       map[string]any{"before": false},
+		Error:       "commit: error starting system: error starting; rollback deferred",
+		CreatedAt:   time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	suite.Require().NoError(store.Save(rollback))
+
+	loaded, err := store.Load("test-op")
+	suite.Require().NoError(err)
+	suite.Equal(rollback, *loaded)
+
+	suite.Require().NoError(store.Delete("test-op"))
+
+	_, err = store.Load("test-op")
+	suite.ErrorIs(err, operator.ErrDeferredRollbackNotFound)
+
+	_, err = store.Load("../test-op")
+	suite.EqualError(err, `invalid operation id "../test-op"`)
+}
@@ -0,0 +1,332 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	RunbookOperatorName = "runbook"
+)
+
+type RunbookOption Option[Runbook]
+
+type runbookStep struct {
+	operator  string
+	arguments Arguments
+}
+
+type runbookArguments struct {
+	steps []runbookStep
+}
+
+type runbookStepDiff struct {
+	Operator string `json:"operator"`
+	Diff     any    `json:"diff"`
+}
+
+type runbookDiffOutput struct {
+	Steps []runbookStepDiff `json:"steps"`
+}
+
+// runbookStepResult holds a step operator and the report of its execution.
+type runbookStepResult struct {
+	step     runbookStep
+	operator Operator
+	report   *ExecutionReport
+}
+
+// Runbook is an operator responsible for running an ordered list of operators in the host,
+// so maintenance procedures don't depend on the connectivity with the server between steps.
+//
+// The operator accepts the next arguments:
+// - steps (list): The operators to run in order. Each step is a map with the next fields:
+//   - operator (string): The operator name, with an optional version in the
+//                        <operatorName>@<version> syntax. The latest version is used if omitted.
+//   - arguments (map): The arguments of the operator.
+//
+// Example: {"steps": [
+//   {"operator": "clustermaintenancechange@v1", "arguments": {"maintenance": true}},
+//   {"operator": "sapsystemstop@v1", "arguments": {"instance_number": "00"}}
+// ]}
+//
+// Runbooks cannot be nested.
+//
+// # Execution Phases
+//
+// - PLAN:
+//   The steps are parsed and the operators are built from the registry.
+//   The operation fails if any of the operators doesn't exist, before running any step.
+//
+// - COMMIT:
+//   The steps are run in order, each of them going through its own phases.
+//   The execution stops at the first failed step. The failed step rolls back its own changes.
+//
+// - VERIFY:
+//   Each step verifies its own changes, so it only checks that all the steps were run.
+//
+// - ROLLBACK:
+//   The completed steps are rolled back in reverse order. Steps skipped during their PLAN phase
+//   are not rolled back, as they didn't change anything. The rollback stops at the first
+//   step failing to roll back, as the next steps most likely depend on it.
+//   The steps keep their resources, like the systemd connection, until the runbook finishes,
+//   so they can still be rolled back.
+//   The rollback policy applies to the steps as well. Runbook rollbacks cannot be deferred,
+//   so runbooks refuse the deferred policy before PLAN.
+//
+// The diff contains the diff of each step, in the same order.
+
+type Runbook struct {
+	baseOperator
+
+	operationID     string
+	registry        *Registry
+	parsedArguments *runbookArguments
+	stepOperators   []Operator
+	completedSteps  []runbookStepResult
+}
+
+func WithRunbookRegistry(registry *Registry) RunbookOption {
+	return func(o *Runbook) {
+		o.registry = registry
+	}
+}
+
+func NewRunbook(
+	arguments Arguments,
+	operationID string,
+	options Options[Runbook],
+) *Executor {
+	runbook := &Runbook{
+		baseOperator: newBaseOperator(
+			RunbookOperatorName, operationID, arguments, options.BaseOperatorOptions...,
+		),
+		operationID: operationID,
+	}
+
+	for _, opt := range options.OperatorOptions {
+		opt(runbook)
+	}
+
+	return &Executor{
+		phaser:      runbook,
+		operationID: operationID,
+		logger:      runbook.logger,
+	}
+}
+
+func (r *Runbook) plan(_ context.Context) (bool, error) {
+	if r.registry == nil {
+		return false, errors.New("operators registry not available")
+	}
+
+	opArguments, err := parseRunbookArguments(r.arguments)
+	if err != nil {
+		return false, err
+	}
+
+	r.parsedArguments = opArguments
+	r.stepOperators = make([]Operator, 0, len(opArguments.steps))
+
+	for index, step := range opArguments.steps {
+		builder, err := r.registry.GetOperatorBuilder(step.operator)
+		if err != nil {
+			return false, fmt.Errorf("step %d: %w", index, err)
+		}
+
+		// Each step has its own operation ID, as some operators use it to name their backups
+		stepOperationID := fmt.Sprintf("%s-%d", r.operationID, index)
+		stepOperator := builder(stepOperationID, step.arguments)
+
+		// The steps keep their resources until the runbook finishes, as they might be rolled back
+		if stepExecutor, ok := stepOperator.(*Executor); ok {
+			stepExecutor.delayAfter = true
+		}
+
+		r.stepOperators = append(r.stepOperators, stepOperator)
+	}
+
+	return false, nil
+}
+
+func (r *Runbook) commit(ctx context.Context) error {
+	for index, step := range r.parsedArguments.steps {
+		r.logger.Info("running runbook step", "step", index, "operator", step.operator)
+
+		stepOperator := r.stepOperators[index]
+		report := stepOperator.Run(ctx)
+
+		if report.Error != nil {
+			return fmt.Errorf(
+				"step %d (%s) failed in phase %s: %s",
+				index, step.operator, report.Error.ErrorPhase, report.Error.Message,
+			)
+		}
+
+		r.completedSteps = append(r.completedSteps, runbookStepResult{
+			step:     step,
+			operator: stepOperator,
+			report:   report,
+		})
+	}
+
+	return nil
+}
+
+func (r *Runbook) verify(_ context.Context) error {
+	if len(r.completedSteps) != len(r.parsedArguments.steps) {
+		return fmt.Errorf(
+			"only %d of %d steps were completed",
+			len(r.completedSteps), len(r.parsedArguments.steps),
+		)
+	}
+
+	return nil
+}
+
+func (r *Runbook) rollback(ctx context.Context) error {
+	for index := len(r.completedSteps) - 1; index >= 0; index-- {
+		result := r.completedSteps[index]
+
+		if result.report.Success.LastPhase == PLAN {
+			continue
+		}
+
+		stepExecutor, ok := result.operator.(*Executor)
+		if !ok {
+			r.logger.Warn("runbook step doesn't support rollback, skipping",
+				"step", index, "operator", result.step.operator)
+
+			continue
+		}
+
+		r.logger.Info("rolling back runbook step", "step", index, "operator", result.step.operator)
+
+		err := stepExecutor.phaser.rollback(ctx)
+		if err != nil {
+			return fmt.Errorf("step %d (%s): %w", index, result.step.operator, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Runbook) after(ctx context.Context) {
+	for index := len(r.stepOperators) - 1; index >= 0; index-- {
+		if stepExecutor, ok := r.stepOperators[index].(*Executor); ok {
+			stepExecutor.runAfter(ctx)
+		}
+	}
+}
+
+func (r *Runbook) operationDiff(_ context.Context) map[string]any {
+	diff := make(map[string]any)
+
+	before := runbookDiffOutput{Steps: make([]runbookStepDiff, 0, len(r.completedSteps))}
+	after := runbookDiffOutput{Steps: make([]runbookStepDiff, 0, len(r.completedSteps))}
+
+	for _, result := range r.completedSteps {
+		stepDiff := result.report.Success.Diff
+
+		before.Steps = append(before.Steps, runbookStepDiff{
+			Operator: result.step.operator,
+			Diff:     runbookStepDiffValue(stepDiff[beforeDiffField]),
+		})
+		after.Steps = append(after.Steps, runbookStepDiff{
+			Operator: result.step.operator,
+			Diff:     runbookStepDiffValue(stepDiff[afterDiffField]),
+		})
+	}
+
+	beforeJSON, err := json.Marshal(before)
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling before diff output: %v", err))
+	}
+
+	diff[beforeDiffField] = string(beforeJSON)
+
+	afterJSON, err := json.Marshal(after)
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling after diff output: %v", err))
+	}
+
+	diff[afterDiffField] = string(afterJSON)
+
+	return diff
+}
+
+// runbookStepDiffValue embeds the diff of a step as JSON if possible,
+// instead of nesting an encoded JSON string.
+func runbookStepDiffValue(value any) any {
+	stringValue, ok := value.(string)
+	if !ok || !json.Valid([]byte(stringValue)) {
+		return value
+	}
+
+	return json.RawMessage(stringValue)
+}
+
+func parseRunbookArguments(rawArguments Arguments) (*runbookArguments, error) {
+	stepsArgument, found := rawArguments["steps"]
+	if !found {
+		return nil, errors.New("argument steps not provided, could not use the operator")
+	}
+
+	rawSteps, ok := stepsArgument.([]any)
+	if !ok || len(rawSteps) == 0 {
+		return nil, fmt.Errorf(
+			"could not parse steps argument as a non empty list, argument provided: %v",
+			stepsArgument,
+		)
+	}
+
+	steps := make([]runbookStep, 0, len(rawSteps))
+
+	for index, rawStep := range rawSteps {
+		stepMap, ok := rawStep.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("could not parse step %d as a map, step provided: %v", index, rawStep)
+		}
+
+		operatorName, ok := stepMap["operator"].(string)
+		if !ok || operatorName == "" {
+			return nil, fmt.Errorf("could not parse step %d operator, operator provided: %v",
+				index, stepMap["operator"])
+		}
+
+		name, _, err := extractOperatorNameAndVersion(operatorName)
+		if err != nil {
+			return nil, fmt.Errorf("step %d: %w", index, err)
+		}
+
+		if name == RunbookOperatorName {
+			return nil, fmt.Errorf("step %d: runbooks cannot be nested", index)
+		}
+
+		arguments := Arguments{}
+
+		if rawArguments, found := stepMap["arguments"]; found {
+			argumentsMap, ok := rawArguments.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("could not parse step %d arguments as a map, arguments provided: %v",
+					index, rawArguments)
+			}
+
+			arguments = Arguments(argumentsMap)
+		}
+
+		steps = append(steps, runbookStep{
+			operator:  operatorName,
+			arguments: arguments,
+		})
+	}
+
+	return &runbookArguments{
+		steps: steps,
+	}, nil
+}
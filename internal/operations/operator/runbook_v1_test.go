@@ -0,0 +1,314 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	systemdMocks "github.com/trento-project/agent/v3/internal/core/systemd/mocks"
+	"github.com/trento-project/agent/v3/internal/operations/operator"
+)
+
+type RunbookOperatorTestSuite struct {
+	suite.Suite
+
+	phasers map[string]*operator.Mockphaser
+}
+
+func TestRunbookOperator(t *testing.T) {
+	suite.Run(t, new(RunbookOperatorTestSuite))
+}
+
+func (suite *RunbookOperatorTestSuite) SetupTest() {
+	suite.phasers = map[string]*operator.Mockphaser{
+		"first":  operator.NewMockphaser(suite.T()),
+		"second": operator.NewMockphaser(suite.T()),
+		"third":  operator.NewMockphaser(suite.T()),
+	}
+}
+
+// registry registers the mocked phasers as step operators
+func (suite *RunbookOperatorTestSuite) registry() *operator.Registry {
+	tree := operator.BuildersTree{}
+
+	for name, phaser := range suite.phasers {
+		tree[name] = map[string]operator.Builder{
+			"v1": func(operationID string, _ operator.Arguments) operator.Operator {
+				return operator.NewExecutor(phaser, operationID, slog.Default())
+			},
+		}
+	}
+
+	return operator.NewRegistry(tree)
+}
+
+func (suite *RunbookOperatorTestSuite) expectSuccessfulStep(name string, diff map[string]any) {
+	phaser := suite.phasers[name]
+
+	phaser.On("plan", mock.Anything).Return(false, nil).Once()
+	phaser.On("commit", mock.Anything).Return(nil).Once()
+	phaser.On("verify", mock.Anything).Return(nil).Once()
+	phaser.On("operationDiff", mock.Anything).Return(diff).Once()
+	phaser.On("after", mock.Anything).Return().Once()
+}
+
+func runbookSteps(names ...string) operator.Arguments {
+	steps := []any{}
+	for _, name := range names {
+		steps = append(steps, map[string]any{
+			"operator":  name,
+			"arguments": map[string]any{"key": "value"},
+		})
+	}
+
+	return operator.Arguments{"steps": steps}
+}
+
+func (suite *RunbookOperatorTestSuite) TestRunbookPlanErrorRegistryNotAvailable() {
+	report := operator.NewRunbook(
+		runbookSteps("first@v1"),
+		"test-op",
+		operator.Options[operator.Runbook]{},
+	).Run(context.Background())
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal("plan: operators registry not available", report.Error.Message)
+}
+
+func (suite *RunbookOperatorTestSuite) TestRunbookPlanErrorParsingArguments() {
+	cases := []struct {
+		arguments    operator.Arguments
+		errorMessage string
+	}{
+		{
+			arguments:    operator.Arguments{},
+			errorMessage: "plan: argument steps not provided, could not use the operator",
+		},
+		{
+			arguments:    operator.Arguments{"steps": []any{}},
+			errorMessage: "plan: could not parse steps argument as a non empty list, argument provided: []",
+		},
+		{
+			arguments:    operator.Arguments{"steps": []any{"first@v1"}},
+			errorMessage: "plan: could not parse step 0 as a map, step provided: first@v1",
+		},
+		{
+			arguments:    operator.Arguments{"steps": []any{map[string]any{"arguments": map[string]any{}}}},
+			errorMessage: "plan: could not parse step 0 operator, operator provided: <nil>",
+		},
+		{
+			arguments: operator.Arguments{"steps": []any{
+				map[string]any{"operator": "first@v1", "arguments": "value"},
+			}},
+			errorMessage: "plan: could not parse step 0 arguments as a map, arguments provided: value",
+		},
+		{
+			arguments:    runbookSteps("first@v1", "runbook@v1"),
+			errorMessage: "plan: step 1: runbooks cannot be nested",
+		},
+		{
+			arguments:    runbookSteps("first@v1", "unknown@v1"),
+			errorMessage: "plan: step 1: operator unknown@v1 not found",
+		},
+	}
+
+	for _, tc := range cases {
+		report := operator.NewRunbook(
+			tc.arguments,
+			"test-op",
+			operator.Options[operator.Runbook]{
+				OperatorOptions: []operator.Option[operator.Runbook]{
+					operator.Option[operator.Runbook](operator.WithRunbookRegistry(suite.registry())),
+				},
+			},
+		).Run(context.Background())
+
+		suite.Nil(report.Success)
+		suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+		suite.Equal(tc.errorMessage, report.Error.Message)
+	}
+}
+
+func (suite *RunbookOperatorTestSuite) TestRunbookSuccess() {
+	suite.expectSuccessfulStep("first", map[string]any{
+		"before": `{"maintenance":false}`,
+		"after":  `{"maintenance":true}`,
+	})
+
+	skippedDiff := map[string]any{
+		"before": `{"stopped":true}`,
+		"after":  `{"stopped":true}`,
+	}
+	suite.phasers["second"].On("plan", mock.Anything).Return(true, nil).Once()
+	suite.phasers["second"].On("operationDiff", mock.Anything).Return(skippedDiff).Once()
+	suite.phasers["second"].On("after", mock.Anything).Return().Once()
+
+	report := operator.NewRunbook(
+		runbookSteps("first@v1", "second"),
+		"test-op",
+		operator.Options[operator.Runbook]{
+			OperatorOptions: []operator.Option[operator.Runbook]{
+				operator.Option[operator.Runbook](operator.WithRunbookRegistry(suite.registry())),
+			},
+		},
+	).Run(context.Background())
+
+	expectedDiff := map[string]any{
+		"before": `{"steps":[` +
+			`{"operator":"first@v1","diff":{"maintenance":false}},` +
+			`{"operator":"second","diff":{"stopped":true}}]}`,
+		"after": `{"steps":[` +
+			`{"operator":"first@v1","diff":{"maintenance":true}},` +
+			`{"operator":"second","diff":{"stopped":true}}]}`,
+	}
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.VERIFY, report.Success.LastPhase)
+	suite.Equal(expectedDiff, report.Success.Diff)
+}
+
+func (suite *RunbookOperatorTestSuite) TestRunbookStepFailedRollback() {
+	firstCommit := suite.phasers["first"].On("commit", mock.Anything).Return(nil).Once()
+	suite.phasers["first"].On("plan", mock.Anything).Return(false, nil).Once()
+	suite.phasers["first"].On("verify", mock.Anything).Return(nil).Once()
+	suite.phasers["first"].On("operationDiff", mock.Anything).Return(map[string]any{}).Once()
+	suite.phasers["first"].On("after", mock.Anything).Return().Once()
+
+	suite.phasers["second"].On("plan", mock.Anything).Return(true, nil).Once()
+	suite.phasers["second"].On("operationDiff", mock.Anything).Return(map[string]any{}).Once()
+	suite.phasers["second"].On("after", mock.Anything).Return().Once()
+
+	suite.phasers["third"].On("plan", mock.Anything).Return(false, nil).Once()
+	thirdCommit := suite.phasers["third"].On("commit", mock.Anything).
+		Return(errors.New("error stopping")).
+		Once()
+	thirdRollback := suite.phasers["third"].On("rollback", mock.Anything).
+		Return(nil).
+		Once().
+		NotBefore(thirdCommit)
+	suite.phasers["third"].On("after", mock.Anything).Return().Once()
+
+	suite.phasers["first"].On("rollback", mock.Anything).
+		Return(nil).
+		Once().
+		NotBefore(firstCommit, thirdRollback)
+
+	report := operator.NewRunbook(
+		runbookSteps("first@v1", "second@v1", "third@v1"),
+		"test-op",
+		operator.Options[operator.Runbook]{
+			OperatorOptions: []operator.Option[operator.Runbook]{
+				operator.Option[operator.Runbook](operator.WithRunbookRegistry(suite.registry())),
+			},
+		},
+	).Run(context.Background())
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.COMMIT, report.Error.ErrorPhase)
+	suite.Equal(
+		"commit: step 2 (third@v1) failed in phase COMMIT: commit: error stopping",
+		report.Error.Message,
+	)
+	suite.phasers["second"].AssertNotCalled(suite.T(), "rollback", mock.Anything)
+}
+
+func (suite *RunbookOperatorTestSuite) TestRunbookStepFailedRollbackError() {
+	suite.expectSuccessfulStep("first", map[string]any{})
+	suite.expectSuccessfulStep("second", map[string]any{})
+
+	suite.phasers["third"].On("plan", mock.Anything).
+		Return(false, errors.New("argument instance_number not provided")).
+		Once()
+
+	suite.phasers["second"].On("rollback", mock.Anything).
+		Return(errors.New("error starting")).
+		Once()
+
+	report := operator.NewRunbook(
+		runbookSteps("first@v1", "second@v1", "third@v1"),
+		"test-op",
+		operator.Options[operator.Runbook]{
+			OperatorOptions: []operator.Option[operator.Runbook]{
+				operator.Option[operator.Runbook](operator.WithRunbookRegistry(suite.registry())),
+			},
+		},
+	).Run(context.Background())
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.ROLLBACK, report.Error.ErrorPhase)
+	suite.Equal(
+		"commit: step 2 (third@v1) failed in phase PLAN: plan: argument instance_number not provided; "+
+			"rollback: step 1 (second@v1): error starting",
+		report.Error.Message,
+	)
+	suite.phasers["first"].AssertNotCalled(suite.T(), "rollback", mock.Anything)
+}
+
+func (suite *RunbookOperatorTestSuite) TestRunbookStepRollbackBeforeReleasingResources() {
+	ctx := context.Background()
+	mockSystemd := systemdMocks.NewMockSystemd(suite.T())
+	mockSystemdLoader := systemdMocks.NewMockLoader(suite.T())
+
+	mockSystemdLoader.On("NewSystemd", ctx, mock.Anything).Return(mockSystemd, nil).Once()
+	mockSystemd.On("IsActive", ctx, "chronyd.service").Return(true, nil).Once()
+	mockSystemd.On("Stop", ctx, "chronyd.service").Return(nil).Once()
+	mockSystemd.On("IsActive", mock.Anything, "chronyd.service").Return(false, nil).Once()
+
+	rollbackStart := mockSystemd.On("Start", ctx, "chronyd.service").Return(nil).Once()
+	rollbackCheck := mockSystemd.On("IsActive", mock.Anything, "chronyd.service").
+		Return(true, nil).
+		Once().
+		NotBefore(rollbackStart)
+	mockSystemd.On("Close").Return().Once().NotBefore(rollbackCheck)
+
+	suite.phasers["first"].On("plan", mock.Anything).Return(false, nil).Once()
+	suite.phasers["first"].On("commit", mock.Anything).Return(errors.New("error stopping")).Once()
+	suite.phasers["first"].On("rollback", mock.Anything).Return(nil).Once()
+	suite.phasers["first"].On("after", mock.Anything).Return().Once()
+
+	registry := suite.registry()
+	registry.AddOperators(operator.BuildersTree{
+		operator.ServiceStateOperatorName: map[string]operator.Builder{
+			"v1": func(operationID string, arguments operator.Arguments) operator.Operator {
+				return operator.NewServiceState(
+					arguments,
+					operationID,
+					operator.Options[operator.ServiceState]{
+						OperatorOptions: []operator.Option[operator.ServiceState]{
+							operator.Option[operator.ServiceState](operator.WithCustomServiceStateSystemdLoader(mockSystemdLoader)),
+							operator.Option[operator.ServiceState](operator.WithCustomServiceStateInterval(0 * time.Second)),
+						},
+					},
+				)
+			},
+		},
+	})
+
+	report := operator.NewRunbook(
+		operator.Arguments{"steps": []any{
+			map[string]any{
+				"operator":  "servicestate@v1",
+				"arguments": map[string]any{"unit": "chronyd", "state": "stopped"},
+			},
+			map[string]any{"operator": "first@v1"},
+		}},
+		"test-op",
+		operator.Options[operator.Runbook]{
+			OperatorOptions: []operator.Option[operator.Runbook]{
+				operator.Option[operator.Runbook](operator.WithRunbookRegistry(registry)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.COMMIT, report.Error.ErrorPhase)
+	suite.Equal("commit: step 1 (first@v1) failed in phase COMMIT: commit: error stopping", report.Error.Message)
+}
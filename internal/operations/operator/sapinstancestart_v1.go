@@ -94,10 +94,10 @@ func NewSAPInstanceStart(
 	}
 }
 
-func (s *SAPInstanceStart) plan(ctx context.Context) (bool, error) {
+func (s *SAPInstanceStart) setup(_ context.Context) error {
 	opArguments, err := parseSAPStateChangeArguments(s.arguments)
 	if err != nil {
-		return false, err
+		return err
 	}
 
 	s.parsedArguments = opArguments
@@ -107,6 +107,15 @@ func (s *SAPInstanceStart) plan(ctx context.Context) (bool, error) {
 		s.sapControlConnector = sapcontrolapi.NewWebServiceUnix(s.parsedArguments.instNumber)
 	}
 
+	return nil
+}
+
+func (s *SAPInstanceStart) plan(ctx context.Context) (bool, error) {
+	err := s.setup(ctx)
+	if err != nil {
+		return false, err
+	}
+
 	started, err := allProcessesInState(ctx, s.sapControlConnector, sapcontrolapi.STATECOLOR_GREEN)
 	if err != nil {
 		return false, fmt.Errorf("error checking processes state: %w", err)
@@ -288,6 +297,27 @@ func sleepContext(ctx context.Context, interval time.Duration) error {
 	}
 }
 
+// parseTimeoutArgument parses the optional timeout argument, given in seconds.
+// It accepts the same values as the number type of the arguments schema.
+func parseTimeoutArgument(rawArguments Arguments, defaultTimeout time.Duration) (time.Duration, error) {
+	timeoutArgument, found := rawArguments["timeout"]
+	if !found {
+		return defaultTimeout, nil
+	}
+
+	switch timeout := timeoutArgument.(type) {
+	case float64:
+		return time.Duration(timeout * float64(time.Second)), nil
+	case int:
+		return time.Duration(timeout) * time.Second, nil
+	default:
+		return 0, fmt.Errorf(
+			"could not parse timeout argument as a number, argument provided: %v",
+			timeoutArgument,
+		)
+	}
+}
+
 func parseSAPStateChangeArguments(rawArguments Arguments) (*sapStateChangeArguments, error) {
 	instNumberArgument, found := rawArguments["instance_number"]
 	if !found {
@@ -302,18 +332,9 @@ func parseSAPStateChangeArguments(rawArguments Arguments) (*sapStateChangeArgume
 		)
 	}
 
-	timeout := defaultSapInstanceStateTimeout
-
-	if timeoutArgument, found := rawArguments["timeout"]; found {
-		timeoutFloat, ok := timeoutArgument.(float64)
-		if !ok {
-			return nil, fmt.Errorf(
-				"could not parse timeout argument as a number, argument provided: %v",
-				timeoutArgument,
-			)
-		}
-
-		timeout = time.Duration(timeoutFloat) * time.Second
+	timeout, err := parseTimeoutArgument(rawArguments, defaultSapInstanceStateTimeout)
+	if err != nil {
+		return nil, err
 	}
 
 	return &sapStateChangeArguments{
@@ -310,6 +310,49 @@ func (suite *SAPInstanceStartOperatorTestSuite) TestSAPInstanceStartVerifyTimeou
 			" rollback: error waiting until instance is in desired state", report.Error.Message)
 }
 
+func (suite *SAPInstanceStartOperatorTestSuite) TestSAPInstanceStartVerifyTimeoutIntegerArgument() {
+	ctx := context.Background()
+
+	suite.mockSapcontrol.
+		On("GetProcessListContext", mock.Anything, mock.Anything).
+		Return(
+			&sapcontrolapi.GetProcessListResponse{
+				Processes: []*sapcontrolapi.OSProcess{
+					{
+						Dispstatus: sapcontrolapi.STATECOLOR_GRAY,
+					},
+				},
+			}, nil,
+		).
+		Times(3).
+		On("StartContext", ctx, mock.Anything).
+		Return(nil, nil).
+		On("StopContext", ctx, mock.Anything).
+		Return(nil, nil)
+
+	sapInstanceStartOperator := operator.NewSAPInstanceStart(
+		operator.Arguments{
+			"instance_number": "00",
+			"timeout":         0,
+		},
+		"test-op",
+		operator.Options[operator.SAPInstanceStart]{
+			OperatorOptions: []operator.Option[operator.SAPInstanceStart]{
+				operator.Option[operator.SAPInstanceStart](operator.WithCustomStartSapcontrol(suite.mockSapcontrol)),
+				operator.Option[operator.SAPInstanceStart](operator.WithCustomStartInterval(0 * time.Second)),
+			},
+		},
+	)
+
+	report := sapInstanceStartOperator.Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.ROLLBACK, report.Error.ErrorPhase)
+	suite.Equal(
+		"verify: error waiting until instance is in desired state;"+
+			" rollback: error waiting until instance is in desired state", report.Error.Message)
+}
+
 func (suite *SAPInstanceStartOperatorTestSuite) TestSAPInstanceStartRollbackStoppingError() {
 	ctx := context.Background()
 
@@ -86,10 +86,10 @@ func NewSAPInstanceStop(
 	}
 }
 
-func (s *SAPInstanceStop) plan(ctx context.Context) (bool, error) {
+func (s *SAPInstanceStop) setup(_ context.Context) error {
 	opArguments, err := parseSAPStateChangeArguments(s.arguments)
 	if err != nil {
-		return false, err
+		return err
 	}
 
 	s.parsedArguments = opArguments
@@ -99,6 +99,15 @@ func (s *SAPInstanceStop) plan(ctx context.Context) (bool, error) {
 		s.sapControlConnector = sapcontrolapi.NewWebServiceUnix(s.parsedArguments.instNumber)
 	}
 
+	return nil
+}
+
+func (s *SAPInstanceStop) plan(ctx context.Context) (bool, error) {
+	err := s.setup(ctx)
+	if err != nil {
+		return false, err
+	}
+
 	stopped, err := allProcessesInState(ctx, s.sapControlConnector, sapcontrolapi.STATECOLOR_GRAY)
 	if err != nil {
 		return false, fmt.Errorf("error checking processes state: %w", err)
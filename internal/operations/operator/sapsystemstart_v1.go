@@ -105,10 +105,10 @@ func NewSAPSystemStart(
 	}
 }
 
-func (s *SAPSystemStart) plan(ctx context.Context) (bool, error) {
+func (s *SAPSystemStart) setup(_ context.Context) error {
 	opArguments, err := parseSAPSystemStateChangeArguments(s.arguments)
 	if err != nil {
-		return false, err
+		return err
 	}
 
 	s.parsedArguments = opArguments
@@ -118,6 +118,15 @@ func (s *SAPSystemStart) plan(ctx context.Context) (bool, error) {
 		s.sapControlConnector = sapcontrolapi.NewWebServiceUnix(s.parsedArguments.instNumber)
 	}
 
+	return nil
+}
+
+func (s *SAPSystemStart) plan(ctx context.Context) (bool, error) {
+	err := s.setup(ctx)
+	if err != nil {
+		return false, err
+	}
+
 	started, err := allInstancesInState(
 		ctx,
 		s.sapControlConnector,
@@ -88,10 +88,10 @@ func NewSAPSystemStop(
 	}
 }
 
-func (s *SAPSystemStop) plan(ctx context.Context) (bool, error) {
+func (s *SAPSystemStop) setup(_ context.Context) error {
 	opArguments, err := parseSAPSystemStateChangeArguments(s.arguments)
 	if err != nil {
-		return false, err
+		return err
 	}
 
 	s.parsedArguments = opArguments
@@ -101,6 +101,15 @@ func (s *SAPSystemStop) plan(ctx context.Context) (bool, error) {
 		s.sapControlConnector = sapcontrolapi.NewWebServiceUnix(s.parsedArguments.instNumber)
 	}
 
+	return nil
+}
+
+func (s *SAPSystemStop) plan(ctx context.Context) (bool, error) {
+	err := s.setup(ctx)
+	if err != nil {
+		return false, err
+	}
+
 	stopped, err := allInstancesInState(
 		ctx,
 		s.sapControlConnector,
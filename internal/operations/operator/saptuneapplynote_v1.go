@@ -0,0 +1,260 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/trento-project/agent/v3/internal/core/saptune"
+	"github.com/trento-project/agent/v3/pkg/utils"
+)
+
+type saptuneNoteArguments struct {
+	note string
+}
+
+type saptuneNoteDiffOutput struct {
+	Note      string `json:"note"`
+	Enabled   bool   `json:"enabled"`
+	Compliant bool   `json:"compliant"`
+}
+
+func parseSaptuneNoteArguments(rawArguments Arguments) (*saptuneNoteArguments, error) {
+	argument, found := rawArguments["note"]
+	if !found {
+		return nil, errors.New("argument note not provided, could not use the operator")
+	}
+
+	note, ok := argument.(string)
+	if !ok {
+		return nil, fmt.Errorf(
+			"could not parse note argument as string, argument provided: %v",
+			argument,
+		)
+	}
+
+	if note == "" {
+		return nil, errors.New("note argument is empty")
+	}
+
+	return &saptuneNoteArguments{
+		note: note,
+	}, nil
+}
+
+// getSaptuneNoteState returns if the note is enabled and if the system is compliant with it
+// using the saptune note verify output.
+func getSaptuneNoteState(
+	ctx context.Context,
+	saptuneClient saptune.Saptune,
+	note string,
+) (saptuneNoteDiffOutput, error) {
+	state := saptuneNoteDiffOutput{Note: note}
+
+	output, err := saptuneClient.VerifyNote(ctx)
+	if err != nil {
+		return state, err
+	}
+
+	for _, enabledNote := range gjson.GetBytes(output, "result.Notes enabled").Array() {
+		if enabledNote.String() == note {
+			state.Enabled = true
+		}
+	}
+
+	if !state.Enabled {
+		return state, nil
+	}
+
+	state.Compliant = true
+
+	for _, verification := range gjson.GetBytes(output, "result.verifications").Array() {
+		if verification.Get("Note ID").String() == note && !verification.Get("compliant").Bool() {
+			state.Compliant = false
+		}
+	}
+
+	return state, nil
+}
+
+func computeSaptuneNoteOperationDiff(resources map[string]any) map[string]any {
+	diff := make(map[string]any)
+
+	beforeState, ok := resources[beforeDiffField].(saptuneNoteDiffOutput)
+	if !ok {
+		panic(fmt.Sprintf("invalid beforeState value: cannot parse '%v' to note state",
+			resources[beforeDiffField]))
+	}
+
+	afterState, ok := resources[afterDiffField].(saptuneNoteDiffOutput)
+	if !ok {
+		panic(fmt.Sprintf("invalid afterState value: cannot parse '%v' to note state",
+			resources[afterDiffField]))
+	}
+
+	before, err := json.Marshal(beforeState)
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling before diff output: %v", err))
+	}
+
+	diff[beforeDiffField] = string(before)
+
+	after, err := json.Marshal(afterState)
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling after diff output: %v", err))
+	}
+
+	diff[afterDiffField] = string(after)
+
+	return diff
+}
+
+const SaptuneApplyNoteOperatorName = "saptuneapplynote"
+
+type SaptuneApplyNoteOption Option[SaptuneApplyNote]
+
+// SaptuneApplyNote is an operator responsible for applying a single saptune note,
+// on top of the applied solution if any.
+//
+// The operator requires an argument in the form of a map containing a key named "note"
+// with the note ID, e.g. {"note": "2382421"}.
+//
+// # Execution Phases
+//
+// - PLAN:
+//   The operator checks for the presence of the saptune binary and verifies its version.
+//   The minimum required version is 3.1.0.
+//   The operation fails if the note is not available in saptune.
+//   The note state is collected as the "before" diff from the saptune note verify output.
+//   If the note is already enabled, no action is taken.
+//
+// - COMMIT:
+//   The saptune command to apply the note is executed.
+//
+// - VERIFY:
+//   The operator verifies whether the note is enabled using the saptune note verify output.
+//   If not, an error is raised. If successful, the note state, including the compliance
+//   of the system with the note, is collected as the "after" diff.
+//
+// - ROLLBACK:
+//   If an error occurs during the COMMIT or VERIFY phase, the note is reverted.
+
+type SaptuneApplyNote struct {
+	baseOperator
+
+	saptune         saptune.Saptune
+	parsedArguments *saptuneNoteArguments
+}
+
+func WithSaptuneClientApplyNote(saptuneClient saptune.Saptune) SaptuneApplyNoteOption {
+	return func(o *SaptuneApplyNote) {
+		o.saptune = saptuneClient
+	}
+}
+
+func NewSaptuneApplyNote(
+	arguments Arguments,
+	operationID string,
+	options Options[SaptuneApplyNote],
+) *Executor {
+	saptuneApplyNote := &SaptuneApplyNote{
+		baseOperator: newBaseOperator(
+			SaptuneApplyNoteOperatorName, operationID, arguments, options.BaseOperatorOptions...,
+		),
+	}
+
+	saptuneApplyNote.saptune = saptune.NewSaptuneClient(
+		utils.Executor{},
+		saptuneApplyNote.logger,
+	)
+
+	for _, opt := range options.OperatorOptions {
+		opt(saptuneApplyNote)
+	}
+
+	return &Executor{
+		phaser:      saptuneApplyNote,
+		operationID: operationID,
+		logger:      saptuneApplyNote.logger,
+	}
+}
+
+func (sa *SaptuneApplyNote) plan(ctx context.Context) (bool, error) {
+	opArguments, err := parseSaptuneNoteArguments(sa.arguments)
+	if err != nil {
+		return false, err
+	}
+
+	sa.parsedArguments = opArguments
+
+	err = sa.saptune.CheckVersionSupport(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	notesList, err := sa.saptune.ListNote(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	available := false
+
+	for _, availableNote := range gjson.GetBytes(notesList, "result.Notes available.#.Note ID").Array() {
+		if availableNote.String() == sa.parsedArguments.note {
+			available = true
+		}
+	}
+
+	if !available {
+		return false, fmt.Errorf("note %s is not available", sa.parsedArguments.note)
+	}
+
+	noteState, err := getSaptuneNoteState(ctx, sa.saptune, sa.parsedArguments.note)
+	if err != nil {
+		return false, err
+	}
+
+	sa.resources[beforeDiffField] = noteState
+
+	if noteState.Enabled {
+		sa.logger.Info("note is already applied, skipping operation", "note", sa.parsedArguments.note)
+		sa.resources[afterDiffField] = noteState
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (sa *SaptuneApplyNote) commit(ctx context.Context) error {
+	return sa.saptune.ApplyNote(ctx, sa.parsedArguments.note)
+}
+
+func (sa *SaptuneApplyNote) verify(ctx context.Context) error {
+	noteState, err := getSaptuneNoteState(ctx, sa.saptune, sa.parsedArguments.note)
+	if err != nil {
+		return err
+	}
+
+	if !noteState.Enabled {
+		return fmt.Errorf("the note %s was not applied", sa.parsedArguments.note)
+	}
+
+	sa.resources[afterDiffField] = noteState
+
+	return nil
+}
+
+func (sa *SaptuneApplyNote) rollback(ctx context.Context) error {
+	return sa.saptune.RevertNote(ctx, sa.parsedArguments.note)
+}
+
+func (sa *SaptuneApplyNote) operationDiff(_ context.Context) map[string]any {
+	return computeSaptuneNoteOperationDiff(sa.resources)
+}
@@ -0,0 +1,271 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/trento-project/agent/v3/internal/core/saptune/mocks"
+	"github.com/trento-project/agent/v3/internal/operations/operator"
+)
+
+const saptuneNoteListOutput = `{"result":{"Notes available":[` +
+	`{"Note ID":"1656250","Note description":"SAP on AWS: prerequisites - only Linux"},` +
+	`{"Note ID":"2382421","Note description":"Optimizing the Network Configuration on HANA- and OS-Level"}],` +
+	`"Notes enabled":["1656250"]}}`
+
+const saptuneNoteVerifyOutput = `{"result":{"verifications":[` +
+	`{"Note ID":"1656250","parameter":"net.ipv4.tcp_keepalive_time","compliant":true}],` +
+	`"Notes enabled":["1656250"],"system compliance":true}}`
+
+const saptuneNoteVerifyAppliedOutput = `{"result":{"verifications":[` +
+	`{"Note ID":"1656250","parameter":"net.ipv4.tcp_keepalive_time","compliant":true},` +
+	`{"Note ID":"2382421","parameter":"net.core.somaxconn","compliant":true},` +
+	`{"Note ID":"2382421","parameter":"net.ipv4.tcp_max_syn_backlog","compliant":false}],` +
+	`"Notes enabled":["1656250","2382421"],"system compliance":false}}`
+
+type SaptuneApplyNoteOperatorTestSuite struct {
+	suite.Suite
+
+	mockSaptuneClient *mocks.MockSaptune
+}
+
+func TestSaptuneApplyNoteOperator(t *testing.T) {
+	suite.Run(t, new(SaptuneApplyNoteOperatorTestSuite))
+}
+
+func (suite *SaptuneApplyNoteOperatorTestSuite) SetupTest() {
+	suite.mockSaptuneClient = mocks.NewMockSaptune(suite.T())
+}
+
+func (suite *SaptuneApplyNoteOperatorTestSuite) TestSaptuneApplyNotePlanErrorParsingArguments() {
+	cases := []struct {
+		arguments    operator.Arguments
+		errorMessage string
+	}{
+		{
+			arguments:    operator.Arguments{},
+			errorMessage: "plan: argument note not provided, could not use the operator",
+		},
+		{
+			arguments:    operator.Arguments{"note": 2382421},
+			errorMessage: "plan: could not parse note argument as string, argument provided: 2382421",
+		},
+		{
+			arguments:    operator.Arguments{"note": ""},
+			errorMessage: "plan: note argument is empty",
+		},
+	}
+
+	for _, tc := range cases {
+		report := operator.NewSaptuneApplyNote(
+			tc.arguments,
+			"test-op",
+			operator.Options[operator.SaptuneApplyNote]{
+				OperatorOptions: []operator.Option[operator.SaptuneApplyNote]{
+					operator.Option[operator.SaptuneApplyNote](operator.WithSaptuneClientApplyNote(suite.mockSaptuneClient)),
+				},
+			},
+		).Run(context.Background())
+
+		suite.Nil(report.Success)
+		suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+		suite.Equal(tc.errorMessage, report.Error.Message)
+	}
+}
+
+func (suite *SaptuneApplyNoteOperatorTestSuite) TestSaptuneApplyNotePlanErrorVersionCheck() {
+	ctx := context.Background()
+
+	suite.mockSaptuneClient.On("CheckVersionSupport", ctx).
+		Return(errors.New("saptune version not supported")).
+		Once()
+
+	report := operator.NewSaptuneApplyNote(
+		operator.Arguments{"note": "2382421"},
+		"test-op",
+		operator.Options[operator.SaptuneApplyNote]{
+			OperatorOptions: []operator.Option[operator.SaptuneApplyNote]{
+				operator.Option[operator.SaptuneApplyNote](operator.WithSaptuneClientApplyNote(suite.mockSaptuneClient)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal("plan: saptune version not supported", report.Error.Message)
+}
+
+func (suite *SaptuneApplyNoteOperatorTestSuite) TestSaptuneApplyNotePlanErrorNoteNotAvailable() {
+	ctx := context.Background()
+
+	suite.mockSaptuneClient.On("CheckVersionSupport", ctx).Return(nil).Once()
+	suite.mockSaptuneClient.On("ListNote", ctx).Return([]byte(saptuneNoteListOutput), nil).Once()
+
+	report := operator.NewSaptuneApplyNote(
+		operator.Arguments{"note": "9999999"},
+		"test-op",
+		operator.Options[operator.SaptuneApplyNote]{
+			OperatorOptions: []operator.Option[operator.SaptuneApplyNote]{
+				operator.Option[operator.SaptuneApplyNote](operator.WithSaptuneClientApplyNote(suite.mockSaptuneClient)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal("plan: note 9999999 is not available", report.Error.Message)
+}
+
+func (suite *SaptuneApplyNoteOperatorTestSuite) TestSaptuneApplyNotePlanErrorVerifyNote() {
+	ctx := context.Background()
+
+	suite.mockSaptuneClient.On("CheckVersionSupport", ctx).Return(nil).Once()
+	suite.mockSaptuneClient.On("ListNote", ctx).Return([]byte(saptuneNoteListOutput), nil).Once()
+	suite.mockSaptuneClient.On("VerifyNote", ctx).
+		Return(nil, errors.New("error executing saptune command: exit status 2")).
+		Once()
+
+	report := operator.NewSaptuneApplyNote(
+		operator.Arguments{"note": "2382421"},
+		"test-op",
+		operator.Options[operator.SaptuneApplyNote]{
+			OperatorOptions: []operator.Option[operator.SaptuneApplyNote]{
+				operator.Option[operator.SaptuneApplyNote](operator.WithSaptuneClientApplyNote(suite.mockSaptuneClient)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal("plan: error executing saptune command: exit status 2", report.Error.Message)
+}
+
+func (suite *SaptuneApplyNoteOperatorTestSuite) TestSaptuneApplyNoteAlreadyApplied() {
+	ctx := context.Background()
+
+	suite.mockSaptuneClient.On("CheckVersionSupport", ctx).Return(nil).Once()
+	suite.mockSaptuneClient.On("ListNote", ctx).Return([]byte(saptuneNoteListOutput), nil).Once()
+	suite.mockSaptuneClient.On("VerifyNote", ctx).Return([]byte(saptuneNoteVerifyOutput), nil).Once()
+
+	report := operator.NewSaptuneApplyNote(
+		operator.Arguments{"note": "1656250"},
+		"test-op",
+		operator.Options[operator.SaptuneApplyNote]{
+			OperatorOptions: []operator.Option[operator.SaptuneApplyNote]{
+				operator.Option[operator.SaptuneApplyNote](operator.WithSaptuneClientApplyNote(suite.mockSaptuneClient)),
+			},
+		},
+	).Run(ctx)
+
+	expectedDiff := map[string]any{
+		"before": `{"note":"1656250","enabled":true,"compliant":true}`,
+		"after":  `{"note":"1656250","enabled":true,"compliant":true}`,
+	}
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.PLAN, report.Success.LastPhase)
+	suite.Equal(expectedDiff, report.Success.Diff)
+}
+
+func (suite *SaptuneApplyNoteOperatorTestSuite) TestSaptuneApplyNoteSuccess() {
+	ctx := context.Background()
+
+	suite.mockSaptuneClient.On("CheckVersionSupport", ctx).Return(nil).Once()
+	suite.mockSaptuneClient.On("ListNote", ctx).Return([]byte(saptuneNoteListOutput), nil).Once()
+	planVerifyCall := suite.mockSaptuneClient.On("VerifyNote", ctx).
+		Return([]byte(saptuneNoteVerifyOutput), nil).
+		Once()
+	applyCall := suite.mockSaptuneClient.On("ApplyNote", ctx, "2382421").
+		Return(nil).
+		Once().
+		NotBefore(planVerifyCall)
+	suite.mockSaptuneClient.On("VerifyNote", ctx).
+		Return([]byte(saptuneNoteVerifyAppliedOutput), nil).
+		Once().
+		NotBefore(applyCall)
+
+	report := operator.NewSaptuneApplyNote(
+		operator.Arguments{"note": "2382421"},
+		"test-op",
+		operator.Options[operator.SaptuneApplyNote]{
+			OperatorOptions: []operator.Option[operator.SaptuneApplyNote]{
+				operator.Option[operator.SaptuneApplyNote](operator.WithSaptuneClientApplyNote(suite.mockSaptuneClient)),
+			},
+		},
+	).Run(ctx)
+
+	expectedDiff := map[string]any{
+		"before": `{"note":"2382421","enabled":false,"compliant":false}`,
+		"after":  `{"note":"2382421","enabled":true,"compliant":false}`,
+	}
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.VERIFY, report.Success.LastPhase)
+	suite.Equal(expectedDiff, report.Success.Diff)
+}
+
+func (suite *SaptuneApplyNoteOperatorTestSuite) TestSaptuneApplyNoteCommitErrorRollback() {
+	ctx := context.Background()
+
+	suite.mockSaptuneClient.On("CheckVersionSupport", ctx).Return(nil).Once()
+	suite.mockSaptuneClient.On("ListNote", ctx).Return([]byte(saptuneNoteListOutput), nil).Once()
+	suite.mockSaptuneClient.On("VerifyNote", ctx).Return([]byte(saptuneNoteVerifyOutput), nil).Once()
+	applyCall := suite.mockSaptuneClient.On("ApplyNote", ctx, "2382421").
+		Return(errors.New("error executing saptune command: exit status 1")).
+		Once()
+	suite.mockSaptuneClient.On("RevertNote", ctx, "2382421").
+		Return(nil).
+		Once().
+		NotBefore(applyCall)
+
+	report := operator.NewSaptuneApplyNote(
+		operator.Arguments{"note": "2382421"},
+		"test-op",
+		operator.Options[operator.SaptuneApplyNote]{
+			OperatorOptions: []operator.Option[operator.SaptuneApplyNote]{
+				operator.Option[operator.SaptuneApplyNote](operator.WithSaptuneClientApplyNote(suite.mockSaptuneClient)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.COMMIT, report.Error.ErrorPhase)
+	suite.Equal("commit: error executing saptune command: exit status 1", report.Error.Message)
+}
+
+func (suite *SaptuneApplyNoteOperatorTestSuite) TestSaptuneApplyNoteVerifyErrorRollbackFailed() {
+	ctx := context.Background()
+
+	suite.mockSaptuneClient.On("CheckVersionSupport", ctx).Return(nil).Once()
+	suite.mockSaptuneClient.On("ListNote", ctx).Return([]byte(saptuneNoteListOutput), nil).Once()
+	suite.mockSaptuneClient.On("VerifyNote", ctx).Return([]byte(saptuneNoteVerifyOutput), nil).Twice()
+	applyCall := suite.mockSaptuneClient.On("ApplyNote", ctx, "2382421").
+		Return(nil).
+		Once()
+	suite.mockSaptuneClient.On("RevertNote", ctx, "2382421").
+		Return(errors.New("error executing saptune command: exit status 1")).
+		Once().
+		NotBefore(applyCall)
+
+	report := operator.NewSaptuneApplyNote(
+		operator.Arguments{"note": "2382421"},
+		"test-op",
+		operator.Options[operator.SaptuneApplyNote]{
+			OperatorOptions: []operator.Option[operator.SaptuneApplyNote]{
+				operator.Option[operator.SaptuneApplyNote](operator.WithSaptuneClientApplyNote(suite.mockSaptuneClient)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.ROLLBACK, report.Error.ErrorPhase)
+	suite.Equal(
+		"verify: the note 2382421 was not applied; rollback: error executing saptune command: exit status 1",
+		report.Error.Message,
+	)
+}
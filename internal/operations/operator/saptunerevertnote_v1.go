@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/trento-project/agent/v3/internal/core/saptune"
+	"github.com/trento-project/agent/v3/pkg/utils"
+)
+
+const SaptuneRevertNoteOperatorName = "saptunerevertnote"
+
+type SaptuneRevertNoteOption Option[SaptuneRevertNote]
+
+// SaptuneRevertNote is an operator responsible for reverting a single saptune note.
+// Notes enabled by the applied solution can be reverted as well.
+//
+// The operator requires an argument in the form of a map containing a key named "note"
+// with the note ID, e.g. {"note": "2382421"}.
+//
+// # Execution Phases
+//
+// - PLAN:
+//   The operator checks for the presence of the saptune binary and verifies its version.
+//   The minimum required version is 3.1.0.
+//   The note state is collected as the "before" diff from the saptune note verify output.
+//   If the note is not enabled, no action is taken.
+//
+// - COMMIT:
+//   The saptune command to revert the note is executed.
+//
+// - VERIFY:
+//   The operator verifies whether the note is not enabled anymore using the saptune note verify output.
+//   If it is still enabled, an error is raised. If successful, the note state is collected as
+//   the "after" diff.
+//
+// - ROLLBACK:
+//   If an error occurs during the COMMIT or VERIFY phase, the note is applied again.
+
+type SaptuneRevertNote struct {
+	baseOperator
+
+	saptune         saptune.Saptune
+	parsedArguments *saptuneNoteArguments
+}
+
+func WithSaptuneClientRevertNote(saptuneClient saptune.Saptune) SaptuneRevertNoteOption {
+	return func(o *SaptuneRevertNote) {
+		o.saptune = saptuneClient
+	}
+}
+
+func NewSaptuneRevertNote(
+	arguments Arguments,
+	operationID string,
+	options Options[SaptuneRevertNote],
+) *Executor {
+	saptuneRevertNote := &SaptuneRevertNote{
+		baseOperator: newBaseOperator(
+			SaptuneRevertNoteOperatorName, operationID, arguments, options.BaseOperatorOptions...,
+		),
+	}
+
+	saptuneRevertNote.saptune = saptune.NewSaptuneClient(
+		utils.Executor{},
+		saptuneRevertNote.logger,
+	)
+
+	for _, opt := range options.OperatorOptions {
+		opt(saptuneRevertNote)
+	}
+
+	return &Executor{
+		phaser:      saptuneRevertNote,
+		operationID: operationID,
+		logger:      saptuneRevertNote.logger,
+	}
+}
+
+func (sr *SaptuneRevertNote) plan(ctx context.Context) (bool, error) {
+	opArguments, err := parseSaptuneNoteArguments(sr.arguments)
+	if err != nil {
+		return false, err
+	}
+
+	sr.parsedArguments = opArguments
+
+	err = sr.saptune.CheckVersionSupport(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	noteState, err := getSaptuneNoteState(ctx, sr.saptune, sr.parsedArguments.note)
+	if err != nil {
+		return false, err
+	}
+
+	sr.resources[beforeDiffField] = noteState
+
+	if !noteState.Enabled {
+		sr.logger.Info("note is not applied, skipping operation", "note", sr.parsedArguments.note)
+		sr.resources[afterDiffField] = noteState
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (sr *SaptuneRevertNote) commit(ctx context.Context) error {
+	return sr.saptune.RevertNote(ctx, sr.parsedArguments.note)
+}
+
+func (sr *SaptuneRevertNote) verify(ctx context.Context) error {
+	noteState, err := getSaptuneNoteState(ctx, sr.saptune, sr.parsedArguments.note)
+	if err != nil {
+		return err
+	}
+
+	if noteState.Enabled {
+		return fmt.Errorf("the note %s was not reverted", sr.parsedArguments.note)
+	}
+
+	sr.resources[afterDiffField] = noteState
+
+	return nil
+}
+
+func (sr *SaptuneRevertNote) rollback(ctx context.Context) error {
+	return sr.saptune.ApplyNote(ctx, sr.parsedArguments.note)
+}
+
+func (sr *SaptuneRevertNote) operationDiff(_ context.Context) map[string]any {
+	return computeSaptuneNoteOperationDiff(sr.resources)
+}
@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/trento-project/agent/v3/internal/core/saptune/mocks"
+	"github.com/trento-project/agent/v3/internal/operations/operator"
+)
+
+type SaptuneRevertNoteOperatorTestSuite struct {
+	suite.Suite
+
+	mockSaptuneClient *mocks.MockSaptune
+}
+
+func TestSaptuneRevertNoteOperator(t *testing.T) {
+	suite.Run(t, new(SaptuneRevertNoteOperatorTestSuite))
+}
+
+func (suite *SaptuneRevertNoteOperatorTestSuite) SetupTest() {
+	suite.mockSaptuneClient = mocks.NewMockSaptune(suite.T())
+}
+
+func (suite *SaptuneRevertNoteOperatorTestSuite) TestSaptuneRevertNotePlanErrorParsingArguments() {
+	report := operator.NewSaptuneRevertNote(
+		operator.Arguments{"notes": "2382421"},
+		"test-op",
+		operator.Options[operator.SaptuneRevertNote]{
+			OperatorOptions: []operator.Option[operator.SaptuneRevertNote]{
+				operator.Option[operator.SaptuneRevertNote](operator.WithSaptuneClientRevertNote(suite.mockSaptuneClient)),
+			},
+		},
+	).Run(context.Background())
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal("plan: argument note not provided, could not use the operator", report.Error.Message)
+}
+
+func (suite *SaptuneRevertNoteOperatorTestSuite) TestSaptuneRevertNotePlanErrorVersionCheck() {
+	ctx := context.Background()
+
+	suite.mockSaptuneClient.On("CheckVersionSupport", ctx).
+		Return(errors.New("saptune version not supported")).
+		Once()
+
+	report := operator.NewSaptuneRevertNote(
+		operator.Arguments{"note": "2382421"},
+		"test-op",
+		operator.Options[operator.SaptuneRevertNote]{
+			OperatorOptions: []operator.Option[operator.SaptuneRevertNote]{
+				operator.Option[operator.SaptuneRevertNote](operator.WithSaptuneClientRevertNote(suite.mockSaptuneClient)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal("plan: saptune version not supported", report.Error.Message)
+}
+
+func (suite *SaptuneRevertNoteOperatorTestSuite) TestSaptuneRevertNoteNotApplied() {
+	ctx := context.Background()
+
+	suite.mockSaptuneClient.On("CheckVersionSupport", ctx).Return(nil).Once()
+	suite.mockSaptuneClient.On("VerifyNote", ctx).Return([]byte(saptuneNoteVerifyOutput), nil).Once()
+
+	report := operator.NewSaptuneRevertNote(
+		operator.Arguments{"note": "2382421"},
+		"test-op",
+		operator.Options[operator.SaptuneRevertNote]{
+			OperatorOptions: []operator.Option[operator.SaptuneRevertNote]{
+				operator.Option[operator.SaptuneRevertNote](operator.WithSaptuneClientRevertNote(suite.mockSaptuneClient)),
+			},
+		},
+	).Run(ctx)
+
+	expectedDiff := map[string]any{
+		"before": `{"note":"2382421","enabled":false,"compliant":false}`,
+		"after":  `{"note":"2382421","enabled":false,"compliant":false}`,
+	}
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.PLAN, report.Success.LastPhase)
+	suite.Equal(expectedDiff, report.Success.Diff)
+}
+
+func (suite *SaptuneRevertNoteOperatorTestSuite) TestSaptuneRevertNoteSuccess() {
+	ctx := context.Background()
+
+	suite.mockSaptuneClient.On("CheckVersionSupport", ctx).Return(nil).Once()
+	planVerifyCall := suite.mockSaptuneClient.On("VerifyNote", ctx).
+		Return([]byte(saptuneNoteVerifyAppliedOutput), nil).
+		Once()
+	revertCall := suite.mockSaptuneClient.On("RevertNote", ctx, "2382421").
+		Return(nil).
+		Once().
+		NotBefore(planVerifyCall)
+	suite.mockSaptuneClient.On("VerifyNote", ctx).
+		Return([]byte(saptuneNoteVerifyOutput), nil).
+		Once().
+		NotBefore(revertCall)
+
+	report := operator.NewSaptuneRevertNote(
+		operator.Arguments{"note": "2382421"},
+		"test-op",
+		operator.Options[operator.SaptuneRevertNote]{
+			OperatorOptions: []operator.Option[operator.SaptuneRevertNote]{
+				operator.Option[operator.SaptuneRevertNote](operator.WithSaptuneClientRevertNote(suite.mockSaptuneClient)),
+			},
+		},
+	).Run(ctx)
+
+	expectedDiff := map[string]any{
+		"before": `{"note":"2382421","enabled":true,"compliant":false}`,
+		"after":  `{"note":"2382421","enabled":false,"compliant":false}`,
+	}
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.VERIFY, report.Success.LastPhase)
+	suite.Equal(expectedDiff, report.Success.Diff)
+}
+
+func (suite *SaptuneRevertNoteOperatorTestSuite) TestSaptuneRevertNoteVerifyErrorRollback() {
+	ctx := context.Background()
+
+	suite.mockSaptuneClient.On("CheckVersionSupport", ctx).Return(nil).Once()
+	suite.mockSaptuneClient.On("VerifyNote", ctx).Return([]byte(saptuneNoteVerifyAppliedOutput), nil).Twice()
+	revertCall := suite.mockSaptuneClient.On("RevertNote", ctx, "2382421").
+		Return(nil).
+		Once()
+	suite.mockSaptuneClient.On("ApplyNote", ctx, "2382421").
+		Return(nil).
+		Once().
+		NotBefore(revertCall)
+
+	report := operator.NewSaptuneRevertNote(
+		operator.Arguments{"note": "2382421"},
+		"test-op",
+		operator.Options[operator.SaptuneRevertNote]{
+			OperatorOptions: []operator.Option[operator.SaptuneRevertNote]{
+				operator.Option[operator.SaptuneRevertNote](operator.WithSaptuneClientRevertNote(suite.mockSaptuneClient)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.VERIFY, report.Error.ErrorPhase)
+	suite.Equal("verify: the note 2382421 was not reverted", report.Error.Message)
+}
@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/trento-project/agent/v3/internal/core/saptune"
+	"github.com/trento-project/agent/v3/pkg/utils"
+)
+
+const SaptuneRevertSolutionOperatorName = "saptunerevertsolution"
+
+type SaptuneRevertSolutionOption Option[SaptuneRevertSolution]
+
+// SaptuneRevertSolution is an operator responsible for reverting the applied saptune solution.
+//
+// The operator doesn't require any argument, as only one solution can be applied at a time.
+//
+// # Execution Phases
+//
+// - PLAN:
+//   The operator checks for the presence of the saptune binary and verifies its version.
+//   The minimum required version is 3.1.0.
+//   The applied solution, if any, is collected as the "before" diff.
+//   If there is no solution applied, no action is taken.
+//
+// - COMMIT:
+//   The saptune command to revert the applied solution is executed.
+//
+// - VERIFY:
+//   The operator verifies whether there isn't any solution applied.
+//   If not, an error is raised.
+//
+// - ROLLBACK:
+//   If an error occurs during the COMMIT or VERIFY phase, the initially applied solution
+//   is applied again.
+
+type SaptuneRevertSolution struct {
+	baseOperator
+
+	saptune saptune.Saptune
+}
+
+func WithSaptuneClientRevertSolution(saptuneClient saptune.Saptune) SaptuneRevertSolutionOption {
+	return func(o *SaptuneRevertSolution) {
+		o.saptune = saptuneClient
+	}
+}
+
+func NewSaptuneRevertSolution(
+	arguments Arguments,
+	operationID string,
+	options Options[SaptuneRevertSolution],
+) *Executor {
+	saptuneRevert := &SaptuneRevertSolution{
+		baseOperator: newBaseOperator(
+			SaptuneRevertSolutionOperatorName, operationID, arguments, options.BaseOperatorOptions...,
+		),
+	}
+
+	saptuneRevert.saptune = saptune.NewSaptuneClient(
+		utils.Executor{},
+		saptuneRevert.logger,
+	)
+
+	for _, opt := range options.OperatorOptions {
+		opt(saptuneRevert)
+	}
+
+	return &Executor{
+		phaser:      saptuneRevert,
+		operationID: operationID,
+		logger:      saptuneRevert.logger,
+	}
+}
+
+func (sr *SaptuneRevertSolution) plan(ctx context.Context) (bool, error) {
+	err := sr.saptune.CheckVersionSupport(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	initiallyAppliedSolution, err := sr.saptune.GetAppliedSolution(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	sr.resources[beforeDiffField] = initiallyAppliedSolution
+
+	if initiallyAppliedSolution == "" {
+		sr.logger.Info("no solution applied, skipping operation")
+		sr.resources[afterDiffField] = initiallyAppliedSolution
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (sr *SaptuneRevertSolution) commit(ctx context.Context) error {
+	initiallyAppliedSolution, _ := sr.resources[beforeDiffField].(string)
+
+	return sr.saptune.RevertSolution(ctx, initiallyAppliedSolution)
+}
+
+func (sr *SaptuneRevertSolution) verify(ctx context.Context) error {
+	appliedSolution, err := sr.saptune.GetAppliedSolution(ctx)
+	if err != nil {
+		return err
+	}
+
+	if appliedSolution != "" {
+		return fmt.Errorf("the solution %s was not reverted", appliedSolution)
+	}
+
+	sr.resources[afterDiffField] = appliedSolution
+
+	return nil
+}
+
+func (sr *SaptuneRevertSolution) rollback(ctx context.Context) error {
+	initiallyAppliedSolution, _ := sr.resources[beforeDiffField].(string)
+
+	return sr.saptune.ApplySolution(ctx, initiallyAppliedSolution)
+}
+
+// operationDiff needs to be refactored, ignoring duplication issues for now
+//
+//nolint:dupl
+func (sr *SaptuneRevertSolution) operationDiff(_ context.Context) map[string]any {
+	diff := make(map[string]any)
+
+	beforeSolution, ok := sr.resources[beforeDiffField].(string)
+	if !ok {
+		panic(fmt.Sprintf("invalid beforeSolution value: cannot parse '%s' to string",
+			sr.resources[beforeDiffField]))
+	}
+
+	afterSolution, ok := sr.resources[afterDiffField].(string)
+	if !ok {
+		panic(fmt.Sprintf("invalid afterSolution value: cannot parse '%s' to string",
+			sr.resources[afterDiffField]))
+	}
+
+	before, err := json.Marshal(saptuneOperationDiffOutput{
+		Solution: beforeSolution,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling before diff output: %v", err))
+	}
+
+	diff[beforeDiffField] = string(before)
+
+	after, err := json.Marshal(saptuneOperationDiffOutput{
+		Solution: afterSolution,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling after diff output: %v", err))
+	}
+
+	diff[afterDiffField] = string(after)
+
+	return diff
+}
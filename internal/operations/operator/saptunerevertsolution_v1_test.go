@@ -0,0 +1,204 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/trento-project/agent/v3/internal/core/saptune/mocks"
+	"github.com/trento-project/agent/v3/internal/operations/operator"
+)
+
+type SaptuneRevertSolutionOperatorTestSuite struct {
+	suite.Suite
+
+	mockSaptuneClient *mocks.MockSaptune
+}
+
+func TestSaptuneRevertSolutionOperator(t *testing.T) {
+	suite.Run(t, new(SaptuneRevertSolutionOperatorTestSuite))
+}
+
+func (suite *SaptuneRevertSolutionOperatorTestSuite) SetupTest() {
+	suite.mockSaptuneClient = mocks.NewMockSaptune(suite.T())
+}
+
+func (suite *SaptuneRevertSolutionOperatorTestSuite) TestSaptuneRevertSolutionPlanErrorVersionCheck() {
+	ctx := context.Background()
+
+	suite.mockSaptuneClient.On("CheckVersionSupport", ctx).
+		Return(errors.New("saptune version not supported")).
+		Once()
+
+	report := operator.NewSaptuneRevertSolution(
+		operator.Arguments{},
+		"test-op",
+		operator.Options[operator.SaptuneRevertSolution]{
+			OperatorOptions: []operator.Option[operator.SaptuneRevertSolution]{
+				operator.Option[operator.SaptuneRevertSolution](
+					operator.WithSaptuneClientRevertSolution(suite.mockSaptuneClient),
+				),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal("plan: saptune version not supported", report.Error.Message)
+}
+
+func (suite *SaptuneRevertSolutionOperatorTestSuite) TestSaptuneRevertSolutionPlanErrorAppliedSolution() {
+	ctx := context.Background()
+
+	suite.mockSaptuneClient.On("CheckVersionSupport", ctx).Return(nil).Once()
+	suite.mockSaptuneClient.On("GetAppliedSolution", ctx).
+		Return("", errors.New("error executing saptune command: exit status 1")).
+		Once()
+
+	report := operator.NewSaptuneRevertSolution(
+		operator.Arguments{},
+		"test-op",
+		operator.Options[operator.SaptuneRevertSolution]{
+			OperatorOptions: []operator.Option[operator.SaptuneRevertSolution]{
+				operator.Option[operator.SaptuneRevertSolution](
+					operator.WithSaptuneClientRevertSolution(suite.mockSaptuneClient),
+				),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal("plan: error executing saptune command: exit status 1", report.Error.Message)
+}
+
+func (suite *SaptuneRevertSolutionOperatorTestSuite) TestSaptuneRevertSolutionNoSolutionApplied() {
+	ctx := context.Background()
+
+	suite.mockSaptuneClient.On("CheckVersionSupport", ctx).Return(nil).Once()
+	suite.mockSaptuneClient.On("GetAppliedSolution", ctx).Return("", nil).Once()
+
+	report := operator.NewSaptuneRevertSolution(
+		operator.Arguments{},
+		"test-op",
+		operator.Options[operator.SaptuneRevertSolution]{
+			OperatorOptions: []operator.Option[operator.SaptuneRevertSolution]{
+				operator.Option[operator.SaptuneRevertSolution](
+					operator.WithSaptuneClientRevertSolution(suite.mockSaptuneClient),
+				),
+			},
+		},
+	).Run(ctx)
+
+	expectedDiff := map[string]any{
+		"before": `{"solution":""}`,
+		"after":  `{"solution":""}`,
+	}
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.PLAN, report.Success.LastPhase)
+	suite.Equal(expectedDiff, report.Success.Diff)
+}
+
+func (suite *SaptuneRevertSolutionOperatorTestSuite) TestSaptuneRevertSolutionSuccess() {
+	ctx := context.Background()
+
+	suite.mockSaptuneClient.On("CheckVersionSupport", ctx).Return(nil).Once()
+	planCall := suite.mockSaptuneClient.On("GetAppliedSolution", ctx).Return("HANA", nil).Once()
+	revertCall := suite.mockSaptuneClient.On("RevertSolution", ctx, "HANA").
+		Return(nil).
+		Once().
+		NotBefore(planCall)
+	suite.mockSaptuneClient.On("GetAppliedSolution", ctx).
+		Return("", nil).
+		Once().
+		NotBefore(revertCall)
+
+	report := operator.NewSaptuneRevertSolution(
+		operator.Arguments{},
+		"test-op",
+		operator.Options[operator.SaptuneRevertSolution]{
+			OperatorOptions: []operator.Option[operator.SaptuneRevertSolution]{
+				operator.Option[operator.SaptuneRevertSolution](
+					operator.WithSaptuneClientRevertSolution(suite.mockSaptuneClient),
+				),
+			},
+		},
+	).Run(ctx)
+
+	expectedDiff := map[string]any{
+		"before": `{"solution":"HANA"}`,
+		"after":  `{"solution":""}`,
+	}
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.VERIFY, report.Success.LastPhase)
+	suite.Equal(expectedDiff, report.Success.Diff)
+}
+
+func (suite *SaptuneRevertSolutionOperatorTestSuite) TestSaptuneRevertSolutionCommitErrorRollback() {
+	ctx := context.Background()
+
+	suite.mockSaptuneClient.On("CheckVersionSupport", ctx).Return(nil).Once()
+	suite.mockSaptuneClient.On("GetAppliedSolution", ctx).Return("HANA", nil).Once()
+	revertCall := suite.mockSaptuneClient.On("RevertSolution", ctx, "HANA").
+		Return(errors.New("error executing saptune command: exit status 1")).
+		Once()
+	suite.mockSaptuneClient.On("ApplySolution", ctx, "HANA").
+		Return(nil).
+		Once().
+		NotBefore(revertCall)
+
+	report := operator.NewSaptuneRevertSolution(
+		operator.Arguments{},
+		"test-op",
+		operator.Options[operator.SaptuneRevertSolution]{
+			OperatorOptions: []operator.Option[operator.SaptuneRevertSolution]{
+				operator.Option[operator.SaptuneRevertSolution](
+					operator.WithSaptuneClientRevertSolution(suite.mockSaptuneClient),
+				),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.COMMIT, report.Error.ErrorPhase)
+	suite.Equal("commit: error executing saptune command: exit status 1", report.Error.Message)
+}
+
+func (suite *SaptuneRevertSolutionOperatorTestSuite) TestSaptuneRevertSolutionVerifyErrorRollbackFailed() {
+	ctx := context.Background()
+
+	suite.mockSaptuneClient.On("CheckVersionSupport", ctx).Return(nil).Once()
+	suite.mockSaptuneClient.On("GetAppliedSolution", ctx).Return("HANA", nil).Twice()
+	revertCall := suite.mockSaptuneClient.On("RevertSolution", ctx, "HANA").
+		Return(nil).
+		Once()
+	suite.mockSaptuneClient.On("ApplySolution", ctx, "HANA").
+		Return(errors.New("error executing saptune command: exit status 1")).
+		Once().
+		NotBefore(revertCall)
+
+	report := operator.NewSaptuneRevertSolution(
+		operator.Arguments{},
+		"test-op",
+		operator.Options[operator.SaptuneRevertSolution]{
+			OperatorOptions: []operator.Option[operator.SaptuneRevertSolution]{
+				operator.Option[operator.SaptuneRevertSolution](
+					operator.WithSaptuneClientRevertSolution(suite.mockSaptuneClient),
+				),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.ROLLBACK, report.Error.ErrorPhase)
+	suite.Equal(
+		"verify: the solution HANA was not reverted; rollback: error executing saptune command: exit status 1",
+		report.Error.Message,
+	)
+}
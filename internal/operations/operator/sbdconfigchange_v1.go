@@ -0,0 +1,343 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/trento-project/agent/v3/internal/core/cluster"
+)
+
+const (
+	SbdConfigChangeOperatorName = "sbdconfigchange"
+	sbdDeviceEntry              = "SBD_DEVICE"
+)
+
+type SbdConfigChangeOption Option[SbdConfigChange]
+
+type sbdConfigChangeArguments struct {
+	config map[string]string
+	force  bool
+}
+
+type sbdConfigDiffOutput struct {
+	Config map[string]string `json:"config"`
+}
+
+// SbdConfigChange is an operator responsible for changing the sbd configuration
+// stored in /etc/sysconfig/sbd.
+// sbd reads its configuration when the cluster stack starts, so the changes apply on the next
+// cluster start on the host. The operator doesn't restart sbd, as that requires restarting the whole cluster stack.
+//
+// The operator accepts the next arguments:
+// - config (map): The sbd entries to change with their desired values. Supported entries:
+//                 SBD_DEVICE, SBD_PACEMAKER, SBD_STARTMODE, SBD_DELAY_START, SBD_WATCHDOG_DEV,
+//                 SBD_WATCHDOG_TIMEOUT, SBD_TIMEOUT_ACTION, SBD_MOVE_TO_ROOT_CGROUP,
+//                 SBD_SYNC_RESOURCE_STARTUP and SBD_OPTS.
+//                 Boolean values are written as yes/no.
+// - force (bool): SBD_DEVICE is only changed if force is true, as using the wrong devices
+//                 can cause fencing storms in the cluster.
+//
+// Example: {"config": {"SBD_WATCHDOG_TIMEOUT": 15, "SBD_STARTMODE": "clean"}}
+//
+// # Execution Phases
+//
+// - PLAN:
+//   The current sbd configuration is loaded and the current values of the requested entries
+//   are stored as the "before" diff. If all of them already have the requested value,
+//   the operation is skipped.
+//   Otherwise, the sbd configuration file is backed up next to the original file. The backup is removed
+//   once the changes are verified or rolled back.
+//
+// - COMMIT:
+//   The entries are updated in the sbd configuration file, preserving its comments.
+//
+// - VERIFY:
+//   The sbd configuration file is loaded again and the values of the requested entries
+//   are compared with the requested values.
+//
+// - ROLLBACK:
+//   The backed up sbd configuration file is restored.
+
+type SbdConfigChange struct {
+	baseOperator
+
+	configPath string
+	backupPath string
+	// keepBackup is set while the changes are neither verified nor rolled back
+	keepBackup      bool
+	parsedArguments *sbdConfigChangeArguments
+}
+
+func WithCustomSbdConfigPath(configPath string) SbdConfigChangeOption {
+	return func(o *SbdConfigChange) {
+		o.configPath = configPath
+	}
+}
+
+func NewSbdConfigChange(
+	arguments Arguments,
+	operationID string,
+	options Options[SbdConfigChange],
+) *Executor {
+	sbdConfigChange := &SbdConfigChange{
+		baseOperator: newBaseOperator(
+			SbdConfigChangeOperatorName, operationID, arguments, options.BaseOperatorOptions...,
+		),
+		configPath: cluster.SBDConfigPath,
+	}
+
+	for _, opt := range options.OperatorOptions {
+		opt(sbdConfigChange)
+	}
+
+	sbdConfigChange.backupPath = backupFilePath(sbdConfigChange.configPath, operationID)
+
+	return &Executor{
+		phaser:      sbdConfigChange,
+		operationID: operationID,
+		logger:      sbdConfigChange.logger,
+	}
+}
+
+func (s *SbdConfigChange) plan(_ context.Context) (bool, error) {
+	opArguments, err := parseSbdConfigChangeArguments(s.arguments)
+	if err != nil {
+		return false, err
+	}
+
+	s.parsedArguments = opArguments
+
+	currentConfig, err := cluster.LoadSbdConfig(s.configPath)
+	if err != nil {
+		return false, err
+	}
+
+	currentValues := make(map[string]string)
+	alreadyApplied := true
+
+	for entry, value := range s.parsedArguments.config {
+		currentValue, found := currentConfig[entry]
+		if found {
+			currentValues[entry] = currentValue
+		}
+
+		if currentValue != value {
+			alreadyApplied = false
+		}
+	}
+
+	s.resources[beforeDiffField] = currentValues
+
+	if alreadyApplied {
+		s.logger.Info("sbd configuration already set, skipping operation", "config", s.parsedArguments.config)
+		s.resources[afterDiffField] = currentValues
+
+		return true, nil
+	}
+
+	err = backupFile(s.configPath, s.backupPath)
+	if err != nil {
+		return false, fmt.Errorf("error backing up sbd configuration: %w", err)
+	}
+
+	s.logger.Info("sbd configuration backed up", "backup", s.backupPath)
+
+	return false, nil
+}
+
+func (s *SbdConfigChange) commit(_ context.Context) error {
+	s.keepBackup = true
+
+	content, err := os.ReadFile(s.configPath)
+	if err != nil {
+		return fmt.Errorf("error reading sbd configuration: %w", err)
+	}
+
+	return writeFileAtomically(s.configPath, cluster.SetSbdConfigValues(content, s.parsedArguments.config))
+}
+
+func (s *SbdConfigChange) verify(_ context.Context) error {
+	currentConfig, err := cluster.LoadSbdConfig(s.configPath)
+	if err != nil {
+		return err
+	}
+
+	currentValues := make(map[string]string)
+
+	for entry, value := range s.parsedArguments.config {
+		if currentConfig[entry] != value {
+			return fmt.Errorf("sbd entry %s has value %s, expected %s", entry, currentConfig[entry], value)
+		}
+
+		currentValues[entry] = currentConfig[entry]
+	}
+
+	s.resources[afterDiffField] = currentValues
+
+	s.keepBackup = false
+
+	return nil
+}
+
+func (s *SbdConfigChange) rollback(_ context.Context) error {
+	err := restoreFile(s.backupPath, s.configPath)
+	if err != nil {
+		return fmt.Errorf("error restoring sbd configuration: %w", err)
+	}
+
+	s.keepBackup = false
+
+	return nil
+}
+
+func (s *SbdConfigChange) after(_ context.Context) {
+	removeBackupFile(s.backupPath, s.keepBackup, s.logger)
+}
+
+func (s *SbdConfigChange) operationDiff(_ context.Context) map[string]any {
+	diff := make(map[string]any)
+
+	beforeConfig, ok := s.resources[beforeDiffField].(map[string]string)
+	if !ok {
+		panic(fmt.Sprintf("invalid beforeConfig value: cannot parse '%v' to map",
+			s.resources[beforeDiffField]))
+	}
+
+	afterConfig, ok := s.resources[afterDiffField].(map[string]string)
+	if !ok {
+		panic(fmt.Sprintf("invalid afterConfig value: cannot parse '%v' to map",
+			s.resources[afterDiffField]))
+	}
+
+	before, err := json.Marshal(sbdConfigDiffOutput{Config: beforeConfig})
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling before diff output: %v", err))
+	}
+
+	diff[beforeDiffField] = string(before)
+
+	after, err := json.Marshal(sbdConfigDiffOutput{Config: afterConfig})
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling after diff output: %v", err))
+	}
+
+	diff[afterDiffField] = string(after)
+
+	return diff
+}
+
+func isSupportedSbdEntry(entry string) bool {
+	return slices.Contains([]string{
+		"SBD_DEVICE",
+		"SBD_PACEMAKER",
+		"SBD_STARTMODE",
+		"SBD_DELAY_START",
+		"SBD_WATCHDOG_DEV",
+		"SBD_WATCHDOG_TIMEOUT",
+		"SBD_TIMEOUT_ACTION",
+		"SBD_MOVE_TO_ROOT_CGROUP",
+		"SBD_SYNC_RESOURCE_STARTUP",
+		"SBD_OPTS",
+	}, entry)
+}
+
+func parseSbdConfigValue(argument any) (string, error) {
+	switch value := argument.(type) {
+	case string:
+		err := cluster.ValidateSbdConfigValue(value)
+		if err != nil {
+			return "", err
+		}
+
+		return value, nil
+	case bool:
+		if value {
+			return "yes", nil
+		}
+
+		return "no", nil
+	case float64, int:
+		parsedValue, err := parseUnsignedIntegerArgument(value)
+		if err != nil {
+			return "", err
+		}
+
+		return strconv.FormatUint(parsedValue, 10), nil
+	default:
+		return "", fmt.Errorf("value %v is not a string, boolean or integer", argument)
+	}
+}
+
+func parseOptionalBoolArgument(rawArguments Arguments, name string) (bool, error) {
+	argument, found := rawArguments[name]
+	if !found {
+		return false, nil
+	}
+
+	value, ok := argument.(bool)
+	if !ok {
+		return false, fmt.Errorf(
+			"could not parse %s argument as bool, argument provided: %v",
+			name,
+			argument,
+		)
+	}
+
+	return value, nil
+}
+
+func parseSbdConfigChangeArguments(rawArguments Arguments) (*sbdConfigChangeArguments, error) {
+	configArgument, found := rawArguments["config"]
+	if !found {
+		return nil, errors.New("argument config not provided, could not use the operator")
+	}
+
+	rawConfig, ok := configArgument.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf(
+			"could not parse config argument as map, argument provided: %v",
+			configArgument,
+		)
+	}
+
+	if len(rawConfig) == 0 {
+		return nil, errors.New("config argument is empty")
+	}
+
+	force, err := parseOptionalBoolArgument(rawArguments, "force")
+	if err != nil {
+		return nil, err
+	}
+
+	config := make(map[string]string, len(rawConfig))
+
+	for entry, rawValue := range rawConfig {
+		if !isSupportedSbdEntry(entry) {
+			return nil, fmt.Errorf("unsupported sbd entry: %s", entry)
+		}
+
+		if entry == sbdDeviceEntry && !force {
+			return nil, fmt.Errorf("%s can only be changed using the force argument", sbdDeviceEntry)
+		}
+
+		value, err := parseSbdConfigValue(rawValue)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse %s entry: %w", entry, err)
+		}
+
+		config[entry] = value
+	}
+
+	return &sbdConfigChangeArguments{
+		config: config,
+		force:  force,
+	}, nil
+}
@@ -0,0 +1,185 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator_test
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/trento-project/agent/v3/internal/operations/operator"
+	"github.com/trento-project/agent/v3/pkg/utils"
+)
+
+const sbdConfigTestContent = `## Type: yesno
+# Whether to enable the pacemaker integration.
+SBD_PACEMAKER=yes
+SBD_STARTMODE=always
+SBD_DELAY_START=no
+SBD_WATCHDOG_TIMEOUT=5
+SBD_DEVICE="/dev/vdc"
+`
+
+type SbdConfigChangeOperatorTestSuite struct {
+	suite.Suite
+
+	logger     *slog.Logger
+	configPath string
+	backupPath string
+}
+
+func TestSbdConfigChangeOperator(t *testing.T) {
+	suite.Run(t, new(SbdConfigChangeOperatorTestSuite))
+}
+
+func (suite *SbdConfigChangeOperatorTestSuite) SetupTest() {
+	suite.logger = utils.NewDefaultLogger("info")
+	suite.configPath = path.Join(suite.T().TempDir(), "sbd")
+	suite.backupPath = suite.configPath + ".trento-test-op.bak"
+
+	err := os.WriteFile(suite.configPath, []byte(sbdConfigTestContent), 0o644)
+	suite.Require().NoError(err)
+}
+
+func (suite *SbdConfigChangeOperatorTestSuite) TestSbdConfigChangePlanErrors() {
+	cases := []struct {
+		arguments    operator.Arguments
+		errorMessage string
+	}{
+		{
+			arguments:    operator.Arguments{},
+			errorMessage: "plan: argument config not provided, could not use the operator",
+		},
+		{
+			arguments:    operator.Arguments{"config": "SBD_STARTMODE=clean"},
+			errorMessage: "plan: could not parse config argument as map, argument provided: SBD_STARTMODE=clean",
+		},
+		{
+			arguments:    operator.Arguments{"config": map[string]any{}},
+			errorMessage: "plan: config argument is empty",
+		},
+		{
+			arguments:    operator.Arguments{"config": map[string]any{"SBD_UNKNOWN": "yes"}},
+			errorMessage: "plan: unsupported sbd entry: SBD_UNKNOWN",
+		},
+		{
+			arguments:    operator.Arguments{"config": map[string]any{"SBD_DEVICE": "/dev/vdd"}},
+			errorMessage: "plan: SBD_DEVICE can only be changed using the force argument",
+		},
+		{
+			arguments:    operator.Arguments{"config": map[string]any{"SBD_WATCHDOG_TIMEOUT": 1.5}},
+			errorMessage: "plan: could not parse SBD_WATCHDOG_TIMEOUT entry: value 1.5 is not a valid unsigned integer",
+		},
+		{
+			arguments: operator.Arguments{"config": map[string]any{"SBD_OPTS": "x\nSBD_DEVICE=/dev/evil"}},
+			errorMessage: "plan: could not parse SBD_OPTS entry: " +
+				`value "x\nSBD_DEVICE=/dev/evil" contains control characters`,
+		},
+		{
+			arguments: operator.Arguments{
+				"config": map[string]any{"SBD_STARTMODE": "clean"},
+				"force":  "yes",
+			},
+			errorMessage: "plan: could not parse force argument as bool, argument provided: yes",
+		},
+	}
+
+	for _, tc := range cases {
+		report := operator.NewSbdConfigChange(
+			tc.arguments,
+			"test-op",
+			operator.Options[operator.SbdConfigChange]{
+				BaseOperatorOptions: []operator.BaseOperatorOption{
+					operator.WithCustomLogger(suite.logger),
+				},
+				OperatorOptions: []operator.Option[operator.SbdConfigChange]{
+					operator.Option[operator.SbdConfigChange](operator.WithCustomSbdConfigPath(suite.configPath)),
+				},
+			},
+		).Run(context.Background())
+
+		suite.Nil(report.Success)
+		suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+		suite.Equal(tc.errorMessage, report.Error.Message)
+	}
+}
+
+func (suite *SbdConfigChangeOperatorTestSuite) TestSbdConfigChangeAlreadyApplied() {
+	report := operator.NewSbdConfigChange(
+		operator.Arguments{
+			"config": map[string]any{"SBD_PACEMAKER": true, "SBD_WATCHDOG_TIMEOUT": 5.0},
+		},
+		"test-op",
+		operator.Options[operator.SbdConfigChange]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.SbdConfigChange]{
+				operator.Option[operator.SbdConfigChange](operator.WithCustomSbdConfigPath(suite.configPath)),
+			},
+		},
+	).Run(context.Background())
+
+	expectedDiff := map[string]any{
+		"before": `{"config":{"SBD_PACEMAKER":"yes","SBD_WATCHDOG_TIMEOUT":"5"}}`,
+		"after":  `{"config":{"SBD_PACEMAKER":"yes","SBD_WATCHDOG_TIMEOUT":"5"}}`,
+	}
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.PLAN, report.Success.LastPhase)
+	suite.Equal(expectedDiff, report.Success.Diff)
+	suite.NoFileExists(suite.backupPath)
+}
+
+func (suite *SbdConfigChangeOperatorTestSuite) TestSbdConfigChangeSuccess() {
+	report := operator.NewSbdConfigChange(
+		operator.Arguments{
+			"config": map[string]any{
+				"SBD_WATCHDOG_TIMEOUT": 15.0,
+				"SBD_DELAY_START":      true,
+				"SBD_DEVICE":           "/dev/vdc;/dev/vdd",
+				"SBD_TIMEOUT_ACTION":   "flush,reboot",
+			},
+			"force": true,
+		},
+		"test-op",
+		operator.Options[operator.SbdConfigChange]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.SbdConfigChange]{
+				operator.Option[operator.SbdConfigChange](operator.WithCustomSbdConfigPath(suite.configPath)),
+			},
+		},
+	).Run(context.Background())
+
+	expectedDiff := map[string]any{
+		"before": `{"config":{"SBD_DELAY_START":"no","SBD_DEVICE":"/dev/vdc","SBD_WATCHDOG_TIMEOUT":"5"}}`,
+		"after": `{"config":{"SBD_DELAY_START":"yes","SBD_DEVICE":"/dev/vdc;/dev/vdd",` +
+			`"SBD_TIMEOUT_ACTION":"flush,reboot","SBD_WATCHDOG_TIMEOUT":"15"}}`,
+	}
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.VERIFY, report.Success.LastPhase)
+	suite.Equal(expectedDiff, report.Success.Diff)
+
+	expectedContent := `## Type: yesno
+# Whether to enable the pacemaker integration.
+SBD_PACEMAKER=yes
+SBD_STARTMODE=always
+SBD_DELAY_START=yes
+SBD_WATCHDOG_TIMEOUT=15
+SBD_DEVICE="/dev/vdc;/dev/vdd"
+SBD_TIMEOUT_ACTION=flush,reboot
+`
+
+	content, err := os.ReadFile(suite.configPath)
+	suite.Require().NoError(err)
+	suite.Equal(expectedContent, string(content))
+
+	suite.NoFileExists(suite.backupPath)
+}
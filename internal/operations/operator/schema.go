@@ -0,0 +1,171 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+)
+
+type ArgumentType string
+
+const (
+	ArgumentTypeString  ArgumentType = "string"
+	ArgumentTypeNumber  ArgumentType = "number"
+	ArgumentTypeInteger ArgumentType = "integer"
+	ArgumentTypeBoolean ArgumentType = "boolean"
+	ArgumentTypeList    ArgumentType = "list"
+	ArgumentTypeMap     ArgumentType = "map"
+)
+
+// ArgumentSchema describes a single operator argument.
+// Integers are accepted as JSON numbers without decimals or as numeric strings,
+// the same way the operators parse them.
+type ArgumentSchema struct {
+	Name        string          `json:"name"`
+	Type        ArgumentType    `json:"type"`
+	Description string          `json:"description"`
+	Required    bool            `json:"required"`
+	Enum        []any           `json:"enum,omitempty"`
+	Default     any             `json:"default,omitempty"`
+	Items       *ArgumentSchema `json:"items,omitempty"`
+}
+
+// Schema describes the arguments accepted by an operator version.
+// Defaults are informative, the operators apply them when the argument is not provided.
+// Arguments not described in the schema are ignored.
+type Schema struct {
+	Description string           `json:"description"`
+	Arguments   []ArgumentSchema `json:"arguments"`
+}
+
+// SchemasTree has the same structure as BuildersTree.
+// map[operatorName]map[operatorVersion]Schema.
+type SchemasTree map[string]map[string]Schema
+
+// CatalogueEntry describes an available operator version with its arguments schema.
+type CatalogueEntry struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Schema
+}
+
+// Validate checks the arguments against the schema.
+func (s Schema) Validate(arguments Arguments) error {
+	for _, argumentSchema := range s.Arguments {
+		value, found := arguments[argumentSchema.Name]
+		if !found {
+			if argumentSchema.Required {
+				return fmt.Errorf("argument %s is required", argumentSchema.Name)
+			}
+
+			continue
+		}
+
+		err := argumentSchema.validate(argumentSchema.Name, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a ArgumentSchema) validate(name string, value any) error {
+	if !isArgumentType(a.Type, value) {
+		return fmt.Errorf("argument %s must be a %s, argument provided: %v", name, a.Type, value)
+	}
+
+	if len(a.Enum) > 0 && !slices.Contains(a.Enum, value) {
+		return fmt.Errorf("argument %s must be one of %v, argument provided: %v", name, a.Enum, value)
+	}
+
+	if a.Type != ArgumentTypeList || a.Items == nil {
+		return nil
+	}
+
+	for _, item := range value.([]any) {
+		err := a.Items.validate(name+" item", item)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isArgumentType(argumentType ArgumentType, value any) bool {
+	switch argumentType {
+	case ArgumentTypeString:
+		_, ok := value.(string)
+
+		return ok
+	case ArgumentTypeNumber:
+		switch value.(type) {
+		case float64, int:
+			return true
+		}
+
+		return false
+	case ArgumentTypeInteger:
+		switch typedValue := value.(type) {
+		case float64:
+			return typedValue == math.Trunc(typedValue)
+		case int:
+			return true
+		case string:
+			_, err := strconv.ParseInt(typedValue, 10, 64)
+
+			return err == nil
+		}
+
+		return false
+	case ArgumentTypeBoolean:
+		_, ok := value.(bool)
+
+		return ok
+	case ArgumentTypeList:
+		_, ok := value.([]any)
+
+		return ok
+	case ArgumentTypeMap:
+		_, ok := value.(map[string]any)
+
+		return ok
+	default:
+		return false
+	}
+}
+
+// invalidArgumentsOperator is returned by the registry builders when the arguments don't match
+// the operator schema, so the operator is never run.
+type invalidArgumentsOperator struct {
+	operationID string
+	err         error
+}
+
+func (o *invalidArgumentsOperator) Run(_ context.Context) *ExecutionReport {
+	return executionReportWithError(
+		fmt.Errorf("plan: invalid arguments: %w", o.err),
+		PLAN,
+		o.operationID,
+	)
+}
+
+func withSchemaValidation(builder Builder, schema Schema) Builder {
+	return func(operationID string, arguments Arguments) Operator {
+		err := schema.Validate(arguments)
+		if err != nil {
+			return &invalidArgumentsOperator{
+				operationID: operationID,
+				err:         err,
+			}
+		}
+
+		return builder(operationID, arguments)
+	}
+}
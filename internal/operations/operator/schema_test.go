@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/trento-project/agent/v3/internal/operations/operator"
+)
+
+type SchemaTestSuite struct {
+	suite.Suite
+}
+
+func TestSchemaTestSuite(t *testing.T) {
+	suite.Run(t, new(SchemaTestSuite))
+}
+
+func testSchema() operator.Schema {
+	return operator.Schema{
+		Description: "test operator",
+		Arguments: []operator.ArgumentSchema{
+			{Name: "name", Type: operator.ArgumentTypeString, Required: true},
+			{Name: "timeout", Type: operator.ArgumentTypeNumber},
+			{Name: "count", Type: operator.ArgumentTypeInteger},
+			{Name: "force", Type: operator.ArgumentTypeBoolean},
+			{Name: "config", Type: operator.ArgumentTypeMap},
+			{Name: "state", Type: operator.ArgumentTypeString, Enum: []any{"started", "stopped"}},
+			{
+				Name: "categories",
+				Type: operator.ArgumentTypeList,
+				Items: &operator.ArgumentSchema{
+					Type: operator.ArgumentTypeString,
+					Enum: []any{"security", "recommended"},
+				},
+			},
+		},
+	}
+}
+
+func (suite *SchemaTestSuite) TestValidateSuccess() {
+	cases := []operator.Arguments{
+		{"name": "value"},
+		{
+			"name":       "value",
+			"timeout":    30.5,
+			"count":      10.0,
+			"force":      true,
+			"config":     map[string]any{"key": "value"},
+			"state":      "started",
+			"categories": []any{"security"},
+			"unknown":    "ignored",
+		},
+		{"name": "value", "count": "10"},
+		{"name": "value", "count": 10},
+	}
+
+	for _, arguments := range cases {
+		suite.NoError(testSchema().Validate(arguments))
+	}
+}
+
+func (suite *SchemaTestSuite) TestValidateError() {
+	cases := []struct {
+		arguments    operator.Arguments
+		errorMessage string
+	}{
+		{
+			arguments:    operator.Arguments{},
+			errorMessage: "argument name is required",
+		},
+		{
+			arguments:    operator.Arguments{"name": 1.0},
+			errorMessage: "argument name must be a string, argument provided: 1",
+		},
+		{
+			arguments:    operator.Arguments{"name": "value", "timeout": "30"},
+			errorMessage: "argument timeout must be a number, argument provided: 30",
+		},
+		{
+			arguments:    operator.Arguments{"name": "value", "count": 1.5},
+			errorMessage: "argument count must be a integer, argument provided: 1.5",
+		},
+		{
+			arguments:    operator.Arguments{"name": "value", "force": "true"},
+			errorMessage: "argument force must be a boolean, argument provided: true",
+		},
+		{
+			arguments:    operator.Arguments{"name": "value", "config": []any{}},
+			errorMessage: "argument config must be a map, argument provided: []",
+		},
+		{
+			arguments:    operator.Arguments{"name": "value", "state": "restarted"},
+			errorMessage: "argument state must be one of [started stopped], argument provided: restarted",
+		},
+		{
+			arguments:    operator.Arguments{"name": "value", "categories": "security"},
+			errorMessage: "argument categories must be a list, argument provided: security",
+		},
+		{
+			arguments:    operator.Arguments{"name": "value", "categories": []any{"security", "feature"}},
+			errorMessage: "argument categories item must be one of [security recommended], argument provided: feature",
+		},
+	}
+
+	for _, tc := range cases {
+		suite.EqualError(testSchema().Validate(tc.arguments), tc.errorMessage)
+	}
+}
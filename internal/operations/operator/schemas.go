@@ -0,0 +1,375 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator
+
+//nolint:gochecknoglobals
+var (
+	instanceNumberArgumentSchema = ArgumentSchema{
+		Name:        "instance_number",
+		Type:        ArgumentTypeString,
+		Description: "Instance number of the local SAP instance, e.g. \"00\"",
+		Required:    true,
+	}
+	sidArgumentSchema = ArgumentSchema{
+		Name:        "sid",
+		Type:        ArgumentTypeString,
+		Description: "SID of the HANA database, e.g. PRD",
+		Required:    true,
+	}
+	saptuneSolutionArgumentSchema = ArgumentSchema{
+		Name:        "solution",
+		Type:        ArgumentTypeString,
+		Description: "saptune solution name, e.g. HANA",
+		Required:    true,
+	}
+	saptuneNoteArgumentSchema = ArgumentSchema{
+		Name:        "note",
+		Type:        ArgumentTypeString,
+		Description: "saptune note ID, e.g. 2382421",
+		Required:    true,
+	}
+	sapSystemInstanceTypes = []any{
+		instanceTypeALL, instanceTypeABAP, instanceTypeJ2EE, instanceTypeSCS, instanceTypeENQREP,
+	}
+)
+
+func timeoutArgumentSchema(defaultTimeout float64) ArgumentSchema {
+	return ArgumentSchema{
+		Name:        "timeout",
+		Type:        ArgumentTypeNumber,
+		Description: "Timeout in seconds to wait until the desired state is reached",
+		Default:     defaultTimeout,
+	}
+}
+
+func noArgumentsSchema(description string) map[string]Schema {
+	return map[string]Schema{
+		"v1": {
+			Description: description,
+			Arguments:   []ArgumentSchema{},
+		},
+	}
+}
+
+func corosyncTotemArgumentsSchema() []ArgumentSchema {
+	arguments := make([]ArgumentSchema, 0, len(corosyncTotemOptions))
+	for _, option := range corosyncTotemOptions {
+		arguments = append(arguments, ArgumentSchema{
+			Name:        option,
+			Type:        ArgumentTypeInteger,
+			Description: "Value of the totem." + option + " corosync option",
+		})
+	}
+
+	return arguments
+}
+
+// standardSchemas returns the arguments schema of the operators in the standard registry.
+// The schemas only check the shape of the arguments, the operators still validate
+// the rules involving several arguments.
+//
+//nolint:maintidx
+func standardSchemas() SchemasTree {
+	return SchemasTree{
+		ClusterMaintenanceChangeOperatorName: {
+			"v1": {
+				Description: "Change the maintenance state of the cluster, a cluster resource or a cluster node",
+				Arguments: []ArgumentSchema{
+					{
+						Name:        "maintenance",
+						Type:        ArgumentTypeBoolean,
+						Description: "Desired maintenance state",
+						Required:    true,
+					},
+					{
+						Name:        "resource_id",
+						Type:        ArgumentTypeString,
+						Description: "Resource to change. Mutually exclusive with node_id",
+					},
+					{
+						Name:        "node_id",
+						Type:        ArgumentTypeString,
+						Description: "Node to change. Mutually exclusive with resource_id",
+					},
+				},
+			},
+		},
+		ClusterResourceRefreshOperatorName: {
+			"v1": {
+				Description: "Refresh the state of the cluster resources",
+				Arguments: []ArgumentSchema{
+					{
+						Name:        "resource_id",
+						Type:        ArgumentTypeString,
+						Description: "Resource to refresh. All the resources are refreshed if not given",
+					},
+					{
+						Name:        "node_id",
+						Type:        ArgumentTypeString,
+						Description: "Node where the resource is refreshed. Requires resource_id",
+					},
+				},
+			},
+		},
+		CorosyncTotemChangeOperatorName: {
+			"v1": {
+				Description: "Change corosync totem options. At least one option must be given",
+				Arguments:   corosyncTotemArgumentsSchema(),
+			},
+		},
+		CrmClusterStartOperatorName:  noArgumentsSchema("Start the cluster services in the host"),
+		CrmClusterStopOperatorName:   noArgumentsSchema("Stop the cluster services in the host"),
+		HostRebootOperatorName:       noArgumentsSchema("Schedule a host reboot"),
+		PacemakerEnableOperatorName:  noArgumentsSchema("Enable the pacemaker service"),
+		PacemakerDisableOperatorName: noArgumentsSchema("Disable the pacemaker service"),
+		HanaStartOperatorName: {
+			"v1": {
+				Description: "Start a HANA database instance not managed by the cluster",
+				Arguments: []ArgumentSchema{
+					sidArgumentSchema,
+					instanceNumberArgumentSchema,
+					timeoutArgumentSchema(defaultSapInstanceStateTimeout.Seconds()),
+				},
+			},
+		},
+		HanaStopOperatorName: {
+			"v1": {
+				Description: "Stop a HANA database instance not managed by the cluster",
+				Arguments: []ArgumentSchema{
+					sidArgumentSchema,
+					instanceNumberArgumentSchema,
+					timeoutArgumentSchema(defaultSapInstanceStateTimeout.Seconds()),
+					{
+						Name:        "allow_sr_primary",
+						Type:        ArgumentTypeBoolean,
+						Description: "Allow stopping a system replication primary instance",
+						Default:     false,
+					},
+				},
+			},
+		},
+		HostsEntryChangeOperatorName: {
+			"v1": {
+				Description: "Add, update or remove entries in the Trento managed block of /etc/hosts",
+				Arguments: []ArgumentSchema{
+					{
+						Name: "entries",
+						Type: ArgumentTypeList,
+						Description: "Entries to change. Each entry is a map with hostnames (list), " +
+							"ip (string, required if present) and state (present or absent, default present)",
+						Required: true,
+						Items: &ArgumentSchema{
+							Type:        ArgumentTypeMap,
+							Description: "Hosts entry",
+						},
+					},
+				},
+			},
+		},
+		SapInstanceStartOperatorName: {
+			"v1": {
+				Description: "Start a SAP instance",
+				Arguments: []ArgumentSchema{
+					instanceNumberArgumentSchema,
+					timeoutArgumentSchema(defaultSapInstanceStateTimeout.Seconds()),
+				},
+			},
+		},
+		SapInstanceStopOperatorName: {
+			"v1": {
+				Description: "Stop a SAP instance",
+				Arguments: []ArgumentSchema{
+					instanceNumberArgumentSchema,
+					timeoutArgumentSchema(defaultSapInstanceStateTimeout.Seconds()),
+				},
+			},
+		},
+		SapSystemStartOperatorName: {
+			"v1": {
+				Description: "Start a SAP system",
+				Arguments: []ArgumentSchema{
+					instanceNumberArgumentSchema,
+					timeoutArgumentSchema(defaultSapSystemStateTimeout.Seconds()),
+					{
+						Name:        "instance_type",
+						Type:        ArgumentTypeString,
+						Description: "Instance type to start",
+						Enum:        sapSystemInstanceTypes,
+						Default:     instanceTypeALL,
+					},
+				},
+			},
+		},
+		SapSystemStopOperatorName: {
+			"v1": {
+				Description: "Stop a SAP system",
+				Arguments: []ArgumentSchema{
+					instanceNumberArgumentSchema,
+					timeoutArgumentSchema(defaultSapSystemStateTimeout.Seconds()),
+					{
+						Name:        "instance_type",
+						Type:        ArgumentTypeString,
+						Description: "Instance type to stop",
+						Enum:        sapSystemInstanceTypes,
+						Default:     instanceTypeALL,
+					},
+				},
+			},
+		},
+		SaptuneApplySolutionOperatorName: {
+			"v1": {
+				Description: "Apply a saptune solution",
+				Arguments:   []ArgumentSchema{saptuneSolutionArgumentSchema},
+			},
+		},
+		SaptuneChangeSolutionOperatorName: {
+			"v1": {
+				Description: "Change the applied saptune solution",
+				Arguments:   []ArgumentSchema{saptuneSolutionArgumentSchema},
+			},
+		},
+		SaptuneRevertSolutionOperatorName: noArgumentsSchema("Revert the applied saptune solution"),
+		SaptuneApplyNoteOperatorName: {
+			"v1": {
+				Description: "Apply a saptune note",
+				Arguments:   []ArgumentSchema{saptuneNoteArgumentSchema},
+			},
+		},
+		SaptuneRevertNoteOperatorName: {
+			"v1": {
+				Description: "Revert a saptune note",
+				Arguments:   []ArgumentSchema{saptuneNoteArgumentSchema},
+			},
+		},
+		SbdConfigChangeOperatorName: {
+			"v1": {
+				Description: "Change the sbd configuration, applied on the next cluster start",
+				Arguments: []ArgumentSchema{
+					{
+						Name:        "config",
+						Type:        ArgumentTypeMap,
+						Description: "sbd entries to change with their desired values, e.g. {\"SBD_STARTMODE\": \"clean\"}",
+						Required:    true,
+					},
+					{
+						Name:        "force",
+						Type:        ArgumentTypeBoolean,
+						Description: "Required to change SBD_DEVICE",
+						Default:     false,
+					},
+				},
+			},
+		},
+		ServiceStateOperatorName: {
+			"v1": {
+				Description: "Start, stop or restart an allowed systemd unit",
+				Arguments: []ArgumentSchema{
+					{
+						Name:        "unit",
+						Type:        ArgumentTypeString,
+						Description: "systemd unit name. The .service suffix is added if no suffix is given",
+						Required:    true,
+					},
+					{
+						Name:        "state",
+						Type:        ArgumentTypeString,
+						Description: "Desired unit state",
+						Required:    true,
+						Enum:        []any{serviceStateStarted, serviceStateStopped, serviceStateRestarted},
+					},
+					timeoutArgumentSchema(defaultServiceStateTimeout.Seconds()),
+				},
+			},
+		},
+		SupportArchiveOperatorName: {
+			"v1": {
+				Description: "Collect a crm report or supportconfig archive and upload it to the server",
+				Arguments: []ArgumentSchema{
+					{
+						Name:        "tool",
+						Type:        ArgumentTypeString,
+						Description: "Tool collecting the archive",
+						Required:    true,
+						Enum:        []any{supportArchiveToolCrmReport, supportArchiveToolSupportconfig},
+					},
+					{
+						Name:        "from",
+						Type:        ArgumentTypeString,
+						Description: "Start of the collected time range in RFC3339 format, crm report only",
+					},
+					{
+						Name:        "to",
+						Type:        ArgumentTypeString,
+						Description: "End of the collected time range in RFC3339 format, crm report only",
+					},
+					timeoutArgumentSchema(defaultSupportArchiveTimeout.Seconds()),
+				},
+			},
+		},
+		SysctlApplyOperatorName: {
+			"v1": {
+				Description: "Set kernel parameters persistently",
+				Arguments: []ArgumentSchema{
+					{
+						Name:        "parameters",
+						Type:        ArgumentTypeMap,
+						Description: "Kernel parameters with their desired values, e.g. {\"vm.swappiness\": 10}",
+						Required:    true,
+					},
+				},
+			},
+		},
+		ZypperPatchOperatorName: {
+			"v1": {
+				Description: "Install the pending patches",
+				Arguments: []ArgumentSchema{
+					{
+						Name:        "categories",
+						Type:        ArgumentTypeList,
+						Description: "Only install patches of these categories",
+						Items: &ArgumentSchema{
+							Type: ArgumentTypeString,
+							Enum: []any{"security", "recommended", "optional", "feature", "document", "yast"},
+						},
+					},
+					{
+						Name:        "severities",
+						Type:        ArgumentTypeList,
+						Description: "Only install patches of these severities",
+						Items: &ArgumentSchema{
+							Type: ArgumentTypeString,
+							Enum: []any{"critical", "important", "moderate", "low", "unspecified"},
+						},
+					},
+					{
+						Name:        "patches",
+						Type:        ArgumentTypeList,
+						Description: "Only install these patches",
+						Items: &ArgumentSchema{
+							Type: ArgumentTypeString,
+						},
+					},
+				},
+			},
+		},
+		RunbookOperatorName: {
+			"v1": {
+				Description: "Run an ordered list of operators, rolling back the completed ones on failure",
+				Arguments: []ArgumentSchema{
+					{
+						Name: "steps",
+						Type: ArgumentTypeList,
+						Description: "Steps to run. Each step is a map with operator (<operatorName>@<version>) " +
+							"and arguments (map)",
+						Required: true,
+						Items: &ArgumentSchema{
+							Type:        ArgumentTypeMap,
+							Description: "Runbook step",
+						},
+					},
+				},
+			},
+		},
+	}
+}
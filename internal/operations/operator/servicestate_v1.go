@@ -0,0 +1,386 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/trento-project/agent/v3/internal/core/systemd"
+)
+
+const (
+	ServiceStateOperatorName    = "servicestate"
+	defaultServiceStateTimeout  = 2 * time.Minute
+	defaultServiceStateInterval = 2 * time.Second
+	serviceStateStarted         = "started"
+	serviceStateStopped         = "stopped"
+	serviceStateRestarted       = "restarted"
+	systemdServiceUnitSuffix    = ".service"
+	unitTypeSeparator           = "."
+)
+
+// DefaultServiceStateAllowedUnits lists the units that can be managed by the servicestate
+// operator when no custom allowlist is configured.
+// The SAP*_[0-9][0-9] pattern matches the sapstartsrv units registered by systemd enabled
+// SAP instances, e.g. SAPPRD_00.service.
+// sbd.service is not included, as it refuses manual start and stop and is controlled by pacemaker.
+func DefaultServiceStateAllowedUnits() []string {
+	return []string{
+		"sapinit.service",
+		"SAP*_[0-9][0-9].service",
+		"chronyd.service",
+		"saptune.service",
+	}
+}
+
+type ServiceStateOption Option[ServiceState]
+
+type serviceStateArguments struct {
+	unit    string
+	state   string
+	timeout time.Duration
+}
+
+type serviceStateDiffOutput struct {
+	Unit   string `json:"unit"`
+	Active bool   `json:"active"`
+}
+
+// ServiceState operator starts, stops or restarts a systemd unit.
+// Only the units included in the allowlist can be managed. The allowlist entries
+// support shell file name patterns, and the units without suffix are considered services.
+//
+// Arguments:
+//  unit (required): String with the name of the unit, e.g. chronyd or chronyd.service
+//  state (required): Desired state of the unit. Supported values: started, stopped and restarted
+//  timeout: Timeout in seconds to wait until the unit reaches the desired state
+//
+// # Execution Phases
+//
+// - PLAN:
+//   The operator connects to systemd and determines if the unit is active.
+//   The operation is skipped if the unit is already active and started is requested,
+//   or if the unit is already inactive and stopped is requested.
+//   The restarted state is always applied.
+//
+// - COMMIT:
+//   It starts, stops or restarts the systemd unit.
+//
+// - VERIFY:
+//   The operator waits until the unit reaches the desired active state.
+//
+// - ROLLBACK:
+//   If an error occurs during the COMMIT or VERIFY phase, the unit is started or stopped
+//   back to its previous active state.
+
+type ServiceState struct {
+	baseOperator
+
+	systemdLoader    systemd.Loader
+	systemdConnector systemd.Systemd
+	allowedUnits     []string
+	interval         time.Duration
+	parsedArguments  *serviceStateArguments
+}
+
+func WithCustomServiceStateSystemdLoader(systemdLoader systemd.Loader) ServiceStateOption {
+	return func(o *ServiceState) {
+		o.systemdLoader = systemdLoader
+	}
+}
+
+func WithServiceStateAllowedUnits(allowedUnits []string) ServiceStateOption {
+	return func(o *ServiceState) {
+		o.allowedUnits = allowedUnits
+	}
+}
+
+func WithCustomServiceStateInterval(interval time.Duration) ServiceStateOption {
+	return func(o *ServiceState) {
+		o.interval = interval
+	}
+}
+
+func NewServiceState(
+	arguments Arguments,
+	operationID string,
+	options Options[ServiceState],
+) *Executor {
+	serviceState := &ServiceState{
+		baseOperator: newBaseOperator(
+			ServiceStateOperatorName, operationID, arguments, options.BaseOperatorOptions...,
+		),
+		systemdLoader: systemd.NewDefaultSystemdLoader(),
+		allowedUnits:  DefaultServiceStateAllowedUnits(),
+		interval:      defaultServiceStateInterval,
+	}
+
+	for _, opt := range options.OperatorOptions {
+		opt(serviceState)
+	}
+
+	return &Executor{
+		phaser:      serviceState,
+		operationID: operationID,
+		logger:      serviceState.logger,
+	}
+}
+
+func (s *ServiceState) setup(ctx context.Context) error {
+	opArguments, err := parseServiceStateArguments(s.arguments)
+	if err != nil {
+		return err
+	}
+
+	s.parsedArguments = opArguments
+
+	if !isServiceStateUnitAllowed(s.parsedArguments.unit, s.allowedUnits) {
+		return fmt.Errorf("unit %s is not allowed to be managed", s.parsedArguments.unit)
+	}
+
+	systemdConnector, err := s.systemdLoader.NewSystemd(ctx, systemd.WithCustomLogger(s.logger))
+	if err != nil {
+		s.logger.Error("unable to initialize systemd connector", "error", err)
+
+		return fmt.Errorf("unable to initialize systemd connector: %w", err)
+	}
+
+	s.systemdConnector = systemdConnector
+
+	return nil
+}
+
+func (s *ServiceState) plan(ctx context.Context) (bool, error) {
+	err := s.setup(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	active, err := s.systemdConnector.IsActive(ctx, s.parsedArguments.unit)
+	if err != nil {
+		s.logger.Error("failed to check if unit is active", "unit", s.parsedArguments.unit, "error", err)
+
+		return false, fmt.Errorf("failed to check if %s unit is active: %w", s.parsedArguments.unit, err)
+	}
+
+	s.resources[beforeDiffField] = active
+
+	switch {
+	case s.parsedArguments.state == serviceStateStarted && active,
+		s.parsedArguments.state == serviceStateStopped && !active:
+		s.logger.Info("unit already in the desired state, skipping operation",
+			"unit", s.parsedArguments.unit,
+			"state", s.parsedArguments.state)
+		s.resources[afterDiffField] = active
+
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+func (s *ServiceState) commit(ctx context.Context) error {
+	unit := s.parsedArguments.unit
+
+	var err error
+
+	switch s.parsedArguments.state {
+	case serviceStateStarted:
+		err = s.systemdConnector.Start(ctx, unit)
+	case serviceStateStopped:
+		err = s.systemdConnector.Stop(ctx, unit)
+	case serviceStateRestarted:
+		err = s.systemdConnector.Restart(ctx, unit)
+	}
+
+	if err != nil {
+		s.logger.Error("failed to change unit state", "unit", unit, "state", s.parsedArguments.state, "error", err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (s *ServiceState) verify(ctx context.Context) error {
+	expectedActive := s.parsedArguments.state != serviceStateStopped
+
+	err := s.waitUntilUnitActiveState(ctx, expectedActive)
+	if err != nil {
+		return err
+	}
+
+	s.resources[afterDiffField] = expectedActive
+
+	return nil
+}
+
+func (s *ServiceState) rollback(ctx context.Context) error {
+	unit := s.parsedArguments.unit
+
+	previouslyActive, ok := s.resources[beforeDiffField].(bool)
+	if !ok {
+		return errors.New("previous unit state not found")
+	}
+
+	var err error
+
+	if previouslyActive {
+		err = s.systemdConnector.Start(ctx, unit)
+	} else {
+		err = s.systemdConnector.Stop(ctx, unit)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return s.waitUntilUnitActiveState(ctx, previouslyActive)
+}
+
+func (s *ServiceState) operationDiff(_ context.Context) map[string]any {
+	diff := make(map[string]any)
+
+	beforeActive, ok := s.resources[beforeDiffField].(bool)
+	if !ok {
+		panic(fmt.Sprintf("invalid beforeActive value: cannot parse '%s' to bool",
+			s.resources[beforeDiffField]))
+	}
+
+	afterActive, ok := s.resources[afterDiffField].(bool)
+	if !ok {
+		panic(fmt.Sprintf("invalid afterActive value: cannot parse '%s' to bool",
+			s.resources[afterDiffField]))
+	}
+
+	before, err := json.Marshal(serviceStateDiffOutput{
+		Unit:   s.parsedArguments.unit,
+		Active: beforeActive,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling before diff output: %v", err))
+	}
+
+	diff[beforeDiffField] = string(before)
+
+	after, err := json.Marshal(serviceStateDiffOutput{
+		Unit:   s.parsedArguments.unit,
+		Active: afterActive,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling after diff output: %v", err))
+	}
+
+	diff[afterDiffField] = string(after)
+
+	return diff
+}
+
+func (s *ServiceState) after(_ context.Context) {
+	s.systemdConnector.Close()
+}
+
+func (s *ServiceState) waitUntilUnitActiveState(ctx context.Context, expectedActive bool) error {
+	unit := s.parsedArguments.unit
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, s.parsedArguments.timeout)
+	defer cancel()
+
+	for {
+		active, err := s.systemdConnector.IsActive(timeoutCtx, unit)
+		if err != nil {
+			return fmt.Errorf("failed to check if %s unit is active: %w", unit, err)
+		}
+
+		if active == expectedActive {
+			return nil
+		}
+
+		if timeoutCtx.Err() != nil {
+			return fmt.Errorf("error waiting until unit %s is in desired state", unit)
+		}
+
+		err = sleepContext(timeoutCtx, s.interval)
+		if err != nil {
+			return fmt.Errorf("error waiting until unit %s is in desired state", unit)
+		}
+	}
+}
+
+// normalizeUnitName adds the service suffix to the unit names without a unit type suffix.
+func normalizeUnitName(unit string) string {
+	if strings.Contains(unit, unitTypeSeparator) {
+		return unit
+	}
+
+	return unit + systemdServiceUnitSuffix
+}
+
+func isServiceStateUnitAllowed(unit string, allowedUnits []string) bool {
+	return slices.ContainsFunc(allowedUnits, func(allowedUnit string) bool {
+		matched, err := path.Match(normalizeUnitName(allowedUnit), unit)
+
+		return err == nil && matched
+	})
+}
+
+func parseServiceStateArguments(rawArguments Arguments) (*serviceStateArguments, error) {
+	unitArgument, found := rawArguments["unit"]
+	if !found {
+		return nil, errors.New("argument unit not provided, could not use the operator")
+	}
+
+	unit, ok := unitArgument.(string)
+	if !ok {
+		return nil, fmt.Errorf(
+			"could not parse unit argument as string, argument provided: %v",
+			unitArgument,
+		)
+	}
+
+	if unit == "" {
+		return nil, errors.New("unit argument is empty")
+	}
+
+	stateArgument, found := rawArguments["state"]
+	if !found {
+		return nil, errors.New("argument state not provided, could not use the operator")
+	}
+
+	state, ok := stateArgument.(string)
+	if !ok {
+		return nil, fmt.Errorf(
+			"could not parse state argument as string, argument provided: %v",
+			stateArgument,
+		)
+	}
+
+	if !slices.Contains([]string{serviceStateStarted, serviceStateStopped, serviceStateRestarted}, state) {
+		return nil, fmt.Errorf(
+			"invalid state argument %s, supported values: %s, %s, %s",
+			state,
+			serviceStateStarted,
+			serviceStateStopped,
+			serviceStateRestarted,
+		)
+	}
+
+	timeout, err := parseTimeoutArgument(rawArguments, defaultServiceStateTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &serviceStateArguments{
+		unit:    normalizeUnitName(unit),
+		state:   state,
+		timeout: timeout,
+	}, nil
+}
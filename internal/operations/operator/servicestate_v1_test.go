@@ -0,0 +1,490 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/trento-project/agent/v3/internal/core/systemd/mocks"
+	"github.com/trento-project/agent/v3/internal/operations/operator"
+	"github.com/trento-project/agent/v3/pkg/utils"
+)
+
+type ServiceStateOperatorTestSuite struct {
+	suite.Suite
+
+	logger            *slog.Logger
+	mockSystemd       *mocks.MockSystemd
+	mockSystemdLoader *mocks.MockLoader
+}
+
+func TestServiceStateOperator(t *testing.T) {
+	suite.Run(t, new(ServiceStateOperatorTestSuite))
+}
+
+func (suite *ServiceStateOperatorTestSuite) SetupTest() {
+	suite.logger = utils.NewDefaultLogger("info")
+	suite.mockSystemd = mocks.NewMockSystemd(suite.T())
+	suite.mockSystemdLoader = mocks.NewMockLoader(suite.T())
+}
+
+func (suite *ServiceStateOperatorTestSuite) TestServiceStatePlanErrors() {
+	cases := []struct {
+		arguments    operator.Arguments
+		errorMessage string
+	}{
+		{
+			arguments:    operator.Arguments{},
+			errorMessage: "plan: argument unit not provided, could not use the operator",
+		},
+		{
+			arguments:    operator.Arguments{"unit": 1},
+			errorMessage: "plan: could not parse unit argument as string, argument provided: 1",
+		},
+		{
+			arguments:    operator.Arguments{"unit": ""},
+			errorMessage: "plan: unit argument is empty",
+		},
+		{
+			arguments:    operator.Arguments{"unit": "sbd"},
+			errorMessage: "plan: argument state not provided, could not use the operator",
+		},
+		{
+			arguments:    operator.Arguments{"unit": "sbd", "state": "reloaded"},
+			errorMessage: "plan: invalid state argument reloaded, supported values: started, stopped, restarted",
+		},
+		{
+			arguments:    operator.Arguments{"unit": "sbd", "state": "started", "timeout": "10"},
+			errorMessage: "plan: could not parse timeout argument as a number, argument provided: 10",
+		},
+		{
+			arguments:    operator.Arguments{"unit": "sbd", "state": "stopped"},
+			errorMessage: "plan: unit sbd.service is not allowed to be managed",
+		},
+		{
+			arguments:    operator.Arguments{"unit": "pacemaker", "state": "stopped"},
+			errorMessage: "plan: unit pacemaker.service is not allowed to be managed",
+		},
+		{
+			arguments:    operator.Arguments{"unit": "SAPPRD_ASCS.service", "state": "stopped"},
+			errorMessage: "plan: unit SAPPRD_ASCS.service is not allowed to be managed",
+		},
+	}
+
+	for _, tc := range cases {
+		report := operator.NewServiceState(
+			tc.arguments,
+			"test-op",
+			operator.Options[operator.ServiceState]{
+				BaseOperatorOptions: []operator.BaseOperatorOption{
+					operator.WithCustomLogger(suite.logger),
+				},
+				OperatorOptions: []operator.Option[operator.ServiceState]{
+					operator.Option[operator.ServiceState](operator.WithCustomServiceStateSystemdLoader(suite.mockSystemdLoader)),
+					operator.Option[operator.ServiceState](operator.WithCustomServiceStateInterval(0 * time.Second)),
+				},
+			},
+		).Run(context.Background())
+
+		suite.Nil(report.Success)
+		suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+		suite.Equal(tc.errorMessage, report.Error.Message)
+	}
+}
+
+func (suite *ServiceStateOperatorTestSuite) TestServiceStatePlanCustomAllowlist() {
+	report := operator.NewServiceState(
+		operator.Arguments{"unit": "sbd", "state": "started"},
+		"test-op",
+		operator.Options[operator.ServiceState]{
+			OperatorOptions: []operator.Option[operator.ServiceState]{
+				operator.Option[operator.ServiceState](operator.WithCustomServiceStateSystemdLoader(suite.mockSystemdLoader)),
+				operator.Option[operator.ServiceState](operator.WithServiceStateAllowedUnits([]string{"chronyd"})),
+			},
+		},
+	).Run(context.Background())
+
+	suite.Nil(report.Success)
+	suite.Equal("plan: unit sbd.service is not allowed to be managed", report.Error.Message)
+}
+
+func (suite *ServiceStateOperatorTestSuite) TestServiceStatePlanErrorDbusConnection() {
+	ctx := context.Background()
+
+	suite.mockSystemdLoader.On("NewSystemd", ctx, mock.Anything).
+		Return(nil, errors.New("dbus connection error")).
+		Once()
+
+	report := operator.NewServiceState(
+		operator.Arguments{"unit": "chronyd", "state": "started"},
+		"test-op",
+		operator.Options[operator.ServiceState]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.ServiceState]{
+				operator.Option[operator.ServiceState](operator.WithCustomServiceStateSystemdLoader(suite.mockSystemdLoader)),
+				operator.Option[operator.ServiceState](operator.WithCustomServiceStateInterval(0 * time.Second)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal("plan: unable to initialize systemd connector: dbus connection error", report.Error.Message)
+}
+
+func (suite *ServiceStateOperatorTestSuite) TestServiceStatePlanErrorIsActive() {
+	ctx := context.Background()
+
+	systemdLoaderCall := suite.mockSystemdLoader.On("NewSystemd", ctx, mock.Anything).
+		Return(suite.mockSystemd, nil).
+		Once()
+
+	suite.mockSystemd.On("IsActive", ctx, "chronyd.service").
+		Return(false, errors.New("systemd error")).
+		Once().
+		NotBefore(systemdLoaderCall)
+
+	report := operator.NewServiceState(
+		operator.Arguments{"unit": "chronyd", "state": "started"},
+		"test-op",
+		operator.Options[operator.ServiceState]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.ServiceState]{
+				operator.Option[operator.ServiceState](operator.WithCustomServiceStateSystemdLoader(suite.mockSystemdLoader)),
+				operator.Option[operator.ServiceState](operator.WithCustomServiceStateInterval(0 * time.Second)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal("plan: failed to check if chronyd.service unit is active: systemd error", report.Error.Message)
+}
+
+func (suite *ServiceStateOperatorTestSuite) TestServiceStatePlanAlreadyApplied() {
+	cases := []struct {
+		state        string
+		active       bool
+		expectedDiff string
+	}{
+		{state: "started", active: true, expectedDiff: `{"unit":"SAPPRD_00.service","active":true}`},
+		{state: "stopped", active: false, expectedDiff: `{"unit":"SAPPRD_00.service","active":false}`},
+	}
+
+	for _, tc := range cases {
+		ctx := context.Background()
+
+		suite.SetupTest()
+
+		systemdLoaderCall := suite.mockSystemdLoader.On("NewSystemd", ctx, mock.Anything).
+			Return(suite.mockSystemd, nil).
+			Once()
+
+		isActiveCall := suite.mockSystemd.On("IsActive", ctx, "SAPPRD_00.service").
+			Return(tc.active, nil).
+			Once().
+			NotBefore(systemdLoaderCall)
+
+		suite.mockSystemd.On("Close").
+			Return().
+			Once().
+			NotBefore(isActiveCall)
+
+		report := operator.NewServiceState(
+			operator.Arguments{"unit": "SAPPRD_00.service", "state": tc.state},
+			"test-op",
+			operator.Options[operator.ServiceState]{
+				BaseOperatorOptions: []operator.BaseOperatorOption{
+					operator.WithCustomLogger(suite.logger),
+				},
+				OperatorOptions: []operator.Option[operator.ServiceState]{
+					operator.Option[operator.ServiceState](operator.WithCustomServiceStateSystemdLoader(suite.mockSystemdLoader)),
+					operator.Option[operator.ServiceState](operator.WithCustomServiceStateInterval(0 * time.Second)),
+				},
+			},
+		).Run(ctx)
+
+		suite.Nil(report.Error)
+		suite.Equal(operator.PLAN, report.Success.LastPhase)
+		suite.Equal(map[string]any{"before": tc.expectedDiff, "after": tc.expectedDiff}, report.Success.Diff)
+	}
+}
+
+func (suite *ServiceStateOperatorTestSuite) TestServiceStateStartSuccess() {
+	ctx := context.Background()
+
+	systemdLoaderCall := suite.mockSystemdLoader.On("NewSystemd", ctx, mock.Anything).
+		Return(suite.mockSystemd, nil).
+		Once()
+
+	isActiveCall := suite.mockSystemd.On("IsActive", ctx, "sapinit.service").
+		Return(false, nil).
+		Once().
+		NotBefore(systemdLoaderCall)
+
+	startCall := suite.mockSystemd.On("Start", ctx, "sapinit.service").
+		Return(nil).
+		Once().
+		NotBefore(isActiveCall)
+
+	verifyInactiveCall := suite.mockSystemd.On("IsActive", mock.Anything, "sapinit.service").
+		Return(false, nil).
+		Once().
+		NotBefore(startCall)
+
+	verifyActiveCall := suite.mockSystemd.On("IsActive", mock.Anything, "sapinit.service").
+		Return(true, nil).
+		Once().
+		NotBefore(verifyInactiveCall)
+
+	suite.mockSystemd.On("Close").
+		Return().
+		Once().
+		NotBefore(verifyActiveCall)
+
+	report := operator.NewServiceState(
+		operator.Arguments{"unit": "sapinit", "state": "started"},
+		"test-op",
+		operator.Options[operator.ServiceState]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.ServiceState]{
+				operator.Option[operator.ServiceState](operator.WithCustomServiceStateSystemdLoader(suite.mockSystemdLoader)),
+				operator.Option[operator.ServiceState](operator.WithCustomServiceStateInterval(0 * time.Second)),
+			},
+		},
+	).Run(ctx)
+
+	expectedDiff := map[string]any{
+		"before": `{"unit":"sapinit.service","active":false}`,
+		"after":  `{"unit":"sapinit.service","active":true}`,
+	}
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.VERIFY, report.Success.LastPhase)
+	suite.Equal(expectedDiff, report.Success.Diff)
+}
+
+func (suite *ServiceStateOperatorTestSuite) TestServiceStateStopSuccess() {
+	ctx := context.Background()
+
+	systemdLoaderCall := suite.mockSystemdLoader.On("NewSystemd", ctx, mock.Anything).
+		Return(suite.mockSystemd, nil).
+		Once()
+
+	isActiveCall := suite.mockSystemd.On("IsActive", ctx, "chronyd.service").
+		Return(true, nil).
+		Once().
+		NotBefore(systemdLoaderCall)
+
+	stopCall := suite.mockSystemd.On("Stop", ctx, "chronyd.service").
+		Return(nil).
+		Once().
+		NotBefore(isActiveCall)
+
+	verifyCall := suite.mockSystemd.On("IsActive", mock.Anything, "chronyd.service").
+		Return(false, nil).
+		Once().
+		NotBefore(stopCall)
+
+	suite.mockSystemd.On("Close").
+		Return().
+		Once().
+		NotBefore(verifyCall)
+
+	report := operator.NewServiceState(
+		operator.Arguments{"unit": "chronyd.service", "state": "stopped"},
+		"test-op",
+		operator.Options[operator.ServiceState]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.ServiceState]{
+				operator.Option[operator.ServiceState](operator.WithCustomServiceStateSystemdLoader(suite.mockSystemdLoader)),
+				operator.Option[operator.ServiceState](operator.WithCustomServiceStateInterval(0 * time.Second)),
+			},
+		},
+	).Run(ctx)
+
+	expectedDiff := map[string]any{
+		"before": `{"unit":"chronyd.service","active":true}`,
+		"after":  `{"unit":"chronyd.service","active":false}`,
+	}
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.VERIFY, report.Success.LastPhase)
+	suite.Equal(expectedDiff, report.Success.Diff)
+}
+
+func (suite *ServiceStateOperatorTestSuite) TestServiceStateRestartSuccess() {
+	ctx := context.Background()
+
+	systemdLoaderCall := suite.mockSystemdLoader.On("NewSystemd", ctx, mock.Anything).
+		Return(suite.mockSystemd, nil).
+		Once()
+
+	isActiveCall := suite.mockSystemd.On("IsActive", ctx, "chronyd.service").
+		Return(true, nil).
+		Once().
+		NotBefore(systemdLoaderCall)
+
+	restartCall := suite.mockSystemd.On("Restart", ctx, "chronyd.service").
+		Return(nil).
+		Once().
+		NotBefore(isActiveCall)
+
+	verifyCall := suite.mockSystemd.On("IsActive", mock.Anything, "chronyd.service").
+		Return(true, nil).
+		Once().
+		NotBefore(restartCall)
+
+	suite.mockSystemd.On("Close").
+		Return().
+		Once().
+		NotBefore(verifyCall)
+
+	report := operator.NewServiceState(
+		operator.Arguments{"unit": "chronyd", "state": "restarted"},
+		"test-op",
+		operator.Options[operator.ServiceState]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.ServiceState]{
+				operator.Option[operator.ServiceState](operator.WithCustomServiceStateSystemdLoader(suite.mockSystemdLoader)),
+				operator.Option[operator.ServiceState](operator.WithCustomServiceStateInterval(0 * time.Second)),
+			},
+		},
+	).Run(ctx)
+
+	expectedDiff := map[string]any{
+		"before": `{"unit":"chronyd.service","active":true}`,
+		"after":  `{"unit":"chronyd.service","active":true}`,
+	}
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.VERIFY, report.Success.LastPhase)
+	suite.Equal(expectedDiff, report.Success.Diff)
+}
+
+func (suite *ServiceStateOperatorTestSuite) TestServiceStateCommitErrorRollback() {
+	ctx := context.Background()
+
+	systemdLoaderCall := suite.mockSystemdLoader.On("NewSystemd", ctx, mock.Anything).
+		Return(suite.mockSystemd, nil).
+		Once()
+
+	isActiveCall := suite.mockSystemd.On("IsActive", ctx, "saptune.service").
+		Return(false, nil).
+		Once().
+		NotBefore(systemdLoaderCall)
+
+	startCall := suite.mockSystemd.On("Start", ctx, "saptune.service").
+		Return(errors.New("failed to start service saptune.service: job finished with result failed")).
+		Once().
+		NotBefore(isActiveCall)
+
+	stopCall := suite.mockSystemd.On("Stop", ctx, "saptune.service").
+		Return(nil).
+		Once().
+		NotBefore(startCall)
+
+	rollbackVerifyCall := suite.mockSystemd.On("IsActive", mock.Anything, "saptune.service").
+		Return(false, nil).
+		Once().
+		NotBefore(stopCall)
+
+	suite.mockSystemd.On("Close").
+		Return().
+		Once().
+		NotBefore(rollbackVerifyCall)
+
+	report := operator.NewServiceState(
+		operator.Arguments{"unit": "saptune", "state": "started"},
+		"test-op",
+		operator.Options[operator.ServiceState]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.ServiceState]{
+				operator.Option[operator.ServiceState](operator.WithCustomServiceStateSystemdLoader(suite.mockSystemdLoader)),
+				operator.Option[operator.ServiceState](operator.WithCustomServiceStateInterval(0 * time.Second)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.COMMIT, report.Error.ErrorPhase)
+	suite.Equal(
+		"commit: failed to start service saptune.service: job finished with result failed",
+		report.Error.Message,
+	)
+}
+
+func (suite *ServiceStateOperatorTestSuite) TestServiceStateVerifyTimeoutFailedRollback() {
+	ctx := context.Background()
+
+	systemdLoaderCall := suite.mockSystemdLoader.On("NewSystemd", ctx, mock.Anything).
+		Return(suite.mockSystemd, nil).
+		Once()
+
+	isActiveCall := suite.mockSystemd.On("IsActive", ctx, "chronyd.service").
+		Return(true, nil).
+		Once().
+		NotBefore(systemdLoaderCall)
+
+	stopCall := suite.mockSystemd.On("Stop", ctx, "chronyd.service").
+		Return(nil).
+		Once().
+		NotBefore(isActiveCall)
+
+	suite.mockSystemd.On("IsActive", mock.Anything, "chronyd.service").
+		Return(true, nil).
+		NotBefore(stopCall)
+
+	startCall := suite.mockSystemd.On("Start", ctx, "chronyd.service").
+		Return(errors.New("failed to start service chronyd.service: job finished with result failed")).
+		Once().
+		NotBefore(stopCall)
+
+	suite.mockSystemd.On("Close").
+		Return().
+		Once().
+		NotBefore(startCall)
+
+	report := operator.NewServiceState(
+		operator.Arguments{
+			"unit":    "chronyd",
+			"state":   "stopped",
+			"timeout": 0.0,
+		},
+		"test-op",
+		operator.Options[operator.ServiceState]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.ServiceState]{
+				operator.Option[operator.ServiceState](operator.WithCustomServiceStateSystemdLoader(suite.mockSystemdLoader)),
+				operator.Option[operator.ServiceState](operator.WithCustomServiceStateInterval(0 * time.Second)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.ROLLBACK, report.Error.ErrorPhase)
+	suite.Equal(
+		"verify: error waiting until unit chronyd.service is in desired state; "+
+			"rollback: failed to start service chronyd.service: job finished with result failed",
+		report.Error.Message,
+	)
+}
@@ -0,0 +1,436 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/trento-project/agent/v3/internal/discovery/collector"
+	"github.com/trento-project/agent/v3/pkg/utils"
+)
+
+const (
+	SupportArchiveOperatorName      = "supportarchive"
+	supportArchiveToolCrmReport     = "crm_report"
+	supportArchiveToolSupportconfig = "supportconfig"
+	supportArchiveCrmPath           = "/usr/sbin/crm"
+	supportArchiveSupportconfigPath = "/sbin/supportconfig"
+	defaultSupportArchiveDir        = "/var/lib/trento/support-archives"
+	defaultSupportArchiveTimeRange  = 24 * time.Hour
+	defaultSupportArchiveTimeout    = 30 * time.Minute
+	supportArchiveDirMode           = 0700
+	crmReportTimeFormat             = "2006-01-02 15:04"
+)
+
+// the operation ID names the archive and is given to the collection tools, so only
+// characters that are safe in a file name are allowed
+var supportArchiveOperationIDPattern = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+
+// ArtifactUploader uploads the files collected by the operators to the server.
+type ArtifactUploader interface {
+	UploadArtifact(ctx context.Context, artifact collector.Artifact, content io.Reader) (string, error)
+	GetArtifactStatus(ctx context.Context, artifactID string) (collector.ArtifactStatus, error)
+}
+
+type SupportArchiveOption Option[SupportArchive]
+
+type supportArchiveArguments struct {
+	tool    string
+	from    time.Time
+	to      time.Time
+	timeout time.Duration
+}
+
+type supportArchiveDiffOutput struct {
+	Tool       string `json:"tool,omitempty"`
+	Path       string `json:"path,omitempty"`
+	Size       int64  `json:"size,omitempty"`
+	Checksum   string `json:"checksum,omitempty"`
+	ArtifactID string `json:"artifact_id,omitempty"`
+}
+
+// SupportArchive operator collects a support archive with crm report or supportconfig
+// and uploads it to the server.
+//
+// Arguments:
+//  tool (required): Tool collecting the archive. Supported values: crm_report and supportconfig
+//  from: Start of the collected time range in RFC3339 format. Defaults to 24 hours before the end
+//  to: End of the collected time range in RFC3339 format. Defaults to the current time
+//  timeout: Timeout in seconds to wait until the archive is collected
+//
+// The time range only applies to crm report, as supportconfig always collects the current state.
+//
+// # Execution Phases
+//
+// - PLAN:
+//   The arguments and the operation ID are validated and the managed archives directory is created.
+//   The operation ID may only contain letters, digits and hyphens, as it names the archive.
+//   The operation is never skipped, as each execution collects a new archive.
+//
+// - COMMIT:
+//   The archive is collected in the managed directory, named after the operation ID.
+//   Its sha256 checksum is calculated and the archive is uploaded in chunks to the server.
+//
+// - VERIFY:
+//   The server acknowledges the artifact as completed, with the uploaded size and checksum.
+//
+// - ROLLBACK:
+//   The collected archive is removed, as it was not uploaded properly.
+//
+// The local archive is removed once the operation finishes, as the server keeps the uploaded copy.
+// The diff contains the archive path, size, checksum and the artifact ID given by the server.
+
+type SupportArchive struct {
+	baseOperator
+
+	operationID     string
+	executor        utils.CommandExecutor
+	uploader        ArtifactUploader
+	archivesDir     string
+	now             func() time.Time
+	parsedArguments *supportArchiveArguments
+	archivePath     string
+}
+
+func WithCustomSupportArchiveExecutor(executor utils.CommandExecutor) SupportArchiveOption {
+	return func(o *SupportArchive) {
+		o.executor = executor
+	}
+}
+
+func WithSupportArchiveUploader(uploader ArtifactUploader) SupportArchiveOption {
+	return func(o *SupportArchive) {
+		o.uploader = uploader
+	}
+}
+
+func WithCustomSupportArchiveDir(archivesDir string) SupportArchiveOption {
+	return func(o *SupportArchive) {
+		o.archivesDir = archivesDir
+	}
+}
+
+func WithCustomSupportArchiveClock(now func() time.Time) SupportArchiveOption {
+	return func(o *SupportArchive) {
+		o.now = now
+	}
+}
+
+func NewSupportArchive(
+	arguments Arguments,
+	operationID string,
+	options Options[SupportArchive],
+) *Executor {
+	supportArchive := &SupportArchive{
+		baseOperator: newBaseOperator(
+			SupportArchiveOperatorName, operationID, arguments, options.BaseOperatorOptions...,
+		),
+		operationID: operationID,
+		executor:    utils.Executor{},
+		archivesDir: defaultSupportArchiveDir,
+		now:         time.Now,
+	}
+
+	for _, opt := range options.OperatorOptions {
+		opt(supportArchive)
+	}
+
+	return &Executor{
+		phaser:      supportArchive,
+		operationID: operationID,
+		logger:      supportArchive.logger,
+	}
+}
+
+func (s *SupportArchive) plan(_ context.Context) (bool, error) {
+	opArguments, err := parseSupportArchiveArguments(s.arguments, s.now())
+	if err != nil {
+		return false, err
+	}
+
+	s.parsedArguments = opArguments
+
+	if !supportArchiveOperationIDPattern.MatchString(s.operationID) {
+		return false, fmt.Errorf(
+			"invalid operation ID %q, only letters, digits and hyphens are allowed in the archive name",
+			s.operationID,
+		)
+	}
+
+	if s.uploader == nil {
+		return false, errors.New("artifact uploader not available, the archive cannot be uploaded")
+	}
+
+	err = os.MkdirAll(s.archivesDir, supportArchiveDirMode)
+	if err != nil {
+		return false, fmt.Errorf("error creating support archives directory %s: %w", s.archivesDir, err)
+	}
+
+	s.resources[beforeDiffField] = supportArchiveDiffOutput{}
+
+	return false, nil
+}
+
+func (s *SupportArchive) commit(ctx context.Context) error {
+	name := s.archiveName()
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, s.parsedArguments.timeout)
+	defer cancel()
+
+	var (
+		path    string
+		args    []string
+		pattern string
+	)
+
+	switch s.parsedArguments.tool {
+	case supportArchiveToolCrmReport:
+		path = supportArchiveCrmPath
+		args = []string{
+			"report",
+			"-f", s.parsedArguments.from.Local().Format(crmReportTimeFormat),
+			"-t", s.parsedArguments.to.Local().Format(crmReportTimeFormat),
+			filepath.Join(s.archivesDir, name),
+		}
+		pattern = filepath.Join(s.archivesDir, name+".tar*")
+	case supportArchiveToolSupportconfig:
+		path = supportArchiveSupportconfigPath
+		args = []string{"-Q", "-R", s.archivesDir, "-B", name}
+		pattern = filepath.Join(s.archivesDir, "scc_"+name+"*.t*z")
+	}
+
+	s.logger.Info("collecting support archive", "tool", s.parsedArguments.tool)
+
+	output, err := s.executor.CombinedOutputContext(timeoutCtx, path, args...)
+	if err != nil {
+		return fmt.Errorf("error collecting %s archive: %w, output: %s", s.parsedArguments.tool, err, string(output))
+	}
+
+	matches, err := filepath.Glob(pattern)
+	if err != nil || len(matches) == 0 {
+		return fmt.Errorf("%s archive not found in %s", s.parsedArguments.tool, s.archivesDir)
+	}
+
+	s.archivePath = matches[0]
+
+	size, checksum, err := fileChecksum(s.archivePath)
+	if err != nil {
+		return err
+	}
+
+	archive, err := os.Open(s.archivePath)
+	if err != nil {
+		return fmt.Errorf("error opening archive %s: %w", s.archivePath, err)
+	}
+	defer archive.Close()
+
+	s.logger.Info("uploading support archive", "path", s.archivePath, "size", size)
+
+	artifactID, err := s.uploader.UploadArtifact(ctx, collector.Artifact{
+		Name:     filepath.Base(s.archivePath),
+		Size:     size,
+		Checksum: checksum,
+	}, archive)
+	if err != nil {
+		return fmt.Errorf("error uploading archive %s: %w", s.archivePath, err)
+	}
+
+	s.resources[afterDiffField] = supportArchiveDiffOutput{
+		Tool:       s.parsedArguments.tool,
+		Path:       s.archivePath,
+		Size:       size,
+		Checksum:   checksum,
+		ArtifactID: artifactID,
+	}
+
+	return nil
+}
+
+func (s *SupportArchive) verify(ctx context.Context) error {
+	uploaded, ok := s.resources[afterDiffField].(supportArchiveDiffOutput)
+	if !ok {
+		return errors.New("uploaded archive not found")
+	}
+
+	status, err := s.uploader.GetArtifactStatus(ctx, uploaded.ArtifactID)
+	if err != nil {
+		return fmt.Errorf("error getting artifact %s status: %w", uploaded.ArtifactID, err)
+	}
+
+	if status.Status != collector.ArtifactStatusCompleted {
+		return fmt.Errorf("artifact %s upload not completed, status: %s", uploaded.ArtifactID, status.Status)
+	}
+
+	if status.Size != uploaded.Size || status.Checksum != uploaded.Checksum {
+		return fmt.Errorf("artifact %s size %d and checksum %s do not match the uploaded size %d and checksum %s",
+			uploaded.ArtifactID, status.Size, status.Checksum, uploaded.Size, uploaded.Checksum)
+	}
+
+	return nil
+}
+
+func (s *SupportArchive) rollback(_ context.Context) error {
+	return s.removeArchive()
+}
+
+func (s *SupportArchive) after(_ context.Context) {
+	err := s.removeArchive()
+	if err != nil {
+		s.logger.Warn("error removing the support archive", "error", err)
+	}
+}
+
+func (s *SupportArchive) removeArchive() error {
+	if s.archivePath == "" {
+		return nil
+	}
+
+	s.logger.Info("removing support archive", "path", s.archivePath)
+
+	err := os.Remove(s.archivePath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error removing archive %s: %w", s.archivePath, err)
+	}
+
+	return nil
+}
+
+func (s *SupportArchive) operationDiff(_ context.Context) map[string]any {
+	diff := make(map[string]any)
+
+	beforeArchive, ok := s.resources[beforeDiffField].(supportArchiveDiffOutput)
+	if !ok {
+		panic(fmt.Sprintf("invalid beforeArchive value: cannot parse '%v' to archive diff",
+			s.resources[beforeDiffField]))
+	}
+
+	afterArchive, ok := s.resources[afterDiffField].(supportArchiveDiffOutput)
+	if !ok {
+		panic(fmt.Sprintf("invalid afterArchive value: cannot parse '%v' to archive diff",
+			s.resources[afterDiffField]))
+	}
+
+	before, err := json.Marshal(beforeArchive)
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling before diff output: %v", err))
+	}
+
+	diff[beforeDiffField] = string(before)
+
+	after, err := json.Marshal(afterArchive)
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling after diff output: %v", err))
+	}
+
+	diff[afterDiffField] = string(after)
+
+	return diff
+}
+
+// archiveName names the archive after the operation, so archives of different operations don't collide.
+func (s *SupportArchive) archiveName() string {
+	return fmt.Sprintf("%s-%s", s.parsedArguments.tool, s.operationID)
+}
+
+func fileChecksum(path string) (int64, string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, "", fmt.Errorf("error opening archive %s: %w", path, err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return 0, "", fmt.Errorf("error calculating archive %s checksum: %w", path, err)
+	}
+
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func parseSupportArchiveArguments(rawArguments Arguments, now time.Time) (*supportArchiveArguments, error) {
+	toolArgument, found := rawArguments["tool"]
+	if !found {
+		return nil, errors.New("argument tool not provided, could not use the operator")
+	}
+
+	tool, ok := toolArgument.(string)
+	if !ok {
+		return nil, fmt.Errorf(
+			"could not parse tool argument as string, argument provided: %v",
+			toolArgument,
+		)
+	}
+
+	if !slices.Contains([]string{supportArchiveToolCrmReport, supportArchiveToolSupportconfig}, tool) {
+		return nil, fmt.Errorf(
+			"invalid tool argument %s, supported values: %s, %s",
+			tool,
+			supportArchiveToolCrmReport,
+			supportArchiveToolSupportconfig,
+		)
+	}
+
+	to, err := parseSupportArchiveTime(rawArguments, "to", now)
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := parseSupportArchiveTime(rawArguments, "from", to.Add(-defaultSupportArchiveTimeRange))
+	if err != nil {
+		return nil, err
+	}
+
+	if !from.Before(to) {
+		return nil, fmt.Errorf("from argument %s must be before to argument %s",
+			from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	timeout, err := parseTimeoutArgument(rawArguments, defaultSupportArchiveTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &supportArchiveArguments{
+		tool:    tool,
+		from:    from,
+		to:      to,
+		timeout: timeout,
+	}, nil
+}
+
+func parseSupportArchiveTime(rawArguments Arguments, name string, defaultTime time.Time) (time.Time, error) {
+	timeArgument, found := rawArguments[name]
+	if !found {
+		return defaultTime, nil
+	}
+
+	timeString, ok := timeArgument.(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf(
+			"could not parse %s argument as string, argument provided: %v",
+			name,
+			timeArgument,
+		)
+	}
+
+	parsedTime, err := time.Parse(time.RFC3339, timeString)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s argument %s, RFC3339 format expected", name, timeString)
+	}
+
+	return parsedTime, nil
+}
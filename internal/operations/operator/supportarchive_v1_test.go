@@ -0,0 +1,359 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/trento-project/agent/v3/internal/discovery/collector"
+	"github.com/trento-project/agent/v3/internal/operations/operator"
+	"github.com/trento-project/agent/v3/internal/operations/operator/mocks"
+	utilsMocks "github.com/trento-project/agent/v3/pkg/utils/mocks"
+)
+
+const supportArchiveContent = "support archive content"
+
+type SupportArchiveOperatorTestSuite struct {
+	suite.Suite
+
+	archivesDir  string
+	mockExecutor *utilsMocks.MockCommandExecutor
+	mockUploader *mocks.MockArtifactUploader
+	now          time.Time
+}
+
+func TestSupportArchiveOperator(t *testing.T) {
+	suite.Run(t, new(SupportArchiveOperatorTestSuite))
+}
+
+func (suite *SupportArchiveOperatorTestSuite) SetupTest() {
+	suite.archivesDir = filepath.Join(suite.T().TempDir(), "support-archives")
+	suite.mockExecutor = utilsMocks.NewMockCommandExecutor(suite.T())
+	suite.mockUploader = mocks.NewMockArtifactUploader(suite.T())
+	suite.now = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func (suite *SupportArchiveOperatorTestSuite) newSupportArchive(
+	arguments operator.Arguments,
+	uploader operator.ArtifactUploader,
+) *operator.Executor {
+	return suite.newSupportArchiveWithOperationID(arguments, uploader, "test-op")
+}
+
+func (suite *SupportArchiveOperatorTestSuite) newSupportArchiveWithOperationID(
+	arguments operator.Arguments,
+	uploader operator.ArtifactUploader,
+	operationID string,
+) *operator.Executor {
+	return operator.NewSupportArchive(
+		arguments,
+		operationID,
+		operator.Options[operator.SupportArchive]{
+			OperatorOptions: []operator.Option[operator.SupportArchive]{
+				operator.Option[operator.SupportArchive](operator.WithCustomSupportArchiveExecutor(suite.mockExecutor)),
+				operator.Option[operator.SupportArchive](operator.WithSupportArchiveUploader(uploader)),
+				operator.Option[operator.SupportArchive](operator.WithCustomSupportArchiveDir(suite.archivesDir)),
+				operator.Option[operator.SupportArchive](operator.WithCustomSupportArchiveClock(func() time.Time {
+					return suite.now
+				})),
+			},
+		},
+	)
+}
+
+// writeArchive simulates the archive created by the collection tool
+func (suite *SupportArchiveOperatorTestSuite) writeArchive(name string) func(mock.Arguments) {
+	return func(_ mock.Arguments) {
+		err := os.WriteFile(filepath.Join(suite.archivesDir, name), []byte(supportArchiveContent), 0600)
+		suite.Require().NoError(err)
+	}
+}
+
+func (suite *SupportArchiveOperatorTestSuite) completedArtifact() collector.ArtifactStatus {
+	return collector.ArtifactStatus{
+		ID:       "artifact-id",
+		Status:   collector.ArtifactStatusCompleted,
+		Size:     int64(len(supportArchiveContent)),
+		Checksum: supportArchiveChecksum(),
+	}
+}
+
+func supportArchiveChecksum() string {
+	checksum := sha256.Sum256([]byte(supportArchiveContent))
+
+	return hex.EncodeToString(checksum[:])
+}
+
+func (suite *SupportArchiveOperatorTestSuite) TestSupportArchiveInvalidArguments() {
+	cases := []struct {
+		arguments operator.Arguments
+		expected  string
+	}{
+		{
+			arguments: operator.Arguments{},
+			expected:  "plan: argument tool not provided, could not use the operator",
+		},
+		{
+			arguments: operator.Arguments{"tool": "hb_report"},
+			expected:  "plan: invalid tool argument hb_report, supported values: crm_report, supportconfig",
+		},
+		{
+			arguments: operator.Arguments{"tool": "crm_report", "from": "yesterday"},
+			expected:  "plan: invalid from argument yesterday, RFC3339 format expected",
+		},
+		{
+			arguments: operator.Arguments{
+				"tool": "crm_report",
+				"from": "2025-01-01T10:00:00Z",
+				"to":   "2025-01-01T09:00:00Z",
+			},
+			expected: "plan: from argument 2025-01-01T10:00:00Z must be before to argument 2025-01-01T09:00:00Z",
+		},
+	}
+
+	for _, tc := range cases {
+		report := suite.newSupportArchive(tc.arguments, suite.mockUploader).Run(context.Background())
+
+		suite.Nil(report.Success)
+		suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+		suite.Equal(tc.expected, report.Error.Message)
+	}
+}
+
+func (suite *SupportArchiveOperatorTestSuite) TestSupportArchiveInvalidOperationID() {
+	cases := []struct {
+		operationID string
+		expected    string
+	}{
+		{
+			operationID: "",
+			expected:    `plan: invalid operation ID "", only letters, digits and hyphens are allowed in the archive name`,
+		},
+		{
+			operationID: "../../etc/cron.d/op",
+			expected: `plan: invalid operation ID "../../etc/cron.d/op", ` +
+				"only letters, digits and hyphens are allowed in the archive name",
+		},
+		{
+			operationID: "-o /tmp/op",
+			expected: `plan: invalid operation ID "-o /tmp/op", ` +
+				"only letters, digits and hyphens are allowed in the archive name",
+		},
+	}
+
+	for _, tc := range cases {
+		report := suite.newSupportArchiveWithOperationID(
+			operator.Arguments{"tool": "crm_report"},
+			suite.mockUploader,
+			tc.operationID,
+		).Run(context.Background())
+
+		suite.Nil(report.Success)
+		suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+		suite.Equal(tc.expected, report.Error.Message)
+		suite.NoDirExists(suite.archivesDir)
+	}
+}
+
+func (suite *SupportArchiveOperatorTestSuite) TestSupportArchiveUploaderNotAvailable() {
+	report := suite.newSupportArchive(operator.Arguments{"tool": "crm_report"}, nil).Run(context.Background())
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal("plan: artifact uploader not available, the archive cannot be uploaded", report.Error.Message)
+}
+
+func (suite *SupportArchiveOperatorTestSuite) TestSupportArchiveCrmReportSuccess() {
+	ctx := context.Background()
+	from := time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)
+
+	suite.mockExecutor.On(
+		"CombinedOutputContext",
+		mock.Anything,
+		"/usr/sbin/crm",
+		"report",
+		"-f", from.Local().Format("2006-01-02 15:04"),
+		"-t", suite.now.Local().Format("2006-01-02 15:04"),
+		filepath.Join(suite.archivesDir, "crm_report-test-op"),
+	).
+		Run(suite.writeArchive("crm_report-test-op.tar.bz2")).
+		Return([]byte("ok"), nil).
+		Once()
+
+	suite.mockUploader.On(
+		"UploadArtifact",
+		ctx,
+		collector.Artifact{
+			Name:     "crm_report-test-op.tar.bz2",
+			Size:     int64(len(supportArchiveContent)),
+			Checksum: supportArchiveChecksum(),
+		},
+		mock.MatchedBy(func(content io.Reader) bool {
+			uploaded, err := io.ReadAll(content)
+
+			return err == nil && string(uploaded) == supportArchiveContent
+		}),
+	).
+		Return("artifact-id", nil).
+		Once()
+
+	suite.mockUploader.On("GetArtifactStatus", ctx, "artifact-id").
+		Return(suite.completedArtifact(), nil).
+		Once()
+
+	report := suite.newSupportArchive(operator.Arguments{
+		"tool": "crm_report",
+		"from": "2025-01-01T08:00:00Z",
+	}, suite.mockUploader).Run(ctx)
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.VERIFY, report.Success.LastPhase)
+	suite.Equal(map[string]any{
+		"before": `{}`,
+		"after": `{"tool":"crm_report","path":"` +
+			filepath.Join(suite.archivesDir, "crm_report-test-op.tar.bz2") +
+			`","size":23,"checksum":"` + supportArchiveChecksum() + `","artifact_id":"artifact-id"}`,
+	}, report.Success.Diff)
+	suite.NoFileExists(filepath.Join(suite.archivesDir, "crm_report-test-op.tar.bz2"))
+}
+
+func (suite *SupportArchiveOperatorTestSuite) TestSupportArchiveSupportconfigSuccess() {
+	ctx := context.Background()
+
+	suite.mockExecutor.On(
+		"CombinedOutputContext",
+		mock.Anything,
+		"/sbin/supportconfig",
+		"-Q", "-R", suite.archivesDir, "-B", "supportconfig-test-op",
+	).
+		Run(suite.writeArchive("scc_supportconfig-test-op.txz")).
+		Return([]byte("ok"), nil).
+		Once()
+
+	suite.mockUploader.On("UploadArtifact", ctx, mock.Anything, mock.Anything).
+		Return("artifact-id", nil).
+		Once()
+
+	suite.mockUploader.On("GetArtifactStatus", ctx, "artifact-id").
+		Return(suite.completedArtifact(), nil).
+		Once()
+
+	report := suite.newSupportArchive(operator.Arguments{"tool": "supportconfig"}, suite.mockUploader).Run(ctx)
+
+	suite.Nil(report.Error)
+	suite.Contains(report.Success.Diff["after"], `"artifact_id":"artifact-id"`)
+	suite.NoFileExists(filepath.Join(suite.archivesDir, "scc_supportconfig-test-op.txz"))
+}
+
+func (suite *SupportArchiveOperatorTestSuite) TestSupportArchiveCollectionError() {
+	suite.mockExecutor.On(
+		"CombinedOutputContext",
+		mock.Anything,
+		"/sbin/supportconfig",
+		"-Q", "-R", suite.archivesDir, "-B", "supportconfig-test-op",
+	).
+		Return([]byte("no space left"), errors.New("exit status 1")).
+		Once()
+
+	report := suite.newSupportArchive(
+		operator.Arguments{"tool": "supportconfig"},
+		suite.mockUploader,
+	).Run(context.Background())
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.COMMIT, report.Error.ErrorPhase)
+	suite.Equal(
+		"commit: error collecting supportconfig archive: exit status 1, output: no space left",
+		report.Error.Message,
+	)
+}
+
+func (suite *SupportArchiveOperatorTestSuite) TestSupportArchiveUploadErrorRollback() {
+	ctx := context.Background()
+
+	suite.mockExecutor.On("CombinedOutputContext", mock.Anything, "/usr/sbin/crm", mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(suite.writeArchive("crm_report-test-op.tar.bz2")).
+		Return([]byte("ok"), nil).
+		Once()
+
+	suite.mockUploader.On("UploadArtifact", ctx, mock.Anything, mock.Anything).
+		Return("", errors.New("server unavailable")).
+		Once()
+
+	report := suite.newSupportArchive(operator.Arguments{"tool": "crm_report"}, suite.mockUploader).Run(ctx)
+
+	archivePath := filepath.Join(suite.archivesDir, "crm_report-test-op.tar.bz2")
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.COMMIT, report.Error.ErrorPhase)
+	suite.Equal("commit: error uploading archive "+archivePath+": server unavailable", report.Error.Message)
+	suite.NoFileExists(archivePath)
+}
+
+func (suite *SupportArchiveOperatorTestSuite) TestSupportArchiveVerifyError() {
+	incomplete := suite.completedArtifact()
+	incomplete.Status = "uploading"
+
+	mismatch := suite.completedArtifact()
+	mismatch.Checksum = "other-checksum"
+
+	cases := []struct {
+		status    collector.ArtifactStatus
+		statusErr error
+		expected  string
+	}{
+		{
+			statusErr: errors.New("server unavailable"),
+			expected:  "verify: error getting artifact artifact-id status: server unavailable",
+		},
+		{
+			status:   incomplete,
+			expected: "verify: artifact artifact-id upload not completed, status: uploading",
+		},
+		{
+			status: mismatch,
+			expected: "verify: artifact artifact-id size 23 and checksum other-checksum do not match " +
+				"the uploaded size 23 and checksum " + supportArchiveChecksum(),
+		},
+	}
+
+	for _, tc := range cases {
+		ctx := context.Background()
+		mockExecutor := utilsMocks.NewMockCommandExecutor(suite.T())
+		mockUploader := mocks.NewMockArtifactUploader(suite.T())
+		suite.mockExecutor = mockExecutor
+
+		mockExecutor.On("CombinedOutputContext", mock.Anything, "/usr/sbin/crm", mock.Anything,
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Run(suite.writeArchive("crm_report-test-op.tar.bz2")).
+			Return([]byte("ok"), nil).
+			Once()
+
+		mockUploader.On("UploadArtifact", ctx, mock.Anything, mock.Anything).
+			Return("artifact-id", nil).
+			Once()
+
+		mockUploader.On("GetArtifactStatus", ctx, "artifact-id").
+			Return(tc.status, tc.statusErr).
+			Once()
+
+		report := suite.newSupportArchive(operator.Arguments{"tool": "crm_report"}, mockUploader).Run(ctx)
+
+		suite.Nil(report.Success)
+		suite.Equal(operator.VERIFY, report.Error.ErrorPhase)
+		suite.Equal(tc.expected, report.Error.Message)
+		suite.NoFileExists(filepath.Join(suite.archivesDir, "crm_report-test-op.tar.bz2"))
+	}
+}
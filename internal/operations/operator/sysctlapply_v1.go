@@ -0,0 +1,451 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/trento-project/agent/v3/internal/core/saptune"
+	"github.com/trento-project/agent/v3/pkg/utils"
+)
+
+const (
+	SysctlApplyOperatorName = "sysctlapply"
+	sysctlPath              = "/sbin/sysctl"
+	sysctlDropInPath        = "/etc/sysctl.d/90-trento-agent.conf"
+	sysctlDropInHeader      = "# Kernel parameters managed by Trento Agent.\n" +
+		"# Manual changes in this file might be overwritten.\n"
+)
+
+type SysctlApplyOption Option[SysctlApply]
+
+type sysctlApplyArguments struct {
+	parameters map[string]string
+}
+
+type sysctlDiffOutput struct {
+	Parameters map[string]string `json:"parameters"`
+}
+
+// SysctlApply is an operator responsible for setting kernel parameters persistently.
+//
+// The operator accepts the next arguments:
+// - parameters (map): The kernel parameters to set with their desired values.
+//                     Values can be strings or integers. Parameters with multiple values,
+//                     like net.ipv4.tcp_rmem, are given as a space separated string.
+//
+// Example: {"parameters": {"vm.swappiness": 10, "net.ipv4.tcp_rmem": "4096 131072 6291456"}}
+//
+// The parameters are stored in a dedicated /etc/sysctl.d/90-trento-agent.conf drop-in,
+// so they are kept after a reboot. Parameters set by previous executions are kept in
+// the drop-in.
+//
+// Parameters managed by saptune are not changed, as saptune would revert them.
+// They must be changed using saptune notes instead.
+//
+// # Execution Phases
+//
+// - PLAN:
+//   The current runtime values of the parameters are read with `sysctl -a` and stored as
+//   the "before" diff. The operation fails if any of the parameters doesn't exist or if it is
+//   managed by any of the saptune notes applied in the system.
+//   The current drop-in content is stored to be able to restore it.
+//   If the runtime values and the drop-in already have the requested values, the operation is skipped.
+//
+// - COMMIT:
+//   The parameters are written in the drop-in and applied at runtime with `sysctl -w`.
+//
+// - VERIFY:
+//   The runtime values are read again with `sysctl -a` and compared with the requested values.
+//
+// - ROLLBACK:
+//   The previous drop-in is restored, or removed if it didn't exist, and the previous runtime
+//   values are applied again.
+
+type SysctlApply struct {
+	baseOperator
+
+	executor              utils.CommandExecutor
+	saptune               saptune.Saptune
+	dropInPath            string
+	previousDropIn        []byte
+	previousDropInExisted bool
+	parsedArguments       *sysctlApplyArguments
+}
+
+func WithCustomSysctlApplyExecutor(executor utils.CommandExecutor) SysctlApplyOption {
+	return func(o *SysctlApply) {
+		o.executor = executor
+	}
+}
+
+func WithSaptuneClientSysctlApply(saptuneClient saptune.Saptune) SysctlApplyOption {
+	return func(o *SysctlApply) {
+		o.saptune = saptuneClient
+	}
+}
+
+func WithCustomSysctlDropInPath(dropInPath string) SysctlApplyOption {
+	return func(o *SysctlApply) {
+		o.dropInPath = dropInPath
+	}
+}
+
+func NewSysctlApply(
+	arguments Arguments,
+	operationID string,
+	options Options[SysctlApply],
+) *Executor {
+	sysctlApply := &SysctlApply{
+		baseOperator: newBaseOperator(
+			SysctlApplyOperatorName, operationID, arguments, options.BaseOperatorOptions...,
+		),
+		executor:   utils.Executor{},
+		dropInPath: sysctlDropInPath,
+	}
+
+	sysctlApply.saptune = saptune.NewSaptuneClient(
+		utils.Executor{},
+		sysctlApply.logger,
+	)
+
+	for _, opt := range options.OperatorOptions {
+		opt(sysctlApply)
+	}
+
+	return &Executor{
+		phaser:      sysctlApply,
+		operationID: operationID,
+		logger:      sysctlApply.logger,
+	}
+}
+
+func (s *SysctlApply) plan(ctx context.Context) (bool, error) {
+	opArguments, err := parseSysctlApplyArguments(s.arguments)
+	if err != nil {
+		return false, err
+	}
+
+	s.parsedArguments = opArguments
+
+	runtimeValues, err := s.getRuntimeValues(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	s.resources[beforeDiffField] = runtimeValues
+
+	err = s.checkSaptuneConflicts(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	dropInContent, err := os.ReadFile(s.dropInPath)
+	switch {
+	case err == nil:
+		s.previousDropIn = dropInContent
+		s.previousDropInExisted = true
+	case errors.Is(err, os.ErrNotExist):
+		s.previousDropInExisted = false
+	default:
+		return false, fmt.Errorf("error reading sysctl drop-in %s: %w", s.dropInPath, err)
+	}
+
+	dropInValues := parseSysctlConf(s.previousDropIn)
+	alreadyApplied := true
+
+	for parameter, value := range s.parsedArguments.parameters {
+		if runtimeValues[parameter] != value || dropInValues[parameter] != value {
+			alreadyApplied = false
+		}
+	}
+
+	if alreadyApplied {
+		s.logger.Info("kernel parameters already set, skipping operation", "parameters", s.parsedArguments.parameters)
+		s.resources[afterDiffField] = runtimeValues
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (s *SysctlApply) commit(ctx context.Context) error {
+	dropInValues := parseSysctlConf(s.previousDropIn)
+	for parameter, value := range s.parsedArguments.parameters {
+		dropInValues[parameter] = value
+	}
+
+	err := writeFileAtomically(s.dropInPath, renderSysctlConf(dropInValues))
+	if err != nil {
+		return err
+	}
+
+	return s.setRuntimeValues(ctx, s.parsedArguments.parameters)
+}
+
+func (s *SysctlApply) verify(ctx context.Context) error {
+	runtimeValues, err := s.getRuntimeValues(ctx)
+	if err != nil {
+		return err
+	}
+
+	for parameter, value := range s.parsedArguments.parameters {
+		if runtimeValues[parameter] != value {
+			return fmt.Errorf(
+				"kernel parameter %s has runtime value %s, expected %s",
+				parameter, runtimeValues[parameter], value,
+			)
+		}
+	}
+
+	s.resources[afterDiffField] = runtimeValues
+
+	return nil
+}
+
+func (s *SysctlApply) rollback(ctx context.Context) error {
+	var err error
+
+	if s.previousDropInExisted {
+		err = writeFileAtomically(s.dropInPath, s.previousDropIn)
+	} else {
+		err = os.Remove(s.dropInPath)
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("error restoring sysctl drop-in %s: %w", s.dropInPath, err)
+	}
+
+	previousValues, _ := s.resources[beforeDiffField].(map[string]string)
+
+	return s.setRuntimeValues(ctx, previousValues)
+}
+
+func (s *SysctlApply) operationDiff(_ context.Context) map[string]any {
+	diff := make(map[string]any)
+
+	beforeParameters, ok := s.resources[beforeDiffField].(map[string]string)
+	if !ok {
+		panic(fmt.Sprintf("invalid beforeParameters value: cannot parse '%v' to map",
+			s.resources[beforeDiffField]))
+	}
+
+	afterParameters, ok := s.resources[afterDiffField].(map[string]string)
+	if !ok {
+		panic(fmt.Sprintf("invalid afterParameters value: cannot parse '%v' to map",
+			s.resources[afterDiffField]))
+	}
+
+	before, err := json.Marshal(sysctlDiffOutput{Parameters: beforeParameters})
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling before diff output: %v", err))
+	}
+
+	diff[beforeDiffField] = string(before)
+
+	after, err := json.Marshal(sysctlDiffOutput{Parameters: afterParameters})
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling after diff output: %v", err))
+	}
+
+	diff[afterDiffField] = string(after)
+
+	return diff
+}
+
+// getRuntimeValues returns the runtime values of the requested parameters.
+func (s *SysctlApply) getRuntimeValues(ctx context.Context) (map[string]string, error) {
+	output, err := s.executor.OutputContext(ctx, sysctlPath, "-a")
+	if err != nil {
+		return nil, fmt.Errorf("error reading kernel parameters: %w", err)
+	}
+
+	allValues := parseSysctlConf(output)
+	values := make(map[string]string, len(s.parsedArguments.parameters))
+
+	for parameter := range s.parsedArguments.parameters {
+		value, found := allValues[parameter]
+		if !found {
+			return nil, fmt.Errorf("kernel parameter %s not found", parameter)
+		}
+
+		values[parameter] = value
+	}
+
+	return values, nil
+}
+
+func (s *SysctlApply) setRuntimeValues(ctx context.Context, values map[string]string) error {
+	parameters := make([]string, 0, len(values))
+	for parameter := range values {
+		parameters = append(parameters, parameter)
+	}
+
+	slices.Sort(parameters)
+
+	for _, parameter := range parameters {
+		output, err := s.executor.CombinedOutputContext(
+			ctx, sysctlPath, "-w", parameter+"="+values[parameter])
+		if err != nil {
+			return fmt.Errorf("error setting kernel parameter %s: %w, output: %s", parameter, err, string(output))
+		}
+	}
+
+	return nil
+}
+
+// checkSaptuneConflicts returns an error if any of the requested parameters is
+// managed by the saptune notes applied in the system.
+// The check is skipped if saptune is not installed.
+func (s *SysctlApply) checkSaptuneConflicts(ctx context.Context) error {
+	version, err := s.saptune.GetVersion(ctx)
+	if err != nil {
+		s.logger.Info("saptune not installed, skipping saptune conflicts check")
+
+		return nil
+	}
+
+	if !saptune.IsJSONSupported(version) {
+		return fmt.Errorf(
+			"could not check saptune managed parameters, saptune version not supported: %s",
+			version,
+		)
+	}
+
+	output, err := s.saptune.VerifyNote(ctx)
+	if err != nil {
+		return fmt.Errorf("could not check saptune managed parameters: %w", err)
+	}
+
+	conflicts := []string{}
+
+	for _, managedParameter := range gjson.GetBytes(output, "result.verifications.#.parameter").Array() {
+		_, requested := s.parsedArguments.parameters[managedParameter.String()]
+		if requested && !slices.Contains(conflicts, managedParameter.String()) {
+			conflicts = append(conflicts, managedParameter.String())
+		}
+	}
+
+	if len(conflicts) > 0 {
+		slices.Sort(conflicts)
+
+		return fmt.Errorf(
+			"kernel parameters managed by saptune, change them using saptune instead: %s",
+			strings.Join(conflicts, ", "),
+		)
+	}
+
+	return nil
+}
+
+// parseSysctlConf parses the output of `sysctl -a` and sysctl.conf files,
+// normalizing the whitespaces of values with multiple fields.
+func parseSysctlConf(content []byte) map[string]string {
+	values := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+
+		values[strings.TrimSpace(key)] = normalizeSysctlValue(value)
+	}
+
+	return values
+}
+
+func renderSysctlConf(values map[string]string) []byte {
+	parameters := make([]string, 0, len(values))
+	for parameter := range values {
+		parameters = append(parameters, parameter)
+	}
+
+	slices.Sort(parameters)
+
+	var content strings.Builder
+
+	content.WriteString(sysctlDropInHeader)
+
+	for _, parameter := range parameters {
+		content.WriteString(parameter + " = " + values[parameter] + "\n")
+	}
+
+	return []byte(content.String())
+}
+
+func normalizeSysctlValue(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+func parseSysctlApplyArguments(rawArguments Arguments) (*sysctlApplyArguments, error) {
+	parametersArgument, found := rawArguments["parameters"]
+	if !found {
+		return nil, errors.New("argument parameters not provided, could not use the operator")
+	}
+
+	rawParameters, ok := parametersArgument.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf(
+			"could not parse parameters argument as map, argument provided: %v",
+			parametersArgument,
+		)
+	}
+
+	if len(rawParameters) == 0 {
+		return nil, errors.New("parameters argument is empty")
+	}
+
+	parameters := make(map[string]string, len(rawParameters))
+
+	for parameter, rawValue := range rawParameters {
+		if parameter == "" || strings.ContainsAny(parameter, "= \t\n") {
+			return nil, fmt.Errorf("invalid kernel parameter name: %q", parameter)
+		}
+
+		var value string
+
+		switch typedValue := rawValue.(type) {
+		case string:
+			value = normalizeSysctlValue(typedValue)
+		case float64:
+			value = strconv.FormatFloat(typedValue, 'f', -1, 64)
+		case int:
+			value = strconv.Itoa(typedValue)
+		default:
+			return nil, fmt.Errorf("could not parse value of kernel parameter %s: %v", parameter, rawValue)
+		}
+
+		if value == "" {
+			return nil, fmt.Errorf("invalid value for kernel parameter %s: %q", parameter, value)
+		}
+
+		parameters[parameter] = value
+	}
+
+	return &sysctlApplyArguments{
+		parameters: parameters,
+	}, nil
+}
@@ -0,0 +1,397 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	saptuneMocks "github.com/trento-project/agent/v3/internal/core/saptune/mocks"
+	"github.com/trento-project/agent/v3/internal/operations/operator"
+	"github.com/trento-project/agent/v3/pkg/utils"
+	utilsMocks "github.com/trento-project/agent/v3/pkg/utils/mocks"
+)
+
+const sysctlNoteVerifyOutput = `{"result":{"verifications":[` +
+	`{"Note ID":"1771258","parameter":"LIMIT_@dba_hard_nofile","compliant":true},` +
+	`{"Note ID":"2382421","parameter":"net.ipv4.tcp_slow_start_after_idle","compliant":true}]}}`
+
+type SysctlApplyOperatorTestSuite struct {
+	suite.Suite
+
+	logger            *slog.Logger
+	mockExecutor      *utilsMocks.MockCommandExecutor
+	mockSaptuneClient *saptuneMocks.MockSaptune
+	dropInPath        string
+}
+
+func TestSysctlApplyOperator(t *testing.T) {
+	suite.Run(t, new(SysctlApplyOperatorTestSuite))
+}
+
+func (suite *SysctlApplyOperatorTestSuite) SetupTest() {
+	suite.logger = utils.NewDefaultLogger("info")
+	suite.mockExecutor = utilsMocks.NewMockCommandExecutor(suite.T())
+	suite.mockSaptuneClient = saptuneMocks.NewMockSaptune(suite.T())
+	suite.dropInPath = path.Join(suite.T().TempDir(), "90-trento-agent.conf")
+}
+
+func (suite *SysctlApplyOperatorTestSuite) TestSysctlApplyPlanErrorParsingArguments() {
+	cases := []struct {
+		arguments    operator.Arguments
+		errorMessage string
+	}{
+		{
+			arguments:    operator.Arguments{},
+			errorMessage: "plan: argument parameters not provided, could not use the operator",
+		},
+		{
+			arguments:    operator.Arguments{"parameters": "vm.swappiness=10"},
+			errorMessage: "plan: could not parse parameters argument as map, argument provided: vm.swappiness=10",
+		},
+		{
+			arguments:    operator.Arguments{"parameters": map[string]any{}},
+			errorMessage: "plan: parameters argument is empty",
+		},
+		{
+			arguments:    operator.Arguments{"parameters": map[string]any{"vm.swappiness=1": "10"}},
+			errorMessage: `plan: invalid kernel parameter name: "vm.swappiness=1"`,
+		},
+		{
+			arguments:    operator.Arguments{"parameters": map[string]any{"vm.swappiness": true}},
+			errorMessage: "plan: could not parse value of kernel parameter vm.swappiness: true",
+		},
+		{
+			arguments:    operator.Arguments{"parameters": map[string]any{"vm.swappiness": " "}},
+			errorMessage: `plan: invalid value for kernel parameter vm.swappiness: ""`,
+		},
+	}
+
+	for _, tc := range cases {
+		report := operator.NewSysctlApply(
+			tc.arguments,
+			"test-op",
+			operator.Options[operator.SysctlApply]{
+				BaseOperatorOptions: []operator.BaseOperatorOption{
+					operator.WithCustomLogger(suite.logger),
+				},
+				OperatorOptions: []operator.Option[operator.SysctlApply]{
+					operator.Option[operator.SysctlApply](operator.WithCustomSysctlApplyExecutor(suite.mockExecutor)),
+					operator.Option[operator.SysctlApply](operator.WithSaptuneClientSysctlApply(suite.mockSaptuneClient)),
+					operator.Option[operator.SysctlApply](operator.WithCustomSysctlDropInPath(suite.dropInPath)),
+				},
+			},
+		).Run(context.Background())
+
+		suite.Nil(report.Success)
+		suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+		suite.Equal(tc.errorMessage, report.Error.Message)
+	}
+}
+
+func (suite *SysctlApplyOperatorTestSuite) TestSysctlApplyPlanErrorUnknownParameter() {
+	ctx := context.Background()
+
+	suite.mockExecutor.On("OutputContext", ctx, "/sbin/sysctl", "-a").
+		Return([]byte("vm.swappiness = 60\n"), nil).
+		Once()
+
+	report := operator.NewSysctlApply(
+		operator.Arguments{
+			"parameters": map[string]any{"vm.unknown": 1.0},
+		},
+		"test-op",
+		operator.Options[operator.SysctlApply]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.SysctlApply]{
+				operator.Option[operator.SysctlApply](operator.WithCustomSysctlApplyExecutor(suite.mockExecutor)),
+				operator.Option[operator.SysctlApply](operator.WithSaptuneClientSysctlApply(suite.mockSaptuneClient)),
+				operator.Option[operator.SysctlApply](operator.WithCustomSysctlDropInPath(suite.dropInPath)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal("plan: kernel parameter vm.unknown not found", report.Error.Message)
+}
+
+func (suite *SysctlApplyOperatorTestSuite) TestSysctlApplyPlanErrorSaptuneConflict() {
+	ctx := context.Background()
+
+	suite.mockExecutor.On("OutputContext", ctx, "/sbin/sysctl", "-a").
+		Return([]byte("vm.swappiness = 60\nnet.ipv4.tcp_slow_start_after_idle = 1\n"), nil).
+		Once()
+	suite.mockSaptuneClient.On("GetVersion", ctx).Return("3.1.0", nil).Once()
+	suite.mockSaptuneClient.On("VerifyNote", ctx).Return([]byte(sysctlNoteVerifyOutput), nil).Once()
+
+	report := operator.NewSysctlApply(
+		operator.Arguments{
+			"parameters": map[string]any{
+				"vm.swappiness":                      10.0,
+				"net.ipv4.tcp_slow_start_after_idle": 0.0,
+			},
+		},
+		"test-op",
+		operator.Options[operator.SysctlApply]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.SysctlApply]{
+				operator.Option[operator.SysctlApply](operator.WithCustomSysctlApplyExecutor(suite.mockExecutor)),
+				operator.Option[operator.SysctlApply](operator.WithSaptuneClientSysctlApply(suite.mockSaptuneClient)),
+				operator.Option[operator.SysctlApply](operator.WithCustomSysctlDropInPath(suite.dropInPath)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal(
+		"plan: kernel parameters managed by saptune, change them using saptune instead: "+
+			"net.ipv4.tcp_slow_start_after_idle",
+		report.Error.Message,
+	)
+}
+
+func (suite *SysctlApplyOperatorTestSuite) TestSysctlApplyPlanErrorSaptuneVersion() {
+	ctx := context.Background()
+
+	suite.mockExecutor.On("OutputContext", ctx, "/sbin/sysctl", "-a").
+		Return([]byte("vm.swappiness = 60\n"), nil).
+		Once()
+	suite.mockSaptuneClient.On("GetVersion", ctx).Return("3.0.2", nil).Once()
+
+	report := operator.NewSysctlApply(
+		operator.Arguments{
+			"parameters": map[string]any{"vm.swappiness": 10.0},
+		},
+		"test-op",
+		operator.Options[operator.SysctlApply]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.SysctlApply]{
+				operator.Option[operator.SysctlApply](operator.WithCustomSysctlApplyExecutor(suite.mockExecutor)),
+				operator.Option[operator.SysctlApply](operator.WithSaptuneClientSysctlApply(suite.mockSaptuneClient)),
+				operator.Option[operator.SysctlApply](operator.WithCustomSysctlDropInPath(suite.dropInPath)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal(
+		"plan: could not check saptune managed parameters, saptune version not supported: 3.0.2",
+		report.Error.Message,
+	)
+}
+
+func (suite *SysctlApplyOperatorTestSuite) TestSysctlApplyAlreadyApplied() {
+	ctx := context.Background()
+
+	err := os.WriteFile(suite.dropInPath, []byte("vm.swappiness = 10\n"), 0o644)
+	suite.Require().NoError(err)
+
+	suite.mockExecutor.On("OutputContext", ctx, "/sbin/sysctl", "-a").
+		Return([]byte("vm.swappiness = 10\n"), nil).
+		Once()
+	suite.mockSaptuneClient.On("GetVersion", ctx).Return("", errors.New("package saptune is not installed")).Once()
+
+	report := operator.NewSysctlApply(
+		operator.Arguments{
+			"parameters": map[string]any{"vm.swappiness": 10.0},
+		},
+		"test-op",
+		operator.Options[operator.SysctlApply]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.SysctlApply]{
+				operator.Option[operator.SysctlApply](operator.WithCustomSysctlApplyExecutor(suite.mockExecutor)),
+				operator.Option[operator.SysctlApply](operator.WithSaptuneClientSysctlApply(suite.mockSaptuneClient)),
+				operator.Option[operator.SysctlApply](operator.WithCustomSysctlDropInPath(suite.dropInPath)),
+			},
+		},
+	).Run(ctx)
+
+	expectedDiff := map[string]any{
+		"before": `{"parameters":{"vm.swappiness":"10"}}`,
+		"after":  `{"parameters":{"vm.swappiness":"10"}}`,
+	}
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.PLAN, report.Success.LastPhase)
+	suite.Equal(expectedDiff, report.Success.Diff)
+}
+
+func (suite *SysctlApplyOperatorTestSuite) TestSysctlApplySuccess() {
+	ctx := context.Background()
+
+	err := os.WriteFile(suite.dropInPath, []byte("kernel.numa_balancing = 0\n"), 0o644)
+	suite.Require().NoError(err)
+
+	planCall := suite.mockExecutor.On("OutputContext", ctx, "/sbin/sysctl", "-a").
+		Return([]byte("kernel.numa_balancing = 0\nvm.swappiness = 60\nnet.ipv4.tcp_rmem = 4096\t87380\t6291456\n"), nil).
+		Once()
+	suite.mockSaptuneClient.On("GetVersion", ctx).Return("3.1.0", nil).Once()
+	suite.mockSaptuneClient.On("VerifyNote", ctx).Return([]byte(sysctlNoteVerifyOutput), nil).Once()
+
+	rmemCall := suite.mockExecutor.On(
+		"CombinedOutputContext", ctx, "/sbin/sysctl", "-w", "net.ipv4.tcp_rmem=4096 131072 6291456").
+		Return([]byte("net.ipv4.tcp_rmem = 4096 131072 6291456"), nil).
+		Once().
+		NotBefore(planCall)
+	swappinessCall := suite.mockExecutor.On("CombinedOutputContext", ctx, "/sbin/sysctl", "-w", "vm.swappiness=10").
+		Return([]byte("vm.swappiness = 10"), nil).
+		Once().
+		NotBefore(rmemCall)
+
+	suite.mockExecutor.On("OutputContext", ctx, "/sbin/sysctl", "-a").
+		Return([]byte("kernel.numa_balancing = 0\nvm.swappiness = 10\nnet.ipv4.tcp_rmem = 4096\t131072\t6291456\n"), nil).
+		Once().
+		NotBefore(swappinessCall)
+
+	report := operator.NewSysctlApply(
+		operator.Arguments{
+			"parameters": map[string]any{
+				"vm.swappiness":     10.0,
+				"net.ipv4.tcp_rmem": "4096 131072  6291456",
+			},
+		},
+		"test-op",
+		operator.Options[operator.SysctlApply]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.SysctlApply]{
+				operator.Option[operator.SysctlApply](operator.WithCustomSysctlApplyExecutor(suite.mockExecutor)),
+				operator.Option[operator.SysctlApply](operator.WithSaptuneClientSysctlApply(suite.mockSaptuneClient)),
+				operator.Option[operator.SysctlApply](operator.WithCustomSysctlDropInPath(suite.dropInPath)),
+			},
+		},
+	).Run(ctx)
+
+	expectedDiff := map[string]any{
+		"before": `{"parameters":{"net.ipv4.tcp_rmem":"4096 87380 6291456","vm.swappiness":"60"}}`,
+		"after":  `{"parameters":{"net.ipv4.tcp_rmem":"4096 131072 6291456","vm.swappiness":"10"}}`,
+	}
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.VERIFY, report.Success.LastPhase)
+	suite.Equal(expectedDiff, report.Success.Diff)
+
+	expectedDropIn := `# Kernel parameters managed by Trento Agent.
+# Manual changes in this file might be overwritten.
+kernel.numa_balancing = 0
+net.ipv4.tcp_rmem = 4096 131072 6291456
+vm.swappiness = 10
+`
+
+	content, err := os.ReadFile(suite.dropInPath)
+	suite.Require().NoError(err)
+	suite.Equal(expectedDropIn, string(content))
+}
+
+func (suite *SysctlApplyOperatorTestSuite) TestSysctlApplyVerifyErrorRollback() {
+	ctx := context.Background()
+
+	planCall := suite.mockExecutor.On("OutputContext", ctx, "/sbin/sysctl", "-a").
+		Return([]byte("vm.swappiness = 60\n"), nil).
+		Once()
+	suite.mockSaptuneClient.On("GetVersion", ctx).Return("", errors.New("package saptune is not installed")).Once()
+
+	commitCall := suite.mockExecutor.On("CombinedOutputContext", ctx, "/sbin/sysctl", "-w", "vm.swappiness=10").
+		Return([]byte("vm.swappiness = 10"), nil).
+		Once().
+		NotBefore(planCall)
+
+	verifyCall := suite.mockExecutor.On("OutputContext", ctx, "/sbin/sysctl", "-a").
+		Return([]byte("vm.swappiness = 60\n"), nil).
+		Once().
+		NotBefore(commitCall)
+
+	suite.mockExecutor.On("CombinedOutputContext", ctx, "/sbin/sysctl", "-w", "vm.swappiness=60").
+		Return([]byte("vm.swappiness = 60"), nil).
+		Once().
+		NotBefore(verifyCall)
+
+	report := operator.NewSysctlApply(
+		operator.Arguments{
+			"parameters": map[string]any{"vm.swappiness": 10.0},
+		},
+		"test-op",
+		operator.Options[operator.SysctlApply]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.SysctlApply]{
+				operator.Option[operator.SysctlApply](operator.WithCustomSysctlApplyExecutor(suite.mockExecutor)),
+				operator.Option[operator.SysctlApply](operator.WithSaptuneClientSysctlApply(suite.mockSaptuneClient)),
+				operator.Option[operator.SysctlApply](operator.WithCustomSysctlDropInPath(suite.dropInPath)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.VERIFY, report.Error.ErrorPhase)
+	suite.Equal("verify: kernel parameter vm.swappiness has runtime value 60, expected 10", report.Error.Message)
+	suite.NoFileExists(suite.dropInPath)
+}
+
+func (suite *SysctlApplyOperatorTestSuite) TestSysctlApplyCommitErrorRollbackRestoresDropIn() {
+	ctx := context.Background()
+
+	originalDropIn := []byte("# custom\nvm.swappiness = 30\n")
+	err := os.WriteFile(suite.dropInPath, originalDropIn, 0o644)
+	suite.Require().NoError(err)
+
+	suite.mockExecutor.On("OutputContext", ctx, "/sbin/sysctl", "-a").
+		Return([]byte("vm.swappiness = 30\n"), nil).
+		Once()
+	suite.mockSaptuneClient.On("GetVersion", ctx).Return("", errors.New("package saptune is not installed")).Once()
+
+	suite.mockExecutor.On("CombinedOutputContext", ctx, "/sbin/sysctl", "-w", "vm.swappiness=10").
+		Return([]byte("permission denied"), errors.New("exit status 255")).
+		Once()
+	suite.mockExecutor.On("CombinedOutputContext", ctx, "/sbin/sysctl", "-w", "vm.swappiness=30").
+		Return([]byte("permission denied"), errors.New("exit status 255")).
+		Once()
+
+	report := operator.NewSysctlApply(
+		operator.Arguments{
+			"parameters": map[string]any{"vm.swappiness": 10.0},
+		},
+		"test-op",
+		operator.Options[operator.SysctlApply]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.SysctlApply]{
+				operator.Option[operator.SysctlApply](operator.WithCustomSysctlApplyExecutor(suite.mockExecutor)),
+				operator.Option[operator.SysctlApply](operator.WithSaptuneClientSysctlApply(suite.mockSaptuneClient)),
+				operator.Option[operator.SysctlApply](operator.WithCustomSysctlDropInPath(suite.dropInPath)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.ROLLBACK, report.Error.ErrorPhase)
+	suite.Equal(
+		"commit: error setting kernel parameter vm.swappiness: exit status 255, output: permission denied; "+
+			"rollback: error setting kernel parameter vm.swappiness: exit status 255, output: permission denied",
+		report.Error.Message,
+	)
+
+	content, err := os.ReadFile(suite.dropInPath)
+	suite.Require().NoError(err)
+	suite.Equal(originalDropIn, content)
+}
@@ -0,0 +1,429 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/trento-project/agent/v3/pkg/utils"
+)
+
+const (
+	ZypperPatchOperatorName       = "zypperpatch"
+	zypperPath                    = "/usr/bin/zypper"
+	zypperPatchStatusNeeded       = "needed"
+	zypperPatchInstallMaxRestarts = 1
+)
+
+// zypper exit codes, see the EXIT CODES section of man zypper.
+const (
+	zypperExitOK                 = 0
+	zypperExitInfUpdateNeeded    = 100
+	zypperExitInfSecUpdateNeeded = 101
+	zypperExitInfRebootNeeded    = 102
+	zypperExitInfRestartNeeded   = 103
+)
+
+type ZypperPatchOption Option[ZypperPatch]
+
+type zypperPatchArguments struct {
+	categories []string
+	severities []string
+	patches    []string
+}
+
+type zypperListPatchesOutput struct {
+	Updates []zypperPatch `xml:"update-status>update-list>update"`
+}
+
+type zypperPatch struct {
+	Name     string `xml:"name,attr" json:"name"`
+	Kind     string `xml:"kind,attr" json:"-"`
+	Status   string `xml:"status,attr" json:"-"`
+	Category string `xml:"category,attr" json:"category"`
+	Severity string `xml:"severity,attr" json:"severity"`
+	Summary  string `xml:"summary" json:"summary"`
+}
+
+type zypperPatchDiffOutput struct {
+	PendingPatches []zypperPatch `json:"pending_patches"`
+	AppliedPatches []zypperPatch `json:"applied_patches"`
+	RebootNeeded   bool          `json:"reboot_needed"`
+}
+
+// ZypperPatch operator installs the applicable patches of the host using zypper.
+//
+// Arguments:
+//  categories: List of patch categories to install, e.g. ["security", "recommended"].
+//              Supported values: security, recommended, optional, feature, document and yast.
+//  severities: List of patch severities to install, e.g. ["critical", "important"].
+//              Supported values: critical, important, moderate, low and unspecified.
+//  patches: List of explicit patch IDs to install.
+//
+// The filters are combined, so a patch is installed only if it matches all the given filters.
+// If no filter is given, all the applicable patches are installed.
+//
+// # Execution Phases
+//
+// - PLAN:
+//   The applicable patches are listed using `zypper --xmlout list-patches` and filtered
+//   using the given arguments. The operation is skipped if there isn't any patch to install.
+//   It also checks if the host already needs a reboot.
+//
+// - COMMIT:
+//   The selected patches are installed non-interactively, agreeing with the licenses.
+//   If zypper needs to update itself first, the installation is run again.
+//
+// - VERIFY:
+//   The applicable patches are listed again to check that the selected patches are installed.
+//   `zypper needs-rebooting` reports whether a reboot is needed to apply the installed patches,
+//   which can be done afterwards using the hostreboot operator.
+//
+// - ROLLBACK:
+//   No rollback is done, as installed patches cannot be uninstalled by zypper.
+//   The rollback always fails, so the report shows that the patches installed so far are kept.
+
+type ZypperPatch struct {
+	baseOperator
+
+	executor        utils.CommandExecutor
+	parsedArguments *zypperPatchArguments
+	selectedPatches []zypperPatch
+}
+
+func WithCustomZypperPatchExecutor(executor utils.CommandExecutor) ZypperPatchOption {
+	return func(o *ZypperPatch) {
+		o.executor = executor
+	}
+}
+
+func NewZypperPatch(
+	arguments Arguments,
+	operationID string,
+	options Options[ZypperPatch],
+) *Executor {
+	zypperPatch := &ZypperPatch{
+		baseOperator: newBaseOperator(
+			ZypperPatchOperatorName, operationID, arguments, options.BaseOperatorOptions...,
+		),
+		executor: utils.Executor{},
+	}
+
+	for _, opt := range options.OperatorOptions {
+		opt(zypperPatch)
+	}
+
+	return &Executor{
+		phaser:      zypperPatch,
+		operationID: operationID,
+		logger:      zypperPatch.logger,
+	}
+}
+
+func (z *ZypperPatch) plan(ctx context.Context) (bool, error) {
+	opArguments, err := parseZypperPatchArguments(z.arguments)
+	if err != nil {
+		return false, err
+	}
+
+	z.parsedArguments = opArguments
+
+	neededPatches, err := z.listNeededPatches(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	z.selectedPatches = filterZypperPatches(neededPatches, z.parsedArguments)
+
+	rebootNeeded, err := z.needsRebooting(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	z.resources[beforeDiffField] = zypperPatchDiffOutput{
+		PendingPatches: z.selectedPatches,
+		AppliedPatches: []zypperPatch{},
+		RebootNeeded:   rebootNeeded,
+	}
+
+	if len(z.selectedPatches) == 0 {
+		z.logger.Info("no applicable patches found, skipping operation")
+		z.resources[afterDiffField] = z.resources[beforeDiffField]
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (z *ZypperPatch) commit(ctx context.Context) error {
+	patchNames := make([]string, 0, len(z.selectedPatches))
+	for _, patch := range z.selectedPatches {
+		patchNames = append(patchNames, patch.Name)
+	}
+
+	args := append([]string{
+		"--non-interactive",
+		"install",
+		"--auto-agree-with-licenses",
+		"--type", "patch",
+	}, patchNames...)
+
+	for restarts := 0; ; restarts++ {
+		z.logger.Info("installing patches", "patches", patchNames)
+
+		output, err := z.executor.CombinedOutputContext(ctx, zypperPath, args...)
+
+		exitCode, exitErr := zypperExitCode(err)
+		if exitErr != nil {
+			return fmt.Errorf("error installing patches: %w, output: %s", exitErr, string(output))
+		}
+
+		switch {
+		case exitCode == zypperExitOK, exitCode == zypperExitInfRebootNeeded:
+			return nil
+		case exitCode == zypperExitInfRestartNeeded && restarts < zypperPatchInstallMaxRestarts:
+			z.logger.Info("zypper updated itself, running the patches installation again")
+		default:
+			return fmt.Errorf("error installing patches: exit code %d, output: %s", exitCode, string(output))
+		}
+	}
+}
+
+func (z *ZypperPatch) verify(ctx context.Context) error {
+	neededPatches, err := z.listNeededPatches(ctx)
+	if err != nil {
+		return err
+	}
+
+	pendingPatches := []zypperPatch{}
+	pendingNames := []string{}
+
+	for _, patch := range neededPatches {
+		if slices.ContainsFunc(z.selectedPatches, func(selected zypperPatch) bool {
+			return selected.Name == patch.Name
+		}) {
+			pendingPatches = append(pendingPatches, patch)
+			pendingNames = append(pendingNames, patch.Name)
+		}
+	}
+
+	if len(pendingPatches) != 0 {
+		return fmt.Errorf("patches not installed: %s", strings.Join(pendingNames, ", "))
+	}
+
+	rebootNeeded, err := z.needsRebooting(ctx)
+	if err != nil {
+		return err
+	}
+
+	z.resources[afterDiffField] = zypperPatchDiffOutput{
+		PendingPatches: pendingPatches,
+		AppliedPatches: z.selectedPatches,
+		RebootNeeded:   rebootNeeded,
+	}
+
+	return nil
+}
+
+func (z *ZypperPatch) rollback(_ context.Context) error {
+	return errors.New("installed patches cannot be rolled back")
+}
+
+func (z *ZypperPatch) operationDiff(_ context.Context) map[string]any {
+	diff := make(map[string]any)
+
+	beforePatches, ok := z.resources[beforeDiffField].(zypperPatchDiffOutput)
+	if !ok {
+		panic(fmt.Sprintf("invalid beforePatches value: cannot parse '%v' to patches diff",
+			z.resources[beforeDiffField]))
+	}
+
+	afterPatches, ok := z.resources[afterDiffField].(zypperPatchDiffOutput)
+	if !ok {
+		panic(fmt.Sprintf("invalid afterPatches value: cannot parse '%v' to patches diff",
+			z.resources[afterDiffField]))
+	}
+
+	before, err := json.Marshal(beforePatches)
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling before diff output: %v", err))
+	}
+
+	diff[beforeDiffField] = string(before)
+
+	after, err := json.Marshal(afterPatches)
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling after diff output: %v", err))
+	}
+
+	diff[afterDiffField] = string(after)
+
+	return diff
+}
+
+func (z *ZypperPatch) listNeededPatches(ctx context.Context) ([]zypperPatch, error) {
+	output, err := z.executor.OutputContext(ctx, zypperPath, "--non-interactive", "--xmlout", "list-patches")
+
+	exitCode, exitErr := zypperExitCode(err)
+	if exitErr != nil {
+		return nil, fmt.Errorf("error listing patches: %w", exitErr)
+	}
+
+	if !slices.Contains([]int{zypperExitOK, zypperExitInfUpdateNeeded, zypperExitInfSecUpdateNeeded}, exitCode) {
+		return nil, fmt.Errorf("error listing patches: exit code %d", exitCode)
+	}
+
+	var listPatches zypperListPatchesOutput
+
+	err = xml.Unmarshal(output, &listPatches)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing patches list: %w", err)
+	}
+
+	neededPatches := []zypperPatch{}
+
+	for _, patch := range listPatches.Updates {
+		if patch.Kind == "patch" && patch.Status == zypperPatchStatusNeeded {
+			patch.Summary = strings.TrimSpace(patch.Summary)
+			neededPatches = append(neededPatches, patch)
+		}
+	}
+
+	return neededPatches, nil
+}
+
+func (z *ZypperPatch) needsRebooting(ctx context.Context) (bool, error) {
+	_, err := z.executor.CombinedOutputContext(ctx, zypperPath, "needs-rebooting")
+
+	exitCode, exitErr := zypperExitCode(err)
+	if exitErr != nil {
+		return false, fmt.Errorf("error checking if reboot is needed: %w", exitErr)
+	}
+
+	switch exitCode {
+	case zypperExitOK:
+		return false, nil
+	case zypperExitInfRebootNeeded:
+		return true, nil
+	default:
+		return false, fmt.Errorf("error checking if reboot is needed: exit code %d", exitCode)
+	}
+}
+
+// zypperExitCode returns the exit code of a zypper execution. zypper uses
+// exit codes over 100 to return information, so they are not considered errors.
+func zypperExitCode(err error) (int, error) {
+	if err == nil {
+		return zypperExitOK, nil
+	}
+
+	var exitError *exec.ExitError
+	if !errors.As(err, &exitError) {
+		return 0, err
+	}
+
+	return exitError.ExitCode(), nil
+}
+
+func filterZypperPatches(patches []zypperPatch, arguments *zypperPatchArguments) []zypperPatch {
+	filteredPatches := []zypperPatch{}
+
+	for _, patch := range patches {
+		if len(arguments.categories) > 0 && !slices.Contains(arguments.categories, patch.Category) {
+			continue
+		}
+
+		if len(arguments.severities) > 0 && !slices.Contains(arguments.severities, patch.Severity) {
+			continue
+		}
+
+		if len(arguments.patches) > 0 && !slices.Contains(arguments.patches, patch.Name) {
+			continue
+		}
+
+		filteredPatches = append(filteredPatches, patch)
+	}
+
+	return filteredPatches
+}
+
+func parseOptionalStringListArgument(rawArguments Arguments, name string) ([]string, error) {
+	argument, found := rawArguments[name]
+	if !found {
+		return []string{}, nil
+	}
+
+	parseError := fmt.Errorf(
+		"could not parse %s argument as a list of strings, argument provided: %v",
+		name,
+		argument,
+	)
+
+	rawList, ok := argument.([]any)
+	if !ok {
+		return nil, parseError
+	}
+
+	values := make([]string, 0, len(rawList))
+
+	for _, rawValue := range rawList {
+		value, ok := rawValue.(string)
+		if !ok {
+			return nil, parseError
+		}
+
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+func parseZypperPatchArguments(rawArguments Arguments) (*zypperPatchArguments, error) {
+	categories, err := parseOptionalStringListArgument(rawArguments, "categories")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, category := range categories {
+		if !slices.Contains(
+			[]string{"security", "recommended", "optional", "feature", "document", "yast"},
+			category,
+		) {
+			return nil, fmt.Errorf("unsupported patch category: %s", category)
+		}
+	}
+
+	severities, err := parseOptionalStringListArgument(rawArguments, "severities")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, severity := range severities {
+		if !slices.Contains(
+			[]string{"critical", "important", "moderate", "low", "unspecified"},
+			severity,
+		) {
+			return nil, fmt.Errorf("unsupported patch severity: %s", severity)
+		}
+	}
+
+	patches, err := parseOptionalStringListArgument(rawArguments, "patches")
+	if err != nil {
+		return nil, err
+	}
+
+	return &zypperPatchArguments{
+		categories: categories,
+		severities: severities,
+		patches:    patches,
+	}, nil
+}
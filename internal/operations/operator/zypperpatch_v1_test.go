@@ -0,0 +1,443 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operator_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/trento-project/agent/v3/internal/operations/operator"
+	"github.com/trento-project/agent/v3/pkg/utils"
+	utilsMocks "github.com/trento-project/agent/v3/pkg/utils/mocks"
+)
+
+const zypperListPatchesOutput = `<?xml version='1.0'?>
+<stream>
+<message type="info">Loading repository data...</message>
+<message type="info">Reading installed packages...</message>
+<update-status version="0.6">
+<update-list>
+<update kind="patch" name="SUSE-SLE-Module-Basesystem-15-SP5-2025-101" edition="1" arch="noarch" ` +
+	`status="needed" category="security" severity="important" pkgmanager="false" restart="false" interactive="false">
+<summary>Security update for openssl-3</summary>
+<description>This update for openssl-3 fixes several issues.</description>
+</update>
+<update kind="patch" name="SUSE-SLE-Module-Basesystem-15-SP5-2025-102" edition="1" arch="noarch" ` +
+	`status="needed" category="recommended" severity="moderate" pkgmanager="false" restart="false" interactive="false">
+<summary>Recommended update for systemd</summary>
+<description>This update for systemd fixes several issues.</description>
+</update>
+<update kind="patch" name="SUSE-SLE-Module-SAP-Applications-15-SP5-2025-103" edition="1" arch="noarch" ` +
+	`status="needed" category="security" severity="low" pkgmanager="false" restart="false" interactive="false">
+<summary>Security update for saptune</summary>
+<description>This update for saptune fixes one issue.</description>
+</update>
+</update-list>
+</update-status>
+</stream>
+`
+
+const zypperListPatchesAfterOutput = `<?xml version='1.0'?>
+<stream>
+<update-status version="0.6">
+<update-list>
+<update kind="patch" name="SUSE-SLE-Module-Basesystem-15-SP5-2025-102" edition="1" arch="noarch" ` +
+	`status="needed" category="recommended" severity="moderate" pkgmanager="false" restart="false" interactive="false">
+<summary>Recommended update for systemd</summary>
+</update>
+</update-list>
+</update-status>
+</stream>
+`
+
+type ZypperPatchOperatorTestSuite struct {
+	suite.Suite
+
+	logger       *slog.Logger
+	mockExecutor *utilsMocks.MockCommandExecutor
+}
+
+func TestZypperPatchOperator(t *testing.T) {
+	suite.Run(t, new(ZypperPatchOperatorTestSuite))
+}
+
+func (suite *ZypperPatchOperatorTestSuite) SetupTest() {
+	suite.logger = utils.NewDefaultLogger("info")
+	suite.mockExecutor = utilsMocks.NewMockCommandExecutor(suite.T())
+}
+
+func zypperExitError(exitCode int) error {
+	return exec.Command("sh", "-c", fmt.Sprintf("exit %d", exitCode)).Run()
+}
+
+func (suite *ZypperPatchOperatorTestSuite) TestZypperPatchPlanArgumentErrors() {
+	cases := []struct {
+		arguments    operator.Arguments
+		errorMessage string
+	}{
+		{
+			arguments:    operator.Arguments{"categories": "security"},
+			errorMessage: "plan: could not parse categories argument as a list of strings, argument provided: security",
+		},
+		{
+			arguments:    operator.Arguments{"categories": []any{"security", 1}},
+			errorMessage: "plan: could not parse categories argument as a list of strings, argument provided: [security 1]",
+		},
+		{
+			arguments:    operator.Arguments{"categories": []any{"bugfix"}},
+			errorMessage: "plan: unsupported patch category: bugfix",
+		},
+		{
+			arguments:    operator.Arguments{"severities": []any{"urgent"}},
+			errorMessage: "plan: unsupported patch severity: urgent",
+		},
+		{
+			arguments:    operator.Arguments{"patches": map[string]any{}},
+			errorMessage: "plan: could not parse patches argument as a list of strings, argument provided: map[]",
+		},
+	}
+
+	for _, tc := range cases {
+		report := operator.NewZypperPatch(
+			tc.arguments,
+			"test-op",
+			operator.Options[operator.ZypperPatch]{
+				BaseOperatorOptions: []operator.BaseOperatorOption{
+					operator.WithCustomLogger(suite.logger),
+				},
+				OperatorOptions: []operator.Option[operator.ZypperPatch]{
+					operator.Option[operator.ZypperPatch](operator.WithCustomZypperPatchExecutor(suite.mockExecutor)),
+				},
+			},
+		).Run(context.Background())
+
+		suite.Nil(report.Success)
+		suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+		suite.Equal(tc.errorMessage, report.Error.Message)
+	}
+}
+
+func (suite *ZypperPatchOperatorTestSuite) TestZypperPatchPlanListPatchesError() {
+	ctx := context.Background()
+
+	suite.mockExecutor.On("OutputContext", ctx, "/usr/bin/zypper", "--non-interactive", "--xmlout", "list-patches").
+		Return([]byte(""), zypperExitError(7)).
+		Once()
+
+	report := operator.NewZypperPatch(
+		operator.Arguments{},
+		"test-op",
+		operator.Options[operator.ZypperPatch]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.ZypperPatch]{
+				operator.Option[operator.ZypperPatch](operator.WithCustomZypperPatchExecutor(suite.mockExecutor)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal("plan: error listing patches: exit code 7", report.Error.Message)
+}
+
+func (suite *ZypperPatchOperatorTestSuite) TestZypperPatchPlanInvalidXML() {
+	ctx := context.Background()
+
+	suite.mockExecutor.On("OutputContext", ctx, "/usr/bin/zypper", "--non-interactive", "--xmlout", "list-patches").
+		Return([]byte("<stream><update-status>"), nil).
+		Once()
+
+	report := operator.NewZypperPatch(
+		operator.Arguments{},
+		"test-op",
+		operator.Options[operator.ZypperPatch]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.ZypperPatch]{
+				operator.Option[operator.ZypperPatch](operator.WithCustomZypperPatchExecutor(suite.mockExecutor)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal("plan: error parsing patches list: XML syntax error on line 1: unexpected EOF", report.Error.Message)
+}
+
+func (suite *ZypperPatchOperatorTestSuite) TestZypperPatchPlanNothingToInstall() {
+	ctx := context.Background()
+
+	suite.mockExecutor.On("OutputContext", ctx, "/usr/bin/zypper", "--non-interactive", "--xmlout", "list-patches").
+		Return([]byte(zypperListPatchesOutput), zypperExitError(101)).
+		Once()
+	suite.mockExecutor.On("CombinedOutputContext", ctx, "/usr/bin/zypper", "needs-rebooting").
+		Return([]byte(""), nil).
+		Once()
+
+	report := operator.NewZypperPatch(
+		operator.Arguments{
+			"categories": []any{"security"},
+			"severities": []any{"critical"},
+		},
+		"test-op",
+		operator.Options[operator.ZypperPatch]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.ZypperPatch]{
+				operator.Option[operator.ZypperPatch](operator.WithCustomZypperPatchExecutor(suite.mockExecutor)),
+			},
+		},
+	).Run(ctx)
+
+	expectedDiff := map[string]any{
+		"before": `{"pending_patches":[],"applied_patches":[],"reboot_needed":false}`,
+		"after":  `{"pending_patches":[],"applied_patches":[],"reboot_needed":false}`,
+	}
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.PLAN, report.Success.LastPhase)
+	suite.Equal(expectedDiff, report.Success.Diff)
+}
+
+func (suite *ZypperPatchOperatorTestSuite) TestZypperPatchSuccess() {
+	ctx := context.Background()
+
+	listCall := suite.mockExecutor.On(
+		"OutputContext", ctx, "/usr/bin/zypper", "--non-interactive", "--xmlout", "list-patches",
+	).
+		Return([]byte(zypperListPatchesOutput), zypperExitError(101)).
+		Once()
+	needsRebootingCall := suite.mockExecutor.On("CombinedOutputContext", ctx, "/usr/bin/zypper", "needs-rebooting").
+		Return([]byte(""), nil).
+		Once().
+		NotBefore(listCall)
+	installCall := suite.mockExecutor.On(
+		"CombinedOutputContext",
+		ctx,
+		"/usr/bin/zypper",
+		"--non-interactive",
+		"install",
+		"--auto-agree-with-licenses",
+		"--type", "patch",
+		"SUSE-SLE-Module-Basesystem-15-SP5-2025-101",
+		"SUSE-SLE-Module-SAP-Applications-15-SP5-2025-103",
+	).
+		Return([]byte("Installing: openssl-3"), zypperExitError(102)).
+		Once().
+		NotBefore(needsRebootingCall)
+	verifyListCall := suite.mockExecutor.On(
+		"OutputContext", ctx, "/usr/bin/zypper", "--non-interactive", "--xmlout", "list-patches",
+	).
+		Return([]byte(zypperListPatchesAfterOutput), zypperExitError(100)).
+		Once().
+		NotBefore(installCall)
+	suite.mockExecutor.On("CombinedOutputContext", ctx, "/usr/bin/zypper", "needs-rebooting").
+		Return([]byte("Reboot is suggested"), zypperExitError(102)).
+		Once().
+		NotBefore(verifyListCall)
+
+	report := operator.NewZypperPatch(
+		operator.Arguments{"categories": []any{"security"}},
+		"test-op",
+		operator.Options[operator.ZypperPatch]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.ZypperPatch]{
+				operator.Option[operator.ZypperPatch](operator.WithCustomZypperPatchExecutor(suite.mockExecutor)),
+			},
+		},
+	).Run(ctx)
+
+	patches := `[{"name":"SUSE-SLE-Module-Basesystem-15-SP5-2025-101","category":"security",` +
+		`"severity":"important","summary":"Security update for openssl-3"},` +
+		`{"name":"SUSE-SLE-Module-SAP-Applications-15-SP5-2025-103","category":"security",` +
+		`"severity":"low","summary":"Security update for saptune"}]`
+
+	expectedDiff := map[string]any{
+		"before": `{"pending_patches":` + patches + `,"applied_patches":[],"reboot_needed":false}`,
+		"after":  `{"pending_patches":[],"applied_patches":` + patches + `,"reboot_needed":true}`,
+	}
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.VERIFY, report.Success.LastPhase)
+	suite.Equal(expectedDiff, report.Success.Diff)
+}
+
+func (suite *ZypperPatchOperatorTestSuite) TestZypperPatchSuccessAfterZypperUpdate() {
+	ctx := context.Background()
+
+	installArgs := []any{
+		ctx,
+		"/usr/bin/zypper",
+		"--non-interactive",
+		"install",
+		"--auto-agree-with-licenses",
+		"--type", "patch",
+		"SUSE-SLE-Module-Basesystem-15-SP5-2025-102",
+	}
+
+	suite.mockExecutor.On("OutputContext", ctx, "/usr/bin/zypper", "--non-interactive", "--xmlout", "list-patches").
+		Return([]byte(zypperListPatchesOutput), zypperExitError(100)).
+		Once()
+	suite.mockExecutor.On("CombinedOutputContext", ctx, "/usr/bin/zypper", "needs-rebooting").
+		Return([]byte(""), nil).
+		Twice()
+	firstInstallCall := suite.mockExecutor.On("CombinedOutputContext", installArgs...).
+		Return([]byte("zypper updated"), zypperExitError(103)).
+		Once()
+	suite.mockExecutor.On("CombinedOutputContext", installArgs...).
+		Return([]byte(""), nil).
+		Once().
+		NotBefore(firstInstallCall)
+	suite.mockExecutor.On("OutputContext", ctx, "/usr/bin/zypper", "--non-interactive", "--xmlout", "list-patches").
+		Return([]byte("<stream></stream>"), nil).
+		Once()
+
+	report := operator.NewZypperPatch(
+		operator.Arguments{
+			"patches": []any{"SUSE-SLE-Module-Basesystem-15-SP5-2025-102"},
+		},
+		"test-op",
+		operator.Options[operator.ZypperPatch]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.ZypperPatch]{
+				operator.Option[operator.ZypperPatch](operator.WithCustomZypperPatchExecutor(suite.mockExecutor)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Error)
+	suite.Equal(operator.VERIFY, report.Success.LastPhase)
+}
+
+func (suite *ZypperPatchOperatorTestSuite) TestZypperPatchCommitError() {
+	ctx := context.Background()
+
+	suite.mockExecutor.On("OutputContext", ctx, "/usr/bin/zypper", "--non-interactive", "--xmlout", "list-patches").
+		Return([]byte(zypperListPatchesOutput), zypperExitError(100)).
+		Once()
+	suite.mockExecutor.On("CombinedOutputContext", ctx, "/usr/bin/zypper", "needs-rebooting").
+		Return([]byte(""), nil).
+		Once()
+	suite.mockExecutor.On(
+		"CombinedOutputContext",
+		ctx,
+		"/usr/bin/zypper",
+		"--non-interactive",
+		"install",
+		"--auto-agree-with-licenses",
+		"--type", "patch",
+		"SUSE-SLE-Module-Basesystem-15-SP5-2025-102",
+	).
+		Return([]byte("Problem retrieving files from 'SLE-Module-Basesystem'"), zypperExitError(8)).
+		Once()
+
+	report := operator.NewZypperPatch(
+		operator.Arguments{"severities": []any{"moderate"}},
+		"test-op",
+		operator.Options[operator.ZypperPatch]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.ZypperPatch]{
+				operator.Option[operator.ZypperPatch](operator.WithCustomZypperPatchExecutor(suite.mockExecutor)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.ROLLBACK, report.Error.ErrorPhase)
+	suite.Equal(
+		"commit: error installing patches: exit code 8, output: Problem retrieving files from 'SLE-Module-Basesystem'; "+
+			"rollback: installed patches cannot be rolled back",
+		report.Error.Message,
+	)
+}
+
+func (suite *ZypperPatchOperatorTestSuite) TestZypperPatchVerifyPatchesPending() {
+	ctx := context.Background()
+
+	suite.mockExecutor.On("OutputContext", ctx, "/usr/bin/zypper", "--non-interactive", "--xmlout", "list-patches").
+		Return([]byte(zypperListPatchesOutput), zypperExitError(100)).
+		Once()
+	suite.mockExecutor.On("CombinedOutputContext", ctx, "/usr/bin/zypper", "needs-rebooting").
+		Return([]byte(""), nil).
+		Once()
+	suite.mockExecutor.On(
+		"CombinedOutputContext",
+		ctx,
+		"/usr/bin/zypper",
+		"--non-interactive",
+		"install",
+		"--auto-agree-with-licenses",
+		"--type", "patch",
+		"SUSE-SLE-Module-Basesystem-15-SP5-2025-102",
+	).
+		Return([]byte(""), nil).
+		Once()
+	suite.mockExecutor.On("OutputContext", ctx, "/usr/bin/zypper", "--non-interactive", "--xmlout", "list-patches").
+		Return([]byte(zypperListPatchesAfterOutput), zypperExitError(100)).
+		Once()
+
+	report := operator.NewZypperPatch(
+		operator.Arguments{"categories": []any{"recommended"}},
+		"test-op",
+		operator.Options[operator.ZypperPatch]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.ZypperPatch]{
+				operator.Option[operator.ZypperPatch](operator.WithCustomZypperPatchExecutor(suite.mockExecutor)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.ROLLBACK, report.Error.ErrorPhase)
+	suite.Equal(
+		"verify: patches not installed: SUSE-SLE-Module-Basesystem-15-SP5-2025-102; "+
+			"rollback: installed patches cannot be rolled back",
+		report.Error.Message,
+	)
+}
+
+func (suite *ZypperPatchOperatorTestSuite) TestZypperPatchNeedsRebootingError() {
+	ctx := context.Background()
+
+	suite.mockExecutor.On("OutputContext", ctx, "/usr/bin/zypper", "--non-interactive", "--xmlout", "list-patches").
+		Return([]byte(zypperListPatchesOutput), nil).
+		Once()
+	suite.mockExecutor.On("CombinedOutputContext", ctx, "/usr/bin/zypper", "needs-rebooting").
+		Return([]byte(""), errors.New("zypper not found")).
+		Once()
+
+	report := operator.NewZypperPatch(
+		operator.Arguments{},
+		"test-op",
+		operator.Options[operator.ZypperPatch]{
+			BaseOperatorOptions: []operator.BaseOperatorOption{
+				operator.WithCustomLogger(suite.logger),
+			},
+			OperatorOptions: []operator.Option[operator.ZypperPatch]{
+				operator.Option[operator.ZypperPatch](operator.WithCustomZypperPatchExecutor(suite.mockExecutor)),
+			},
+		},
+	).Run(ctx)
+
+	suite.Nil(report.Success)
+	suite.Equal(operator.PLAN, report.Error.ErrorPhase)
+	suite.Equal("plan: error checking if reboot is needed: zypper not found", report.Error.Message)
+}
@@ -5,29 +5,86 @@ package operations
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"time"
 
 	"github.com/trento-project/agent/v3/internal/messaging"
 
+	"github.com/trento-project/agent/v3/internal/operations/localpolicy"
 	"github.com/trento-project/agent/v3/internal/operations/operator"
 	"github.com/trento-project/contracts/go/pkg/events"
 )
 
 const (
 	OperatorExecutionRequestedV1 = "Trento.Operations.V1.OperatorExecutionRequested"
+
+	// PolicyDeniedMessage prefixes the error message of the requests denied by the local policy
+	PolicyDeniedMessage = "operation denied by local policy"
+
+	// rollbackPolicyArgument is the reserved argument with the rollback policy of the request
+	rollbackPolicyArgument = "_rollback_policy"
+)
+
+var errDeferredRollbacksDisabled = errors.New(
+	"deferred rollback policy not available, operations-rollbacks-folder is not configured",
 )
 
+type HandleEventOption func(*handleEventOptions)
+
+type handleEventOptions struct {
+	verifier      *RequestVerifier
+	localPolicy   *localpolicy.Policy
+	rollbackStore *operator.DeferredRollbackStore
+}
+
+// WithRequestVerifier verifies the signature of the requests before running the operator.
+func WithRequestVerifier(verifier *RequestVerifier) HandleEventOption {
+	return func(o *handleEventOptions) {
+		o.verifier = verifier
+	}
+}
+
+// WithLocalPolicy evaluates the requests against the host local policy before running the operator.
+// Denied requests are reported as failed in the PLAN phase.
+func WithLocalPolicy(policy *localpolicy.Policy) HandleEventOption {
+	return func(o *handleEventOptions) {
+		o.localPolicy = policy
+	}
+}
+
+// WithDeferredRollbackStore enables the deferred rollback policy, persisting the deferred rollbacks
+// in the given store. Without it, the requests with the deferred rollback policy are reported
+// as failed in the PLAN phase.
+func WithDeferredRollbackStore(store *operator.DeferredRollbackStore) HandleEventOption {
+	return func(o *handleEventOptions) {
+		o.rollbackStore = store
+	}
+}
+
 func HandleEvent(
 	ctx context.Context,
 	event []byte,
 	agentID string,
 	adapter messaging.Adapter,
 	registry operator.Registry,
+	options ...HandleEventOption,
 ) error {
-	eventType, err := events.EventType(event)
-	if err != nil {
-		return fmt.Errorf("error getting event type: %w", err)
+	handleOptions := &handleEventOptions{}
+	for _, opt := range options {
+		opt(handleOptions)
+	}
+
+	eventType, isJSONEvent := jsonEventType(event)
+	if !isJSONEvent {
+		var err error
+
+		eventType, err = events.EventType(event)
+		if err != nil {
+			return fmt.Errorf("error getting event type: %w", err)
+		}
 	}
 
 	switch eventType {
@@ -46,37 +103,256 @@ func HandleEvent(
 			return nil
 		}
 
-		operatorBuilder, err := registry.GetOperatorBuilder(operatorExecutionRequested.Operator)
-		if err != nil {
-			return fmt.Errorf("error building operator from operators registry: %w", err)
+		arguments := target.Arguments
+
+		if handleOptions.verifier != nil {
+			arguments, err = handleOptions.verifier.Verify(operatorExecutionRequested, target)
+			if err != nil {
+				return fmt.Errorf("error verifying OperatorExecutionRequested signature: %w", err)
+			}
 		}
 
-		op := operatorBuilder(operatorExecutionRequested.OperationID, target.Arguments)
-		report := op.Run(ctx)
+		if handleOptions.localPolicy != nil {
+			decision := handleOptions.localPolicy.Evaluate(operatorExecutionRequested.Operator, arguments)
+			if !decision.Allowed {
+				slog.Warn("Operator execution request denied by local policy",
+					"operator", operatorExecutionRequested.Operator,
+					"reason", decision.Reason)
+
+				return publishExecutionReport(
+					adapter,
+					operatorExecutionRequested,
+					target,
+					policyDeniedReport(operatorExecutionRequested.OperationID, operator.PLAN, decision),
+				)
+			}
+		}
+
+		rollbackPolicy, arguments, err := extractRollbackPolicy(arguments, handleOptions.rollbackStore != nil)
+		if errors.Is(err, errDeferredRollbacksDisabled) {
+			slog.Warn("Operator execution request refused", "operator", operatorExecutionRequested.Operator,
+				"reason", err)
+
+			return publishExecutionReport(
+				adapter,
+				operatorExecutionRequested,
+				target,
+				&operator.ExecutionReport{
+					OperationID: operatorExecutionRequested.OperationID,
+					Error: &operator.ExecutionError{
+						ErrorPhase: operator.PLAN,
+						Message:    err.Error(),
+					},
+				},
+			)
+		}
 
-		completedEvent, err := OperatorExecutionCompletedToEvent(
-			operatorExecutionRequested.OperationID,
-			operatorExecutionRequested.GroupID,
-			target.AgentID,
-			operatorExecutionRequested.StepNumber,
-			report,
-		)
 		if err != nil {
-			return fmt.Errorf("error encoding OperatorExecutionCompleted event: %w", err)
+			return fmt.Errorf("error getting OperatorExecutionRequested rollback policy: %w", err)
 		}
 
+		operatorBuilder, err := registry.GetOperatorBuilder(operatorExecutionRequested.Operator)
+		if err != nil {
+			return fmt.Errorf("error building operator from operators registry: %w", err)
+		}
+
+		op := operatorBuilder(operatorExecutionRequested.OperationID, arguments)
+		report := op.Run(operator.ContextWithRollbackPolicy(ctx, rollbackPolicy))
+
 		slog.Info("Operator execution request completed", "operator", operatorExecutionRequested.Operator)
 
-		err = adapter.Publish(
-			operationsRoutingKey, events.ContentType(), completedEvent)
+		if report.RollbackState != nil {
+			saveDeferredRollback(handleOptions.rollbackStore, operatorExecutionRequested, target, arguments, report)
+		}
+
+		return publishExecutionReport(adapter, operatorExecutionRequested, target, report)
+	case OperatorRollbackRequestedV1:
+		operatorRollbackRequested, err := OperatorRollbackRequestedFromEvent(event)
 		if err != nil {
-			return fmt.Errorf("error publishing operator execution report: %w", err)
+			return fmt.Errorf("error decoding OperatorRollbackRequested event: %w", err)
 		}
 
-		slog.Info("Operation report published properly")
+		if operatorRollbackRequested.AgentID != agentID {
+			slog.Info("OperatorRollbackRequested is not for this agent. Discarding rollback")
 
-		return nil
+			return nil
+		}
+
+		return handleRollbackRequested(ctx, operatorRollbackRequested, adapter, registry, handleOptions)
 	default:
 		return fmt.Errorf("invalid event type: %s", eventType)
 	}
 }
+
+// extractRollbackPolicy gets the rollback policy of the request and removes it from the operator arguments.
+// The deferred policy is refused if the deferred rollbacks cannot be persisted.
+func extractRollbackPolicy(
+	arguments map[string]any,
+	deferredEnabled bool,
+) (operator.RollbackPolicy, map[string]any, error) {
+	rawPolicy, found := arguments[rollbackPolicyArgument]
+	if !found {
+		return operator.RollbackPolicyAuto, arguments, nil
+	}
+
+	policyValue, ok := rawPolicy.(string)
+	if !ok {
+		return "", nil, fmt.Errorf("%s argument must be a string", rollbackPolicyArgument)
+	}
+
+	policy, err := operator.ParseRollbackPolicy(policyValue)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if policy == operator.RollbackPolicyDeferred && !deferredEnabled {
+		return "", nil, errDeferredRollbacksDisabled
+	}
+
+	operatorArguments := maps.Clone(arguments)
+	delete(operatorArguments, rollbackPolicyArgument)
+
+	return policy, operatorArguments, nil
+}
+
+// saveDeferredRollback persists the deferred rollback of a failed operation.
+// The failure is added to the report, as the operation cannot be rolled back later.
+func saveDeferredRollback(
+	store *operator.DeferredRollbackStore,
+	operatorExecutionRequested *OperatorExecutionRequested,
+	target *OperatorExecutionRequestedTarget,
+	arguments map[string]any,
+	report *operator.ExecutionReport,
+) {
+	err := store.Save(operator.DeferredRollback{
+		OperationID: operatorExecutionRequested.OperationID,
+		GroupID:     operatorExecutionRequested.GroupID,
+		StepNumber:  operatorExecutionRequested.StepNumber,
+		AgentID:     target.AgentID,
+		Operator:    operatorExecutionRequested.Operator,
+		Arguments:   arguments,
+		State:       report.RollbackState,
+		Error:       report.Error.Message,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		slog.Error("Error saving deferred rollback", "operation_id", report.OperationID, "error", err)
+		report.Error.Message = fmt.Sprintf("%s; error saving deferred rollback: %s", report.Error.Message, err)
+
+		return
+	}
+
+	slog.Info("Operation rollback deferred", "operation_id", report.OperationID)
+}
+
+// handleRollbackRequested runs a deferred rollback and reports it as a new execution
+// of the operation, failed or succeeded in the ROLLBACK phase.
+// The request signature and the local policy are checked as for the operator execution requests,
+// evaluating the policy with the operator and arguments of the deferred rollback.
+// The deferred rollback is removed once it succeeds, so it can be retried otherwise.
+func handleRollbackRequested(
+	ctx context.Context,
+	operatorRollbackRequested *OperatorRollbackRequested,
+	adapter messaging.Adapter,
+	registry operator.Registry,
+	handleOptions *handleEventOptions,
+) error {
+	store := handleOptions.rollbackStore
+	if store == nil {
+		return errors.New("deferred rollbacks are not enabled")
+	}
+
+	if handleOptions.verifier != nil {
+		err := handleOptions.verifier.VerifyRollback(operatorRollbackRequested)
+		if err != nil {
+			return fmt.Errorf("error verifying OperatorRollbackRequested signature: %w", err)
+		}
+	}
+
+	deferredRollback, err := store.Load(operatorRollbackRequested.OperationID)
+	if err != nil {
+		return fmt.Errorf("error loading deferred rollback: %w", err)
+	}
+
+	slog.Info("Operator rollback request received",
+		"operation_id", deferredRollback.OperationID,
+		"operator", deferredRollback.Operator)
+
+	rollbackRequest := &OperatorExecutionRequested{
+		OperationID: deferredRollback.OperationID,
+		GroupID:     deferredRollback.GroupID,
+		StepNumber:  deferredRollback.StepNumber,
+		Operator:    deferredRollback.Operator,
+	}
+	rollbackTarget := &OperatorExecutionRequestedTarget{AgentID: deferredRollback.AgentID}
+
+	if handleOptions.localPolicy != nil {
+		decision := handleOptions.localPolicy.Evaluate(deferredRollback.Operator, deferredRollback.Arguments)
+		if !decision.Allowed {
+			slog.Warn("Operator rollback request denied by local policy",
+				"operator", deferredRollback.Operator,
+				"reason", decision.Reason)
+
+			return publishExecutionReport(
+				adapter,
+				rollbackRequest,
+				rollbackTarget,
+				policyDeniedReport(deferredRollback.OperationID, operator.ROLLBACK, decision),
+			)
+		}
+	}
+
+	report := operator.RunDeferredRollback(ctx, &registry, deferredRollback)
+	if report.Success != nil {
+		err = store.Delete(deferredRollback.OperationID)
+		if err != nil {
+			slog.Warn("Error removing deferred rollback", "error", err)
+		}
+	}
+
+	slog.Info("Operator rollback request completed", "operator", deferredRollback.Operator)
+
+	return publishExecutionReport(adapter, rollbackRequest, rollbackTarget, report)
+}
+
+func policyDeniedReport(
+	operationID string,
+	phase operator.PhaseName,
+	decision localpolicy.Decision,
+) *operator.ExecutionReport {
+	return &operator.ExecutionReport{
+		OperationID: operationID,
+		Error: &operator.ExecutionError{
+			ErrorPhase: phase,
+			Message:    fmt.Sprintf("%s: %s", PolicyDeniedMessage, decision.Reason),
+		},
+	}
+}
+
+func publishExecutionReport(
+	adapter messaging.Adapter,
+	operatorExecutionRequested *OperatorExecutionRequested,
+	target *OperatorExecutionRequestedTarget,
+	report *operator.ExecutionReport,
+) error {
+	completedEvent, err := OperatorExecutionCompletedToEvent(
+		operatorExecutionRequested.OperationID,
+		operatorExecutionRequested.GroupID,
+		target.AgentID,
+		operatorExecutionRequested.StepNumber,
+		report,
+	)
+	if err != nil {
+		return fmt.Errorf("error encoding OperatorExecutionCompleted event: %w", err)
+	}
+
+	err = adapter.Publish(
+		operationsRoutingKey, events.ContentType(), completedEvent)
+	if err != nil {
+		return fmt.Errorf("error publishing operator execution report: %w", err)
+	}
+
+	slog.Info("Operation report published properly")
+
+	return nil
+}
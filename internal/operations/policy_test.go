@@ -5,7 +5,11 @@ package operations_test
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -13,6 +17,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"github.com/trento-project/agent/v3/internal/messaging/mocks"
+	"github.com/trento-project/agent/v3/internal/operations/localpolicy"
 	"github.com/trento-project/agent/v3/internal/operations/operator"
 	operatorMocks "github.com/trento-project/agent/v3/internal/operations/operator/mocks"
 	"github.com/trento-project/contracts/go/pkg/events"
@@ -133,6 +138,38 @@ func (suite *PolicyTestSuite) TestPolicyHandleEventOperatorNotFound() {
 	suite.mockAdapter.AssertNumberOfCalls(suite.T(), "Publish", 0)
 }
 
+func (suite *PolicyTestSuite) TestPolicyHandleEventUnsignedRequest() {
+	ctx := context.Background()
+
+	operatorRequestsEvent := &events.OperatorExecutionRequested{
+		OperationId: uuid.New().String(),
+		Operator:    "test@v1",
+		Targets: []*events.OperatorExecutionRequestedTarget{
+			{
+				AgentId:   suite.agentID,
+				Arguments: map[string]*structpb.Value{},
+			},
+		},
+	}
+	event, err := events.ToEvent(operatorRequestsEvent,
+		events.WithSource(""),
+		events.WithID(""))
+	suite.Require().NoError(err)
+
+	err = operations.HandleEvent(
+		ctx,
+		event,
+		suite.agentID,
+		&suite.mockAdapter,
+		*suite.testRegistry,
+		operations.WithRequestVerifier(operations.NewRequestVerifier(nil, true, 0)),
+	)
+
+	suite.Require().EqualError(err, "error verifying OperatorExecutionRequested signature: request is not signed")
+	suite.mockOperator.AssertNumberOfCalls(suite.T(), "Run", 0)
+	suite.mockAdapter.AssertNumberOfCalls(suite.T(), "Publish", 0)
+}
+
 func (suite *PolicyTestSuite) TestPolicyHandleEventErrorDecoding() {
 	ctx := context.Background()
 
@@ -311,3 +348,245 @@ func (suite *PolicyTestSuite) TestPolicyHandleEvent() {
 	suite.Require().NoError(err)
 	suite.mockAdapter.AssertNumberOfCalls(suite.T(), "Publish", 1)
 }
+
+func (suite *PolicyTestSuite) TestPolicyHandleEventDeniedByLocalPolicy() {
+	ctx := context.Background()
+
+	policyFile := filepath.Join(suite.T().TempDir(), "operations-policy.yaml")
+	err := os.WriteFile(policyFile, []byte("rules:\n  - name: no-test\n    effect: deny\n    operators: [test]\n"), 0600)
+	suite.Require().NoError(err)
+
+	operationID := uuid.New().String()
+	operatorRequestsEvent := &events.OperatorExecutionRequested{
+		OperationId: operationID,
+		Operator:    "test@v1",
+		Targets: []*events.OperatorExecutionRequestedTarget{
+			{
+				AgentId:   suite.agentID,
+				Arguments: map[string]*structpb.Value{},
+			},
+		},
+	}
+	event, err := events.ToEvent(operatorRequestsEvent,
+		events.WithSource(""),
+		events.WithID(""))
+	suite.Require().NoError(err)
+
+	suite.mockAdapter.On(
+		"Publish",
+		"requests",
+		events.ContentType(),
+		mock.MatchedBy(func(completedEvent []byte) bool {
+			planPhase := events.OperatorPhase(events.OperatorPhase_value[string(operator.PLAN)])
+
+			var operatorExecutionCompleted events.OperatorExecutionCompleted
+
+			err := events.FromEvent(completedEvent, &operatorExecutionCompleted)
+			if err != nil {
+				return false
+			}
+
+			return operatorExecutionCompleted.GetOperationId() == operationID &&
+				operatorExecutionCompleted.GetError().GetPhase() == planPhase &&
+				operatorExecutionCompleted.GetError().GetMessage() ==
+					"operation denied by local policy: denied by rule no-test"
+		}),
+	).Return(nil)
+
+	err = operations.HandleEvent(
+		ctx,
+		event,
+		suite.agentID,
+		&suite.mockAdapter,
+		*suite.testRegistry,
+		operations.WithLocalPolicy(localpolicy.NewPolicy(policyFile)),
+	)
+	suite.Require().NoError(err)
+	suite.mockOperator.AssertNumberOfCalls(suite.T(), "Run", 0)
+	suite.mockAdapter.AssertNumberOfCalls(suite.T(), "Publish", 1)
+}
+
+func (suite *PolicyTestSuite) TestPolicyHandleEventInvalidRollbackPolicy() {
+	operatorRequestsEvent := &events.OperatorExecutionRequested{
+		OperationId: uuid.New().String(),
+		Operator:    "test@v1",
+		Targets: []*events.OperatorExecutionRequestedTarget{
+			{
+				AgentId: suite.agentID,
+				Arguments: map[string]*structpb.Value{
+					"_rollback_policy": structpb.NewStringValue("sometimes"),
+				},
+			},
+		},
+	}
+	event, err := events.ToEvent(operatorRequestsEvent,
+		events.WithSource(""),
+		events.WithID(""))
+	suite.Require().NoError(err)
+
+	err = operations.HandleEvent(
+		context.Background(),
+		event,
+		suite.agentID,
+		&suite.mockAdapter,
+		*suite.testRegistry,
+	)
+	suite.EqualError(err, "error getting OperatorExecutionRequested rollback policy: "+
+		"invalid rollback policy sometimes, supported values: auto, never, deferred")
+	suite.mockOperator.AssertNumberOfCalls(suite.T(), "Run", 0)
+}
+
+func (suite *PolicyTestSuite) TestPolicyHandleEventDeferredRollbacksDisabled() {
+	operationID := uuid.New().String()
+	operatorRequestsEvent := &events.OperatorExecutionRequested{
+		OperationId: operationID,
+		Operator:    "test@v1",
+		Targets: []*events.OperatorExecutionRequestedTarget{
+			{
+				AgentId: suite.agentID,
+				Arguments: map[string]*structpb.Value{
+					"_rollback_policy": structpb.NewStringValue("deferred"),
+				},
+			},
+		},
+	}
+	event, err := events.ToEvent(operatorRequestsEvent,
+		events.WithSource(""),
+		events.WithID(""))
+	suite.Require().NoError(err)
+
+	suite.mockAdapter.On(
+		"Publish",
+		"requests",
+		events.ContentType(),
+		mock.MatchedBy(func(completedEvent []byte) bool {
+			planPhase := events.OperatorPhase(events.OperatorPhase_value[string(operator.PLAN)])
+
+			var operatorExecutionCompleted events.OperatorExecutionCompleted
+
+			err := events.FromEvent(completedEvent, &operatorExecutionCompleted)
+			if err != nil {
+				return false
+			}
+
+			return operatorExecutionCompleted.GetOperationId() == operationID &&
+				operatorExecutionCompleted.GetError().GetPhase() == planPhase &&
+				operatorExecutionCompleted.GetError().GetMessage() ==
+					"deferred rollback policy not available, operations-rollbacks-folder is not configured"
+		}),
+	).Return(nil)
+
+	err = operations.HandleEvent(
+		context.Background(),
+		event,
+		suite.agentID,
+		&suite.mockAdapter,
+		*suite.testRegistry,
+	)
+	suite.Require().NoError(err)
+	suite.mockOperator.AssertNumberOfCalls(suite.T(), "Run", 0)
+	suite.mockAdapter.AssertNumberOfCalls(suite.T(), "Publish", 1)
+}
+
+func (suite *PolicyTestSuite) TestPolicyHandleEventRollbackRequestedNotFound() {
+	event := []byte(`{"type": "Trento.Operations.V1.OperatorRollbackRequested", ` +
+		`"operation_id": "test-op", "agent_id": "` + suite.agentID + `"}`)
+
+	err := operations.HandleEvent(
+		context.Background(),
+		event,
+		suite.agentID,
+		&suite.mockAdapter,
+		*suite.testRegistry,
+		operations.WithDeferredRollbackStore(operator.NewDeferredRollbackStore(suite.T().TempDir())),
+	)
+	suite.ErrorIs(err, operator.ErrDeferredRollbackNotFound)
+	suite.mockAdapter.AssertNumberOfCalls(suite.T(), "Publish", 0)
+}
+
+func (suite *PolicyTestSuite) deferredRollbackStore() *operator.DeferredRollbackStore {
+	store := operator.NewDeferredRollbackStore(suite.T().TempDir())
+	err := store.Save(operator.DeferredRollback{
+		OperationID: "test-op",
+		AgentID:     suite.agentID,
+		Operator:    "test@v1",
+		Arguments:   operator.Arguments{},
+		State:       map[string]any{},
+	})
+	suite.Require().NoError(err)
+
+	return store
+}
+
+func (suite *PolicyTestSuite) TestPolicyHandleEventRollbackRequestedUnsigned() {
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	suite.Require().NoError(err)
+
+	store := suite.deferredRollbackStore()
+	event := []byte(`{"type": "Trento.Operations.V1.OperatorRollbackRequested", ` +
+		`"operation_id": "test-op", "agent_id": "` + suite.agentID + `"}`)
+
+	err = operations.HandleEvent(
+		context.Background(),
+		event,
+		suite.agentID,
+		&suite.mockAdapter,
+		*suite.testRegistry,
+		operations.WithRequestVerifier(operations.NewRequestVerifier(
+			[]ed25519.PublicKey{publicKey}, true, 5*time.Minute,
+		)),
+		operations.WithDeferredRollbackStore(store),
+	)
+	suite.ErrorIs(err, operations.ErrUnsignedRequest)
+	suite.mockAdapter.AssertNumberOfCalls(suite.T(), "Publish", 0)
+
+	_, err = store.Load("test-op")
+	suite.NoError(err)
+}
+
+func (suite *PolicyTestSuite) TestPolicyHandleEventRollbackRequestedDeniedByLocalPolicy() {
+	policyFile := filepath.Join(suite.T().TempDir(), "operations-policy.yaml")
+	err := os.WriteFile(policyFile, []byte("rules:\n  - name: no-test\n    effect: deny\n    operators: [test]\n"), 0600)
+	suite.Require().NoError(err)
+
+	store := suite.deferredRollbackStore()
+	event := []byte(`{"type": "Trento.Operations.V1.OperatorRollbackRequested", ` +
+		`"operation_id": "test-op", "agent_id": "` + suite.agentID + `"}`)
+
+	suite.mockAdapter.On(
+		"Publish",
+		"requests",
+		events.ContentType(),
+		mock.MatchedBy(func(completedEvent []byte) bool {
+			rollbackPhase := events.OperatorPhase(events.OperatorPhase_value[string(operator.ROLLBACK)])
+
+			var operatorExecutionCompleted events.OperatorExecutionCompleted
+
+			err := events.FromEvent(completedEvent, &operatorExecutionCompleted)
+			if err != nil {
+				return false
+			}
+
+			return operatorExecutionCompleted.GetOperationId() == "test-op" &&
+				operatorExecutionCompleted.GetError().GetPhase() == rollbackPhase &&
+				operatorExecutionCompleted.GetError().GetMessage() ==
+					"operation denied by local policy: denied by rule no-test"
+		}),
+	).Return(nil)
+
+	err = operations.HandleEvent(
+		context.Background(),
+		event,
+		suite.agentID,
+		&suite.mockAdapter,
+		*suite.testRegistry,
+		operations.WithLocalPolicy(localpolicy.NewPolicy(policyFile)),
+		operations.WithDeferredRollbackStore(store),
+	)
+	suite.Require().NoError(err)
+	suite.mockOperator.AssertNumberOfCalls(suite.T(), "Run", 0)
+	suite.mockAdapter.AssertNumberOfCalls(suite.T(), "Publish", 1)
+
+	_, err = store.Load("test-op")
+	suite.NoError(err)
+}
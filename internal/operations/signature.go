@@ -0,0 +1,459 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operations
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Reserved target arguments carrying the request signature.
+// They are removed from the arguments before building the operator.
+const (
+	SignatureArgument = "_signature"
+	TimestampArgument = "_timestamp"
+	NonceArgument     = "_nonce"
+
+	defaultSignatureMaxAge = 5 * time.Minute
+)
+
+var (
+	ErrUnsignedRequest  = errors.New("request is not signed")
+	ErrInvalidSignature = errors.New("request signature does not match any of the configured public keys")
+	ErrReplayedRequest  = errors.New("request nonce already used")
+)
+
+// SignatureConfig holds the configuration used to verify the operation requests.
+type SignatureConfig struct {
+	// PublicKeysFiles are PEM files with the Ed25519 public keys of the server.
+	PublicKeysFiles []string
+	// Enforced rejects the requests without signature.
+	Enforced bool
+	// MaxAge is the maximum difference between the request timestamp and the agent clock.
+	MaxAge time.Duration
+	// NoncesFile persists the used nonces, so the requests cannot be replayed after
+	// an agent restart. The nonces are only kept in memory if not set.
+	NoncesFile string
+}
+
+type RequestVerifierOption func(*RequestVerifier)
+
+// RequestVerifier checks the detached Ed25519 signature of the operation requests.
+//
+// The server signs, for each target, the JSON encoding of the object
+// {"agent_id", "arguments", "group_id", "operation_id", "operator", "step_number"},
+// canonicalized with the JSON Canonicalization Scheme (RFC 8785), see canonicalJSON.
+// The arguments include the _timestamp (RFC3339) and _nonce reserved arguments, but not the
+// _signature one, which holds the base64 encoded signature.
+// The deferred rollback requests are signed the same way, with the JSON encoding of the object
+// {"_nonce", "_timestamp", "agent_id", "operation_id", "type"}, and the signature in the
+// _signature field of the request.
+//
+// Used nonces are kept during MaxAge, older requests are rejected by their timestamp.
+// They are saved in the nonces file when configured. Otherwise they are only kept in memory,
+// and a request can be replayed after an agent restart until its timestamp expires,
+// so MaxAge should be kept short.
+type RequestVerifier struct {
+	publicKeys []ed25519.PublicKey
+	enforced   bool
+	maxAge     time.Duration
+	now        func() time.Time
+	noncesFile string
+
+	mu     sync.Mutex
+	nonces map[string]time.Time
+}
+
+func WithCustomVerifierClock(now func() time.Time) RequestVerifierOption {
+	return func(v *RequestVerifier) {
+		v.now = now
+	}
+}
+
+// WithNoncesFile persists the used nonces in the given file.
+// The file is not read until LoadNonces is called.
+func WithNoncesFile(path string) RequestVerifierOption {
+	return func(v *RequestVerifier) {
+		v.noncesFile = path
+	}
+}
+
+func NewRequestVerifier(
+	publicKeys []ed25519.PublicKey,
+	enforced bool,
+	maxAge time.Duration,
+	options ...RequestVerifierOption,
+) *RequestVerifier {
+	if maxAge <= 0 {
+		maxAge = defaultSignatureMaxAge
+	}
+
+	verifier := &RequestVerifier{
+		publicKeys: publicKeys,
+		enforced:   enforced,
+		maxAge:     maxAge,
+		now:        time.Now,
+		nonces:     make(map[string]time.Time),
+	}
+
+	for _, opt := range options {
+		opt(verifier)
+	}
+
+	return verifier
+}
+
+// NewRequestVerifierFromConfig loads the public keys files and the used nonces and creates the verifier.
+func NewRequestVerifierFromConfig(
+	config SignatureConfig,
+	options ...RequestVerifierOption,
+) (*RequestVerifier, error) {
+	publicKeys := make([]ed25519.PublicKey, 0, len(config.PublicKeysFiles))
+
+	for _, path := range config.PublicKeysFiles {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read public key file %s: %w", path, err)
+		}
+
+		publicKey, err := ParseEd25519PublicKey(content)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse public key file %s: %w", path, err)
+		}
+
+		publicKeys = append(publicKeys, publicKey)
+	}
+
+	if config.Enforced && len(publicKeys) == 0 {
+		return nil, errors.New("signature enforcement requires at least one public key")
+	}
+
+	if config.NoncesFile != "" {
+		options = append(options, WithNoncesFile(config.NoncesFile))
+	}
+
+	verifier := NewRequestVerifier(publicKeys, config.Enforced, config.MaxAge, options...)
+
+	err := verifier.LoadNonces()
+	if err != nil {
+		return nil, err
+	}
+
+	return verifier, nil
+}
+
+// LoadNonces reads the nonces used before the agent restart from the nonces file.
+// A missing file is not an error, it is created when the first nonce is used.
+func (v *RequestVerifier) LoadNonces() error {
+	if v.noncesFile == "" {
+		return nil
+	}
+
+	content, err := os.ReadFile(v.noncesFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("could not read nonces file %s: %w", v.noncesFile, err)
+	}
+
+	nonces := make(map[string]time.Time)
+
+	err = json.Unmarshal(content, &nonces)
+	if err != nil {
+		return fmt.Errorf("could not decode nonces file %s: %w", v.noncesFile, err)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.nonces = nonces
+	v.removeExpiredNonces(v.now())
+
+	return nil
+}
+
+// ParseEd25519PublicKey parses a PEM encoded PKIX Ed25519 public key.
+func ParseEd25519PublicKey(content []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported public key type %T, Ed25519 expected", key)
+	}
+
+	return publicKey, nil
+}
+
+// SignedRequestPayload returns the payload signed by the server for the given request target.
+func SignedRequestPayload(
+	request *OperatorExecutionRequested,
+	target *OperatorExecutionRequestedTarget,
+) ([]byte, error) {
+	arguments := make(map[string]any, len(target.Arguments))
+	for key, value := range target.Arguments {
+		if key == SignatureArgument {
+			continue
+		}
+
+		arguments[key] = value
+	}
+
+	return canonicalJSON(map[string]any{
+		"agent_id":     target.AgentID,
+		"arguments":    arguments,
+		"group_id":     request.GroupID,
+		"operation_id": request.OperationID,
+		"operator":     request.Operator,
+		"step_number":  request.StepNumber,
+	})
+}
+
+// SignedRollbackPayload returns the payload signed by the server for the given deferred rollback request.
+func SignedRollbackPayload(request *OperatorRollbackRequested) ([]byte, error) {
+	return canonicalJSON(map[string]any{
+		NonceArgument:     request.Nonce,
+		TimestampArgument: request.Timestamp,
+		"agent_id":        request.AgentID,
+		"operation_id":    request.OperationID,
+		"type":            request.Type,
+	})
+}
+
+// Verify checks the signature of the request target and returns its arguments
+// without the reserved signature arguments.
+func (v *RequestVerifier) Verify(
+	request *OperatorExecutionRequested,
+	target *OperatorExecutionRequestedTarget,
+) (map[string]any, error) {
+	arguments := make(map[string]any, len(target.Arguments))
+	for key, value := range target.Arguments {
+		if key == SignatureArgument || key == TimestampArgument || key == NonceArgument {
+			continue
+		}
+
+		arguments[key] = value
+	}
+
+	if _, signed := target.Arguments[SignatureArgument]; !signed {
+		err := v.checkUnsigned(request.OperationID)
+		if err != nil {
+			return nil, err
+		}
+
+		return arguments, nil
+	}
+
+	payload, err := SignedRequestPayload(request, target)
+	if err != nil {
+		return nil, fmt.Errorf("could not build signed payload: %w", err)
+	}
+
+	err = v.verifySigned(payload, target.Arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	return arguments, nil
+}
+
+// VerifyRollback checks the signature of the deferred rollback request the same way as Verify.
+// The reserved signature arguments are given as fields of the request.
+func (v *RequestVerifier) VerifyRollback(request *OperatorRollbackRequested) error {
+	if request.Signature == "" {
+		return v.checkUnsigned(request.OperationID)
+	}
+
+	payload, err := SignedRollbackPayload(request)
+	if err != nil {
+		return fmt.Errorf("could not build signed payload: %w", err)
+	}
+
+	return v.verifySigned(payload, map[string]any{
+		SignatureArgument: request.Signature,
+		TimestampArgument: request.Timestamp,
+		NonceArgument:     request.Nonce,
+	})
+}
+
+func (v *RequestVerifier) checkUnsigned(operationID string) error {
+	if v.enforced {
+		return ErrUnsignedRequest
+	}
+
+	slog.Warn("Running unsigned operation request, signature enforcement is disabled",
+		"operation_id", operationID)
+
+	return nil
+}
+
+// verifySigned checks the signature of the payload and the freshness of the request,
+// using the reserved signature arguments.
+func (v *RequestVerifier) verifySigned(payload []byte, reservedArguments map[string]any) error {
+	signature, err := decodeSignature(reservedArguments[SignatureArgument])
+	if err != nil {
+		return err
+	}
+
+	timestamp, err := parseSignatureTimestamp(reservedArguments[TimestampArgument])
+	if err != nil {
+		return err
+	}
+
+	now := v.now()
+	if timestamp.Before(now.Add(-v.maxAge)) || timestamp.After(now.Add(v.maxAge)) {
+		return fmt.Errorf("request timestamp %s is out of the allowed %s window", timestamp, v.maxAge)
+	}
+
+	nonce, ok := reservedArguments[NonceArgument].(string)
+	if !ok || nonce == "" {
+		return fmt.Errorf("could not parse %s argument, argument provided: %v",
+			NonceArgument, reservedArguments[NonceArgument])
+	}
+
+	if !v.verifySignature(payload, signature) {
+		return ErrInvalidSignature
+	}
+
+	// The nonce is only registered once the signature is valid,
+	// so forged requests cannot burn the nonces of legit ones
+	return v.registerNonce(nonce, now)
+}
+
+func (v *RequestVerifier) verifySignature(payload, signature []byte) bool {
+	for _, publicKey := range v.publicKeys {
+		if ed25519.Verify(publicKey, payload, signature) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (v *RequestVerifier) registerNonce(nonce string, now time.Time) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.removeExpiredNonces(now)
+
+	if _, found := v.nonces[nonce]; found {
+		return ErrReplayedRequest
+	}
+
+	v.nonces[nonce] = now
+
+	// The request is rejected if the nonce cannot be saved,
+	// as it could be replayed after a restart otherwise
+	err := v.saveNonces()
+	if err != nil {
+		delete(v.nonces, nonce)
+
+		return err
+	}
+
+	return nil
+}
+
+func (v *RequestVerifier) removeExpiredNonces(now time.Time) {
+	for usedNonce, usedAt := range v.nonces {
+		// Requests older than the window are rejected by their timestamp
+		if now.Sub(usedAt) > 2*v.maxAge {
+			delete(v.nonces, usedNonce)
+		}
+	}
+}
+
+// saveNonces writes the nonces in a temporary file renamed to the nonces file,
+// so a crash while writing doesn't leave a truncated file.
+// The temporary file is created with 0600 permissions.
+func (v *RequestVerifier) saveNonces() error {
+	if v.noncesFile == "" {
+		return nil
+	}
+
+	content, err := json.Marshal(v.nonces)
+	if err != nil {
+		return fmt.Errorf("error encoding nonces: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(v.noncesFile), "."+filepath.Base(v.noncesFile)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("error creating temporary file for %s: %w", v.noncesFile, err)
+	}
+
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath)
+
+	_, err = tmpFile.Write(content)
+	if err != nil {
+		tmpFile.Close()
+
+		return fmt.Errorf("error writing temporary file for %s: %w", v.noncesFile, err)
+	}
+
+	err = tmpFile.Close()
+	if err != nil {
+		return fmt.Errorf("error closing temporary file for %s: %w", v.noncesFile, err)
+	}
+
+	err = os.Rename(tmpPath, v.noncesFile)
+	if err != nil {
+		return fmt.Errorf("error replacing %s: %w", v.noncesFile, err)
+	}
+
+	return nil
+}
+
+func decodeSignature(rawSignature any) ([]byte, error) {
+	encodedSignature, ok := rawSignature.(string)
+	if !ok {
+		return nil, fmt.Errorf("could not parse %s argument as string, argument provided: %v",
+			SignatureArgument, rawSignature)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode %s argument: %w", SignatureArgument, err)
+	}
+
+	if len(signature) != ed25519.SignatureSize {
+		return nil, fmt.Errorf("invalid signature size %d", len(signature))
+	}
+
+	return signature, nil
+}
+
+func parseSignatureTimestamp(rawTimestamp any) (time.Time, error) {
+	timestampString, ok := rawTimestamp.(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("could not parse %s argument as string, argument provided: %v",
+			TimestampArgument, rawTimestamp)
+	}
+
+	timestamp, err := time.Parse(time.RFC3339, timestampString)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not parse %s argument: %w", TimestampArgument, err)
+	}
+
+	return timestamp, nil
+}
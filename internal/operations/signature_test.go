@@ -0,0 +1,339 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package operations_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/trento-project/agent/v3/internal/operations"
+)
+
+type SignatureTestSuite struct {
+	suite.Suite
+
+	publicKey  ed25519.PublicKey
+	privateKey ed25519.PrivateKey
+	now        time.Time
+	request    *operations.OperatorExecutionRequested
+}
+
+func TestSignatureTestSuite(t *testing.T) {
+	suite.Run(t, new(SignatureTestSuite))
+}
+
+func (suite *SignatureTestSuite) SetupTest() {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	suite.Require().NoError(err)
+
+	suite.publicKey = publicKey
+	suite.privateKey = privateKey
+	suite.now = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	suite.request = &operations.OperatorExecutionRequested{
+		OperationID: "operation-id",
+		GroupID:     "group-id",
+		StepNumber:  1,
+		Operator:    "test@v1",
+	}
+}
+
+func (suite *SignatureTestSuite) newVerifier(enforced bool) *operations.RequestVerifier {
+	return operations.NewRequestVerifier(
+		[]ed25519.PublicKey{suite.publicKey},
+		enforced,
+		5*time.Minute,
+		operations.WithCustomVerifierClock(func() time.Time { return suite.now }),
+	)
+}
+
+func (suite *SignatureTestSuite) signedTarget(
+	privateKey ed25519.PrivateKey,
+	timestamp time.Time,
+	nonce string,
+) *operations.OperatorExecutionRequestedTarget {
+	target := &operations.OperatorExecutionRequestedTarget{
+		AgentID: "agent-id",
+		Arguments: map[string]any{
+			"instance_number":            "00",
+			"timeout":                    300.0,
+			operations.TimestampArgument: timestamp.Format(time.RFC3339),
+			operations.NonceArgument:     nonce,
+		},
+	}
+
+	payload, err := operations.SignedRequestPayload(suite.request, target)
+	suite.Require().NoError(err)
+
+	target.Arguments[operations.SignatureArgument] = base64.StdEncoding.EncodeToString(
+		ed25519.Sign(privateKey, payload),
+	)
+
+	return target
+}
+
+func (suite *SignatureTestSuite) TestVerifySignedRequest() {
+	verifier := suite.newVerifier(true)
+	target := suite.signedTarget(suite.privateKey, suite.now.Add(-time.Minute), "nonce")
+
+	arguments, err := verifier.Verify(suite.request, target)
+
+	suite.NoError(err)
+	suite.Equal(map[string]any{"instance_number": "00", "timeout": 300.0}, arguments)
+}
+
+func (suite *SignatureTestSuite) TestVerifyUnsignedRequest() {
+	target := &operations.OperatorExecutionRequestedTarget{
+		AgentID:   "agent-id",
+		Arguments: map[string]any{"instance_number": "00"},
+	}
+
+	_, err := suite.newVerifier(true).Verify(suite.request, target)
+	suite.ErrorIs(err, operations.ErrUnsignedRequest)
+
+	arguments, err := suite.newVerifier(false).Verify(suite.request, target)
+	suite.NoError(err)
+	suite.Equal(map[string]any{"instance_number": "00"}, arguments)
+}
+
+func (suite *SignatureTestSuite) TestVerifyInvalidSignature() {
+	_, otherPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	suite.Require().NoError(err)
+
+	target := suite.signedTarget(otherPrivateKey, suite.now, "nonce")
+
+	_, err = suite.newVerifier(true).Verify(suite.request, target)
+	suite.ErrorIs(err, operations.ErrInvalidSignature)
+}
+
+func (suite *SignatureTestSuite) TestVerifyTamperedRequest() {
+	target := suite.signedTarget(suite.privateKey, suite.now, "nonce")
+	target.Arguments["instance_number"] = "01"
+
+	_, err := suite.newVerifier(false).Verify(suite.request, target)
+	suite.ErrorIs(err, operations.ErrInvalidSignature)
+}
+
+func (suite *SignatureTestSuite) TestVerifyOtherAgentRequest() {
+	target := suite.signedTarget(suite.privateKey, suite.now, "nonce")
+	target.AgentID = "other-agent-id"
+
+	_, err := suite.newVerifier(true).Verify(suite.request, target)
+	suite.ErrorIs(err, operations.ErrInvalidSignature)
+}
+
+func (suite *SignatureTestSuite) TestVerifyExpiredRequest() {
+	target := suite.signedTarget(suite.privateKey, suite.now.Add(-10*time.Minute), "nonce")
+
+	_, err := suite.newVerifier(true).Verify(suite.request, target)
+	suite.ErrorContains(err, "is out of the allowed 5m0s window")
+}
+
+func (suite *SignatureTestSuite) TestVerifyReplayedRequest() {
+	verifier := suite.newVerifier(true)
+	target := suite.signedTarget(suite.privateKey, suite.now, "nonce")
+
+	_, err := verifier.Verify(suite.request, target)
+	suite.NoError(err)
+
+	_, err = verifier.Verify(suite.request, target)
+	suite.ErrorIs(err, operations.ErrReplayedRequest)
+}
+
+func (suite *SignatureTestSuite) TestVerifyReplayedRequestAfterRestart() {
+	noncesFile := filepath.Join(suite.T().TempDir(), "nonces.json")
+	newVerifier := func() *operations.RequestVerifier {
+		verifier := operations.NewRequestVerifier(
+			[]ed25519.PublicKey{suite.publicKey},
+			true,
+			5*time.Minute,
+			operations.WithCustomVerifierClock(func() time.Time { return suite.now }),
+			operations.WithNoncesFile(noncesFile),
+		)
+		suite.Require().NoError(verifier.LoadNonces())
+
+		return verifier
+	}
+
+	target := suite.signedTarget(suite.privateKey, suite.now, "nonce")
+
+	_, err := newVerifier().Verify(suite.request, target)
+	suite.NoError(err)
+
+	_, err = newVerifier().Verify(suite.request, target)
+	suite.ErrorIs(err, operations.ErrReplayedRequest)
+
+	suite.now = suite.now.Add(time.Minute)
+	otherTarget := suite.signedTarget(suite.privateKey, suite.now, "other-nonce")
+
+	_, err = newVerifier().Verify(suite.request, otherTarget)
+	suite.NoError(err)
+}
+
+func (suite *SignatureTestSuite) TestVerifyMissingNonce() {
+	target := suite.signedTarget(suite.privateKey, suite.now, "")
+
+	_, err := suite.newVerifier(true).Verify(suite.request, target)
+	suite.EqualError(err, "could not parse _nonce argument, argument provided: ")
+}
+
+func (suite *SignatureTestSuite) TestVerifyRollback() {
+	verifier := suite.newVerifier(true)
+	request := &operations.OperatorRollbackRequested{
+		Type:        operations.OperatorRollbackRequestedV1,
+		OperationID: "operation-id",
+		AgentID:     "agent-id",
+		Timestamp:   suite.now.Format(time.RFC3339),
+		Nonce:       "nonce",
+	}
+
+	suite.ErrorIs(verifier.VerifyRollback(request), operations.ErrUnsignedRequest)
+
+	payload, err := operations.SignedRollbackPayload(request)
+	suite.Require().NoError(err)
+
+	request.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(suite.privateKey, payload))
+
+	tamperedRequest := *request
+	tamperedRequest.OperationID = "other-operation-id"
+
+	suite.ErrorIs(verifier.VerifyRollback(&tamperedRequest), operations.ErrInvalidSignature)
+	suite.NoError(verifier.VerifyRollback(request))
+	suite.ErrorIs(verifier.VerifyRollback(request), operations.ErrReplayedRequest)
+}
+
+// The test vectors are signed with an independent implementation (openssl pkeyutl -sign -rawin)
+// over the canonical payloads written by hand, so they check the canonicalization of SignedRequestPayload
+// and SignedRollbackPayload against RFC 8785 and not against themselves.
+const (
+	testVectorPublicKey = `-----BEGIN PUBLIC KEY-----
+MCowBQYDK2VwAyEAu02GdkCbT/+KocwToSClyYQNRpxUUleWRqtSpYcxnh4=
+-----END PUBLIC KEY-----
+`
+	testVectorRequestPayload = `{"agent_id":"agent-id","arguments":{"_nonce":"nonce",` +
+		`"_timestamp":"2025-01-01T12:00:00Z","big":1e+21,"nested":{"B":null,"a":[1,"x"],"b":true},` +
+		`"note":"<b>&` + "\u2028" + `é\n\u0001","ratio":0.5,"small":0.000001,"timeout":300},` +
+		`"group_id":"group-id","operation_id":"operation-id","operator":"test@v1","step_number":2}`
+	testVectorRequestSignature = "I8OmI73O0q+jvHTAfZD/5Fa+h9fGcA4qOwcqEAqanEhS" +
+		"RSdbFe4E9fz4bxL7U6ybz1HnuICSO7I7RxwAko4wDA=="
+	testVectorRollbackPayload = `{"_nonce":"nonce","_timestamp":"2025-01-01T12:00:00Z","agent_id":"agent-id",` +
+		`"operation_id":"operation-id","type":"Trento.Operations.V1.OperatorRollbackRequested"}`
+	testVectorRollbackSignature = "cjH6uHYrFTYkVYNg3plD0I+bNg80jfDst69584YmO++C" +
+		"YCh6L4Vp/fkfChCUnvV68hOiWcpQ9S0HDQxTvB9XCA=="
+)
+
+func (suite *SignatureTestSuite) newTestVectorVerifier() *operations.RequestVerifier {
+	publicKey, err := operations.ParseEd25519PublicKey([]byte(testVectorPublicKey))
+	suite.Require().NoError(err)
+
+	return operations.NewRequestVerifier(
+		[]ed25519.PublicKey{publicKey},
+		true,
+		5*time.Minute,
+		operations.WithCustomVerifierClock(func() time.Time { return suite.now }),
+	)
+}
+
+func (suite *SignatureTestSuite) TestVerifyRequestTestVector() {
+	request := &operations.OperatorExecutionRequested{
+		OperationID: "operation-id",
+		GroupID:     "group-id",
+		StepNumber:  2,
+		Operator:    "test@v1",
+	}
+	target := &operations.OperatorExecutionRequestedTarget{
+		AgentID: "agent-id",
+		Arguments: map[string]any{
+			"timeout": 300.0,
+			"ratio":   0.5,
+			"big":     1e21,
+			"small":   0.000001,
+			"note":    "<b>&\u2028é\n\u0001",
+			"nested": map[string]any{
+				"b": true,
+				"B": nil,
+				"a": []any{1.0, "x"},
+			},
+			operations.TimestampArgument: "2025-01-01T12:00:00Z",
+			operations.NonceArgument:     "nonce",
+			operations.SignatureArgument: testVectorRequestSignature,
+		},
+	}
+
+	payload, err := operations.SignedRequestPayload(request, target)
+	suite.Require().NoError(err)
+	suite.Equal(testVectorRequestPayload, string(payload))
+
+	_, err = suite.newTestVectorVerifier().Verify(request, target)
+	suite.NoError(err)
+}
+
+func (suite *SignatureTestSuite) TestVerifyRollbackTestVector() {
+	request := &operations.OperatorRollbackRequested{
+		Type:        operations.OperatorRollbackRequestedV1,
+		OperationID: "operation-id",
+		AgentID:     "agent-id",
+		Timestamp:   "2025-01-01T12:00:00Z",
+		Nonce:       "nonce",
+		Signature:   testVectorRollbackSignature,
+	}
+
+	payload, err := operations.SignedRollbackPayload(request)
+	suite.Require().NoError(err)
+	suite.Equal(testVectorRollbackPayload, string(payload))
+
+	suite.NoError(suite.newTestVectorVerifier().VerifyRollback(request))
+}
+
+func (suite *SignatureTestSuite) TestNewRequestVerifierFromConfig() {
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(suite.publicKey)
+	suite.Require().NoError(err)
+
+	keyFile := filepath.Join(suite.T().TempDir(), "server.pub")
+	err = os.WriteFile(
+		keyFile,
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyBytes}),
+		0600,
+	)
+	suite.Require().NoError(err)
+
+	verifier, err := operations.NewRequestVerifierFromConfig(operations.SignatureConfig{
+		PublicKeysFiles: []string{keyFile},
+		Enforced:        true,
+	})
+	suite.NoError(err)
+
+	target := suite.signedTarget(suite.privateKey, time.Now(), "nonce")
+	_, err = verifier.Verify(suite.request, target)
+	suite.NoError(err)
+}
+
+func (suite *SignatureTestSuite) TestNewRequestVerifierFromConfigErrors() {
+	_, err := operations.NewRequestVerifierFromConfig(operations.SignatureConfig{Enforced: true})
+	suite.EqualError(err, "signature enforcement requires at least one public key")
+
+	keyFile := filepath.Join(suite.T().TempDir(), "server.pub")
+	suite.Require().NoError(os.WriteFile(keyFile, []byte("not a key"), 0600))
+
+	_, err = operations.NewRequestVerifierFromConfig(operations.SignatureConfig{
+		PublicKeysFiles: []string{keyFile},
+	})
+	suite.ErrorContains(err, "no PEM block found")
+
+	noncesFile := filepath.Join(suite.T().TempDir(), "nonces.json")
+	suite.Require().NoError(os.WriteFile(noncesFile, []byte("not json"), 0600))
+
+	_, err = operations.NewRequestVerifierFromConfig(operations.SignatureConfig{
+		NoncesFile: noncesFile,
+	})
+	suite.ErrorContains(err, "could not decode nonces file")
+}
@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package plugininterface
+
+import (
+	"context"
+	"encoding/gob"
+	"net/rpc"
+
+	"github.com/hashicorp/go-plugin"
+)
+
+// Handshake is shared by the agent and the operator plugins.
+// It differs from the gatherers one, so a gatherer cannot be loaded as an operator.
+//
+//nolint:gochecknoglobals
+var Handshake = plugin.HandshakeConfig{
+	ProtocolVersion:  1,
+	MagicCookieKey:   "TRENTO_PLUGIN",
+	MagicCookieValue: "operator",
+}
+
+// This is needed by the plugin system to be able to serialize the operator arguments and diff.
+//
+//nolint:gochecknoinits
+func init() {
+	gob.Register([]any{})
+	gob.Register(map[string]any{})
+}
+
+// Operator is the interface implemented by the plugins, with the same phases as the agent operators.
+// A new Operator is built for each operation, and the agent Executor drives its phases,
+// so PLAN runs first and ROLLBACK only runs after a failed COMMIT or VERIFY.
+//
+// Plan returns true if the changes are already applied, skipping the rest of the phases.
+// OperationDiff returns the before and after states, using the "before" and "after" keys.
+type Operator interface {
+	Plan(ctx context.Context) (alreadyApplied bool, err error)
+	Commit(ctx context.Context) error
+	Verify(ctx context.Context) error
+	Rollback(ctx context.Context) error
+	OperationDiff(ctx context.Context) map[string]any
+}
+
+// OperatorBuilder builds the plugin operator for an operation.
+type OperatorBuilder func(operationID string, arguments map[string]any) Operator
+
+// This is the implementation of plugin.Plugin.
+type OperatorPlugin struct {
+	// Builder Injection
+	Builder OperatorBuilder
+}
+
+func (p *OperatorPlugin) Server(*plugin.MuxBroker) (any, error) {
+	return &OperatorRPCServer{Builder: p.Builder}, nil
+}
+
+func (OperatorPlugin) Client(_ *plugin.MuxBroker, c *rpc.Client) (any, error) {
+	return &OperatorRPC{client: c}, nil
+}
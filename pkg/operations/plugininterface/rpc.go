@@ -0,0 +1,227 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package plugininterface
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/rpc"
+	"sync"
+)
+
+type OperatorRPC struct{ client *rpc.Client }
+
+type PlanArgs struct {
+	OperationID string
+	Arguments   map[string]any
+}
+
+type PlanResponse struct {
+	AlreadyApplied bool
+}
+
+func (o *OperatorRPC) Plan(ctx context.Context, operationID string, arguments map[string]any) (bool, error) {
+	var resp PlanResponse
+
+	err := o.call(ctx, "Plugin.Plan", operationID, PlanArgs{
+		OperationID: operationID,
+		Arguments:   arguments,
+	}, &resp)
+	if err != nil {
+		return false, err
+	}
+
+	return resp.AlreadyApplied, nil
+}
+
+func (o *OperatorRPC) Commit(ctx context.Context, operationID string) error {
+	var resp bool
+
+	return o.call(ctx, "Plugin.Commit", operationID, operationID, &resp)
+}
+
+func (o *OperatorRPC) Verify(ctx context.Context, operationID string) error {
+	var resp bool
+
+	return o.call(ctx, "Plugin.Verify", operationID, operationID, &resp)
+}
+
+func (o *OperatorRPC) Rollback(ctx context.Context, operationID string) error {
+	var resp bool
+
+	return o.call(ctx, "Plugin.Rollback", operationID, operationID, &resp)
+}
+
+func (o *OperatorRPC) OperationDiff(ctx context.Context, operationID string) (map[string]any, error) {
+	var resp map[string]any
+
+	err := o.call(ctx, "Plugin.OperationDiff", operationID, operationID, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Release frees the plugin operator once the operation is finished.
+func (o *OperatorRPC) Release(operationID string) error {
+	var resp bool
+
+	return o.client.Call("Plugin.Release", operationID, &resp)
+}
+
+// call runs the remote method, asking the plugin to cancel the running phase
+// if the context is done before the method returns.
+func (o *OperatorRPC) call(ctx context.Context, method, operationID string, args any, resp any) error {
+	calling := make(chan error, 1)
+
+	go func() {
+		calling <- o.client.Call(method, args, resp)
+	}()
+
+	select {
+	case <-ctx.Done():
+		var cancelResp bool
+
+		err := o.client.Call("Plugin.Cancel", operationID, &cancelResp)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Err()
+	case err := <-calling:
+		return err
+	}
+}
+
+type operatorInstance struct {
+	operator Operator
+	cancel   context.CancelFunc
+}
+
+type OperatorRPCServer struct {
+	Builder   OperatorBuilder
+	instances map[string]*operatorInstance
+	mu        sync.Mutex
+}
+
+func (s *OperatorRPCServer) Plan(args PlanArgs, resp *PlanResponse) error {
+	s.mu.Lock()
+	if s.instances == nil {
+		s.instances = make(map[string]*operatorInstance)
+	}
+
+	if _, found := s.instances[args.OperationID]; found {
+		s.mu.Unlock()
+
+		return fmt.Errorf("operation %s already planned", args.OperationID)
+	}
+
+	s.instances[args.OperationID] = &operatorInstance{
+		operator: s.Builder(args.OperationID, args.Arguments),
+	}
+	s.mu.Unlock()
+
+	err := s.runPhase(args.OperationID, func(ctx context.Context, operator Operator) error {
+		alreadyApplied, err := operator.Plan(ctx)
+		resp.AlreadyApplied = alreadyApplied
+
+		return err
+	})
+	if err != nil {
+		// A failed plan finishes the operation, so the agent won't release it
+		s.release(args.OperationID)
+
+		return err
+	}
+
+	return nil
+}
+
+func (s *OperatorRPCServer) Commit(operationID string, _ *bool) error {
+	return s.runPhase(operationID, func(ctx context.Context, operator Operator) error {
+		return operator.Commit(ctx)
+	})
+}
+
+func (s *OperatorRPCServer) Verify(operationID string, _ *bool) error {
+	return s.runPhase(operationID, func(ctx context.Context, operator Operator) error {
+		return operator.Verify(ctx)
+	})
+}
+
+func (s *OperatorRPCServer) Rollback(operationID string, _ *bool) error {
+	return s.runPhase(operationID, func(ctx context.Context, operator Operator) error {
+		return operator.Rollback(ctx)
+	})
+}
+
+func (s *OperatorRPCServer) OperationDiff(operationID string, resp *map[string]any) error {
+	return s.runPhase(operationID, func(ctx context.Context, operator Operator) error {
+		*resp = operator.OperationDiff(ctx)
+
+		return nil
+	})
+}
+
+func (s *OperatorRPCServer) Release(operationID string, _ *bool) error {
+	s.release(operationID)
+
+	return nil
+}
+
+func (s *OperatorRPCServer) Cancel(operationID string, _ *bool) error {
+	s.mu.Lock()
+	instance, found := s.instances[operationID]
+
+	var cancel context.CancelFunc
+	if found {
+		cancel = instance.cancel
+	}
+	s.mu.Unlock()
+
+	if cancel == nil {
+		slog.Warn("Cannot find cancel function for operation", "operationID", operationID)
+
+		return nil
+	}
+
+	cancel()
+
+	return nil
+}
+
+func (s *OperatorRPCServer) runPhase(
+	operationID string,
+	phase func(ctx context.Context, operator Operator) error,
+) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.mu.Lock()
+	instance, found := s.instances[operationID]
+	if found {
+		instance.cancel = cancel
+	}
+	s.mu.Unlock()
+
+	if !found {
+		return fmt.Errorf("operation %s not found", operationID)
+	}
+
+	defer func() {
+		s.mu.Lock()
+		instance.cancel = nil
+		s.mu.Unlock()
+	}()
+
+	return phase(ctx, instance.operator)
+}
+
+func (s *OperatorRPCServer) release(operationID string) {
+	s.mu.Lock()
+	delete(s.instances, operationID)
+	s.mu.Unlock()
+}
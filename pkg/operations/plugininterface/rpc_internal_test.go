@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package plugininterface
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/rpc"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testOperator struct {
+	arguments map[string]any
+	planError error
+	phases    []string
+}
+
+func (o *testOperator) Plan(_ context.Context) (bool, error) {
+	o.phases = append(o.phases, "plan")
+
+	return false, o.planError
+}
+
+func (o *testOperator) Commit(_ context.Context) error {
+	o.phases = append(o.phases, "commit")
+
+	return errors.New("commit failed")
+}
+
+func (o *testOperator) Verify(_ context.Context) error {
+	o.phases = append(o.phases, "verify")
+
+	return nil
+}
+
+func (o *testOperator) Rollback(_ context.Context) error {
+	o.phases = append(o.phases, "rollback")
+
+	return nil
+}
+
+func (o *testOperator) OperationDiff(_ context.Context) map[string]any {
+	return map[string]any{"before": o.arguments["before"], "after": "after"}
+}
+
+// blockingOperator commits until its context is cancelled.
+type blockingOperator struct {
+	testOperator
+}
+
+func (o *blockingOperator) Commit(ctx context.Context) error {
+	<-ctx.Done()
+
+	return ctx.Err()
+}
+
+func newTestClient(t *testing.T, server *OperatorRPCServer) *OperatorRPC {
+	t.Helper()
+
+	clientConn, serverConn := net.Pipe()
+
+	rpcServer := rpc.NewServer()
+	require.NoError(t, rpcServer.RegisterName("Plugin", server))
+
+	go rpcServer.ServeConn(serverConn)
+
+	rpcClient := rpc.NewClient(clientConn)
+	t.Cleanup(func() {
+		rpcClient.Close()
+		serverConn.Close()
+	})
+
+	return &OperatorRPC{client: rpcClient}
+}
+
+func TestOperatorRPCPhases(t *testing.T) {
+	operators := map[string]*testOperator{}
+	server := &OperatorRPCServer{
+		Builder: func(operationID string, arguments map[string]any) Operator {
+			operators[operationID] = &testOperator{arguments: arguments}
+
+			return operators[operationID]
+		},
+	}
+	client := newTestClient(t, server)
+	ctx := context.Background()
+
+	arguments := map[string]any{
+		"before": "before",
+		"list":   []any{"a", 1.0},
+		"map":    map[string]any{"nested": true},
+	}
+
+	alreadyApplied, err := client.Plan(ctx, "operation-id", arguments)
+	require.NoError(t, err)
+	require.False(t, alreadyApplied)
+	require.Equal(t, arguments, operators["operation-id"].arguments)
+
+	_, err = client.Plan(ctx, "operation-id", arguments)
+	require.EqualError(t, err, "operation operation-id already planned")
+
+	require.EqualError(t, client.Commit(ctx, "operation-id"), "commit failed")
+	require.NoError(t, client.Rollback(ctx, "operation-id"))
+
+	diff, err := client.OperationDiff(ctx, "operation-id")
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"before": "before", "after": "after"}, diff)
+
+	require.Equal(t, []string{"plan", "commit", "rollback"}, operators["operation-id"].phases)
+
+	require.NoError(t, client.Release("operation-id"))
+	require.EqualError(t, client.Verify(ctx, "operation-id"), "operation operation-id not found")
+}
+
+func TestOperatorRPCPlanErrorReleasesOperator(t *testing.T) {
+	server := &OperatorRPCServer{
+		Builder: func(_ string, _ map[string]any) Operator {
+			return &testOperator{planError: errors.New("plan failed")}
+		},
+	}
+	client := newTestClient(t, server)
+
+	_, err := client.Plan(context.Background(), "operation-id", map[string]any{})
+	require.EqualError(t, err, "plan failed")
+	require.Empty(t, server.instances)
+}
+
+func TestOperatorRPCCancel(t *testing.T) {
+	server := &OperatorRPCServer{
+		Builder: func(_ string, _ map[string]any) Operator {
+			return &blockingOperator{}
+		},
+	}
+	client := newTestClient(t, server)
+
+	_, err := client.Plan(context.Background(), "operation-id", map[string]any{})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+
+	require.ErrorIs(t, client.Commit(ctx, "operation-id"), context.Canceled)
+}
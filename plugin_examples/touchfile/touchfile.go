@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: SUSE LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+// go build -o /usr/etc/trento/operator-plugins/touchfile ./plugin_examples/touchfile/touchfile.go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/hashicorp/go-plugin"
+	"github.com/trento-project/agent/v3/pkg/operations/plugininterface"
+)
+
+// touchFile creates an empty file in the path given in the path argument.
+type touchFile struct {
+	path    string
+	existed bool
+}
+
+func (t *touchFile) Plan(_ context.Context) (bool, error) {
+	if t.path == "" {
+		return false, errors.New("argument path not provided")
+	}
+
+	_, err := os.Stat(t.path)
+	if err == nil {
+		t.existed = true
+
+		return true, nil
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+
+	return false, nil
+}
+
+func (t *touchFile) Commit(_ context.Context) error {
+	file, err := os.Create(t.path)
+	if err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
+func (t *touchFile) Verify(_ context.Context) error {
+	_, err := os.Stat(t.path)
+	if err != nil {
+		return fmt.Errorf("file %s not created: %w", t.path, err)
+	}
+
+	return nil
+}
+
+func (t *touchFile) Rollback(_ context.Context) error {
+	err := os.Remove(t.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (t *touchFile) OperationDiff(_ context.Context) map[string]any {
+	return map[string]any{
+		"before": strconv.FormatBool(t.existed),
+		"after":  "true",
+	}
+}
+
+func main() {
+	builder := func(_ string, arguments map[string]any) plugininterface.Operator {
+		path, _ := arguments["path"].(string)
+
+		return &touchFile{path: path}
+	}
+
+	var pluginMap = map[string]plugin.Plugin{
+		"operator": &plugininterface.OperatorPlugin{Builder: builder},
+	}
+
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig: plugininterface.Handshake,
+		Plugins:         pluginMap,
+	})
+}